@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"play4good-backend/admin"
+	dbCon "play4good-backend/db/sqlc"
+	"play4good-backend/schemas"
+)
+
+const adminUsage = `usage: play4good admin <command> [flags]
+
+  create-admin           create a verified user with the admin role
+  set-role               change a user's role to user or admin
+  recompute-totals       set every cause's current amount from its donations
+  rebuild-leaderboards   recompute leaderboard entries from donations
+  purge-tokens           delete expired session tokens
+  export                 write teams, causes and leaderboards as JSON Lines
+  import                 create the teams, causes and leaderboards in an export
+`
+
+// runAdmin implements the admin command. Each subcommand is a call into the admin service.
+func runAdmin(ctx context.Context, conn *sql.DB, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		fmt.Print(adminUsage)
+		if len(args) == 0 {
+			return errors.New("missing admin command")
+		}
+		return nil
+	}
+
+	service := admin.NewService(dbCon.NewStore(conn))
+	name, args := args[0], args[1:]
+	fs := flag.NewFlagSet("admin "+name, flag.ContinueOnError)
+
+	switch name {
+	case "create-admin":
+		var req schemas.UserCreateRequest
+		fs.StringVar(&req.Email, "email", "", "email address (required)")
+		fs.StringVar(&req.Username, "username", "", "username (required)")
+		fs.StringVar(&req.FirstName, "first-name", "", "first name (required)")
+		fs.StringVar(&req.LastName, "last-name", "", "last name (required)")
+		fs.StringVar(&req.Password, "password", "", "password; read from stdin when omitted, to keep it out of the process list")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if req.Password == "" {
+			password, err := readLine(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("could not read password: %w", err)
+			}
+			req.Password = password
+		}
+		user, err := service.CreateAdmin(ctx, req)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created admin %s (id %d)\n", user.Email, user.ID)
+
+	case "set-role":
+		email := fs.String("email", "", "email of the user (required)")
+		role := fs.String("role", "", "user or admin (required)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		user, err := service.SetRole(ctx, *email, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s is now %s\n", user.Email, user.UserRole.String)
+
+	case "recompute-totals":
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		changed, err := service.RecomputeCauseTotals(ctx)
+		if err != nil {
+			return err
+		}
+		for _, cause := range changed {
+			fmt.Printf("cause %d %q: %s\n", cause.ID, cause.Name, cause.CurrentAmount.String)
+		}
+		fmt.Printf("%d causes updated\n", len(changed))
+
+	case "rebuild-leaderboards":
+		id := fs.Int("id", 0, "leaderboard to rebuild; all when 0")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		counts, err := service.RebuildLeaderboards(ctx, int32(*id))
+		ids := make([]int32, 0, len(counts))
+		for id := range counts {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			fmt.Printf("leaderboard %d: %d entries\n", id, counts[id])
+		}
+		return err
+
+	case "purge-tokens":
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		n, err := service.PurgeExpiredTokens(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d expired tokens deleted\n", n)
+
+	case "export":
+		out := fs.String("o", "-", "output file, - for stdout")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		w := io.Writer(os.Stdout)
+		if *out != "-" {
+			f, err := os.Create(*out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		bw := bufio.NewWriter(w)
+		if err := service.Export(ctx, bw); err != nil {
+			return err
+		}
+		return bw.Flush()
+
+	case "import":
+		owner := fs.String("owner", "", "email of the user who owns imported causes")
+		dryRun := fs.Bool("dry-run", false, "validate without writing anything")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return errors.New("import needs a file, or - for stdin")
+		}
+		r := io.Reader(os.Stdin)
+		if fs.Arg(0) != "-" {
+			f, err := os.Open(fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		result, err := service.Import(ctx, r, *owner, *dryRun)
+		if err != nil {
+			return err
+		}
+		verb := "imported"
+		if *dryRun {
+			verb = "valid, nothing written:"
+		}
+		fmt.Printf("%s %d teams, %d causes, %d leaderboards\n", verb, result[admin.KindTeam], result[admin.KindCause], result[admin.KindLeaderboard])
+
+	default:
+		fmt.Fprint(os.Stderr, adminUsage)
+		return fmt.Errorf("unknown admin command %q", name)
+	}
+	return nil
+}
+
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,193 @@
+// Package admin implements the operator tasks run from the command line. They go through the same
+// queries, validation and audit log as the API, with no acting user recorded.
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Roles a user can hold
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ErrUnknownRole is returned by SetRole for anything but RoleUser or RoleAdmin
+var ErrUnknownRole = errors.New("role must be user or admin")
+
+type Service struct {
+	store *db.Store
+}
+
+func NewService(store *db.Store) *Service {
+	return &Service{store: store}
+}
+
+// CreateAdmin creates a user with the admin role. Operators vouch for the address, so it starts verified.
+func (s *Service) CreateAdmin(ctx context.Context, req schemas.UserCreateRequest) (db.User, error) {
+	if err := Validate(req); err != nil {
+		return db.User{}, err
+	}
+	if _, err := s.store.GetUserByEmail(ctx, req.Email); err == nil {
+		return db.User{}, fmt.Errorf("a user with email %s already exists", req.Email)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, err
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	var user db.User
+	err = s.store.ExecTx(ctx, func(q db.Querier) error {
+		created, err := q.CreateUser(ctx, db.CreateUserParams{
+			Username:     req.Username,
+			Email:        req.Email,
+			PasswordHash: hashedPassword,
+			FirstName:    sql.NullString{String: req.FirstName, Valid: true},
+			LastName:     sql.NullString{String: req.LastName, Valid: true},
+			AvatarUrl:    sql.NullString{String: req.AvatarURL, Valid: req.AvatarURL != ""},
+			UserRole:     sql.NullString{String: RoleAdmin, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		user, err = q.MarkUserEmailVerified(ctx, created.ID)
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityUser, user.ID, nil, user)
+	})
+	return user, err
+}
+
+// SetRole changes the role of the user with the given email
+func (s *Service) SetRole(ctx context.Context, email, role string) (db.User, error) {
+	if role != RoleUser && role != RoleAdmin {
+		return db.User{}, ErrUnknownRole
+	}
+
+	before, err := s.store.GetUserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, fmt.Errorf("no user with email %s", email)
+	}
+	if err != nil {
+		return db.User{}, err
+	}
+
+	var user db.User
+	err = s.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		user, err = q.SetUserRole(ctx, db.SetUserRoleParams{
+			ID:       before.ID,
+			UserRole: sql.NullString{String: role, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityUser, user.ID, before, user)
+	})
+	return user, err
+}
+
+// RecomputeCauseTotals sets each cause's current amount to the sum of its donations and returns the
+// causes that were out of step
+func (s *Service) RecomputeCauseTotals(ctx context.Context) ([]db.Cause, error) {
+	var changed []db.Cause
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		changed, err = q.RecomputeCauseTotals(ctx)
+		if err != nil {
+			return err
+		}
+		for _, cause := range changed {
+			if err := record(ctx, q, audit.ActionUpdate, audit.EntityCause, cause.ID, nil, cause); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// RebuildLeaderboards replaces the entries of a leaderboard, or of every leaderboard when id is 0,
+// with scores and ranks computed from donations. It returns the number of entries per leaderboard.
+func (s *Service) RebuildLeaderboards(ctx context.Context, id int32) (map[int32]int64, error) {
+	ids := []int32{id}
+	if id == 0 {
+		var err error
+		if ids, err = s.store.ListLeaderboardIDs(ctx); err != nil {
+			return nil, err
+		}
+	} else if _, err := s.store.GetLeaderboard(ctx, id); errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no leaderboard with id %d", id)
+	} else if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int32]int64, len(ids))
+	for _, id := range ids {
+		err := s.store.ExecTx(ctx, func(q db.Querier) error {
+			if err := q.DeleteLeaderboardEntriesByLeaderboard(ctx, id); err != nil {
+				return err
+			}
+			n, err := q.RebuildLeaderboardEntries(ctx, id)
+			if err != nil {
+				return err
+			}
+			counts[id] = n
+			return record(ctx, q, audit.ActionUpdate, audit.EntityLeaderboard, id, nil, map[string]interface{}{"rebuilt_entries": n})
+		})
+		if err != nil {
+			return counts, fmt.Errorf("rebuild leaderboard %d: %w", id, err)
+		}
+	}
+	return counts, nil
+}
+
+// PurgeExpiredTokens deletes session tokens past their expiry
+func (s *Service) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	return s.store.DeleteExpiredTokens(ctx)
+}
+
+// Validate checks v against its binding rules, the same ones the API applies to request bodies
+func Validate(v interface{}) error {
+	err := binding.Validator.ValidateStruct(v)
+	if err == nil {
+		return nil
+	}
+	p := problem.From(err, http.StatusBadRequest)
+	if len(p.Errors) == 0 {
+		return err
+	}
+	msgs := make([]string, len(p.Errors))
+	for i, fe := range p.Errors {
+		msgs[i] = fe.Field + " " + fe.Message
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+func record(ctx context.Context, q db.Querier, action, entityType string, entityID interface{}, before, after interface{}) error {
+	_, err := audit.Record(ctx, q, audit.Entry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     before,
+		After:      after,
+	})
+	return err
+}
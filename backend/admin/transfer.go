@@ -0,0 +1,298 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/schemas"
+)
+
+// Kinds of record in an export
+const (
+	KindTeam        = "team"
+	KindCause       = "cause"
+	KindLeaderboard = "leaderboard"
+)
+
+// exportPageSize is how many rows are read per query while exporting
+const exportPageSize = 500
+
+// Record is one line of an export: a team in the shape of its create request, or a cause or
+// leaderboard in the shape of its record type, so an import applies the same validation as the API
+type Record struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// CauseRecord is a cause in an export. It is the cause create request with optional dates, as
+// causes created before the dates were required, or by a spreadsheet import, may have none.
+type CauseRecord struct {
+	Name        string     `json:"name" binding:"required,min=3,max=100"`
+	Description string     `json:"description"`
+	Goal        float64    `json:"goal" binding:"required,min=0"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Status      string     `json:"status" binding:"required,oneof=active inactive completed"`
+	Image       string     `json:"image" binding:"omitempty,url"`
+	Category    string     `json:"category" binding:"omitempty,min=3,max=50"`
+}
+
+// LeaderboardRecord is a leaderboard in an export: its create request with optional dates
+type LeaderboardRecord struct {
+	Name      string     `json:"name" binding:"required,min=3,max=100"`
+	Type      string     `json:"type" binding:"required,oneof=individual team"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+// ImportResult counts the records created per kind
+type ImportResult map[string]int
+
+// Export writes every live team, cause and leaderboard to w as JSON Lines. Users, donations and
+// anything else tied to a person are left out; they move with the privacy export instead.
+func (s *Service) Export(ctx context.Context, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	write := func(kind string, data interface{}) error {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(Record{Kind: kind, Data: raw})
+	}
+
+	for offset := int32(0); ; offset += exportPageSize {
+		teams, err := s.store.ListTeams(ctx, db.ListTeamsParams{Limit: exportPageSize, Offset: offset})
+		if err != nil {
+			return err
+		}
+		for _, team := range teams {
+			if err := write(KindTeam, schemas.TeamCreateRequest{
+				Name:        team.Name,
+				Description: team.Description.String,
+				AvatarURL:   team.AvatarUrl.String,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(teams) < exportPageSize {
+			break
+		}
+	}
+
+	for offset := int32(0); ; offset += exportPageSize {
+		causes, err := s.store.ListCauses(ctx, db.ListCausesParams{Limit: exportPageSize, Offset: offset})
+		if err != nil {
+			return err
+		}
+		for _, cause := range causes {
+			goal, _ := strconv.ParseFloat(cause.Goal.String, 64)
+			if err := write(KindCause, CauseRecord{
+				Name:        cause.Name,
+				Description: cause.Description.String,
+				Goal:        goal,
+				StartDate:   timePtr(cause.StartDate),
+				EndDate:     timePtr(cause.EndDate),
+				Status:      cause.Status.String,
+				Image:       cause.Image.String,
+				Category:    cause.Category.String,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(causes) < exportPageSize {
+			break
+		}
+	}
+
+	for offset := int32(0); ; offset += exportPageSize {
+		leaderboards, err := s.store.ListLeaderboards(ctx, db.ListLeaderboardsParams{Limit: exportPageSize, Offset: offset})
+		if err != nil {
+			return err
+		}
+		for _, leaderboard := range leaderboards {
+			if err := write(KindLeaderboard, LeaderboardRecord{
+				Name:      leaderboard.Name,
+				Type:      leaderboard.Type.String,
+				StartDate: timePtr(leaderboard.StartDate),
+				EndDate:   timePtr(leaderboard.EndDate),
+			}); err != nil {
+				return err
+			}
+		}
+		if len(leaderboards) < exportPageSize {
+			break
+		}
+	}
+	return nil
+}
+
+// Import creates the records in r, as written by Export, in a single transaction: one invalid line
+// and nothing is imported. Imported causes belong to the user with ownerEmail, or to no one when it
+// is empty. With dryRun the records are validated and counted but not written.
+func (s *Service) Import(ctx context.Context, r io.Reader, ownerEmail string, dryRun bool) (ImportResult, error) {
+	var ownerID int32
+	if ownerEmail != "" {
+		owner, err := s.store.GetUserByEmail(ctx, ownerEmail)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no user with email %s", ownerEmail)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ownerID = owner.ID
+	}
+
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := validateRecord(rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := ImportResult{}
+	if dryRun {
+		for _, rec := range records {
+			result[rec.Kind]++
+		}
+		return result, nil
+	}
+
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		for i, rec := range records {
+			if err := importRecord(ctx, q, rec, ownerID); err != nil {
+				return fmt.Errorf("record %d (%s): %w", i+1, rec.Kind, err)
+			}
+			result[rec.Kind]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func validateRecord(rec Record) error {
+	var req interface{}
+	var start, end **time.Time
+	switch rec.Kind {
+	case KindTeam:
+		req = &schemas.TeamCreateRequest{}
+	case KindCause:
+		cause := &CauseRecord{}
+		req, start, end = cause, &cause.StartDate, &cause.EndDate
+	case KindLeaderboard:
+		leaderboard := &LeaderboardRecord{}
+		req, start, end = leaderboard, &leaderboard.StartDate, &leaderboard.EndDate
+	default:
+		return fmt.Errorf("unknown kind %q", rec.Kind)
+	}
+	if err := json.Unmarshal(rec.Data, req); err != nil {
+		return err
+	}
+	if err := Validate(req); err != nil {
+		return err
+	}
+	if start == nil {
+		return nil
+	}
+	// As in the create requests, a cause or leaderboard ends after it starts, when both dates are set
+	if from, to := nullTime(*start), nullTime(*end); from.Valid && to.Valid && !to.Time.After(from.Time) {
+		return errors.New("end_date must be after start_date")
+	}
+	return nil
+}
+
+// timePtr is the export form of a nullable date
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// nullTime is the stored form of an exported date. Exports written before dates were nullable
+// carry the zero time for a missing date, so that is read as null too.
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil || t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func importRecord(ctx context.Context, q db.Querier, rec Record, ownerID int32) error {
+	switch rec.Kind {
+	case KindTeam:
+		var req schemas.TeamCreateRequest
+		if err := json.Unmarshal(rec.Data, &req); err != nil {
+			return err
+		}
+		team, err := q.CreateTeam(ctx, db.CreateTeamParams{
+			Name:        req.Name,
+			Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+			AvatarUrl:   sql.NullString{String: req.AvatarURL, Valid: req.AvatarURL != ""},
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityTeam, team.ID, nil, team)
+	case KindCause:
+		var req CauseRecord
+		if err := json.Unmarshal(rec.Data, &req); err != nil {
+			return err
+		}
+		cause, err := q.CreateCause(ctx, db.CreateCauseParams{
+			Name:        req.Name,
+			Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+			Goal:        sql.NullString{String: strconv.FormatFloat(req.Goal, 'f', -1, 64), Valid: true},
+			StartDate:   nullTime(req.StartDate),
+			EndDate:     nullTime(req.EndDate),
+			Status:      sql.NullString{String: req.Status, Valid: req.Status != ""},
+			Image:       sql.NullString{String: req.Image, Valid: req.Image != ""},
+			Category:    sql.NullString{String: req.Category, Valid: req.Category != ""},
+			OwnerID:     sql.NullInt32{Int32: ownerID, Valid: ownerID != 0},
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityCause, cause.ID, nil, cause)
+	case KindLeaderboard:
+		var req LeaderboardRecord
+		if err := json.Unmarshal(rec.Data, &req); err != nil {
+			return err
+		}
+		leaderboard, err := q.CreateLeaderboard(ctx, db.CreateLeaderboardParams{
+			Name:      req.Name,
+			Type:      sql.NullString{String: req.Type, Valid: req.Type != ""},
+			StartDate: nullTime(req.StartDate),
+			EndDate:   nullTime(req.EndDate),
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityLeaderboard, leaderboard.ID, nil, leaderboard)
+	}
+	return errors.New("unknown kind")
+}
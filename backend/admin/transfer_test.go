@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateRecordDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		data    string
+		wantErr bool
+	}{
+		{"cause without dates", KindCause, `{"name": "Clean Rivers", "goal": 100, "status": "active", "start_date": null, "end_date": null}`, false},
+		{"cause from an older export", KindCause, `{"name": "Clean Rivers", "goal": 100, "status": "active", "start_date": "0001-01-01T00:00:00Z", "end_date": "0001-01-01T00:00:00Z"}`, false},
+		{"cause with only an end", KindCause, `{"name": "Clean Rivers", "goal": 100, "status": "active", "end_date": "2026-03-01T00:00:00Z"}`, false},
+		{"cause ending before it starts", KindCause, `{"name": "Clean Rivers", "goal": 100, "status": "active", "start_date": "2026-03-01T00:00:00Z", "end_date": "2026-02-01T00:00:00Z"}`, true},
+		{"leaderboard without dates", KindLeaderboard, `{"name": "Spring League", "type": "team"}`, false},
+		{"leaderboard ending when it starts", KindLeaderboard, `{"name": "Spring League", "type": "team", "start_date": "2026-03-01T00:00:00Z", "end_date": "2026-03-01T00:00:00Z"}`, true},
+		{"team", KindTeam, `{"name": "Green Runners"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRecord(Record{Kind: tt.kind, Data: json.RawMessage(tt.data)})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRecord() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+// Package analytics answers dashboard questions about donations: totals, counts, unique donors and
+// average gifts over time, grouped by cause, category, team or donation type. Queries read the
+// donation_rollups table, which holds donations pre-aggregated into 15 minute buckets, so they never
+// scan donations and can bucket days, weeks and months in any time zone.
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+
+	// Time zones are looked up by name; the runtime image has no zoneinfo of its own
+	_ "time/tzdata"
+)
+
+// Intervals a series can be bucketed by. Weeks start on Monday.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// Dimensions a series can be grouped by; GroupNone is a single series of every donation
+const (
+	GroupNone         = ""
+	GroupCategory     = "category"
+	GroupCause        = "cause"
+	GroupTeam         = "team"
+	GroupDonationType = "donation_type"
+)
+
+const (
+	// defaultDays is the range covered when no dates are given, ending today
+	defaultDays = 30
+	// maxPoints caps the number of periods in a series
+	maxPoints  = 1000
+	dateLayout = "2006-01-02"
+)
+
+// Spec describes one analytics query. From and To are calendar days, both included, in TimeZone.
+type Spec struct {
+	Interval     string
+	GroupBy      string
+	From         time.Time
+	To           time.Time
+	TimeZone     string
+	CauseID      int32
+	TeamID       int32
+	DonationType string
+	// Compare adds the totals of the period of the same length just before From
+	Compare bool
+
+	location *time.Location
+}
+
+// Validate checks the interval, grouping, time zone and range, and fills in the defaults: daily
+// buckets in UTC over the last 30 days
+func (s *Spec) Validate() error {
+	switch s.Interval {
+	case "":
+		s.Interval = IntervalDay
+	case IntervalDay, IntervalWeek, IntervalMonth:
+	default:
+		return problem.Invalid("interval", "oneof", "must be one of: "+IntervalDay+", "+IntervalWeek+", "+IntervalMonth)
+	}
+	switch s.GroupBy {
+	case GroupNone, GroupCategory, GroupCause, GroupTeam, GroupDonationType:
+	default:
+		return problem.Invalid("group_by", "oneof", "must be one of: "+GroupCategory+", "+GroupCause+", "+GroupTeam+", "+GroupDonationType)
+	}
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil || s.TimeZone == "Local" {
+		return problem.Invalid("tz", "timezone", "must be an IANA time zone such as Europe/Berlin")
+	}
+	s.location = loc
+
+	if s.To.IsZero() {
+		s.To = time.Now().In(loc)
+	}
+	s.To = day(s.To, loc)
+	if s.From.IsZero() {
+		s.From = s.To.AddDate(0, 0, -(defaultDays - 1))
+	}
+	s.From = day(s.From, loc)
+	if s.To.Before(s.From) {
+		return problem.Invalid("to", "gtefield", "must not be before from")
+	}
+	if len(periods(s.Interval, s.From, s.end())) > maxPoints {
+		return problem.Invalid("from", "max", fmt.Sprintf("the range covers more than %d %ss; use a longer interval or a shorter range", maxPoints, s.Interval))
+	}
+	return nil
+}
+
+// end is the start of the day after To
+func (s Spec) end() time.Time {
+	return s.To.AddDate(0, 0, 1)
+}
+
+// previous is the range of the same number of days just before From
+func (s Spec) previous() (time.Time, time.Time) {
+	days := int(math.Round(s.end().Sub(s.From).Hours() / 24))
+	return s.From.AddDate(0, 0, -days), s.From
+}
+
+// Metrics are the totals of a set of donations. Amounts are decimals with two places.
+type Metrics struct {
+	Amount       json.Number `json:"amount"`
+	Count        int64       `json:"count"`
+	UniqueDonors int64       `json:"unique_donors"`
+	AverageGift  json.Number `json:"average_gift"`
+}
+
+var zeroMetrics = Metrics{Amount: "0.00", AverageGift: "0.00"}
+
+// Change is each metric's relative change from the previous period: 0.25 is a rise of 25%. A metric
+// whose previous value was zero has no change.
+type Change struct {
+	Amount       *float64 `json:"amount"`
+	Count        *float64 `json:"count"`
+	UniqueDonors *float64 `json:"unique_donors"`
+	AverageGift  *float64 `json:"average_gift"`
+}
+
+// Point is one period of a series, starting at local midnight in the query's time zone
+type Point struct {
+	Start time.Time `json:"start"`
+	Metrics
+}
+
+// Series is one group's metrics per period, with a point for every period in the range
+type Series struct {
+	Key      string   `json:"key"`
+	Label    string   `json:"label,omitempty"`
+	Points   []Point  `json:"points"`
+	Total    Metrics  `json:"total"`
+	Previous *Metrics `json:"previous,omitempty"`
+	Change   *Change  `json:"change,omitempty"`
+}
+
+// Result answers a Spec. Series are ordered by total amount, largest first.
+type Result struct {
+	Interval     string `json:"interval"`
+	GroupBy      string `json:"group_by,omitempty"`
+	TimeZone     string `json:"time_zone"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	PreviousFrom string `json:"previous_from,omitempty"`
+	PreviousTo   string `json:"previous_to,omitempty"`
+	// Refreshing means donations in the range changed recently and are still being rolled up
+	Refreshing bool     `json:"refreshing"`
+	Total      Metrics  `json:"total"`
+	Previous   *Metrics `json:"previous,omitempty"`
+	Change     *Change  `json:"change,omitempty"`
+	Series     []Series `json:"series"`
+}
+
+// Query runs a validated spec against the rollups
+func Query(ctx context.Context, q db.Querier, spec Spec) (Result, error) {
+	from, end := spec.From, spec.end()
+	res := Result{
+		Interval: spec.Interval,
+		GroupBy:  spec.GroupBy,
+		TimeZone: spec.TimeZone,
+		From:     spec.From.Format(dateLayout),
+		To:       spec.To.Format(dateLayout),
+		Total:    zeroMetrics,
+		Series:   []Series{},
+	}
+
+	rows, err := q.DonationAnalytics(ctx, spec.params(true, spec.GroupBy, from, end))
+	if err != nil {
+		return Result{}, err
+	}
+	overall, err := totals(ctx, q, spec, GroupNone, from, end)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Total = overall[""]
+	groups := overall
+	if spec.GroupBy != GroupNone {
+		if groups, err = totals(ctx, q, spec, spec.GroupBy, from, end); err != nil {
+			return Result{}, err
+		}
+	}
+
+	var previous map[string]Metrics
+	if spec.Compare {
+		prevFrom, prevEnd := spec.previous()
+		res.PreviousFrom = prevFrom.Format(dateLayout)
+		res.PreviousTo = prevEnd.AddDate(0, 0, -1).Format(dateLayout)
+		prevOverall, err := totals(ctx, q, spec, GroupNone, prevFrom, prevEnd)
+		if err != nil {
+			return Result{}, err
+		}
+		res.Previous, res.Change = compare(res.Total, prevOverall[""])
+		previous = prevOverall
+		if spec.GroupBy != GroupNone {
+			if previous, err = totals(ctx, q, spec, spec.GroupBy, prevFrom, prevEnd); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+
+	dirty, err := q.CountDirtyRollupBuckets(ctx, db.CountDirtyRollupBucketsParams{BucketFrom: from.UTC(), BucketTo: end.UTC()})
+	if err != nil {
+		return Result{}, err
+	}
+	res.Refreshing = dirty > 0
+
+	// One series per group, with every period filled in
+	starts := periods(spec.Interval, from, end)
+	index := map[string]int{}
+	for _, row := range rows {
+		i, ok := index[row.GroupKey]
+		if !ok {
+			i = len(res.Series)
+			index[row.GroupKey] = i
+			series := Series{Key: row.GroupKey, Label: row.GroupLabel, Points: make([]Point, len(starts)), Total: groups[row.GroupKey]}
+			for p, start := range starts {
+				series.Points[p] = Point{Start: start, Metrics: zeroMetrics}
+			}
+			if spec.Compare {
+				series.Previous, series.Change = compare(series.Total, previous[row.GroupKey])
+			}
+			res.Series = append(res.Series, series)
+		}
+		start := local(row.PeriodStart, spec.location)
+		for p := range starts {
+			if starts[p].Equal(start) {
+				res.Series[i].Points[p].Metrics = metrics(row)
+				break
+			}
+		}
+	}
+	sort.SliceStable(res.Series, func(i, j int) bool {
+		return number(res.Series[i].Total.Amount) > number(res.Series[j].Total.Amount)
+	})
+	return res, nil
+}
+
+func (s Spec) params(bucketed bool, groupBy string, from, end time.Time) db.DonationAnalyticsParams {
+	return db.DonationAnalyticsParams{
+		Bucketed:     bucketed,
+		Period:       s.Interval,
+		TimeZone:     s.TimeZone,
+		GroupBy:      groupBy,
+		BucketFrom:   from.UTC(),
+		BucketTo:     end.UTC(),
+		CauseID:      nullInt32(s.CauseID),
+		TeamID:       nullInt32(s.TeamID),
+		DonationType: nullString(s.DonationType),
+	}
+}
+
+// totals sums a range per group, without periods
+func totals(ctx context.Context, q db.Querier, spec Spec, groupBy string, from, end time.Time) (map[string]Metrics, error) {
+	rows, err := q.DonationAnalytics(ctx, spec.params(false, groupBy, from, end))
+	if err != nil {
+		return nil, err
+	}
+	byGroup := map[string]Metrics{"": zeroMetrics}
+	for _, row := range rows {
+		byGroup[row.GroupKey] = metrics(row)
+	}
+	return byGroup, nil
+}
+
+func metrics(row db.DonationAnalyticsRow) Metrics {
+	return Metrics{
+		Amount:       json.Number(row.AmountSum),
+		Count:        row.DonationCount,
+		UniqueDonors: row.UniqueDonors,
+		AverageGift:  json.Number(row.AverageGift),
+	}
+}
+
+// compare returns the previous totals, zero when the group had none, and the change from them
+func compare(current, previous Metrics) (*Metrics, *Change) {
+	if previous.Amount == "" {
+		previous = zeroMetrics
+	}
+	return &previous, &Change{
+		Amount:       change(number(current.Amount), number(previous.Amount)),
+		Count:        change(float64(current.Count), float64(previous.Count)),
+		UniqueDonors: change(float64(current.UniqueDonors), float64(previous.UniqueDonors)),
+		AverageGift:  change(number(current.AverageGift), number(previous.AverageGift)),
+	}
+}
+
+func change(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	c := math.Round((current-previous)/previous*10000) / 10000
+	return &c
+}
+
+func number(n json.Number) float64 {
+	f, _ := strconv.ParseFloat(string(n), 64)
+	return f
+}
+
+// periods lists the start of every period that overlaps [from, end)
+func periods(interval string, from, end time.Time) []time.Time {
+	var starts []time.Time
+	for start := periodStart(interval, from); start.Before(end) && len(starts) <= maxPoints; start = nextPeriod(interval, start) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+func periodStart(interval string, t time.Time) time.Time {
+	t = day(t, t.Location())
+	switch interval {
+	case IntervalWeek:
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case IntervalMonth:
+		return t.AddDate(0, 0, 1-t.Day())
+	}
+	return t
+}
+
+func nextPeriod(interval string, t time.Time) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// day is local midnight in loc on t's calendar day
+func day(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// local reads a timestamp without time zone from Postgres as a wall clock time in loc
+func local(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+func nullInt32(n int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: n, Valid: n != 0}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,167 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+)
+
+func date(s string, loc *time.Location) time.Time {
+	t, err := time.ParseInLocation(dateLayout, s, loc)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestValidate(t *testing.T) {
+	spec := Spec{}
+	if err := spec.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Interval != IntervalDay || spec.TimeZone != "UTC" || spec.To.Sub(spec.From) != (defaultDays-1)*24*time.Hour {
+		t.Errorf("defaults = %s in %s from %s to %s", spec.Interval, spec.TimeZone, spec.From, spec.To)
+	}
+
+	invalid := map[string]Spec{
+		"interval": {Interval: "hour"},
+		"group_by": {GroupBy: "donor"},
+		"tz":       {TimeZone: "Mars/Olympus_Mons"},
+		"to":       {From: date("2026-03-02", time.UTC), To: date("2026-03-01", time.UTC)},
+		"from":     {From: date("2020-01-01", time.UTC), To: date("2026-01-01", time.UTC)},
+	}
+	for field, spec := range invalid {
+		var p *problem.Problem
+		if err := spec.Validate(); !errors.As(err, &p) || len(p.Errors) != 1 || p.Errors[0].Field != field {
+			t.Errorf("Validate(%+v) = %v, want an error on %s", spec, err, field)
+		}
+	}
+
+	// Six years is too many days but fine by month
+	long := Spec{Interval: IntervalMonth, From: date("2020-01-01", time.UTC), To: date("2026-01-01", time.UTC)}
+	if err := long.Validate(); err != nil {
+		t.Errorf("monthly range: %v", err)
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		interval, from, end string
+		want                []string
+	}{
+		{IntervalDay, "2026-03-28", "2026-03-31", []string{"2026-03-28", "2026-03-29", "2026-03-30"}},
+		{IntervalWeek, "2026-03-25", "2026-04-07", []string{"2026-03-23", "2026-03-30", "2026-04-06"}},
+		{IntervalMonth, "2026-01-15", "2026-03-02", []string{"2026-01-01", "2026-02-01", "2026-03-01"}},
+	}
+	for _, tt := range tests {
+		got := periods(tt.interval, date(tt.from, berlin), date(tt.end, berlin))
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s periods = %v, want %v", tt.interval, got, tt.want)
+		}
+		for i, start := range got {
+			// Periods start at local midnight, across the change to summer time too
+			if !start.Equal(date(tt.want[i], berlin)) || start.Hour() != 0 {
+				t.Errorf("%s period %d = %s, want %s", tt.interval, i, start, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	spec := Spec{From: date("2026-03-01", time.UTC), To: date("2026-03-31", time.UTC)}
+	from, end := spec.previous()
+	if !from.Equal(date("2026-01-29", time.UTC)) || !end.Equal(spec.From) {
+		t.Errorf("previous = %s to %s", from, end)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	previous, change := compare(
+		Metrics{Amount: "150.00", Count: 3, UniqueDonors: 2, AverageGift: "50.00"},
+		Metrics{Amount: "100.00", Count: 4, AverageGift: "25.00"},
+	)
+	if previous.Amount != "100.00" {
+		t.Errorf("previous = %+v", previous)
+	}
+	if *change.Amount != 0.5 || *change.Count != -0.25 || change.UniqueDonors != nil || *change.AverageGift != 1 {
+		t.Errorf("change = %+v", change)
+	}
+
+	// A group with no donations before compares against zero
+	previous, change = compare(Metrics{Amount: "10.00", Count: 1}, Metrics{})
+	if *previous != zeroMetrics || change.Amount != nil {
+		t.Errorf("compare with nothing = %+v %+v", previous, change)
+	}
+}
+
+// fakeQuerier answers analytics queries with canned rows
+type fakeQuerier struct {
+	db.Querier
+	bucketed, totals map[string][]db.DonationAnalyticsRow
+	dirty            int64
+}
+
+func (f *fakeQuerier) DonationAnalytics(_ context.Context, arg db.DonationAnalyticsParams) ([]db.DonationAnalyticsRow, error) {
+	if arg.Bucketed {
+		return f.bucketed[arg.GroupBy], nil
+	}
+	return f.totals[arg.GroupBy], nil
+}
+
+func (f *fakeQuerier) CountDirtyRollupBuckets(context.Context, db.CountDirtyRollupBucketsParams) (int64, error) {
+	return f.dirty, nil
+}
+
+func TestQuery(t *testing.T) {
+	row := func(day, key, amount string, count int64) db.DonationAnalyticsRow {
+		start := time.Time{}
+		if day != "" {
+			// Postgres returns local wall clock times without a zone
+			start = date(day, time.UTC)
+		}
+		return db.DonationAnalyticsRow{PeriodStart: start, GroupKey: key, GroupLabel: "Cause " + key, DonationCount: count, AmountSum: amount, AverageGift: amount, UniqueDonors: count}
+	}
+	q := &fakeQuerier{
+		bucketed: map[string][]db.DonationAnalyticsRow{
+			GroupCause: {row("2026-03-02", "1", "10.00", 1), row("2026-03-01", "2", "40.00", 1)},
+		},
+		totals: map[string][]db.DonationAnalyticsRow{
+			GroupNone:  {row("", "", "50.00", 2)},
+			GroupCause: {row("", "1", "10.00", 1), row("", "2", "40.00", 1)},
+		},
+		dirty: 1,
+	}
+	spec := Spec{GroupBy: GroupCause, TimeZone: "America/New_York", From: date("2026-03-01", time.UTC), To: date("2026-03-03", time.UTC)}
+	if err := spec.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Query(context.Background(), q, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Total.Amount != "50.00" || !res.Refreshing || res.Previous != nil {
+		t.Errorf("result = %+v", res)
+	}
+	if len(res.Series) != 2 || res.Series[0].Key != "2" || res.Series[1].Key != "1" {
+		t.Fatalf("series = %+v, want the largest cause first", res.Series)
+	}
+	points := res.Series[1].Points
+	if len(points) != 3 {
+		t.Fatalf("points = %+v, want one per day", points)
+	}
+	if points[0].Amount != "0.00" || points[1].Amount != "10.00" || points[2].Amount != "0.00" {
+		t.Errorf("points = %+v, want a gift on the second day only", points)
+	}
+	if points[1].Start.Location().String() != "America/New_York" || points[1].Start.Day() != 2 {
+		t.Errorf("point starts at %s", points[1].Start)
+	}
+}
@@ -0,0 +1,637 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+)
+
+// Donor cohort reports group donors by the calendar month of their first donation. They read
+// donations directly rather than the rollups, which do not keep per-donor totals.
+
+// Segments of the recurring donor report and the team retention report
+const (
+	SegmentOneOff    = "one_off"
+	SegmentRepeat    = "repeat"
+	SegmentRecurring = "recurring"
+	SegmentTeam      = "team"
+	SegmentNoTeam    = "no_team"
+)
+
+const (
+	defaultCohorts         = 12
+	maxCohorts             = 120
+	defaultRetentionMonths = 12
+	defaultLapsedDays      = 365
+	defaultRecurringMonths = 3
+	monthLayout            = "2006-01"
+)
+
+// CohortSpec describes a donor cohort report. From and To are the first and last cohort months, in
+// TimeZone. Months is how many months of retention to report after each cohort's first month.
+// LapsedDays is how many days after their last donation a donor counts as lapsed. RecurringMonths is
+// how many different months a donor must have given in to count as recurring.
+type CohortSpec struct {
+	From            time.Time
+	To              time.Time
+	TimeZone        string
+	Months          int
+	LapsedDays      int
+	RecurringMonths int
+
+	location *time.Location
+	now      time.Time
+}
+
+// Validate checks the time zone and months and fills in the defaults: the last 12 cohorts in UTC,
+// 12 months of retention, lapsed after a year and recurring from 3 months
+func (s *CohortSpec) Validate() error {
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil || s.TimeZone == "Local" {
+		return problem.Invalid("tz", "timezone", "must be an IANA time zone such as Europe/Berlin")
+	}
+	s.location = loc
+	if s.now.IsZero() {
+		s.now = time.Now()
+	}
+	if s.Months == 0 {
+		s.Months = defaultRetentionMonths
+	}
+	if s.LapsedDays == 0 {
+		s.LapsedDays = defaultLapsedDays
+	}
+	if s.RecurringMonths == 0 {
+		s.RecurringMonths = defaultRecurringMonths
+	}
+
+	if s.To.IsZero() {
+		s.To = s.now.In(loc)
+	}
+	s.To = month(s.To)
+	if s.From.IsZero() {
+		s.From = s.To.AddDate(0, -(defaultCohorts - 1), 0)
+	}
+	s.From = month(s.From)
+	if s.To.Before(s.From) {
+		return problem.Invalid("to", "gtefield", "must not be before from")
+	}
+	if months(s.From, s.To) >= maxCohorts {
+		return problem.Invalid("from", "max", fmt.Sprintf("the range covers more than %d cohorts", maxCohorts))
+	}
+	return nil
+}
+
+// current is this month in the spec's time zone
+func (s CohortSpec) current() time.Time {
+	return month(s.now.In(s.location))
+}
+
+// cohortRange is the half-open range of cohort months, as the wall clock times the queries compare
+func (s CohortSpec) cohortRange() (time.Time, time.Time) {
+	return s.From, s.To.AddDate(0, 1, 0)
+}
+
+func (s CohortSpec) summaries(ctx context.Context, q db.Querier, allCohorts bool) ([]db.ListDonorSummariesRow, error) {
+	arg := db.ListDonorSummariesParams{TimeZone: s.TimeZone}
+	if !allCohorts {
+		from, to := s.cohortRange()
+		arg.CohortFrom = sql.NullTime{Time: from, Valid: true}
+		arg.CohortTo = sql.NullTime{Time: to, Valid: true}
+	}
+	return q.ListDonorSummaries(ctx, arg)
+}
+
+// Table is a report that can be downloaded as CSV
+type Table interface {
+	Columns() []string
+	Rows() [][]string
+}
+
+// WriteCSV writes a report as CSV with a header row
+func WriteCSV(w io.Writer, t Table) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(t.Columns()); err != nil {
+		return err
+	}
+	if err := out.WriteAll(t.Rows()); err != nil {
+		return err
+	}
+	return out.Error()
+}
+
+// Retention is how many of a cohort's donors gave in a month after their first. Month 0 is the
+// cohort's first month, when every donor gave.
+type Retention struct {
+	Month     int         `json:"month"`
+	Donors    int64       `json:"donors"`
+	Rate      float64     `json:"rate"`
+	Donations int64       `json:"donations"`
+	Amount    json.Number `json:"amount"`
+}
+
+// Cohort is the donors who first gave in a month and how many came back in each month since, up to
+// this month
+type Cohort struct {
+	Month     string      `json:"month"`
+	Donors    int64       `json:"donors"`
+	Retention []Retention `json:"retention"`
+}
+
+// CohortReport is month-over-month retention per first-donation cohort
+type CohortReport struct {
+	TimeZone string   `json:"time_zone"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Months   int      `json:"months"`
+	Cohorts  []Cohort `json:"cohorts"`
+}
+
+// Cohorts reports month-over-month retention for each first-donation cohort in the range. Cohorts
+// without donors are left out.
+func Cohorts(ctx context.Context, q db.Querier, spec CohortSpec) (CohortReport, error) {
+	rows, err := cohortRows(ctx, q, spec)
+	if err != nil {
+		return CohortReport{}, err
+	}
+	res := CohortReport{TimeZone: spec.TimeZone, From: spec.From.Format(monthLayout), To: spec.To.Format(monthLayout), Months: spec.Months, Cohorts: []Cohort{}}
+	current := spec.current()
+	for _, group := range groupCohorts(rows) {
+		start := month(group[0].Cohort)
+		elapsed := min(months(start, current), spec.Months)
+		cohort := Cohort{Month: start.Format(monthLayout), Retention: make([]Retention, elapsed+1)}
+		for i := range cohort.Retention {
+			cohort.Retention[i] = Retention{Month: i, Amount: "0.00"}
+		}
+		for _, row := range group {
+			if row.MonthOffset == 0 {
+				cohort.Donors = row.Donors
+			}
+			if int(row.MonthOffset) <= elapsed {
+				cohort.Retention[row.MonthOffset] = Retention{Month: int(row.MonthOffset), Donors: row.Donors, Donations: row.DonationCount, Amount: json.Number(row.AmountSum)}
+			}
+		}
+		for i := range cohort.Retention {
+			cohort.Retention[i].Rate = rate(cohort.Retention[i].Donors, cohort.Donors)
+		}
+		res.Cohorts = append(res.Cohorts, cohort)
+	}
+	return res, nil
+}
+
+func (r CohortReport) Columns() []string {
+	return []string{"cohort", "cohort_donors", "month", "donors", "retention_rate", "donations", "amount"}
+}
+
+func (r CohortReport) Rows() [][]string {
+	var rows [][]string
+	for _, cohort := range r.Cohorts {
+		for _, ret := range cohort.Retention {
+			rows = append(rows, []string{cohort.Month, itoa(cohort.Donors), strconv.Itoa(ret.Month), itoa(ret.Donors), ftoa(ret.Rate), itoa(ret.Donations), string(ret.Amount)})
+		}
+	}
+	return rows
+}
+
+// CohortValue is what a cohort's donors have given so far. Younger cohorts have had less time to
+// give, so their lifetime value is lower.
+type CohortValue struct {
+	Month         string      `json:"month,omitempty"`
+	Donors        int64       `json:"donors"`
+	Donations     int64       `json:"donations"`
+	Amount        json.Number `json:"amount"`
+	LifetimeValue json.Number `json:"lifetime_value"`
+	AverageGift   json.Number `json:"average_gift"`
+}
+
+// LifetimeValueReport is the average amount given per donor, per first-donation cohort and overall
+type LifetimeValueReport struct {
+	TimeZone string        `json:"time_zone"`
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Total    CohortValue   `json:"total"`
+	Cohorts  []CohortValue `json:"cohorts"`
+}
+
+// LifetimeValue reports donor lifetime value for each first-donation cohort in the range
+func LifetimeValue(ctx context.Context, q db.Querier, spec CohortSpec) (LifetimeValueReport, error) {
+	rows, err := cohortRows(ctx, q, spec)
+	if err != nil {
+		return LifetimeValueReport{}, err
+	}
+	res := LifetimeValueReport{TimeZone: spec.TimeZone, From: spec.From.Format(monthLayout), To: spec.To.Format(monthLayout), Cohorts: []CohortValue{}}
+	var donors, donations, amount int64
+	for _, group := range groupCohorts(rows) {
+		var cohortDonors, cohortDonations, cohortAmount int64
+		for _, row := range group {
+			if row.MonthOffset == 0 {
+				cohortDonors = row.Donors
+			}
+			cohortDonations += row.DonationCount
+			cohortAmount += cents(row.AmountSum)
+		}
+		res.Cohorts = append(res.Cohorts, value(month(group[0].Cohort).Format(monthLayout), cohortDonors, cohortDonations, cohortAmount))
+		donors += cohortDonors
+		donations += cohortDonations
+		amount += cohortAmount
+	}
+	res.Total = value("", donors, donations, amount)
+	return res, nil
+}
+
+func (r LifetimeValueReport) Columns() []string {
+	return []string{"cohort", "donors", "donations", "amount", "lifetime_value", "average_gift"}
+}
+
+func (r LifetimeValueReport) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Cohorts)+1)
+	for _, v := range r.Cohorts {
+		rows = append(rows, valueRow(v.Month, v))
+	}
+	return append(rows, valueRow("total", r.Total))
+}
+
+func valueRow(cohort string, v CohortValue) []string {
+	return []string{cohort, itoa(v.Donors), itoa(v.Donations), string(v.Amount), string(v.LifetimeValue), string(v.AverageGift)}
+}
+
+func value(cohort string, donors, donations, amount int64) CohortValue {
+	return CohortValue{
+		Month:         cohort,
+		Donors:        donors,
+		Donations:     donations,
+		Amount:        formatCents(amount),
+		LifetimeValue: formatCents(divide(amount, donors)),
+		AverageGift:   formatCents(divide(amount, donations)),
+	}
+}
+
+// LapsedDonor is a donor who has not given for at least the lapsed period
+type LapsedDonor struct {
+	UserID        int32       `json:"user_id"`
+	Username      string      `json:"username"`
+	Email         string      `json:"email"`
+	FirstDonation time.Time   `json:"first_donation"`
+	LastDonation  time.Time   `json:"last_donation"`
+	Donations     int64       `json:"donations"`
+	Amount        json.Number `json:"amount"`
+	DaysLapsed    int         `json:"days_lapsed"`
+}
+
+// LapsedReport lists lapsed donors, largest lifetime giving first. Deleted and anonymized accounts
+// cannot be contacted and are left out.
+type LapsedReport struct {
+	AsOf       time.Time     `json:"as_of"`
+	LapsedDays int           `json:"lapsed_days"`
+	Donors     int64         `json:"donors"`
+	Lapsed     int64         `json:"lapsed"`
+	Rate       float64       `json:"rate"`
+	LapsedList []LapsedDonor `json:"lapsed_donors"`
+}
+
+// Lapsed finds donors, from every cohort, whose last donation is older than the lapsed period
+func Lapsed(ctx context.Context, q db.Querier, spec CohortSpec) (LapsedReport, error) {
+	rows, err := spec.summaries(ctx, q, true)
+	if err != nil {
+		return LapsedReport{}, err
+	}
+	res := LapsedReport{AsOf: spec.now.UTC(), LapsedDays: spec.LapsedDays, LapsedList: []LapsedDonor{}}
+	cutoff := spec.now.UTC().AddDate(0, 0, -spec.LapsedDays)
+	for _, row := range rows {
+		if !row.Active {
+			continue
+		}
+		res.Donors++
+		if !row.LastDonation.Before(cutoff) {
+			continue
+		}
+		res.LapsedList = append(res.LapsedList, LapsedDonor{
+			UserID:        row.UserID,
+			Username:      row.Username,
+			Email:         row.Email,
+			FirstDonation: row.FirstDonation,
+			LastDonation:  row.LastDonation,
+			Donations:     row.DonationCount,
+			Amount:        json.Number(row.AmountSum),
+			DaysLapsed:    int(spec.now.UTC().Sub(row.LastDonation).Hours() / 24),
+		})
+	}
+	res.Lapsed = int64(len(res.LapsedList))
+	res.Rate = rate(res.Lapsed, res.Donors)
+	sort.SliceStable(res.LapsedList, func(i, j int) bool {
+		return cents(string(res.LapsedList[i].Amount)) > cents(string(res.LapsedList[j].Amount))
+	})
+	return res, nil
+}
+
+func (r LapsedReport) Columns() []string {
+	return []string{"user_id", "username", "email", "first_donation", "last_donation", "donations", "amount", "days_lapsed"}
+}
+
+func (r LapsedReport) Rows() [][]string {
+	rows := make([][]string, len(r.LapsedList))
+	for i, d := range r.LapsedList {
+		rows[i] = []string{strconv.Itoa(int(d.UserID)), d.Username, d.Email, d.FirstDonation.Format(time.RFC3339), d.LastDonation.Format(time.RFC3339), itoa(d.Donations), string(d.Amount), strconv.Itoa(d.DaysLapsed)}
+	}
+	return rows
+}
+
+// Segment totals one group of donors
+type Segment struct {
+	Segment       string      `json:"segment"`
+	Donors        int64       `json:"donors"`
+	Donations     int64       `json:"donations"`
+	Amount        json.Number `json:"amount"`
+	DonorShare    float64     `json:"donor_share"`
+	AmountShare   float64     `json:"amount_share"`
+	LifetimeValue json.Number `json:"lifetime_value"`
+	AverageGift   json.Number `json:"average_gift"`
+}
+
+// RecurringReport splits the cohorts' donors into one-off donors, who gave once, recurring donors,
+// who gave in at least RecurringMonths different months, and repeat donors in between
+type RecurringReport struct {
+	TimeZone        string    `json:"time_zone"`
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	RecurringMonths int       `json:"recurring_months"`
+	Segments        []Segment `json:"segments"`
+}
+
+// Recurring reports the recurring versus one-off split of the donors in the cohort range
+func Recurring(ctx context.Context, q db.Querier, spec CohortSpec) (RecurringReport, error) {
+	rows, err := spec.summaries(ctx, q, false)
+	if err != nil {
+		return RecurringReport{}, err
+	}
+	segments := newSegments(SegmentOneOff, SegmentRepeat, SegmentRecurring)
+	for _, row := range rows {
+		switch {
+		case row.DonationCount == 1:
+			segments.add(SegmentOneOff, row)
+		case row.ActiveMonths >= int64(spec.RecurringMonths):
+			segments.add(SegmentRecurring, row)
+		default:
+			segments.add(SegmentRepeat, row)
+		}
+	}
+	return RecurringReport{
+		TimeZone:        spec.TimeZone,
+		From:            spec.From.Format(monthLayout),
+		To:              spec.To.Format(monthLayout),
+		RecurringMonths: spec.RecurringMonths,
+		Segments:        segments.list(),
+	}, nil
+}
+
+func (r RecurringReport) Columns() []string {
+	return segmentColumns
+}
+
+func (r RecurringReport) Rows() [][]string {
+	return segmentRows(r.Segments)
+}
+
+// TeamSegment is how often the donors in or out of a team came back
+type TeamSegment struct {
+	Segment
+	RepeatDonors int64 `json:"repeat_donors"`
+	// RepeatRate is the share of donors who gave in more than one month
+	RepeatRate          float64 `json:"repeat_rate"`
+	AverageActiveMonths float64 `json:"average_active_months"`
+}
+
+// TeamJoinImpact compares how often donors who joined a team after their first donation gave before
+// and after joining. An active rate is the share of months with at least one donation.
+type TeamJoinImpact struct {
+	Donors           int64   `json:"donors"`
+	ActiveRateBefore float64 `json:"active_rate_before"`
+	ActiveRateAfter  float64 `json:"active_rate_after"`
+	// Retained is how many gave again after joining
+	Retained     int64   `json:"retained"`
+	RetainedRate float64 `json:"retained_rate"`
+}
+
+// TeamRetentionReport compares the retention of donors in teams with those who are not, and of
+// donors before and after they joined a team
+type TeamRetentionReport struct {
+	TimeZone             string         `json:"time_zone"`
+	From                 string         `json:"from"`
+	To                   string         `json:"to"`
+	Segments             []TeamSegment  `json:"segments"`
+	JoinedAfterFirstGift TeamJoinImpact `json:"joined_after_first_gift"`
+}
+
+// TeamRetention reports the impact of joining a team on the retention of the donors in the cohort
+// range. Membership is current membership; donors who left every team count as not in one.
+func TeamRetention(ctx context.Context, q db.Querier, spec CohortSpec) (TeamRetentionReport, error) {
+	rows, err := spec.summaries(ctx, q, false)
+	if err != nil {
+		return TeamRetentionReport{}, err
+	}
+	segments := newSegments(SegmentTeam, SegmentNoTeam)
+	repeat := map[string]int64{}
+	activeMonths := map[string]int64{}
+	var impact TeamJoinImpact
+	var monthsBefore, activeBefore, monthsAfter, activeAfter int64
+	current := spec.current()
+	for _, row := range rows {
+		segment := SegmentNoTeam
+		if row.InTeam {
+			segment = SegmentTeam
+		}
+		segments.add(segment, row)
+		activeMonths[segment] += row.ActiveMonths
+		if row.ActiveMonths > 1 {
+			repeat[segment]++
+		}
+
+		// Only donors with at least a month of giving before they joined can be compared
+		joined := month(row.JoinedTeamAt.In(spec.location))
+		if !row.InTeam || !month(row.Cohort).Before(joined) {
+			continue
+		}
+		impact.Donors++
+		monthsBefore += int64(months(month(row.Cohort), joined))
+		activeBefore += row.ActiveMonthsBeforeJoin
+		monthsAfter += int64(months(joined, current) + 1)
+		activeAfter += row.ActiveMonthsAfterJoin
+		if row.ActiveMonthsAfterJoin > 0 {
+			impact.Retained++
+		}
+	}
+	impact.ActiveRateBefore = rate(activeBefore, monthsBefore)
+	impact.ActiveRateAfter = rate(activeAfter, monthsAfter)
+	impact.RetainedRate = rate(impact.Retained, impact.Donors)
+
+	res := TeamRetentionReport{
+		TimeZone:             spec.TimeZone,
+		From:                 spec.From.Format(monthLayout),
+		To:                   spec.To.Format(monthLayout),
+		JoinedAfterFirstGift: impact,
+	}
+	for _, s := range segments.list() {
+		res.Segments = append(res.Segments, TeamSegment{
+			Segment:             s,
+			RepeatDonors:        repeat[s.Segment],
+			RepeatRate:          rate(repeat[s.Segment], s.Donors),
+			AverageActiveMonths: math.Round(float64(activeMonths[s.Segment])/math.Max(float64(s.Donors), 1)*100) / 100,
+		})
+	}
+	return res, nil
+}
+
+func (r TeamRetentionReport) Columns() []string {
+	return append(append([]string{}, segmentColumns...), "repeat_donors", "repeat_rate", "average_active_months")
+}
+
+func (r TeamRetentionReport) Rows() [][]string {
+	rows := make([][]string, len(r.Segments))
+	for i, s := range r.Segments {
+		rows[i] = append(segmentRows([]Segment{s.Segment})[0], itoa(s.RepeatDonors), ftoa(s.RepeatRate), ftoa(s.AverageActiveMonths))
+	}
+	return rows
+}
+
+var segmentColumns = []string{"segment", "donors", "donations", "amount", "donor_share", "amount_share", "lifetime_value", "average_gift"}
+
+func segmentRows(segments []Segment) [][]string {
+	rows := make([][]string, len(segments))
+	for i, s := range segments {
+		rows[i] = []string{s.Segment, itoa(s.Donors), itoa(s.Donations), string(s.Amount), ftoa(s.DonorShare), ftoa(s.AmountShare), string(s.LifetimeValue), string(s.AverageGift)}
+	}
+	return rows
+}
+
+// segments adds up donors into named segments, kept in the order they are named
+type segments struct {
+	names  []string
+	totals map[string]*segmentTotal
+}
+
+type segmentTotal struct {
+	donors, donations, amount int64
+}
+
+func newSegments(names ...string) *segments {
+	s := &segments{names: names, totals: map[string]*segmentTotal{}}
+	for _, name := range names {
+		s.totals[name] = &segmentTotal{}
+	}
+	return s
+}
+
+func (s *segments) add(name string, row db.ListDonorSummariesRow) {
+	t := s.totals[name]
+	t.donors++
+	t.donations += row.DonationCount
+	t.amount += cents(row.AmountSum)
+}
+
+func (s *segments) list() []Segment {
+	var donors, amount int64
+	for _, t := range s.totals {
+		donors += t.donors
+		amount += t.amount
+	}
+	res := make([]Segment, len(s.names))
+	for i, name := range s.names {
+		t := s.totals[name]
+		res[i] = Segment{
+			Segment:       name,
+			Donors:        t.donors,
+			Donations:     t.donations,
+			Amount:        formatCents(t.amount),
+			DonorShare:    rate(t.donors, donors),
+			AmountShare:   rate(t.amount, amount),
+			LifetimeValue: formatCents(divide(t.amount, t.donors)),
+			AverageGift:   formatCents(divide(t.amount, t.donations)),
+		}
+	}
+	return res
+}
+
+func cohortRows(ctx context.Context, q db.Querier, spec CohortSpec) ([]db.DonorCohortsRow, error) {
+	from, to := spec.cohortRange()
+	return q.DonorCohorts(ctx, db.DonorCohortsParams{CohortFrom: from, CohortTo: to, TimeZone: spec.TimeZone})
+}
+
+// groupCohorts splits rows ordered by cohort into one slice per cohort
+func groupCohorts(rows []db.DonorCohortsRow) [][]db.DonorCohortsRow {
+	var groups [][]db.DonorCohortsRow
+	for i, row := range rows {
+		if i == 0 || !row.Cohort.Equal(rows[i-1].Cohort) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], row)
+	}
+	return groups
+}
+
+// month is the first of t's month as a wall clock time in UTC, the way the queries compare months
+func month(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// months counts the whole months from one month to another
+func months(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// rate is part of a whole rounded to four places, or 0 of nothing
+func rate(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 10000
+}
+
+// divide rounds cents divided by n to the nearest cent, or 0 when n is 0
+func divide(c, n int64) int64 {
+	if n == 0 {
+		return 0
+	}
+	return int64(math.Round(float64(c) / float64(n)))
+}
+
+// cents parses a decimal amount with at most two places
+func cents(amount string) int64 {
+	negative := strings.HasPrefix(amount, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	frac = (frac + "00")[:2]
+	n, _ := strconv.ParseInt(whole, 10, 64)
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	if negative {
+		return -(n*100 + f)
+	}
+	return n*100 + f
+}
+
+func formatCents(c int64) json.Number {
+	sign := ""
+	if c < 0 {
+		sign, c = "-", -c
+	}
+	return json.Number(fmt.Sprintf("%s%d.%02d", sign, c/100, c%100))
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func ftoa(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,175 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+)
+
+// fakeDonors answers the donor queries with canned rows
+type fakeDonors struct {
+	db.Querier
+	cohorts   []db.DonorCohortsRow
+	summaries []db.ListDonorSummariesRow
+}
+
+func (f *fakeDonors) DonorCohorts(context.Context, db.DonorCohortsParams) ([]db.DonorCohortsRow, error) {
+	return f.cohorts, nil
+}
+
+func (f *fakeDonors) ListDonorSummaries(context.Context, db.ListDonorSummariesParams) ([]db.ListDonorSummariesRow, error) {
+	return f.summaries, nil
+}
+
+func testCohortSpec(t *testing.T) CohortSpec {
+	t.Helper()
+	spec := CohortSpec{now: time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC), From: date("2026-01-01", time.UTC)}
+	if err := spec.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestCohortSpecValidate(t *testing.T) {
+	spec := CohortSpec{now: time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC), TimeZone: "Pacific/Kiritimati"}
+	if err := spec.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// It is already the next day in Kiritimati, but still April
+	if spec.From.Format(monthLayout) != "2025-05" || spec.To.Format(monthLayout) != "2026-04" {
+		t.Errorf("default cohorts = %s to %s", spec.From, spec.To)
+	}
+	if spec.Months != defaultRetentionMonths || spec.LapsedDays != defaultLapsedDays || spec.RecurringMonths != defaultRecurringMonths {
+		t.Errorf("defaults = %+v", spec)
+	}
+
+	for _, spec := range []CohortSpec{
+		{TimeZone: "Nowhere/Special"},
+		{From: date("2026-05-01", time.UTC), To: date("2026-04-01", time.UTC)},
+		{From: date("2000-01-01", time.UTC), To: date("2026-04-01", time.UTC)},
+	} {
+		if err := spec.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded", spec)
+		}
+	}
+}
+
+func TestCohorts(t *testing.T) {
+	january, march := date("2026-01-01", time.UTC), date("2026-03-01", time.UTC)
+	q := &fakeDonors{cohorts: []db.DonorCohortsRow{
+		{Cohort: january, MonthOffset: 0, Donors: 4, DonationCount: 5, AmountSum: "100.00"},
+		{Cohort: january, MonthOffset: 2, Donors: 1, DonationCount: 1, AmountSum: "10.50"},
+		{Cohort: march, MonthOffset: 0, Donors: 2, DonationCount: 2, AmountSum: "30.00"},
+		{Cohort: march, MonthOffset: 1, Donors: 1, DonationCount: 2, AmountSum: "20.00"},
+	}}
+	spec := testCohortSpec(t)
+
+	res, err := Cohorts(context.Background(), q, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Cohorts) != 2 {
+		t.Fatalf("cohorts = %+v", res.Cohorts)
+	}
+	// January has had three months since its first, March one
+	jan := res.Cohorts[0]
+	if jan.Month != "2026-01" || jan.Donors != 4 || len(jan.Retention) != 4 {
+		t.Fatalf("January = %+v", jan)
+	}
+	if jan.Retention[0].Rate != 1 || jan.Retention[1].Rate != 0 || jan.Retention[1].Amount != "0.00" || jan.Retention[2].Rate != 0.25 {
+		t.Errorf("January retention = %+v", jan.Retention)
+	}
+	if mar := res.Cohorts[1]; len(mar.Retention) != 2 || mar.Retention[1].Rate != 0.5 {
+		t.Errorf("March = %+v", mar)
+	}
+
+	value, err := LifetimeValue(context.Background(), q, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := value.Cohorts[0]; v.Amount != "110.50" || v.LifetimeValue != "27.63" || v.AverageGift != "18.42" {
+		t.Errorf("January value = %+v", v)
+	}
+	if value.Total.Donors != 6 || value.Total.Donations != 10 || value.Total.Amount != "160.50" || value.Total.LifetimeValue != "26.75" {
+		t.Errorf("total value = %+v", value.Total)
+	}
+}
+
+func TestDonorSegments(t *testing.T) {
+	spec := testCohortSpec(t)
+	joined := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	q := &fakeDonors{summaries: []db.ListDonorSummariesRow{
+		// One gift long ago
+		{UserID: 1, Active: true, Cohort: date("2025-01-01", time.UTC), LastDonation: date("2025-01-05", time.UTC), DonationCount: 1, AmountSum: "50.00", ActiveMonths: 1},
+		// Gave in January, joined a team in March and gave again in March and April
+		{UserID: 2, Active: true, Cohort: date("2026-01-01", time.UTC), LastDonation: date("2026-04-02", time.UTC), DonationCount: 3, AmountSum: "30.00", ActiveMonths: 3,
+			InTeam: true, JoinedTeamAt: joined, ActiveMonthsBeforeJoin: 1, ActiveMonthsAfterJoin: 2},
+		// Two gifts in one month, lapsed but anonymized
+		{UserID: 3, Active: false, Cohort: date("2024-06-01", time.UTC), LastDonation: date("2024-06-20", time.UTC), DonationCount: 2, AmountSum: "20.00", ActiveMonths: 1},
+	}}
+
+	lapsed, err := Lapsed(context.Background(), q, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lapsed.Donors != 2 || lapsed.Lapsed != 1 || lapsed.Rate != 0.5 || lapsed.LapsedList[0].UserID != 1 || lapsed.LapsedList[0].DaysLapsed != 465 {
+		t.Errorf("lapsed = %+v", lapsed)
+	}
+
+	recurring, err := Recurring(context.Background(), q, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{SegmentOneOff: 1, SegmentRepeat: 1, SegmentRecurring: 1}
+	for _, s := range recurring.Segments {
+		if s.Donors != want[s.Segment] {
+			t.Errorf("%s donors = %d, want %d", s.Segment, s.Donors, want[s.Segment])
+		}
+	}
+	if s := recurring.Segments[2]; s.AmountShare != 0.3 || s.DonorShare != 0.3333 {
+		t.Errorf("recurring segment = %+v", s)
+	}
+
+	teams, err := TeamRetention(context.Background(), q, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team := teams.Segments[0]; team.Segment.Segment != SegmentTeam || team.Donors != 1 || team.RepeatRate != 1 {
+		t.Errorf("team segment = %+v", team)
+	}
+	if noTeam := teams.Segments[1]; noTeam.Donors != 2 || noTeam.RepeatDonors != 0 {
+		t.Errorf("no team segment = %+v", noTeam)
+	}
+	// Active in one of January and February, then in both of March and April
+	impact := teams.JoinedAfterFirstGift
+	if impact.Donors != 1 || impact.ActiveRateBefore != 0.5 || impact.ActiveRateAfter != 1 || impact.RetainedRate != 1 {
+		t.Errorf("join impact = %+v", impact)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := RecurringReport{Segments: []Segment{{Segment: SegmentOneOff, Donors: 2, Donations: 2, Amount: "15.00", DonorShare: 1, AmountShare: 1, LifetimeValue: "7.50", AverageGift: "7.50"}}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join(segmentColumns, ",") + "\none_off,2,2,15.00,1,1,7.50,7.50\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestCents(t *testing.T) {
+	for amount, want := range map[string]int64{"0": 0, "12.5": 1250, "12.34": 1234, "-3.05": -305, "1000.00": 100000} {
+		if got := cents(amount); got != want {
+			t.Errorf("cents(%q) = %d, want %d", amount, got, want)
+		}
+		if amount != "12.5" && amount != "0" && string(formatCents(want)) != amount {
+			t.Errorf("formatCents(%d) = %s, want %s", want, formatCents(want), amount)
+		}
+	}
+}
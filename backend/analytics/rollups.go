@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/jobs"
+)
+
+// refreshBatch is how many dirty buckets are rebuilt per transaction
+const refreshBatch = 500
+
+// Service keeps the donation rollups up to date
+type Service struct {
+	store *db.Store
+}
+
+func NewService(store *db.Store) *Service {
+	return &Service{store: store}
+}
+
+// Tasks returns the background job that rebuilds dirty rollup buckets
+func (s *Service) Tasks() []jobs.Task {
+	return []jobs.Task{
+		{Name: "donation-rollups", Interval: 30 * time.Second, Run: s.RefreshRollups},
+	}
+}
+
+// RefreshRollups rebuilds every bucket whose donations changed since it was last rolled up. Buckets
+// with writes still in flight are left for the next run, so several instances can share the work.
+func (s *Service) RefreshRollups(ctx context.Context) error {
+	for {
+		var buckets []time.Time
+		err := s.store.ExecTx(ctx, func(q db.Querier) error {
+			var err error
+			buckets, err = q.ClaimDirtyRollupBuckets(ctx, refreshBatch)
+			if err != nil || len(buckets) == 0 {
+				return err
+			}
+			if err := q.DeleteDonationRollups(ctx, buckets); err != nil {
+				return err
+			}
+			_, err = q.RebuildDonationRollups(ctx, buckets)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(buckets) < refreshBatch {
+			if len(buckets) > 0 {
+				slog.DebugContext(ctx, "donation rollups refreshed", "buckets", len(buckets))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+
+	db "play4good-backend/db/sqlc"
+
+	"github.com/sqlc-dev/pqtype"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+	// ActionPasswordReset is an update to a user's password; the hash itself is never recorded
+	ActionPasswordReset = "password_reset"
+)
+
+// Entity types recorded in the audit log
+const (
+	EntityUser             = "user"
+	EntityTeam             = "team"
+	EntityTeamMember       = "team_member"
+	EntityCause            = "cause"
+	EntityDonation         = "donation"
+	EntityLeaderboard      = "leaderboard"
+	EntityLeaderboardEntry = "leaderboard_entry"
+	EntityAPIKey           = "api_key"
+	EntityRolePolicy       = "role_policy"
+	EntityDonationImport   = "donation_import"
+	EntityReceipt          = "receipt"
+	EntityReceiptTemplate  = "receipt_template"
+	EntityTwoFactor        = "two_factor"
+	EntityUserIdentity     = "user_identity"
+)
+
+// redactedFields are never copied into audit snapshots. Receipt PDFs are left out for size; the
+// snapshot keeps their sha256.
+var redactedFields = []string{"password_hash", "key_hash", "secret", "token", "pdf"}
+
+// Entry describes one change. Before is nil for creates and After is nil for permanent deletes.
+type Entry struct {
+	ActorID    int32
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     interface{}
+	After      interface{}
+	RequestID  string
+	IP         string
+}
+
+// Actor is who made a change and from which request. Handlers attach it to the request context so
+// entries recorded further down, in the services, are attributed without threading it through.
+type Actor struct {
+	ID        int32
+	RequestID string
+	IP        string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor attached to ctx, or the zero Actor for changes made outside a request
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// FieldChange is one field's value before and after a change
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Record writes an entry using q. Pass the transaction's queries so the entry commits or rolls back with the change.
+// Actor fields left empty in entry are taken from the Actor on ctx.
+func Record(ctx context.Context, q db.Querier, entry Entry) (db.AuditLog, error) {
+	actor := ActorFrom(ctx)
+	if entry.ActorID == 0 {
+		entry.ActorID = actor.ID
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = actor.RequestID
+	}
+	if entry.IP == "" {
+		entry.IP = actor.IP
+	}
+
+	before, err := snapshot(entry.Before)
+	if err != nil {
+		return db.AuditLog{}, err
+	}
+	after, err := snapshot(entry.After)
+	if err != nil {
+		return db.AuditLog{}, err
+	}
+
+	diff, err := json.Marshal(Diff(before, after))
+	if err != nil {
+		return db.AuditLog{}, err
+	}
+
+	return q.CreateAuditLogEntry(ctx, db.CreateAuditLogEntryParams{
+		ActorID:    sql.NullInt32{Int32: entry.ActorID, Valid: entry.ActorID != 0},
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     rawJSON(before),
+		After:      rawJSON(after),
+		Diff:       diff,
+		RequestID:  sql.NullString{String: entry.RequestID, Valid: entry.RequestID != ""},
+		Ip:         sql.NullString{String: entry.IP, Valid: entry.IP != ""},
+	})
+}
+
+// Diff lists the fields whose values differ between two snapshots
+func Diff(before, after map[string]interface{}) map[string]FieldChange {
+	changes := map[string]FieldChange{}
+	for field, from := range before {
+		if to, ok := after[field]; !ok || !reflect.DeepEqual(from, to) {
+			changes[field] = FieldChange{From: from, To: after[field]}
+		}
+	}
+	for field, to := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = FieldChange{From: nil, To: to}
+		}
+	}
+	return changes
+}
+
+// snapshot converts an entity to its JSON field map, dropping secrets
+func snapshot(entity interface{}) (map[string]interface{}, error) {
+	if entity == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range redactedFields {
+		delete(fields, field)
+	}
+	return fields, nil
+}
+
+func rawJSON(fields map[string]interface{}) pqtype.NullRawMessage {
+	if fields == nil {
+		return pqtype.NullRawMessage{}
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return pqtype.NullRawMessage{}
+	}
+	return pqtype.NullRawMessage{RawMessage: raw, Valid: true}
+}
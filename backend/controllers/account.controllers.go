@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/schemas"
+	"play4good-backend/security"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	verifyEmailTokenTTL   = 48 * time.Hour
+	resetPasswordTokenTTL = time.Hour
+)
+
+// issueActionToken creates a single-use token for the user, revoking any earlier unused token with the same purpose
+func (c *Play4GoodController) issueActionToken(ctx *gin.Context, userID int32, purpose string, ttl time.Duration) (string, error) {
+	err := c.db.InvalidateUserActionTokens(ctx, db.InvalidateUserActionTokensParams{
+		UserID:  userID,
+		Purpose: purpose,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	token, err := util.GenerateActionToken(int(userID), purpose, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = c.db.CreateUserActionToken(ctx, db.CreateUserActionTokenParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeActionToken verifies a token and marks it used so it cannot be redeemed twice
+func (c *Play4GoodController) consumeActionToken(ctx *gin.Context, token, purpose string) (int32, error) {
+	userID, err := util.ParseActionToken(token, purpose)
+	if err != nil {
+		return 0, fmt.Errorf("invalid or expired token")
+	}
+
+	record, err := c.db.ConsumeUserActionToken(ctx, db.ConsumeUserActionTokenParams{
+		TokenHash: util.HashToken(token),
+		Purpose:   purpose,
+	})
+	if err != nil || record.UserID != int32(userID) {
+		return 0, fmt.Errorf("invalid or expired token")
+	}
+
+	return record.UserID, nil
+}
+
+func (c *Play4GoodController) sendVerificationEmail(ctx *gin.Context, user db.User) error {
+	token, err := c.issueActionToken(ctx, user.ID, util.TokenPurposeVerifyEmail, verifyEmailTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", c.config.FrontendURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in 48 hours.", user.Username, link)
+	return c.mailer.Send(user.Email, "Confirm your Play4Good email address", body)
+}
+
+// requireVerifiedEmail checks that the authenticated user has confirmed their email address
+func (c *Play4GoodController) requireVerifiedEmail(ctx *gin.Context) error {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return fmt.Errorf("authentication required")
+	}
+
+	user, err := c.db.GetUser(ctx, int32(userID.(int)))
+	if err != nil {
+		return fmt.Errorf("authentication required")
+	}
+
+	if !user.EmailVerifiedAt.Valid {
+		return fmt.Errorf("email verification required")
+	}
+	return nil
+}
+
+// RequestEmailVerification sends a fresh verification link to the authenticated user
+func (c *Play4GoodController) RequestEmailVerification(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	user, err := c.db.GetUser(ctx, int32(userID.(int)))
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to fetch user data")
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		ctx.JSON(http.StatusOK, gin.H{"message": "Email already verified"})
+		return
+	}
+
+	if wait, _, err := c.emailThrottle.Take(ctx, "verify:"+user.Email); err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Database error")
+		return
+	} else if wait > 0 {
+		setRetryAfter(ctx, wait)
+		respondMessage(ctx, http.StatusTooManyRequests, "Too many requests, please try again later")
+		return
+	}
+
+	if err := c.sendVerificationEmail(ctx, user); err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
+}
+
+// VerifyEmail confirms the email address a verification token was issued for
+func (c *Play4GoodController) VerifyEmail(ctx *gin.Context) {
+	var payload *schemas.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := c.consumeActionToken(ctx, payload.Token, util.TokenPurposeVerifyEmail)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var user db.User
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		user, err = q.MarkUserEmailVerified(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return c.auditAs(ctx, q, userID, audit.ActionUpdate, audit.EntityUser, userID, before, user)
+	})
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":           "Email verified successfully",
+		"email_verified_at": user.EmailVerifiedAt.Time,
+	})
+}
+
+// ForgotPassword emails a reset link. The response is identical whether or not the
+// email belongs to an account so the endpoint cannot be used to enumerate users.
+func (c *Play4GoodController) ForgotPassword(ctx *gin.Context) {
+	var payload *schemas.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	response := gin.H{"message": "If an account exists for that email, a reset link has been sent"}
+
+	if wait, _, err := c.emailThrottle.Take(ctx, "reset:"+payload.Email); err != nil || wait > 0 {
+		if err != nil {
+			slog.ErrorContext(ctx.Request.Context(), "could not throttle password reset emails", "error", err)
+		}
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	user, err := c.db.GetUserByEmail(ctx, payload.Email)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.ErrorContext(ctx.Request.Context(), "forgot password lookup failed", "error", err)
+		}
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, err := c.issueActionToken(ctx, user.ID, util.TokenPurposeResetPassword, resetPasswordTokenTTL)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "could not issue reset token", "user_id", user.ID, "error", err)
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", c.config.FrontendURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nThe link expires in one hour. If you did not ask for this, you can ignore this email.", user.Username, link)
+	if err := c.mailer.Send(user.Email, "Reset your Play4Good password", body); err != nil {
+		slog.WarnContext(ctx.Request.Context(), "could not send reset email", "user_id", user.ID, "error", err)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ResetPassword sets a new password and signs the user out everywhere
+func (c *Play4GoodController) ResetPassword(ctx *gin.Context) {
+	var payload *schemas.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := c.consumeActionToken(ctx, payload.Token, util.TokenPurposeResetPassword)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(payload.Password)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	var user db.User
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		err = q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+			ID:           userID,
+			PasswordHash: hashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+		// Revoke every existing session for the account
+		err = q.DeleteUserTokensByUserID(ctx, sql.NullInt32{Int32: userID, Valid: true})
+		if err != nil {
+			return err
+		}
+		user, err = q.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return c.auditAs(ctx, q, userID, audit.ActionPasswordReset, audit.EntityUser, userID, before, user)
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// A reset proves control of the mailbox, so it also lifts any login lockout
+	accountKey, _ := loginThrottleKeys(ctx, user.Email)
+	if err := c.accountThrottle.Succeed(ctx, accountKey); err != nil {
+		slog.WarnContext(ctx.Request.Context(), "could not reset failed logins", "user_id", userID, "error", err)
+	}
+	c.recordSecurityEvent(ctx, userID, security.EventPasswordReset)
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"play4good-backend/analytics"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDonationAnalytics returns donation totals per day, week or month, optionally grouped and
+// compared with the period before. Admins see every donation; cause owners see their own causes
+// by cause_id.
+func (c *Play4GoodController) GetDonationAnalytics(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var query schemas.DonationAnalyticsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	spec := analytics.Spec{
+		Interval:     query.Interval,
+		GroupBy:      query.GroupBy,
+		From:         query.From,
+		To:           query.To,
+		TimeZone:     query.TimeZone,
+		CauseID:      int32(query.CauseID),
+		TeamID:       int32(query.TeamID),
+		DonationType: query.DonationType,
+		Compare:      query.Compare,
+	}
+	if err := spec.Validate(); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := c.authorizeAnalytics(ctx, spec.CauseID); errors.Is(err, errAnalyticsForbidden) {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	} else if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res, err := analytics.Query(ctx.Request.Context(), c.db, spec)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// errAnalyticsForbidden is returned by authorizeAnalytics to anyone but an admin or the cause's owner
+var errAnalyticsForbidden error = problem.New(http.StatusForbidden, problem.CodeForbidden, "Only admins can see donation analytics; cause owners can see their own causes by cause_id")
+
+// authorizeAnalytics lets admins query every donation and cause owners the donations to one of
+// their own causes. Other callers get errAnalyticsForbidden; any other error is a failed lookup.
+func (c *Play4GoodController) authorizeAnalytics(ctx *gin.Context, causeID int32) error {
+	if c.requireAdmin(ctx) == nil {
+		return nil
+	}
+	if causeID == 0 {
+		return errAnalyticsForbidden
+	}
+	cause, err := c.db.GetCause(ctx.Request.Context(), causeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errAnalyticsForbidden
+		}
+		return err
+	}
+	if cause.OwnerID.Int32 != int32(ctx.GetInt("userID")) {
+		return errAnalyticsForbidden
+	}
+	return nil
+}
+
+// GetDonorCohorts reports month-over-month retention per first-donation cohort
+func (c *Play4GoodController) GetDonorCohorts(ctx *gin.Context) {
+	c.sendDonorReport(ctx, "donor-cohorts", func(ctx context.Context, spec analytics.CohortSpec) (analytics.Table, error) {
+		return analytics.Cohorts(ctx, c.db, spec)
+	})
+}
+
+// GetLapsedDonors lists donors who have not given for a while, to win back
+func (c *Play4GoodController) GetLapsedDonors(ctx *gin.Context) {
+	c.sendDonorReport(ctx, "lapsed-donors", func(ctx context.Context, spec analytics.CohortSpec) (analytics.Table, error) {
+		return analytics.Lapsed(ctx, c.db, spec)
+	})
+}
+
+// GetDonorLifetimeValue reports the average amount given per donor, per first-donation cohort
+func (c *Play4GoodController) GetDonorLifetimeValue(ctx *gin.Context) {
+	c.sendDonorReport(ctx, "donor-lifetime-value", func(ctx context.Context, spec analytics.CohortSpec) (analytics.Table, error) {
+		return analytics.LifetimeValue(ctx, c.db, spec)
+	})
+}
+
+// GetRecurringDonors splits donors into one-off, repeat and recurring donors
+func (c *Play4GoodController) GetRecurringDonors(ctx *gin.Context) {
+	c.sendDonorReport(ctx, "recurring-donors", func(ctx context.Context, spec analytics.CohortSpec) (analytics.Table, error) {
+		return analytics.Recurring(ctx, c.db, spec)
+	})
+}
+
+// GetTeamRetention compares the retention of donors in teams with those who are not
+func (c *Play4GoodController) GetTeamRetention(ctx *gin.Context) {
+	c.sendDonorReport(ctx, "team-retention", func(ctx context.Context, spec analytics.CohortSpec) (analytics.Table, error) {
+		return analytics.TeamRetention(ctx, c.db, spec)
+	})
+}
+
+// sendDonorReport checks that an admin is asking, builds a donor cohort report and sends it as JSON,
+// or as a CSV download with format=csv
+func (c *Play4GoodController) sendDonorReport(ctx *gin.Context, name string, build func(context.Context, analytics.CohortSpec) (analytics.Table, error)) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	var query schemas.DonorAnalyticsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	spec := analytics.CohortSpec{
+		From:            query.From,
+		To:              query.To,
+		TimeZone:        query.TimeZone,
+		Months:          query.Months,
+		LapsedDays:      query.LapsedDays,
+		RecurringMonths: query.RecurringMonths,
+	}
+	if err := spec.Validate(); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := build(ctx.Request.Context(), spec)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if query.Format != "csv" {
+		ctx.JSON(http.StatusOK, report)
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="`+name+"-"+time.Now().UTC().Format("2006-01-02")+`.csv"`)
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	if err := analytics.WriteCSV(ctx.Writer, report); err != nil {
+		slog.ErrorContext(ctx, "donor report download interrupted", "report", name, "error", err)
+		ctx.Abort()
+	}
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyResponse is what the API returns for a key; the hash is never exposed
+type apiKeyResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	TeamID     *int32     `json:"team_id"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newAPIKeyResponse(key db.ApiKey) apiKeyResponse {
+	res := apiKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+	}
+	if key.TeamID.Valid {
+		res.TeamID = &key.TeamID.Int32
+	}
+	if key.ExpiresAt.Valid {
+		res.ExpiresAt = &key.ExpiresAt.Time
+	}
+	if key.LastUsedAt.Valid {
+		res.LastUsedAt = &key.LastUsedAt.Time
+	}
+	if key.RevokedAt.Valid {
+		res.RevokedAt = &key.RevokedAt.Time
+	}
+	return res
+}
+
+// validateAPIKey authenticates a request made with an API key. The key must hold the scope
+// declared on the route, team-scoped keys may only touch their own team, and the owner must have
+// enabled two-factor if their role requires it.
+func (c *Play4GoodController) validateAPIKey(ctx *gin.Context, rawKey string) error {
+	key, err := c.db.GetActiveAPIKeyByHash(ctx, util.HashToken(rawKey))
+	if err != nil {
+		return fmt.Errorf("invalid API key")
+	}
+	if key.ExpiresAt.Valid && key.ExpiresAt.Time.Before(time.Now()) {
+		return fmt.Errorf("API key has expired")
+	}
+	// A key acts as its owner, so it is held to the owner's role policy; enrolment itself needs a session
+	if err := c.requireTwoFactorEnrolment(ctx, key.UserID, ""); err != nil {
+		return err
+	}
+
+	required := ctx.GetString(util.RequiredScopeKey)
+	if required == "" {
+		return fmt.Errorf("API keys cannot be used for this endpoint")
+	}
+	if !util.HasScope(key.Scopes, required) {
+		return fmt.Errorf("API key is missing the %s scope", required)
+	}
+
+	if key.TeamID.Valid {
+		if err := checkAPIKeyTeam(ctx, key.TeamID.Int32); err != nil {
+			return err
+		}
+		ctx.Set("apiKeyTeamID", key.TeamID.Int32)
+	}
+
+	if err := c.db.TouchAPIKey(ctx, key.ID); err != nil {
+		return fmt.Errorf("invalid API key")
+	}
+
+	ctx.Set("userID", int(key.UserID))
+	ctx.Set("apiKeyID", key.ID)
+	return nil
+}
+
+// errAPIKeyNotForTeam rejects a team-scoped key on a route that is not bound to its team
+var errAPIKeyNotForTeam error = problem.New(http.StatusForbidden, problem.CodeForbidden, "API key is not valid for this team")
+
+// teamPayloadRoutes name their team in the request body rather than the path. Their handlers
+// check it with checkAPIKeyTeamPayload.
+var teamPayloadRoutes = map[string]bool{
+	http.MethodPost + " /api/user-team": true,
+}
+
+// checkAPIKeyTeam only lets a team-scoped key through on routes bound to its own team. Every other
+// route is denied, including ones that create teams or list every team.
+func checkAPIKeyTeam(ctx *gin.Context, teamID int32) error {
+	param := ctx.Param("teamId")
+	if param == "" && strings.HasPrefix(ctx.FullPath(), "/api/teams/:id") {
+		param = ctx.Param("id")
+	}
+	if param == "" {
+		if teamPayloadRoutes[ctx.Request.Method+" "+ctx.FullPath()] {
+			return nil
+		}
+		return errAPIKeyNotForTeam
+	}
+	if id, err := strconv.ParseInt(param, 10, 32); err != nil || int32(id) != teamID {
+		return errAPIKeyNotForTeam
+	}
+	return nil
+}
+
+// checkAPIKeyTeamPayload rejects team-scoped keys acting on a team named in a request body
+func checkAPIKeyTeamPayload(ctx *gin.Context, teamID int64) error {
+	if keyTeam, ok := ctx.Get("apiKeyTeamID"); ok && keyTeam.(int32) != int32(teamID) {
+		return errAPIKeyNotForTeam
+	}
+	return nil
+}
+
+// CreateAPIKey issues a new API key for the authenticated user. The key is only returned once.
+func (c *Play4GoodController) CreateAPIKey(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var payload *schemas.APIKeyCreateRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !payload.ExpiresAt.IsZero() && payload.ExpiresAt.Before(time.Now()) {
+		respondMessage(ctx, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	teamID := sql.NullInt32{Int32: int32(payload.TeamID), Valid: payload.TeamID != 0}
+
+	allowed := util.AllScopes
+	if teamID.Valid {
+		// Only team admins can mint keys that manage the team
+		membership, err := c.db.GetUserTeam(ctx, db.GetUserTeamParams{
+			UserID: int32(userID.(int)),
+			TeamID: teamID.Int32,
+		})
+		if err != nil || membership.Role != "admin" {
+			respondMessage(ctx, http.StatusForbidden, "Only team admins can create team API keys")
+			return
+		}
+		allowed = util.TeamScopes
+	}
+	for _, scope := range payload.Scopes {
+		if !util.HasScope(allowed, scope) {
+			respondMessage(ctx, http.StatusBadRequest, fmt.Sprintf("Scope %q is not allowed for this key", scope))
+			return
+		}
+	}
+
+	rawKey, prefix, err := util.GenerateAPIKey()
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	var key db.ApiKey
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		key, err = q.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+			UserID:    int32(userID.(int)),
+			TeamID:    teamID,
+			Name:      payload.Name,
+			Prefix:    prefix,
+			KeyHash:   util.HashToken(rawKey),
+			Scopes:    payload.Scopes,
+			ExpiresAt: sql.NullTime{Time: payload.ExpiresAt, Valid: !payload.ExpiresAt.IsZero()},
+		})
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionCreate, audit.EntityAPIKey, key.ID, nil, key)
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"key":     rawKey,
+		"api_key": newAPIKeyResponse(key),
+	})
+}
+
+// ListAPIKeys returns the authenticated user's API keys, including revoked ones
+func (c *Play4GoodController) ListAPIKeys(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	keys, err := c.db.ListAPIKeysByUser(ctx, int32(userID.(int)))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]apiKeyResponse, len(keys))
+	for i, key := range keys {
+		res[i] = newAPIKeyResponse(key)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// RevokeAPIKey permanently disables one of the authenticated user's API keys
+func (c *Play4GoodController) RevokeAPIKey(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	var key db.ApiKey
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		key, err = q.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{
+			ID:     int32(id64),
+			UserID: int32(userID.(int)),
+		})
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionDelete, audit.EntityAPIKey, key.ID, key, nil)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondMessage(ctx, http.StatusNotFound, "API key not found")
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAPIKeyResponse(key))
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createTestTeam creates a team through the API and returns its ID
+func (s *testServer) createTestTeam(token, name string) int32 {
+	s.t.Helper()
+	var team struct {
+		ID int32 `json:"id"`
+	}
+	decode(s.t, s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/teams", Token: token, Body: gin.H{"name": name}}), &team)
+	return team.ID
+}
+
+func TestTeamAPIKeysOnlyReachTheirTeam(t *testing.T) {
+	s := newTestServer(t)
+	aliceID, token := s.signUp("alice")
+	own := s.createTestTeam(token, "Green Runners")
+	other := s.createTestTeam(token, "Blue Swimmers")
+	if _, err := s.store.AddUserToTeam(context.Background(), db.AddUserToTeamParams{UserID: aliceID, TeamID: own, Role: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	decode(t, s.expect(http.StatusCreated, request{Method: http.MethodPost, Path: "/api/api-keys", Token: token, Body: gin.H{
+		"name":    "team bot",
+		"team_id": own,
+		"scopes":  []string{"teams:read", "teams:write"},
+	}}), &created)
+
+	s.expect(http.StatusOK, request{Method: http.MethodGet, Path: fmt.Sprintf("/api/teams/%d", own), Token: created.Key})
+	s.expect(http.StatusForbidden, request{Method: http.MethodGet, Path: fmt.Sprintf("/api/teams/%d", other), Token: created.Key})
+
+	// Routes that are not bound to a team are closed to the key, whatever its scopes
+	recorder := s.expect(http.StatusForbidden, request{Method: http.MethodPost, Path: "/api/teams", Token: created.Key, Body: gin.H{"name": "Unrelated Team"}})
+	if code := problemCode(t, recorder); code != problem.CodeForbidden {
+		t.Errorf("creating a team: code = %q, want %q", code, problem.CodeForbidden)
+	}
+	s.expect(http.StatusForbidden, request{Method: http.MethodGet, Path: "/api/listTeams", Token: created.Key, Body: gin.H{"limit": 10}})
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/middleware"
+	"play4good-backend/schemas"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// requestContext is the context handed to services: the request's own, carrying the acting user,
+// request ID and client IP for the audit entries they record
+func (c *Play4GoodController) requestContext(ctx *gin.Context) context.Context {
+	return audit.WithActor(ctx.Request.Context(), audit.Actor{
+		ID:        int32(ctx.GetInt("userID")),
+		RequestID: ctx.GetString(middleware.RequestIDKey),
+		IP:        ctx.ClientIP(),
+	})
+}
+
+// audit records a change made in the transaction behind q, attributed to the authenticated user
+func (c *Play4GoodController) audit(ctx *gin.Context, q db.Querier, action, entityType string, entityID interface{}, before, after interface{}) error {
+	return c.auditAs(ctx, q, int32(ctx.GetInt("userID")), action, entityType, entityID, before, after)
+}
+
+// auditAs records a change attributed to actorID, for requests where the user proves who they are
+// with an emailed token or an identity provider rather than a session
+func (c *Play4GoodController) auditAs(ctx *gin.Context, q db.Querier, actorID int32, action, entityType string, entityID interface{}, before, after interface{}) error {
+	_, err := audit.Record(ctx, q, audit.Entry{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     before,
+		After:      after,
+		RequestID:  ctx.GetString(middleware.RequestIDKey),
+		IP:         ctx.ClientIP(),
+	})
+	return err
+}
+
+// auditHistoryResponse is the per-entity history shown outside the admin API, without request metadata
+type auditHistoryResponse struct {
+	ID        int64                 `json:"id"`
+	ActorID   *int32                `json:"actor_id"`
+	Action    string                `json:"action"`
+	Before    pqtype.NullRawMessage `json:"before"`
+	After     pqtype.NullRawMessage `json:"after"`
+	Diff      interface{}           `json:"diff"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+func newAuditHistoryResponse(entries []db.AuditLog) []auditHistoryResponse {
+	res := make([]auditHistoryResponse, len(entries))
+	for i, entry := range entries {
+		res[i] = auditHistoryResponse{
+			ID:        entry.ID,
+			Action:    entry.Action,
+			Before:    entry.Before,
+			After:     entry.After,
+			Diff:      entry.Diff,
+			CreatedAt: entry.CreatedAt,
+		}
+		if entry.ActorID.Valid {
+			res[i].ActorID = &entry.ActorID.Int32
+		}
+	}
+	return res
+}
+
+// ListAuditLog lets admins search the audit log by actor, action, entity, request and time range
+func (c *Play4GoodController) ListAuditLog(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	var query schemas.ListAuditLogRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	entries, err := c.db.ListAuditLog(ctx, db.ListAuditLogParams{
+		ActorID:     nullInt32(query.ActorID),
+		Action:      nullString(query.Action),
+		EntityType:  nullString(query.EntityType),
+		EntityID:    nullString(query.EntityID),
+		RequestID:   nullString(query.RequestID),
+		CreatedFrom: nullTime(query.From),
+		CreatedTo:   nullTime(query.To),
+		Limit:       int32(query.Limit),
+		Offset:      int32(query.Offset),
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// GetCauseHistory returns the audit history of a cause to admins and the cause owner
+func (c *Play4GoodController) GetCauseHistory(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.requireAdmin(ctx); err != nil {
+		cause, err := c.db.GetCause(ctx, int32(id64))
+		if err != nil || cause.OwnerID.Int32 != int32(ctx.GetInt("userID")) {
+			respondMessage(ctx, http.StatusForbidden, "Only admins and the cause owner can view its history")
+			return
+		}
+	}
+
+	c.entityHistory(ctx, audit.EntityCause, id64)
+}
+
+// GetTeamHistory returns the audit history of a team to admins and the team's admins
+func (c *Play4GoodController) GetTeamHistory(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.requireAdmin(ctx); err != nil {
+		membership, err := c.db.GetUserTeam(ctx, db.GetUserTeamParams{
+			UserID: int32(ctx.GetInt("userID")),
+			TeamID: int32(id64),
+		})
+		if err != nil || membership.Role != "admin" {
+			respondMessage(ctx, http.StatusForbidden, "Only admins and team admins can view its history")
+			return
+		}
+	}
+
+	c.entityHistory(ctx, audit.EntityTeam, id64)
+}
+
+func (c *Play4GoodController) entityHistory(ctx *gin.Context, entityType string, id int64) {
+	var query schemas.ListAuditLogRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	entries, err := c.db.ListAuditLogByEntity(ctx, db.ListAuditLogByEntityParams{
+		EntityType: entityType,
+		EntityID:   strconv.FormatInt(id, 10),
+		Limit:      int32(query.Limit),
+		Offset:     int32(query.Offset),
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAuditHistoryResponse(entries))
+}
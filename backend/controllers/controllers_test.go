@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"play4good-backend/db/memdb"
+	"play4good-backend/middleware"
+	"play4good-backend/services"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testLargeDonation is the threshold above which donors must have verified their email
+const testLargeDonation = 1000
+
+// testNextAuthSecret signs the NextAuth identity tokens the tests sign in with
+const testNextAuthSecret = "nextauth-test-secret"
+
+// sentMail is an email captured by testMailer
+type sentMail struct {
+	To, Subject, Body string
+}
+
+// testMailer records emails instead of sending them
+type testMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+}
+
+func (m *testMailer) Send(to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentMail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+func (m *testMailer) Sent() []sentMail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]sentMail(nil), m.sent...)
+}
+
+// testServer is a controller on an in-memory store, mounted on the routes the tests exercise
+type testServer struct {
+	t      *testing.T
+	store  *memdb.Store
+	mailer *testMailer
+	engine *gin.Engine
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := memdb.New()
+	mailer := &testMailer{}
+	config := util.Config{
+		LoginThrottleStore:     "memory",
+		LargeDonationThreshold: testLargeDonation,
+		FrontendURL:            "http://play4good.test",
+		NextAuthSecret:         testNextAuthSecret,
+	}
+	c := newPlay4GoodController(store, services.New(store), config, mailer)
+
+	engine := gin.New()
+	engine.ContextWithFallback = true
+	api := engine.Group("/api")
+
+	api.POST("/signup", c.SignUpUser)
+	api.POST("/login", c.LoginUser)
+	api.POST("/auth/oidc", c.ExchangeIdentityToken)
+
+	scope := middleware.Scope
+
+	api.POST("/api-keys", c.CreateAPIKey)
+
+	api.POST("/teams", scope(util.ScopeTeamsWrite), c.CreateTeam)
+	api.GET("/teams/:id", scope(util.ScopeTeamsRead), c.GetTeam)
+	api.GET("/listTeams", scope(util.ScopeTeamsRead), c.ListTeams)
+	api.PUT("/teams/:id", scope(util.ScopeTeamsWrite), c.UpdateTeam)
+	api.PATCH("/teams/:id", scope(util.ScopeTeamsWrite), c.PatchTeam)
+	api.DELETE("/teams/:id", scope(util.ScopeTeamsWrite), c.DeleteTeam)
+
+	api.POST("/causes", c.CreateCause)
+	api.GET("/causes/:id", c.GetCause)
+	api.POST("/listCauses", c.ListCauses)
+	api.PUT("/causes/:id", c.UpdateCause)
+	api.PATCH("/causes/:id", c.PatchCause)
+	api.DELETE("/causes/:id", c.DeleteCause)
+
+	api.POST("/donations", c.CreateDonation)
+	api.GET("/donations/:id", c.GetDonation)
+	api.GET("/listDonations", c.ListDonations)
+
+	api.POST("/leaderboards", c.CreateLeaderboard)
+	api.GET("/leaderboards/:id", c.GetLeaderboard)
+	api.PATCH("/leaderboards/:id", c.PatchLeaderboard)
+	api.PUT("/leaderboards/:id/entries", c.UpdateLeaderboardEntry)
+	api.PUT("/listLeaderBoards", c.ListLeaderboards)
+
+	api.POST("/user-team", scope(util.ScopeTeamsWrite), c.AddUserToTeam)
+	api.PUT("/user-team/:userId/:teamId", scope(util.ScopeTeamsWrite), c.UpdateUserTeamRole)
+	api.DELETE("/user-team/:userId/:teamId", scope(util.ScopeTeamsWrite), c.RemoveUserFromTeam)
+
+	return &testServer{t: t, store: store, mailer: mailer, engine: engine}
+}
+
+// request is one call to the test server. Token is sent as a bearer session token.
+type request struct {
+	Method, Path string
+	Body         interface{}
+	Token        string
+	Header       map[string]string
+}
+
+func (s *testServer) do(r request) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var body bytes.Buffer
+	if r.Body != nil {
+		if err := json.NewEncoder(&body).Encode(r.Body); err != nil {
+			s.t.Fatalf("encoding %s %s body: %v", r.Method, r.Path, err)
+		}
+	}
+	req := httptest.NewRequest(r.Method, r.Path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	if r.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.Token)
+	}
+	for name, value := range r.Header {
+		req.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// expect calls the server and fails the test unless it answers with status
+func (s *testServer) expect(status int, r request) *httptest.ResponseRecorder {
+	s.t.Helper()
+	recorder := s.do(r)
+	if recorder.Code != status {
+		s.t.Fatalf("%s %s: got status %d, want %d: %s", r.Method, r.Path, recorder.Code, status, recorder.Body.String())
+	}
+	return recorder
+}
+
+// signUp creates an account and returns its ID and session token
+func (s *testServer) signUp(username string) (int32, string) {
+	s.t.Helper()
+	recorder := s.expect(http.StatusCreated, request{Method: http.MethodPost, Path: "/api/signup", Body: gin.H{
+		"username":   username,
+		"email":      username + "@play4good.test",
+		"password":   "correct horse",
+		"first_name": "Test",
+		"last_name":  "User",
+	}})
+
+	var user struct {
+		ID int32 `json:"id"`
+	}
+	decode(s.t, recorder, &user)
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == "token" {
+			return user.ID, cookie.Value
+		}
+	}
+	s.t.Fatalf("sign-up of %s set no session cookie", username)
+	return 0, ""
+}
+
+// verify marks a user's email address as verified
+func (s *testServer) verify(userID int32) {
+	s.t.Helper()
+	if _, err := s.store.MarkUserEmailVerified(context.Background(), userID); err != nil {
+		s.t.Fatalf("verifying user %d: %v", userID, err)
+	}
+}
+
+// audited returns the actions recorded in the audit log for an entity type, oldest first
+func (s *testServer) audited(entityType string) []string {
+	var actions []string
+	for _, entry := range s.store.AuditLog() {
+		if entry.EntityType == entityType {
+			actions = append(actions, entry.Action)
+		}
+	}
+	return actions
+}
+
+func decode(t *testing.T, recorder *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+		t.Fatalf("decoding %s: %v", recorder.Body.String(), err)
+	}
+}
+
+// problemCode returns the code of a problem response
+func problemCode(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p struct {
+		Code string `json:"code"`
+	}
+	decode(t, recorder, &p)
+	return p.Code
+}
+
+func equalActions(got []string, want ...string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
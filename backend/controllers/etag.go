@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http"
+
+	"play4good-backend/middleware"
+	"play4good-backend/problem"
+	"play4good-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errVersionConflict means the row changed after the client fetched it
+var errVersionConflict = services.ErrVersionConflict
+
+// requireIfMatch returns the If-Match header of an update, answering 428 when it is missing
+func requireIfMatch(ctx *gin.Context) (string, bool) {
+	match := ctx.GetHeader("If-Match")
+	if match == "" {
+		problem.Write(ctx, problem.New(http.StatusPreconditionRequired, "", "If-Match header with the resource's ETag is required"))
+		return "", false
+	}
+	return match, true
+}
+
+// checkVersion compares an If-Match header with the row's current version
+func checkVersion(match string, version int32) error {
+	if !middleware.StrongETagMatches(match, middleware.VersionETag(version)) {
+		return errVersionConflict
+	}
+	return nil
+}
+
+// ifMatch turns an If-Match header into the version check the services apply
+func ifMatch(match string) services.VersionCheck {
+	return func(version int32) bool {
+		return middleware.StrongETagMatches(match, middleware.VersionETag(version))
+	}
+}
+
+// respondVersioned writes a versioned row along with its ETag
+func respondVersioned(ctx *gin.Context, status int, version int32, body interface{}) {
+	ctx.Header("ETag", middleware.VersionETag(version))
+	ctx.JSON(status, body)
+}
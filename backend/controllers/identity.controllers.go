@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// ExchangeIdentityToken signs in with an identity token from the OIDC issuer or NextAuth.
+// The identity is linked to an existing account with the same verified email, or a new account is created.
+func (c *Play4GoodController) ExchangeIdentityToken(ctx *gin.Context) {
+	var payload *schemas.IdentityTokenRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	identity, err := c.identities.Verify(ctx, payload.IDToken)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	user, err := c.userForIdentity(ctx, identity)
+	if err != nil {
+		if err == errIdentityEmailTaken {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+		if err == errIdentityNoEmail {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		respondMessage(ctx, http.StatusInternalServerError, "Could not sign in with identity provider")
+		return
+	}
+
+	c.beginSession(ctx, user)
+}
+
+var (
+	errIdentityEmailTaken error = problem.New(http.StatusConflict, problem.CodeEmailTaken, "an account with this email already exists; sign in with your password to link this provider")
+	errIdentityNoEmail    error = problem.New(http.StatusBadRequest, "", "identity token has no email address")
+)
+
+// userForIdentity finds the user linked to an identity, linking or creating one on first sign-in
+func (c *Play4GoodController) userForIdentity(ctx *gin.Context, identity util.ExternalIdentity) (db.User, error) {
+	linked, err := c.db.GetUserIdentity(ctx, db.GetUserIdentityParams{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+	})
+	if err == nil {
+		err = c.db.TouchUserIdentity(ctx, db.TouchUserIdentityParams{
+			ID:    linked.ID,
+			Email: sql.NullString{String: identity.Email, Valid: identity.Email != ""},
+		})
+		if err != nil {
+			return db.User{}, err
+		}
+		return c.db.GetUser(ctx, linked.UserID)
+	}
+	if err != sql.ErrNoRows {
+		return db.User{}, err
+	}
+
+	if identity.Email == "" {
+		return db.User{}, errIdentityNoEmail
+	}
+
+	user, err := c.db.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		// Only link to an existing account when the provider vouches for the email
+		if !identity.EmailVerified {
+			return db.User{}, errIdentityEmailTaken
+		}
+	case err == sql.ErrNoRows:
+		user, err = c.createUserForIdentity(ctx, identity)
+		if err != nil {
+			return db.User{}, err
+		}
+	default:
+		return db.User{}, err
+	}
+
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		linked, err := q.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+			UserID:   user.ID,
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    sql.NullString{String: identity.Email, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return c.auditAs(ctx, q, user.ID, audit.ActionCreate, audit.EntityUserIdentity, linked.ID, nil, linked)
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+
+	return user, nil
+}
+
+func (c *Play4GoodController) createUserForIdentity(ctx *gin.Context, identity util.ExternalIdentity) (db.User, error) {
+	username, err := c.availableUsername(ctx, identity.Email)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	// The account has no usable password until the user sets one through a password reset
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return db.User{}, err
+	}
+	hashedPassword, err := util.HashPassword(hex.EncodeToString(secret))
+	if err != nil {
+		return db.User{}, err
+	}
+
+	var user db.User
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		user, err = q.CreateUser(ctx, db.CreateUserParams{
+			Username:     username,
+			Email:        identity.Email,
+			PasswordHash: hashedPassword,
+			FirstName:    sql.NullString{String: identity.FirstName, Valid: identity.FirstName != ""},
+			LastName:     sql.NullString{String: identity.LastName, Valid: identity.LastName != ""},
+			AvatarUrl:    sql.NullString{String: identity.AvatarURL, Valid: identity.AvatarURL != ""},
+			UserRole:     sql.NullString{String: "user", Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if identity.EmailVerified {
+			user, err = q.MarkUserEmailVerified(ctx, user.ID)
+			if err != nil {
+				return err
+			}
+		}
+		// The new account is the actor of its own sign-up, as with a password sign-up
+		return c.auditAs(ctx, q, user.ID, audit.ActionCreate, audit.EntityUser, user.ID, nil, user)
+	})
+	return user, err
+}
+
+// availableUsername derives a unique username from the local part of an email address
+func (c *Play4GoodController) availableUsername(ctx *gin.Context, email string) (string, error) {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	base := usernameInvalidChars.ReplaceAllString(local, "")
+	if len(base) < 3 {
+		base = "user" + base
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 10; attempt++ {
+		_, err := c.db.GetUserByUsername(ctx, candidate)
+		if err == sql.ErrNoRows {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+	return "", fmt.Errorf("could not find an available username")
+}
+
+// ListIdentities returns the external providers linked to the authenticated user
+func (c *Play4GoodController) ListIdentities(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	identities, err := c.db.ListUserIdentities(ctx, int32(userID.(int)))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, identities)
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"play4good-backend/audit"
+	"play4good-backend/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// nextAuthToken signs an identity token as the NextAuth frontend would
+func nextAuthToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	claims["exp"] = time.Now().Add(time.Minute).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testNextAuthSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestIdentityWithUnverifiedEmailIsNotLinked(t *testing.T) {
+	s := newTestServer(t)
+	s.signUp("alice")
+
+	// A provider that does not vouch for the address cannot take over the password account
+	unverified := nextAuthToken(t, jwt.MapClaims{"email": "alice@play4good.test", "provider": "forum", "providerAccountId": "1"})
+	recorder := s.expect(http.StatusConflict, request{Method: http.MethodPost, Path: "/api/auth/oidc", Body: gin.H{"id_token": unverified}})
+	if code := problemCode(t, recorder); code != problem.CodeEmailTaken {
+		t.Errorf("code = %q, want %q", code, problem.CodeEmailTaken)
+	}
+	if got := s.audited(audit.EntityUserIdentity); len(got) != 0 {
+		t.Errorf("identity audit actions = %v, want none", got)
+	}
+
+	verified := nextAuthToken(t, jwt.MapClaims{"email": "alice@play4good.test", "email_verified": true, "provider": "github", "providerAccountId": "2"})
+	s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/auth/oidc", Body: gin.H{"id_token": verified}})
+	if got := s.audited(audit.EntityUserIdentity); len(got) != 1 {
+		t.Errorf("identity audit actions = %v, want the link", got)
+	}
+}
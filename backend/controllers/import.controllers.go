@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/imports"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// donationImportResponse is the validation report of an upload and, unless it was a dry run, the
+// batch it staged
+type donationImportResponse struct {
+	Import *db.DonationImport    `json:"import,omitempty"`
+	Report services.ImportReport `json:"report"`
+}
+
+// ImportDonations validates an uploaded CSV or XLSX file of donations row by row. A dry run only
+// reports the errors; otherwise the valid rows are staged as a batch to commit.
+func (c *Play4GoodController) ImportDonations(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	var req schemas.DonationImportRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.File.Size > imports.MaxFileSize {
+		respondMessage(ctx, http.StatusRequestEntityTooLarge, "The file is larger than 10 MB; split it into several imports")
+		return
+	}
+	mapping, ok := importMapping(ctx, req.Mapping)
+	if !ok {
+		return
+	}
+
+	body, err := req.File.Open()
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	defer body.Close()
+	file := services.ImportFile{Name: req.File.Filename, Source: req.Source, Mapping: mapping, Body: body}
+	if file.Source == "" {
+		file.Source = req.File.Filename
+	}
+
+	if req.DryRun {
+		report, err := c.imports.Preview(ctx.Request.Context(), file)
+		if err != nil {
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, donationImportResponse{Report: report})
+		return
+	}
+
+	batch, report, err := c.imports.Stage(c.requestContext(ctx), int32(ctx.GetInt("userID")), file)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, donationImportResponse{Import: &batch, Report: report})
+}
+
+// ListDonationImports lists import batches, newest first
+func (c *Play4GoodController) ListDonationImports(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	var query schemas.ListDonationImportsRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	batches, err := c.imports.List(ctx.Request.Context(), int32(query.Limit), int32(query.Offset))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, batches)
+}
+
+// GetDonationImport returns one batch with its progress and row errors
+func (c *Play4GoodController) GetDonationImport(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	batch, err := c.imports.Get(ctx.Request.Context(), id)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, batch)
+}
+
+// CommitDonationImport creates the donations of a staged batch, atomically or in resumable chunks.
+// Committing a completed batch again changes nothing.
+func (c *Play4GoodController) CommitDonationImport(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req schemas.DonationImportCommitRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			respondError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	batch, err := c.imports.Commit(c.requestContext(ctx), id, services.CommitOptions{
+		Mode:        req.Mode,
+		ChunkSize:   req.ChunkSize,
+		SkipInvalid: req.SkipInvalid,
+	})
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, batch)
+}
+
+// RollBackDonationImport deletes every donation a batch created
+func (c *Play4GoodController) RollBackDonationImport(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	batch, err := c.imports.RollBack(c.requestContext(ctx), id)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, batch)
+}
+
+// importMapping reads the mapping form field, and responds with an error if it is not a JSON object
+func importMapping(ctx *gin.Context, field string) (imports.Mapping, bool) {
+	var mapping imports.Mapping
+	if field != "" {
+		if err := json.Unmarshal([]byte(field), &mapping); err != nil {
+			respondError(ctx, http.StatusBadRequest, problem.Invalid("mapping", "json", "must be a JSON object from field to column header"))
+			return nil, false
+		}
+	}
+	return mapping, true
+}
+
+// causeImportResponse is the validation report of a file of causes and the causes it created
+type causeImportResponse struct {
+	Causes []db.Cause            `json:"causes"`
+	Report services.ImportReport `json:"report"`
+}
+
+// teamImportResponse is the validation report of a file of teams and the teams it created
+type teamImportResponse struct {
+	Teams  []db.Team             `json:"teams"`
+	Report services.ImportReport `json:"report"`
+}
+
+// recordImport binds and opens an uploaded file of causes or teams
+func (c *Play4GoodController) recordImport(ctx *gin.Context) (services.ImportFile, services.RecordImportOptions, func(), bool) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return services.ImportFile{}, services.RecordImportOptions{}, nil, false
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return services.ImportFile{}, services.RecordImportOptions{}, nil, false
+	}
+
+	var req schemas.RecordImportRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return services.ImportFile{}, services.RecordImportOptions{}, nil, false
+	}
+	if req.File.Size > imports.MaxFileSize {
+		respondMessage(ctx, http.StatusRequestEntityTooLarge, "The file is larger than 10 MB; split it into several imports")
+		return services.ImportFile{}, services.RecordImportOptions{}, nil, false
+	}
+	mapping, ok := importMapping(ctx, req.Mapping)
+	if !ok {
+		return services.ImportFile{}, services.RecordImportOptions{}, nil, false
+	}
+	body, err := req.File.Open()
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return services.ImportFile{}, services.RecordImportOptions{}, nil, false
+	}
+	file := services.ImportFile{Name: req.File.Filename, Source: req.File.Filename, Mapping: mapping, Body: body}
+	opts := services.RecordImportOptions{DryRun: req.DryRun, SkipInvalid: req.SkipInvalid}
+	return file, opts, func() { body.Close() }, true
+}
+
+// ImportCauses creates a cause for every row of an uploaded CSV or XLSX file, in one transaction.
+// The importing admin owns them. A dry run only reports the errors.
+func (c *Play4GoodController) ImportCauses(ctx *gin.Context) {
+	file, opts, closeFile, ok := c.recordImport(ctx)
+	if !ok {
+		return
+	}
+	defer closeFile()
+
+	causes, report, err := c.imports.ImportCauses(c.requestContext(ctx), int32(ctx.GetInt("userID")), file, opts)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	status := http.StatusCreated
+	if opts.DryRun {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, causeImportResponse{Causes: causes, Report: report})
+}
+
+// ImportTeams creates a team for every row of an uploaded CSV or XLSX file, in one transaction. A
+// dry run only reports the errors.
+func (c *Play4GoodController) ImportTeams(ctx *gin.Context) {
+	file, opts, closeFile, ok := c.recordImport(ctx)
+	if !ok {
+		return
+	}
+	defer closeFile()
+
+	teams, report, err := c.imports.ImportTeams(c.requestContext(ctx), file, opts)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	status := http.StatusCreated
+	if opts.DryRun {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, teamImportResponse{Teams: teams, Report: report})
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Helpers for turning optional filter values into nullable query parameters
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullInt32(i int64) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(i), Valid: i != 0}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
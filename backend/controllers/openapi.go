@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"database/sql"
+	"time"
+
+	"play4good-backend/analytics"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/health"
+	"play4good-backend/openapi"
+	"play4good-backend/schemas"
+	"play4good-backend/util"
+)
+
+// The response types below describe bodies the handlers build with gin.H. They only feed the
+// OpenAPI document and must be kept in step with the handlers.
+
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+type signUpResponse struct {
+	ID            int32  `json:"id"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	AvatarURL     string `json:"avatarUrl"`
+	UserRole      string `json:"user_role"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// loginResponse is either a session or, for accounts with two-factor on, a login challenge
+type loginResponse struct {
+	TwoFactorRequired           bool           `json:"two_factor_required,omitempty"`
+	ChallengeToken              string         `json:"challenge_token,omitempty"`
+	ID                          int32          `json:"id,omitempty"`
+	FirstName                   sql.NullString `json:"first_name"`
+	LastName                    sql.NullString `json:"last_name"`
+	Username                    string         `json:"username,omitempty"`
+	Email                       string         `json:"email,omitempty"`
+	AvatarURL                   sql.NullString `json:"avatarUrl"`
+	Token                       string         `json:"token,omitempty"`
+	TwoFactorEnrollmentRequired bool           `json:"two_factor_enrollment_required,omitempty"`
+}
+
+type verifyEmailResponse struct {
+	Message         string    `json:"message"`
+	EmailVerifiedAt time.Time `json:"email_verified_at"`
+}
+
+type createAPIKeyResponse struct {
+	Key    string         `json:"key"`
+	APIKey apiKeyResponse `json:"api_key"`
+}
+
+type twoFactorStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+type twoFactorEnrollResponse struct {
+	Secret    string `json:"secret"`
+	QRPayload string `json:"qr_payload"`
+}
+
+type twoFactorEnableResponse struct {
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type erasureStatusResponse struct {
+	ErasureRequestedAt  *time.Time `json:"erasure_requested_at"`
+	ErasureScheduledFor *time.Time `json:"erasure_scheduled_for"`
+}
+
+// Operations documents every API route. The routes test fails when a route is added without
+// an entry here, or an entry outlives its route.
+var Operations = []openapi.Operation{
+	{Method: "GET", Path: "/api/healthcheck", Summary: "Check that the API is up and its database is ready", Tag: "system", Response: messageResponse{}},
+	{Method: "GET", Path: "/livez", Summary: "Liveness probe", Tag: "system", Response: health.Status{}},
+	{Method: "GET", Path: "/readyz", Summary: "Readiness probe: database connectivity and schema version", Tag: "system", Response: health.Status{}},
+	{Method: "GET", Path: "/api/openapi.json", Summary: "This OpenAPI document", Tag: "system", Response: map[string]interface{}{}},
+	{Method: "GET", Path: "/api/docs", Summary: "Interactive API documentation", Tag: "system", ResponseType: "text/html"},
+
+	// Authentication
+	{Method: "POST", Path: "/api/signup", Summary: "Create an account and start a session", Tag: "auth", Request: schemas.UserCreateRequest{}, Response: signUpResponse{}, Status: 201},
+	{Method: "POST", Path: "/api/login", Summary: "Sign in with email and password", Tag: "auth", Request: schemas.LoginRequest{}, Response: loginResponse{}},
+	{Method: "POST", Path: "/api/login/2fa", Summary: "Complete a login challenge with a two-factor or recovery code", Tag: "auth", Request: schemas.LoginTwoFactorRequest{}, Response: loginResponse{}, RateLimited: true},
+	{Method: "POST", Path: "/api/auth/oidc", Summary: "Sign in with an OIDC or NextAuth identity token", Tag: "auth", Request: schemas.IdentityTokenRequest{}, Response: loginResponse{}, RateLimited: true},
+	{Method: "POST", Path: "/api/auth/verify-email/request", Summary: "Send a new verification email", Tag: "auth", Auth: openapi.AuthSession, Response: messageResponse{}, RateLimited: true},
+	{Method: "POST", Path: "/api/auth/verify-email", Summary: "Confirm an email address", Tag: "auth", Request: schemas.VerifyEmailRequest{}, Response: verifyEmailResponse{}, RateLimited: true},
+	{Method: "POST", Path: "/api/auth/forgot-password", Summary: "Email a password reset link", Tag: "auth", Request: schemas.ForgotPasswordRequest{}, Response: messageResponse{}, RateLimited: true},
+	{Method: "POST", Path: "/api/auth/reset-password", Summary: "Choose a new password and sign out everywhere", Tag: "auth", Request: schemas.ResetPasswordRequest{}, Response: messageResponse{}, RateLimited: true},
+	{Method: "POST", Path: "/api/auth/unlock", Summary: "Unlock an account from the emailed link", Tag: "auth", Request: schemas.UnlockAccountRequest{}, Response: messageResponse{}, RateLimited: true},
+
+	// Current user
+	{Method: "GET", Path: "/api/user/me", Summary: "Get the signed-in user", Tag: "me", Auth: openapi.AuthSession, Response: db.User{}},
+	{Method: "GET", Path: "/api/user/me/security-events", Summary: "List the signed-in user's security events", Tag: "me", Auth: openapi.AuthSession, Query: schemas.ListSecurityEventsRequest{}, Response: []db.SecurityEvent{}},
+	{Method: "GET", Path: "/api/user/me/identities", Summary: "List linked identity providers", Tag: "me", Auth: openapi.AuthSession, Response: []db.UserIdentity{}},
+	{Method: "POST", Path: "/api/user/me/exports", Summary: "Request an export of the signed-in user's data", Tag: "me", Auth: openapi.AuthSession, Response: dataExportResponse{}, Status: 202},
+	{Method: "GET", Path: "/api/user/me/exports", Summary: "List data exports", Tag: "me", Auth: openapi.AuthSession, Response: []dataExportResponse{}},
+	{Method: "GET", Path: "/api/user/me/exports/:id/download", Summary: "Download a completed data export", Tag: "me", Auth: openapi.AuthSession, ResponseType: "application/zip", Response: []byte{}},
+	{Method: "GET", Path: "/api/user/me/erasure", Summary: "Get the account erasure schedule", Tag: "me", Auth: openapi.AuthSession, Response: erasureStatusResponse{}},
+	{Method: "POST", Path: "/api/user/me/erasure", Summary: "Schedule erasure of the account after the grace period", Tag: "me", Auth: openapi.AuthSession, Response: erasureStatusResponse{}, Status: 202},
+	{Method: "DELETE", Path: "/api/user/me/erasure", Summary: "Cancel a scheduled account erasure", Tag: "me", Auth: openapi.AuthSession, Response: erasureStatusResponse{}},
+
+	// Two-factor authentication
+	{Method: "GET", Path: "/api/2fa/status", Summary: "Get the two-factor status", Tag: "2fa", Auth: openapi.AuthSession, Response: twoFactorStatusResponse{}},
+	{Method: "POST", Path: "/api/2fa/enroll", Summary: "Generate a TOTP secret", Tag: "2fa", Auth: openapi.AuthSession, Response: twoFactorEnrollResponse{}},
+	{Method: "POST", Path: "/api/2fa/enable", Summary: "Turn two-factor on with a code from the app", Tag: "2fa", Auth: openapi.AuthSession, Request: schemas.TwoFactorCodeRequest{}, Response: twoFactorEnableResponse{}},
+	{Method: "POST", Path: "/api/2fa/disable", Summary: "Turn two-factor off", Tag: "2fa", Auth: openapi.AuthSession, Request: schemas.TwoFactorDisableRequest{}, Response: messageResponse{}},
+	{Method: "POST", Path: "/api/2fa/recovery-codes", Summary: "Replace the recovery codes", Tag: "2fa", Auth: openapi.AuthSession, Request: schemas.TwoFactorCodeRequest{}, Response: recoveryCodesResponse{}},
+
+	// Reports
+	{Method: "GET", Path: "/api/exports/:dataset", Summary: "Download donations, donors or team stats as CSV, XLSX or JSON Lines", Tag: "reports", Auth: openapi.AuthSession, Query: schemas.ReportQuery{}, ResponseType: "text/csv", Response: []byte{}},
+	{Method: "POST", Path: "/api/export-jobs", Summary: "Queue a report to build in the background", Tag: "reports", Auth: openapi.AuthSession, Request: schemas.ReportExportRequest{}, Response: reportExportResponse{}, Status: 202},
+	{Method: "GET", Path: "/api/export-jobs", Summary: "List queued reports", Tag: "reports", Auth: openapi.AuthSession, Response: []reportExportResponse{}},
+	{Method: "GET", Path: "/api/export-jobs/:id", Summary: "Get the status of a queued report", Tag: "reports", Auth: openapi.AuthSession, Response: reportExportResponse{}},
+	{Method: "GET", Path: "/api/export-jobs/:id/download", Summary: "Download a finished report", Tag: "reports", Auth: openapi.AuthSession, ResponseType: "application/octet-stream", Response: []byte{}},
+
+	// Analytics
+	{Method: "GET", Path: "/api/analytics/donations", Summary: "Donation totals per day, week or month, grouped and compared with the previous period", Tag: "analytics", Auth: openapi.AuthSession, Query: schemas.DonationAnalyticsQuery{}, Response: analytics.Result{}},
+	{Method: "GET", Path: "/api/analytics/cohorts", Summary: "Month-over-month retention per first-donation cohort; format=csv downloads CSV", Tag: "analytics", Auth: openapi.AuthAdmin, Query: schemas.DonorAnalyticsQuery{}, Response: analytics.CohortReport{}},
+	{Method: "GET", Path: "/api/analytics/lapsed-donors", Summary: "Donors who have not given for lapsed_days; format=csv downloads CSV", Tag: "analytics", Auth: openapi.AuthAdmin, Query: schemas.DonorAnalyticsQuery{}, Response: analytics.LapsedReport{}},
+	{Method: "GET", Path: "/api/analytics/lifetime-value", Summary: "Donor lifetime value per first-donation cohort; format=csv downloads CSV", Tag: "analytics", Auth: openapi.AuthAdmin, Query: schemas.DonorAnalyticsQuery{}, Response: analytics.LifetimeValueReport{}},
+	{Method: "GET", Path: "/api/analytics/recurring-donors", Summary: "One-off, repeat and recurring donors; format=csv downloads CSV", Tag: "analytics", Auth: openapi.AuthAdmin, Query: schemas.DonorAnalyticsQuery{}, Response: analytics.RecurringReport{}},
+	{Method: "GET", Path: "/api/analytics/team-retention", Summary: "Retention of donors in and out of teams, and before and after joining one; format=csv downloads CSV", Tag: "analytics", Auth: openapi.AuthAdmin, Query: schemas.DonorAnalyticsQuery{}, Response: analytics.TeamRetentionReport{}},
+
+	// Receipts
+	{Method: "GET", Path: "/api/donations/:id/receipt", Summary: "Download a donation's receipt as a PDF, issuing it on first request", Tag: "receipts", Auth: openapi.AuthSession, Scope: util.ScopeDonationsRead, ResponseType: "application/pdf", Response: []byte{}},
+	{Method: "GET", Path: "/api/user/me/receipts", Summary: "List the receipts and statements issued to the current user", Tag: "receipts", Auth: openapi.AuthSession, Response: []receiptResponse{}},
+	{Method: "GET", Path: "/api/user/me/statements/:year", Summary: "Download the current user's statement of donations in a year", Tag: "receipts", Auth: openapi.AuthSession, ResponseType: "application/pdf", Response: []byte{}},
+	{Method: "POST", Path: "/api/admin/donations/:id/receipt/void", Summary: "Void a donation's receipt", Tag: "receipts", Auth: openapi.AuthAdmin, Request: schemas.ReceiptVoidRequest{}, Response: receiptResponse{}},
+	{Method: "POST", Path: "/api/admin/donations/:id/receipt/reissue", Summary: "Void a donation's receipt and issue a replacement", Tag: "receipts", Auth: openapi.AuthAdmin, Request: schemas.ReceiptVoidRequest{}, Response: receiptResponse{}, Status: 201},
+	{Method: "GET", Path: "/api/admin/users/:id/statements/:year", Summary: "Download a donor's statement of donations in a year", Tag: "receipts", Auth: openapi.AuthAdmin, ResponseType: "application/pdf", Response: []byte{}},
+	{Method: "GET", Path: "/api/admin/receipt-template", Summary: "Get the organization's receipt branding", Tag: "receipts", Auth: openapi.AuthAdmin, Response: db.ReceiptTemplate{}},
+	{Method: "PUT", Path: "/api/admin/receipt-template", Summary: "Replace the organization's receipt branding", Tag: "receipts", Auth: openapi.AuthAdmin, Request: schemas.ReceiptTemplateRequest{}, Response: db.ReceiptTemplate{}},
+	{Method: "GET", Path: "/api/causes/:id/receipt-template", Summary: "Get a cause's receipt branding", Tag: "receipts", Auth: openapi.AuthSession, Response: db.ReceiptTemplate{}},
+	{Method: "PUT", Path: "/api/causes/:id/receipt-template", Summary: "Replace a cause's receipt branding; empty fields use the organization's", Tag: "receipts", Auth: openapi.AuthSession, Request: schemas.ReceiptTemplateRequest{}, Response: db.ReceiptTemplate{}},
+
+	// API keys
+	{Method: "POST", Path: "/api/api-keys", Summary: "Create an API key; the key is only returned here", Tag: "api-keys", Auth: openapi.AuthSession, Request: schemas.APIKeyCreateRequest{}, Response: createAPIKeyResponse{}, Status: 201},
+	{Method: "GET", Path: "/api/api-keys", Summary: "List API keys", Tag: "api-keys", Auth: openapi.AuthSession, Response: []apiKeyResponse{}},
+	{Method: "DELETE", Path: "/api/api-keys/:id", Summary: "Revoke an API key", Tag: "api-keys", Auth: openapi.AuthSession, Response: apiKeyResponse{}},
+
+	// Administration
+	{Method: "GET", Path: "/api/admin/roles", Summary: "List role security policies", Tag: "admin", Auth: openapi.AuthAdmin, Response: []db.RolePolicy{}},
+	{Method: "PUT", Path: "/api/admin/roles/:role", Summary: "Change a role's security policy", Tag: "admin", Auth: openapi.AuthAdmin, Request: schemas.RolePolicyUpdateRequest{}, Response: db.RolePolicy{}},
+	{Method: "GET", Path: "/api/admin/audit", Summary: "Search the audit log", Tag: "admin", Auth: openapi.AuthAdmin, Query: schemas.ListAuditLogRequest{}, Response: []db.AuditLog{}},
+	{Method: "POST", Path: "/api/admin/users/:id/restore", Summary: "Restore a deleted user", Tag: "admin", Auth: openapi.AuthAdmin, Response: db.User{}},
+	{Method: "POST", Path: "/api/admin/teams/:id/restore", Summary: "Restore a deleted team", Tag: "admin", Auth: openapi.AuthAdmin, Response: db.Team{}},
+	{Method: "POST", Path: "/api/admin/causes/:id/restore", Summary: "Restore a deleted cause", Tag: "admin", Auth: openapi.AuthAdmin, Response: db.Cause{}},
+	{Method: "POST", Path: "/api/admin/donation-imports", Summary: "Upload a CSV or XLSX file of donations to validate and stage", Tag: "admin", Auth: openapi.AuthAdmin, Request: schemas.DonationImportRequest{}, RequestType: openapi.MultipartForm, Response: donationImportResponse{}, Status: 201},
+	{Method: "GET", Path: "/api/admin/donation-imports", Summary: "List donation imports", Tag: "admin", Auth: openapi.AuthAdmin, Query: schemas.ListDonationImportsRequest{}, Response: []db.DonationImport{}},
+	{Method: "GET", Path: "/api/admin/donation-imports/:id", Summary: "Get a donation import with its row errors", Tag: "admin", Auth: openapi.AuthAdmin, Response: db.DonationImport{}},
+	{Method: "POST", Path: "/api/admin/donation-imports/:id/commit", Summary: "Commit a staged donation import, atomically or in resumable chunks", Tag: "admin", Auth: openapi.AuthAdmin, Request: schemas.DonationImportCommitRequest{}, Response: db.DonationImport{}},
+	{Method: "POST", Path: "/api/admin/donation-imports/:id/rollback", Summary: "Delete every donation an import created and void their receipts", Tag: "admin", Auth: openapi.AuthAdmin, Response: db.DonationImport{}},
+	{Method: "POST", Path: "/api/admin/cause-imports", Summary: "Create causes from a CSV or XLSX file, all or none", Tag: "admin", Auth: openapi.AuthAdmin, Request: schemas.RecordImportRequest{}, RequestType: openapi.MultipartForm, Response: causeImportResponse{}, Status: 201},
+	{Method: "POST", Path: "/api/admin/team-imports", Summary: "Create teams from a CSV or XLSX file, all or none", Tag: "admin", Auth: openapi.AuthAdmin, Request: schemas.RecordImportRequest{}, RequestType: openapi.MultipartForm, Response: teamImportResponse{}, Status: 201},
+
+	// Users
+	{Method: "POST", Path: "/api/users", Summary: "Create a user", Tag: "users", Auth: openapi.AuthSession, Scope: util.ScopeUsersWrite, Request: schemas.UserCreateRequest{}, Response: db.User{}},
+	{Method: "GET", Path: "/api/users/id/:id", Summary: "Get a user", Tag: "users", Auth: openapi.AuthSession, Scope: util.ScopeUsersRead, Response: db.User{}},
+	{Method: "GET", Path: "/api/users/email/:email", Summary: "Get a user by email", Tag: "users", Auth: openapi.AuthSession, Scope: util.ScopeUsersRead, Response: db.User{}},
+	{Method: "GET", Path: "/api/listUsers", Summary: "List users; paging is read from the JSON body", Tag: "users", Auth: openapi.AuthSession, Scope: util.ScopeUsersRead, Request: schemas.ListUsersRequest{}, Response: []db.User{}},
+	{Method: "PUT", Path: "/api/users/:id", Summary: "Replace a user's profile", Tag: "users", Auth: openapi.AuthSession, Scope: util.ScopeUsersWrite, Request: schemas.UserUpdateRequest{}, Response: db.User{}, IfMatch: true},
+	{Method: "PATCH", Path: "/api/users/:id", Summary: "Change some of a user's fields", Tag: "users", Auth: openapi.AuthSession, Scope: util.ScopeUsersWrite, Request: schemas.UserPatchRequest{}, RequestType: openapi.MergePatchJSON, Response: db.User{}, IfMatch: true},
+	{Method: "DELETE", Path: "/api/users/:id", Summary: "Delete a user", Tag: "users", Auth: openapi.AuthSession, Scope: util.ScopeUsersWrite, Response: messageResponse{}},
+
+	// Teams
+	{Method: "POST", Path: "/api/teams", Summary: "Create a team", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsWrite, Request: schemas.TeamCreateRequest{}, Response: db.Team{}},
+	{Method: "GET", Path: "/api/teams/:id", Summary: "Get a team", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsRead, Response: db.Team{}},
+	{Method: "GET", Path: "/api/listTeams", Summary: "List teams; paging is read from the JSON body", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsRead, Request: schemas.ListTeamsRequest{}, Response: []db.Team{}},
+	{Method: "PUT", Path: "/api/teams/:id", Summary: "Replace a team", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsWrite, Request: schemas.TeamUpdateRequest{}, Response: db.Team{}, IfMatch: true},
+	{Method: "PATCH", Path: "/api/teams/:id", Summary: "Change some of a team's fields", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsWrite, Request: schemas.TeamPatchRequest{}, RequestType: openapi.MergePatchJSON, Response: db.Team{}, IfMatch: true},
+	{Method: "DELETE", Path: "/api/teams/:id", Summary: "Delete a team", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsWrite, Response: messageResponse{}},
+	{Method: "GET", Path: "/api/teams/:id/history", Summary: "Get a team's change history", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsRead, Query: schemas.ListAuditLogRequest{}, Response: []auditHistoryResponse{}},
+	{Method: "POST", Path: "/api/user-team", Summary: "Add a user to a team", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsWrite, Request: schemas.UserTeamCreateRequest{}, Response: db.UserTeam{}},
+	{Method: "PUT", Path: "/api/user-team/:userId/:teamId", Summary: "Change a member's role", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsWrite, Request: schemas.UserTeamUpdateRequest{}, Response: db.UserTeam{}},
+	{Method: "DELETE", Path: "/api/user-team/:userId/:teamId", Summary: "Remove a user from a team", Tag: "teams", Auth: openapi.AuthSession, Scope: util.ScopeTeamsWrite, Response: messageResponse{}},
+
+	// Causes
+	{Method: "POST", Path: "/api/causes", Summary: "Create a cause", Tag: "causes", Auth: openapi.AuthSession, Scope: util.ScopeCausesWrite, Request: schemas.CauseCreateRequest{}, Response: db.Cause{}},
+	{Method: "GET", Path: "/api/causes/:id", Summary: "Get a cause", Tag: "causes", Auth: openapi.AuthSession, Scope: util.ScopeCausesRead, Response: db.Cause{}},
+	{Method: "POST", Path: "/api/listCauses", Summary: "List causes", Tag: "causes", Auth: openapi.AuthSession, Scope: util.ScopeCausesRead, Request: schemas.ListCausesRequest{}, Response: []db.Cause{}},
+	{Method: "PUT", Path: "/api/causes/:id", Summary: "Replace a cause", Tag: "causes", Auth: openapi.AuthSession, Scope: util.ScopeCausesWrite, Request: schemas.CauseUpdateRequest{}, Response: db.Cause{}, IfMatch: true},
+	{Method: "PATCH", Path: "/api/causes/:id", Summary: "Change some of a cause's fields", Tag: "causes", Auth: openapi.AuthSession, Scope: util.ScopeCausesWrite, Request: schemas.CausePatchRequest{}, RequestType: openapi.MergePatchJSON, Response: db.Cause{}, IfMatch: true},
+	{Method: "DELETE", Path: "/api/causes/:id", Summary: "Delete a cause", Tag: "causes", Auth: openapi.AuthSession, Scope: util.ScopeCausesWrite, Response: messageResponse{}},
+	{Method: "GET", Path: "/api/causes/:id/history", Summary: "Get a cause's change history", Tag: "causes", Auth: openapi.AuthSession, Scope: util.ScopeCausesRead, Query: schemas.ListAuditLogRequest{}, Response: []auditHistoryResponse{}},
+
+	// Donations
+	{Method: "POST", Path: "/api/donations", Summary: "Record a donation", Tag: "donations", Auth: openapi.AuthSession, Scope: util.ScopeDonationsWrite, Request: schemas.DonationCreateRequest{}, Response: db.Donation{}},
+	{Method: "GET", Path: "/api/donations/:id", Summary: "Get a donation", Tag: "donations", Auth: openapi.AuthSession, Scope: util.ScopeDonationsRead, Response: db.Donation{}},
+	{Method: "GET", Path: "/api/listDonations", Summary: "List donations; paging is read from the JSON body", Tag: "donations", Auth: openapi.AuthSession, Scope: util.ScopeDonationsRead, Request: schemas.ListDonationsRequest{}, Response: []db.Donation{}},
+
+	// Leaderboards
+	{Method: "POST", Path: "/api/leaderboards", Summary: "Create a leaderboard", Tag: "leaderboards", Auth: openapi.AuthSession, Scope: util.ScopeLeaderboardsWrite, Request: schemas.LeaderboardCreateRequest{}, Response: db.Leaderboard{}},
+	{Method: "GET", Path: "/api/leaderboards/:id", Summary: "Get a leaderboard", Tag: "leaderboards", Auth: openapi.AuthSession, Scope: util.ScopeLeaderboardsRead, Response: db.Leaderboard{}},
+	{Method: "PATCH", Path: "/api/leaderboards/:id", Summary: "Change some of a leaderboard's fields", Tag: "leaderboards", Auth: openapi.AuthSession, Scope: util.ScopeLeaderboardsWrite, Request: schemas.LeaderboardPatchRequest{}, RequestType: openapi.MergePatchJSON, Response: db.Leaderboard{}, IfMatch: true},
+	{Method: "PUT", Path: "/api/leaderboards/:id/entries", Summary: "Set a leaderboard entry's score", Tag: "leaderboards", Auth: openapi.AuthSession, Scope: util.ScopeLeaderboardsWrite, Request: schemas.LeaderboardEntryUpdateRequest{}, Response: db.LeaderboardEntry{}},
+	{Method: "PUT", Path: "/api/listLeaderBoards", Summary: "List leaderboards", Tag: "leaderboards", Auth: openapi.AuthSession, Scope: util.ScopeLeaderboardsRead, Request: schemas.ListLeaderBoardsRequest{}, Response: []db.Leaderboard{}, Conditional: true},
+}
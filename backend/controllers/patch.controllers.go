@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mergePatchContentType is the media type of RFC 7396 JSON merge patches
+const mergePatchContentType = "application/merge-patch+json"
+
+// bindMergePatch decodes a JSON merge patch, rejecting unknown members and validating only the members sent
+func bindMergePatch(ctx *gin.Context, patch interface{ Validate() error }) bool {
+	if contentType := ctx.ContentType(); contentType != mergePatchContentType && contentType != "application/json" {
+		problem.Write(ctx, problem.New(http.StatusUnsupportedMediaType, "", "Content-Type must be "+mergePatchContentType))
+		return false
+	}
+
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patch); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return false
+	}
+	if err := patch.Validate(); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// respondPatchError maps the errors a patch transaction can return to responses
+func respondPatchError(ctx *gin.Context, err error) {
+	switch err {
+	case errVersionConflict:
+		respondError(ctx, http.StatusPreconditionFailed, err)
+	case services.ErrEndBeforeStart:
+		respondError(ctx, http.StatusBadRequest, err)
+	case sql.ErrNoRows:
+		respondError(ctx, http.StatusNotFound, err)
+	default:
+		respondError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+func patchString(f schemas.Field[string]) sql.NullString {
+	return sql.NullString{String: f.Value, Valid: f.Present()}
+}
+
+// PatchUser applies a JSON merge patch to the authenticated user's profile
+func (c *Play4GoodController) PatchUser(ctx *gin.Context) {
+	if err := c.validateToken(ctx); err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	id := int32(id64)
+	if err != nil || id != int32(ctx.GetInt("userID")) {
+		respondMessage(ctx, http.StatusForbidden, "Unauthorized access")
+		return
+	}
+
+	var patch schemas.UserPatchRequest
+	if !bindMergePatch(ctx, &patch) {
+		return
+	}
+	match, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	var user db.User
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(match, before.Version); err != nil {
+			return err
+		}
+		user, err = q.PatchUser(ctx, db.PatchUserParams{
+			SetUsername:  patch.Username.Set,
+			Username:     patch.Username.Value,
+			SetEmail:     patch.Email.Set,
+			Email:        patch.Email.Value,
+			SetFirstName: patch.FirstName.Set,
+			FirstName:    patchString(patch.FirstName),
+			SetLastName:  patch.LastName.Set,
+			LastName:     patchString(patch.LastName),
+			SetAvatarUrl: patch.AvatarURL.Set,
+			AvatarUrl:    patchString(patch.AvatarURL),
+			ID:           id,
+			Version:      before.Version,
+		})
+		if err == sql.ErrNoRows {
+			return errVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionUpdate, audit.EntityUser, id, before, user)
+	})
+	if err != nil {
+		respondPatchError(ctx, err)
+		return
+	}
+
+	respondVersioned(ctx, http.StatusOK, user.Version, user)
+}
+
+// PatchTeam applies a JSON merge patch to a team
+func (c *Play4GoodController) PatchTeam(ctx *gin.Context) {
+	if err := c.validateToken(ctx); err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var patch schemas.TeamPatchRequest
+	if !bindMergePatch(ctx, &patch) {
+		return
+	}
+	match, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	team, err := c.teams.Patch(c.requestContext(ctx), id, patch, ifMatch(match))
+	if err != nil {
+		respondPatchError(ctx, err)
+		return
+	}
+
+	respondVersioned(ctx, http.StatusOK, team.Version, team)
+}
+
+// PatchCause applies a JSON merge patch to a cause
+func (c *Play4GoodController) PatchCause(ctx *gin.Context) {
+	if err := c.validateToken(ctx); err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var patch schemas.CausePatchRequest
+	if !bindMergePatch(ctx, &patch) {
+		return
+	}
+	match, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	cause, err := c.causes.Patch(c.requestContext(ctx), id, patch, ifMatch(match))
+	if err != nil {
+		respondPatchError(ctx, err)
+		return
+	}
+
+	respondVersioned(ctx, http.StatusOK, cause.Version, cause)
+}
+
+// PatchLeaderboard applies a JSON merge patch to a leaderboard
+func (c *Play4GoodController) PatchLeaderboard(ctx *gin.Context) {
+	if err := c.validateToken(ctx); err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var patch schemas.LeaderboardPatchRequest
+	if !bindMergePatch(ctx, &patch) {
+		return
+	}
+	match, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	leaderboard, err := c.leaderboards.Patch(c.requestContext(ctx), id, patch, ifMatch(match))
+	if err != nil {
+		respondPatchError(ctx, err)
+		return
+	}
+
+	respondVersioned(ctx, http.StatusOK, leaderboard.Version, leaderboard)
+}
@@ -1,15 +1,21 @@
 package controllers
 
 import (
-	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
-	"time"
 
+	"play4good-backend/audit"
 	db "play4good-backend/db/sqlc"
+	"play4good-backend/privacy"
+	"play4good-backend/reports"
+	"play4good-backend/problem"
 	"play4good-backend/schemas"
+	"play4good-backend/security"
+	"play4good-backend/services"
 	"play4good-backend/util"
 	"strconv"
 
@@ -17,107 +23,171 @@ import (
 )
 
 type Play4GoodController struct {
-	db  *db.Queries
-	ctx context.Context
+	db     db.Repository
+	config util.Config
+	mailer util.Mailer
+
+	auth         services.AuthService
+	donations    services.DonationService
+	teams        services.TeamService
+	causes       services.CauseService
+	leaderboards services.LeaderboardService
+	imports      services.ImportService
+	receipts     services.ReceiptService
+
+	identities *util.IdentityVerifier
+	privacy    *privacy.Service
+	reports    *reports.Service
+
+	// Limits account emails per address so they cannot be used to flood an inbox
+	emailThrottle *security.Throttle
+	// Limits two-factor code attempts per account
+	twoFactorThrottle *security.Throttle
+	// Back off and lock out repeated failed logins per account and per client IP
+	accountThrottle *security.Throttle
+	ipThrottle      *security.Throttle
 }
 
-func NewPlay4GoodController(db *db.Queries, ctx context.Context) *Play4GoodController {
-	return &Play4GoodController{db, ctx}
+func NewPlay4GoodController(store *db.Store, config util.Config, mailer util.Mailer) *Play4GoodController {
+	c := newPlay4GoodController(store, services.New(store), config, mailer)
+	c.privacy = privacy.NewService(store, config)
+	c.reports = reports.NewService(store, config)
+	return c
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// newPlay4GoodController builds a controller on any repository and set of services, so tests can
+// pass fakes. The privacy and report services work on files and Postgres directly and are left to
+// the caller.
+func newPlay4GoodController(repo db.Repository, svc services.Services, config util.Config, mailer util.Mailer) *Play4GoodController {
+	attempts := security.NewAttemptStore(config.LoginThrottleStore, repo)
+	return &Play4GoodController{
+		db:                repo,
+		config:            config,
+		mailer:            mailer,
+		auth:              svc.Auth,
+		donations:         svc.Donations,
+		teams:             svc.Teams,
+		causes:            svc.Causes,
+		leaderboards:      svc.Leaderboards,
+		imports:           svc.Imports,
+		receipts:          svc.Receipts,
+		identities:        util.NewIdentityVerifier(config),
+		emailThrottle:     security.NewThrottle(attempts, security.EmailPolicy),
+		twoFactorThrottle: security.NewThrottle(attempts, security.TwoFactorPolicy),
+		accountThrottle:   security.NewThrottle(attempts, security.AccountPolicy),
+		ipThrottle:        security.NewThrottle(attempts, security.IPPolicy),
+	}
+}
+
+// respondError writes err as a problem. Database and validation errors are classified by
+// problem.From; other errors get status.
+func respondError(ctx *gin.Context, status int, err error) {
+	problem.Respond(ctx, status, err)
+}
+
+// respondMessage writes a problem with a hand-written detail and the default code for status
+func respondMessage(ctx *gin.Context, status int, detail string) {
+	problem.Write(ctx, problem.New(status, "", detail))
+}
+
+// respondLookupError answers 404 when the row does not exist and 500 for anything else
+func respondLookupError(ctx *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(ctx, http.StatusNotFound, err)
+		return
+	}
+	respondError(ctx, http.StatusInternalServerError, err)
+}
+
+// paramID parses a path parameter as a row ID
+func paramID(ctx *gin.Context, name string) (int32, error) {
+	id, err := strconv.ParseInt(ctx.Param(name), 10, 32)
+	return int32(id), err
 }
 
 // Helper function to validate token and return associated user ID
 func (c *Play4GoodController) validateToken(ctx *gin.Context) error {
-	// Get token from cookie instead of Authorization header
+	// API keys are sent as bearer credentials and checked against the route's scope
+	bearer := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if util.IsAPIKey(bearer) {
+		return c.validateAPIKey(ctx, bearer)
+	}
+
+	// Get token from cookie, falling back to a bearer session token
 	cookie, err := ctx.Cookie("token")
 	if err != nil {
-		return fmt.Errorf("authentication required")
+		if bearer == "" || bearer == ctx.GetHeader("Authorization") {
+			return fmt.Errorf("authentication required")
+		}
+		cookie = bearer
 	}
 
-	// Parse and validate the token
-	userID, err := util.ParseToken(cookie)
+	userID, err := c.auth.ResolveSession(ctx.Request.Context(), cookie)
 	if err != nil {
-		return fmt.Errorf("invalid token")
+		return err
+	}
+
+	// Roles that require two-factor may only reach the enrolment routes until it is enabled
+	if err := c.requireTwoFactorEnrolment(ctx, userID, "/api/2fa/"); err != nil {
+		return err
 	}
 
 	// Store userID in context for later use
-	ctx.Set("userID", userID)
+	ctx.Set("userID", int(userID))
+	return nil
+}
+
+// requireTwoFactorEnrolment rejects a user whose role requires two-factor but who has not enabled
+// it, except on routes under allowedPrefix; an empty prefix allows none
+func (c *Play4GoodController) requireTwoFactorEnrolment(ctx *gin.Context, userID int32, allowedPrefix string) error {
+	twoFactor, err := c.db.GetTwoFactorStatus(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("invalid token")
+	}
+	if twoFactor.Required && !twoFactor.Enabled && (allowedPrefix == "" || !strings.HasPrefix(ctx.FullPath(), allowedPrefix)) {
+		return fmt.Errorf("two-factor authentication must be enabled for this account")
+	}
 	return nil
 }
 
 func (pc *Play4GoodController) SignUpUser(ctx *gin.Context) {
     // Parse and validate the request body
-    var req struct {
-        Username  string `json:"username" binding:"required,min=3,max=50"`
-        Email     string `json:"email" binding:"required,email"`
-        Password  string `json:"password" binding:"required,min=8"`
-        FirstName string `json:"first_name" binding:"required"`
-        LastName  string `json:"last_name" binding:"required"`
-        AvatarURL string `json:"avatar_url" binding:"omitempty,url"`
-    }
+    var req schemas.UserCreateRequest
     if err := ctx.ShouldBindJSON(&req); err != nil {
-        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-        return
-    }
-
-    // Check if the email already exists
-    _, err := pc.db.GetUserByEmail(pc.ctx, req.Email)
-    if err == nil {
-        ctx.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
-        return
-    }
-
-    // Hash the password
-    hashedPassword, err := util.HashPassword(req.Password)
-    if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not process password"})
+        respondError(ctx, http.StatusBadRequest, err)
         return
     }
 
     // Create the user
-    newUser := db.CreateUserParams{
-        Username:     req.Username,
-        Email:        req.Email,
-        PasswordHash: hashedPassword,
-        FirstName:    sql.NullString{String: req.FirstName, Valid: true},
-        LastName:     sql.NullString{String: req.LastName, Valid: true},
-        AvatarUrl:    sql.NullString{String: req.AvatarURL, Valid: req.AvatarURL != ""},
-        UserRole:     sql.NullString{String: "user", Valid: true}, // Default role
-    }
-
-    user, err := pc.db.CreateUser(pc.ctx, newUser)
+    user, err := pc.auth.SignUp(pc.requestContext(ctx), req)
     if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create user"})
+        if errors.Is(err, services.ErrEmailTaken) {
+            respondError(ctx, http.StatusConflict, err)
+            return
+        }
+        respondError(ctx, http.StatusInternalServerError, err)
         return
     }
+    // The new account is the actor of its own sign-up
+    ctx.Set("userID", int(user.ID))
 
-    // Generate JWT token
-    tokenString, err := util.GenerateJWT(int(user.ID))
+    // Start a session
+    tokenString, err := pc.auth.StartSession(ctx.Request.Context(), user.ID)
     if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+        respondMessage(ctx, http.StatusInternalServerError, "Failed to generate token")
         return
     }
 
-    // Create token record in database
-    expiry := time.Now().Add(24 * time.Hour)
-    _, err = pc.db.CreateUserToken(pc.ctx, db.CreateUserTokenParams{
-        UserID: sql.NullInt32{Int32: user.ID, Valid: true},
-        Token:  tokenString,
-        Expiry: expiry,
-    })
-    if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save token"})
-        return
+    // Send the verification email; the account is usable without it, so failures are only logged
+    if err := pc.sendVerificationEmail(ctx, user); err != nil {
+        slog.WarnContext(ctx.Request.Context(), "could not send verification email", "user_id", user.ID, "error", err)
     }
 
     // Set HTTP-only cookie
     ctx.SetCookie(
         "token",
         tokenString,
-        3600*24, // 24 hours
+        int(services.SessionTTL.Seconds()),
         "/",
         "",
         false, // Set to true in production for HTTPS
@@ -126,106 +196,124 @@ func (pc *Play4GoodController) SignUpUser(ctx *gin.Context) {
 
     // Return user data (excluding sensitive information)
     ctx.JSON(http.StatusCreated, gin.H{
-        "id":         user.ID,
-        "username":   user.Username,
-        "email":      user.Email,
-        "first_name": user.FirstName.String,
-        "last_name":  user.LastName.String,
-        "avatarUrl":  user.AvatarUrl.String,
-        "user_role":  user.UserRole.String,
+        "id":             user.ID,
+        "username":       user.Username,
+        "email":          user.Email,
+        "first_name":     user.FirstName.String,
+        "last_name":      user.LastName.String,
+        "avatarUrl":      user.AvatarUrl.String,
+        "user_role":      user.UserRole.String,
+        "email_verified": user.EmailVerifiedAt.Valid,
     })
 }
 
 func (pc *Play4GoodController) LoginUser(ctx *gin.Context) {
 	// Parse and validate the request
-	var req struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
+	var req schemas.LoginRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondMessage(ctx, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
-	// Retrieve the user from the database
-	user, err := pc.db.GetUserByEmail(pc.ctx, req.Email)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-		}
+	// Refuse attempts while the account is locked or recent failures are backing off
+	accountKey, ipKey := loginThrottleKeys(ctx, req.Email)
+	allowed, locked := pc.takeLoginAttempt(ctx, accountKey, ipKey)
+	if !allowed {
 		return
 	}
 
-	// Verify the password
-	if err := util.CheckPassword(req.Password, user.PasswordHash); err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	// Check the email and password
+	user, err := pc.auth.Authenticate(ctx.Request.Context(), req.Email, req.Password)
+	if errors.Is(err, services.ErrInvalidCredentials) {
+		// Failures against an existing account also count towards locking it
+		var account *db.User
+		if user.ID != 0 {
+			account = &user
+		}
+		pc.recordLoginFailure(ctx, account, locked)
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-
-	// Check if the user already has a valid token
-	userToken, err := pc.db.GetUserTokenByUserID(pc.ctx, sql.NullInt32{Int32: user.ID, Valid: true})
-	if err == nil && userToken.Expiry.After(time.Now()) {
-		// Return the existing token if it's still valid
-		ctx.JSON(http.StatusOK, gin.H{
-			"id":         user.ID,
-			"first_name": user.FirstName,
-			"username":   user.Username,
-			"last_name":  user.LastName,
-			"email":      user.Email,
-			"avatarUrl":  user.AvatarUrl,
-			"token":      userToken.Token,
-		})
+	pc.releaseLoginAttempt(ctx, ipKey)
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Database error")
 		return
 	}
 
-	// If no valid token, generate a new one
-	tokenString, err := util.GenerateJWT(int(user.ID))
+	if err := pc.accountThrottle.Succeed(ctx, accountKey); err != nil {
+		slog.WarnContext(ctx.Request.Context(), "could not reset failed logins", "key", accountKey, "error", err)
+	}
+
+	pc.beginSession(ctx, user)
+}
+
+// beginSession signs in a user whose primary credential has been checked. Accounts with
+// two-factor enabled get a login challenge instead of a session.
+func (pc *Play4GoodController) beginSession(ctx *gin.Context, user db.User) {
+	twoFactor, err := pc.db.GetTwoFactorStatus(ctx, user.ID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		respondMessage(ctx, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if twoFactor.Enabled {
+		challenge, err := pc.issueActionToken(ctx, user.ID, util.TokenPurposeLoginChallenge, loginChallengeTTL)
+		if err != nil {
+			respondMessage(ctx, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{
+			"two_factor_required": true,
+			"challenge_token":     challenge,
+		})
 		return
 	}
 
-	// Create a new token record in the database
-	expiry := time.Now().Add(24 * time.Hour) // 24-hour expiry
-	_, err = pc.db.CreateUserToken(pc.ctx, db.CreateUserTokenParams{
-		UserID: sql.NullInt32{Int32: user.ID, Valid: true},
-		Token:  tokenString,
-		Expiry: expiry,
-	})
+	pc.completeLogin(ctx, user, twoFactor.Required)
+}
+
+// completeLogin issues (or reuses) a session token for a fully authenticated user.
+// enrollmentRequired flags users whose role needs 2FA but who have not set it up yet.
+func (pc *Play4GoodController) completeLogin(ctx *gin.Context, user db.User, enrollmentRequired bool) {
+	pc.recordSecurityEvent(ctx, user.ID, security.EventLoginSuccess)
+
+	tokenString, err := pc.auth.StartSession(ctx.Request.Context(), user.ID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save token"})
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
 	// Return the token to the client
 	ctx.JSON(http.StatusOK, gin.H{
-		"id":         user.ID,
-		"first_name": user.FirstName,
-		"last_name":  user.LastName,
-		"username":   user.Username,
-		"email":      user.Email,
-		"avatarUrl":  user.AvatarUrl,
-		"token":      tokenString,
+		"id":                             user.ID,
+		"first_name":                     user.FirstName,
+		"last_name":                      user.LastName,
+		"username":                       user.Username,
+		"email":                          user.Email,
+		"avatarUrl":                      user.AvatarUrl,
+		"token":                          tokenString,
+		"two_factor_enrollment_required": enrollmentRequired,
 	})
 }
 
 
 func (c *Play4GoodController) GetCurrentUser(ctx *gin.Context) {
+    if err := c.validateToken(ctx); err != nil {
+        respondError(ctx, http.StatusUnauthorized, err)
+        return
+    }
     userID, exists := ctx.Get("userID")
     if !exists {
-        ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+        respondMessage(ctx, http.StatusUnauthorized, "Not authenticated")
         return
     }
 
     user, err := c.db.GetUser(ctx, int32(userID.(int)))
     if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user data"})
+        respondMessage(ctx, http.StatusInternalServerError, "Failed to fetch user data")
         return
     }
 
-    ctx.JSON(http.StatusOK, user)
+    respondVersioned(ctx, http.StatusOK, user.Version, user)
 }
 
 func (pc *Play4GoodController) LogoutUser(ctx *gin.Context) {
@@ -247,7 +335,7 @@ func (pc *Play4GoodController) LogoutUser(ctx *gin.Context) {
 func (c *Play4GoodController) CreateUser(ctx *gin.Context) {
 	var payload *schemas.UserCreateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -260,19 +348,27 @@ func (c *Play4GoodController) CreateUser(ctx *gin.Context) {
 		AvatarUrl:    sql.NullString{String: payload.AvatarURL, Valid: payload.AvatarURL != ""},
 	}
 
-	user, err := c.db.CreateUser(ctx, arg)
+	var user db.User
+	err := c.db.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionCreate, audit.EntityUser, user.ID, nil, user)
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	respondVersioned(ctx, http.StatusOK, user.Version, user)
 }
 
 func (c *Play4GoodController) GetUser(ctx *gin.Context) {
 	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -281,67 +377,57 @@ func (c *Play4GoodController) GetUser(ctx *gin.Context) {
 	user, err := c.db.GetUser(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			respondError(ctx, http.StatusNotFound, err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	respondVersioned(ctx, http.StatusOK, user.Version, user)
 }
 
 func (c *Play4GoodController) GetUserByEmail(ctx *gin.Context) {
 	email := ctx.Param("email")
 
 	if email == "" {
-		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("email is required")))
+		respondMessage(ctx, http.StatusBadRequest, "email is required")
 		return
 	}
 
 	user, err := c.db.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("user not found")))
+			respondMessage(ctx, http.StatusNotFound, "user not found")
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	respondVersioned(ctx, http.StatusOK, user.Version, user)
 }
 
 func (c *Play4GoodController) UpdateUser(ctx *gin.Context) {
-	// Extract the token from the Authorization header
-	authHeader := ctx.GetHeader("Authorization")
-	if authHeader == "" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-		return
-	}
-
-	// Extract the token string
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-	// Parse and validate the token
-	userID, err := util.ParseToken(tokenString)
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+	// Authenticate with a session token or an API key holding users:write
+	if err := c.validateToken(ctx); err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
+	userID := ctx.GetInt("userID")
 
 	// Get the user ID from the URL parameter and ensure it matches the token's user ID
 	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
 	id := int32(id64)
 	if err != nil || id != int32(userID) {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized access"})
+		respondMessage(ctx, http.StatusForbidden, "Unauthorized access")
 		return
 	}
 
 	// Proceed with updating the user information
 	var payload *schemas.UserUpdateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondMessage(ctx, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
@@ -354,32 +440,88 @@ func (c *Play4GoodController) UpdateUser(ctx *gin.Context) {
 		AvatarUrl: sql.NullString{String: payload.AvatarURL, Valid: payload.AvatarURL != ""},
 	}
 
-	user, err := c.db.UpdateUser(ctx, arg)
+	match, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	var user db.User
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(match, before.Version); err != nil {
+			return err
+		}
+		arg.Version = before.Version
+		user, err = q.UpdateUser(ctx, arg)
+		if err == sql.ErrNoRows {
+			return errVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionUpdate, audit.EntityUser, id, before, user)
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		if err == errVersionConflict {
+			respondError(ctx, http.StatusPreconditionFailed, err)
+			return
+		}
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	respondVersioned(ctx, http.StatusOK, user.Version, user)
 }
 
+// DeleteUser soft-deletes an account and signs it out everywhere. Admins can restore it until
+// the retention purger removes it, or anonymizes it if it has donations.
 func (c *Play4GoodController) DeleteUser(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	id := int32(id64)
+	if id != int32(ctx.GetInt("userID")) {
+		if err := c.requireAdmin(ctx); err != nil {
+			respondMessage(ctx, http.StatusForbidden, "Unauthorized access")
+			return
+		}
+	}
 
-	err = c.db.DeleteUser(ctx, id)
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		after, err := q.DeleteUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteUserTokensByUserID(ctx, sql.NullInt32{Int32: id, Valid: true}); err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionDelete, audit.EntityUser, id, before, after)
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if err == sql.ErrNoRows {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -388,113 +530,93 @@ func (c *Play4GoodController) DeleteUser(ctx *gin.Context) {
 
 // Team Controllers
 func (c *Play4GoodController) CreateTeam(ctx *gin.Context) {
-
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
 	// Parse the request payload
-	var payload *schemas.TeamCreateRequest
+	var payload schemas.TeamCreateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondMessage(ctx, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
-	// Create the team, associating it with the authenticated user (userID)
-	arg := db.CreateTeamParams{
-		Name:        payload.Name,
-		Description: sql.NullString{String: payload.Description, Valid: payload.Description != ""},
-		AvatarUrl:   sql.NullString{String: payload.AvatarURL, Valid: payload.AvatarURL != ""},
-	}
-
-	team, err := c.db.CreateTeam(ctx, arg)
+	team, err := c.teams.Create(c.requestContext(ctx), payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create team"})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, team)
+	respondVersioned(ctx, http.StatusOK, team.Version, team)
 }
 
 func (c *Play4GoodController) GetTeam(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
-	id := int32(id64)
-	team, err := c.db.GetTeam(ctx, id)
+	team, err := c.teams.Get(ctx.Request.Context(), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondLookupError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, team)
+	respondVersioned(ctx, http.StatusOK, team.Version, team)
 }
 
 func (c *Play4GoodController) UpdateTeam(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
-	id := int32(id64)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	var payload *schemas.TeamUpdateRequest
-
+	var payload schemas.TeamUpdateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.UpdateTeamParams{
-		ID:          id,
-		Name:        payload.Name,
-		Description: sql.NullString{String: payload.Description, Valid: payload.Description != ""},
-		AvatarUrl:   sql.NullString{String: payload.AvatarURL, Valid: payload.AvatarURL != ""},
+	match, ok := requireIfMatch(ctx)
+	if !ok {
+		return
 	}
 
-	team, err := c.db.UpdateTeam(ctx, arg)
+	team, err := c.teams.Update(c.requestContext(ctx), id, payload, ifMatch(match))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondPatchError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, team)
+	respondVersioned(ctx, http.StatusOK, team.Version, team)
 }
 
 func (c *Play4GoodController) DeleteTeam(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	id := int32(id64)
-
-	err = c.db.DeleteTeam(ctx, id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if err := c.teams.Delete(c.requestContext(ctx), id); err != nil {
+		respondLookupError(ctx, err)
 		return
 	}
 
@@ -505,133 +627,91 @@ func (c *Play4GoodController) DeleteTeam(ctx *gin.Context) {
 func (c *Play4GoodController) CreateCause(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	var payload *schemas.CauseCreateRequest
-	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if err := c.requireVerifiedEmail(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
 		return
 	}
-
-	arg := db.CreateCauseParams{
-		Name:        payload.Name,
-		Description: sql.NullString{String: payload.Description, Valid: payload.Description != ""},
-		Goal: sql.NullString{
-			String: strconv.FormatFloat(payload.Goal, 'f', -1, 64),
-			Valid:  true,
-		},
-		StartDate: sql.NullTime{
-			Time:  payload.StartDate,
-			Valid: !payload.StartDate.IsZero(),
-		},
-		EndDate: sql.NullTime{
-			Time:  payload.EndDate,
-			Valid: !payload.EndDate.IsZero(),
-		},
-		Status:   sql.NullString{String: payload.Status, Valid: payload.Status != ""},
-		Image:    sql.NullString{String: payload.Image, Valid: payload.Image != ""},
-		Category: sql.NullString{String: payload.Category, Valid: payload.Category != ""},
+	var payload schemas.CauseCreateRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
 	}
 
-	cause, err := c.db.CreateCause(ctx, arg)
+	cause, err := c.causes.Create(c.requestContext(ctx), int32(ctx.GetInt("userID")), payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, cause)
+	respondVersioned(ctx, http.StatusOK, cause.Version, cause)
 }
 
 func (c *Play4GoodController) GetCause(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
-	id := int32(id64)
-	cause, err := c.db.GetCause(ctx, id)
+	cause, err := c.causes.Get(ctx.Request.Context(), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondLookupError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, cause)
+	respondVersioned(ctx, http.StatusOK, cause.Version, cause)
 }
 func (c *Play4GoodController) UpdateCause(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
-	id := int32(id64)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	var payload *schemas.CauseUpdateRequest
-
+	var payload schemas.CauseUpdateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.UpdateCauseParams{
-		ID:          id,
-		Name:        payload.Name,
-		Description: sql.NullString{String: payload.Description, Valid: payload.Description != ""},
-		Goal: sql.NullString{
-			String: strconv.FormatFloat(payload.Goal, 'f', -1, 64),
-			Valid:  true,
-		},
-		StartDate: sql.NullTime{
-			Time:  payload.StartDate,
-			Valid: !payload.StartDate.IsZero(),
-		},
-		EndDate: sql.NullTime{
-			Time:  payload.EndDate,
-			Valid: !payload.EndDate.IsZero(),
-		},
-		Status:   sql.NullString{String: payload.Status, Valid: payload.Status != ""},
-		Image:    sql.NullString{String: payload.Image, Valid: payload.Image != ""},
-		Category: sql.NullString{String: payload.Category, Valid: payload.Category != ""},
+	match, ok := requireIfMatch(ctx)
+	if !ok {
+		return
 	}
 
-	cause, err := c.db.UpdateCause(ctx, arg)
+	cause, err := c.causes.Update(c.requestContext(ctx), id, payload, ifMatch(match))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondPatchError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, cause)
+	respondVersioned(ctx, http.StatusOK, cause.Version, cause)
 }
 func (c *Play4GoodController) DeleteCause(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	id := int32(id64)
-
-	err = c.db.DeleteCause(ctx, id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if err := c.causes.Delete(c.requestContext(ctx), id); err != nil {
+		respondLookupError(ctx, err)
 		return
 	}
 
@@ -642,38 +722,25 @@ func (c *Play4GoodController) DeleteCause(ctx *gin.Context) {
 func (c *Play4GoodController) CreateDonation(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	var payload *schemas.DonationCreateRequest
+	var payload schemas.DonationCreateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.CreateDonationParams{
-		UserID: sql.NullInt32{
-			Int32: int32(payload.UserID),
-			Valid: true,
-		},
-		CauseID: sql.NullInt32{
-			Int32: int32(payload.CauseID),
-			Valid: true,
-		},
-		TeamID: sql.NullInt32{
-			Int32: int32(payload.TeamID),
-			Valid: payload.TeamID != 0,
-		},
-		Amount: sql.NullString{
-			String: strconv.FormatFloat(payload.Amount, 'f', -1, 64),
-			Valid:  true,
-		},
-		DonationType: sql.NullString{String: payload.DonationType, Valid: payload.DonationType != ""},
+	if payload.Amount >= c.config.LargeDonationThreshold {
+		if err := c.requireVerifiedEmail(ctx); err != nil {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
 	}
 
-	donation, err := c.db.CreateDonation(ctx, arg)
+	donation, err := c.donations.Create(c.requestContext(ctx), payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -683,22 +750,17 @@ func (c *Play4GoodController) CreateDonation(ctx *gin.Context) {
 func (c *Play4GoodController) GetDonation(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
-	id := int32(id64)
-	donation, err := c.db.GetDonation(ctx, id)
+	donation, err := c.donations.Get(ctx.Request.Context(), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondLookupError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, donation)
@@ -708,24 +770,22 @@ func (c *Play4GoodController) GetDonation(ctx *gin.Context) {
 func (c *Play4GoodController) AddUserToTeam(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	var payload *schemas.UserTeamCreateRequest
+	var payload schemas.UserTeamCreateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
-
-	arg := db.AddUserToTeamParams{
-		UserID: int32(payload.UserID),
-		TeamID: int32(payload.TeamID),
-		Role:   payload.Role,
+	if err := checkAPIKeyTeamPayload(ctx, payload.TeamID); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
 	}
 
-	userTeam, err := c.db.AddUserToTeam(ctx, arg)
+	userTeam, err := c.teams.AddMember(c.requestContext(ctx), payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -736,36 +796,29 @@ func (c *Play4GoodController) AddUserToTeam(ctx *gin.Context) {
 func (c *Play4GoodController) UpdateUserTeamRole(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	userID, err := strconv.ParseInt(ctx.Param("userId"), 10, 32)
+	userID, err := paramID(ctx, "userId")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
-
-	teamID, err := strconv.ParseInt(ctx.Param("teamId"), 10, 32)
+	teamID, err := paramID(ctx, "teamId")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	var payload *schemas.UserTeamUpdateRequest
+	var payload schemas.UserTeamUpdateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.UpdateUserTeamRoleParams{
-		UserID: int32(userID),
-		TeamID: int32(teamID),
-		Role:   payload.Role,
-	}
-
-	userTeam, err := c.db.UpdateUserTeamRole(ctx, arg)
+	userTeam, err := c.teams.SetMemberRole(c.requestContext(ctx), teamID, userID, payload.Role)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -776,29 +829,22 @@ func (c *Play4GoodController) UpdateUserTeamRole(ctx *gin.Context) {
 func (c *Play4GoodController) RemoveUserFromTeam(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	userID, err := strconv.ParseInt(ctx.Param("userId"), 10, 32)
+	userID, err := paramID(ctx, "userId")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
-
-	teamID, err := strconv.ParseInt(ctx.Param("teamId"), 10, 32)
+	teamID, err := paramID(ctx, "teamId")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.RemoveUserFromTeamParams{
-		UserID: int32(userID),
-		TeamID: int32(teamID),
-	}
-
-	err = c.db.RemoveUserFromTeam(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if err := c.teams.RemoveMember(c.requestContext(ctx), teamID, userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -809,86 +855,58 @@ func (c *Play4GoodController) RemoveUserFromTeam(ctx *gin.Context) {
 func (c *Play4GoodController) CreateLeaderboard(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	var payload *schemas.LeaderboardCreateRequest
+	var payload schemas.LeaderboardCreateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.CreateLeaderboardParams{
-		Name: payload.Name,
-		Type: sql.NullString{String: payload.Type, Valid: payload.Type != ""},
-		StartDate: sql.NullTime{
-			Time:  payload.StartDate,
-			Valid: !payload.StartDate.IsZero(),
-		},
-		EndDate: sql.NullTime{
-			Time:  payload.EndDate,
-			Valid: !payload.EndDate.IsZero(),
-		},
-	}
-
-	leaderboard, err := c.db.CreateLeaderboard(ctx, arg)
+	leaderboard, err := c.leaderboards.Create(c.requestContext(ctx), payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, leaderboard)
+	respondVersioned(ctx, http.StatusOK, leaderboard.Version, leaderboard)
 }
 
 func (c *Play4GoodController) GetLeaderboard(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	id, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
-	id := int32(id64)
-	leaderboard, err := c.db.GetLeaderboard(ctx, id)
+	leaderboard, err := c.leaderboards.Get(ctx.Request.Context(), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondLookupError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, leaderboard)
+	respondVersioned(ctx, http.StatusOK, leaderboard.Version, leaderboard)
 }
 
 func (c *Play4GoodController) UpdateLeaderboardEntry(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
-	var payload *schemas.LeaderboardEntryUpdateRequest
+	var payload schemas.LeaderboardEntryUpdateRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.UpdateLeaderboardEntryParams{
-		LeaderboardID: int32(payload.LeaderboardID),
-		UserID:        int32(payload.UserID),
-		TeamID:        int32(payload.TeamID),
-		Score: sql.NullString{
-			String: strconv.FormatFloat(payload.Score, 'f', -1, 64),
-			Valid:  true,
-		},
-	}
-
-	entry, err := c.db.UpdateLeaderboardEntry(ctx, arg)
+	entry, err := c.leaderboards.SetEntry(c.requestContext(ctx), payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -899,12 +917,12 @@ func (c *Play4GoodController) UpdateLeaderboardEntry(ctx *gin.Context) {
 func (c *Play4GoodController) ListUsers(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 	var payload *schemas.ListUsersRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -914,7 +932,7 @@ func (c *Play4GoodController) ListUsers(ctx *gin.Context) {
 	}
 	users, err := c.db.ListUsers(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -925,24 +943,19 @@ func (c *Play4GoodController) ListUsers(ctx *gin.Context) {
 func (c *Play4GoodController) ListCauses(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
-	var payload *schemas.ListCausesRequest
+	var payload schemas.ListCausesRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.ListCausesParams{
-		Limit:  int32(payload.Limit),
-		Offset: int32(payload.Offset),
-	}
-
-	causes, err := c.db.ListCauses(ctx, arg)
+	causes, err := c.causes.List(ctx.Request.Context(), int32(payload.Limit), int32(payload.Offset))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -953,23 +966,19 @@ func (c *Play4GoodController) ListCauses(ctx *gin.Context) {
 func (c *Play4GoodController) ListDonations(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
-	var payload *schemas.ListDonationsRequest
+	var payload schemas.ListDonationsRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.ListDonationsParams{
-		Limit:  int32(payload.Limit),
-		Offset: int32(payload.Offset),
-	}
-	donations, err := c.db.ListDonations(ctx, arg)
+	donations, err := c.donations.List(ctx.Request.Context(), int32(payload.Limit), int32(payload.Offset))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -980,23 +989,19 @@ func (c *Play4GoodController) ListDonations(ctx *gin.Context) {
 func (c *Play4GoodController) ListTeams(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
-	var payload *schemas.ListTeamsRequest
+	var payload schemas.ListTeamsRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.ListTeamsParams{
-		Limit:  int32(payload.Limit),
-		Offset: int32(payload.Offset),
-	}
-	teams, err := c.db.ListTeams(ctx, arg)
+	teams, err := c.teams.List(ctx.Request.Context(), int32(payload.Limit), int32(payload.Offset))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -1007,24 +1012,19 @@ func (c *Play4GoodController) ListTeams(ctx *gin.Context) {
 func (c *Play4GoodController) ListLeaderboards(ctx *gin.Context) {
 	err := c.validateToken(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
-	var payload *schemas.ListLeaderBoardsRequest
+	var payload schemas.ListLeaderBoardsRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.ListLeaderboardsParams{
-		Limit:  int32(payload.Limit),
-		Offset: int32(payload.Offset),
-	}
-
-	leaderboards, err := c.db.ListLeaderboards(ctx, arg)
+	leaderboards, err := c.leaderboards.List(ctx.Request.Context(), int32(payload.Limit), int32(payload.Offset))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,424 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"play4good-backend/audit"
+	"play4good-backend/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSignUp(t *testing.T) {
+	s := newTestServer(t)
+
+	userID, token := s.signUp("alice")
+	if userID == 0 || token == "" {
+		t.Fatalf("sign-up returned user %d and token %q", userID, token)
+	}
+
+	// The session cookie authenticates straight away
+	s.expect(http.StatusOK, request{Method: http.MethodGet, Path: "/api/listTeams", Token: token, Body: gin.H{"limit": 10}})
+
+	sent := s.mailer.Sent()
+	if len(sent) != 1 || sent[0].To != "alice@play4good.test" {
+		t.Errorf("sent %+v, want one verification email to alice", sent)
+	}
+	if got := s.audited(audit.EntityUser); !equalActions(got, audit.ActionCreate) {
+		t.Errorf("user audit actions = %v", got)
+	}
+	if actor := s.store.AuditLog()[0].ActorID; actor.Int32 != userID {
+		t.Errorf("sign-up audited with actor %v, want the new user %d", actor, userID)
+	}
+}
+
+func TestSignUpRejectsTakenEmail(t *testing.T) {
+	s := newTestServer(t)
+	s.signUp("alice")
+
+	recorder := s.expect(http.StatusConflict, request{Method: http.MethodPost, Path: "/api/signup", Body: gin.H{
+		"username":   "alice2",
+		"email":      "alice@play4good.test",
+		"password":   "another password",
+		"first_name": "Alice",
+		"last_name":  "Again",
+	}})
+	if code := problemCode(t, recorder); code != problem.CodeEmailTaken {
+		t.Errorf("code = %q, want %q", code, problem.CodeEmailTaken)
+	}
+}
+
+func TestSignUpRejectsTakenUsername(t *testing.T) {
+	s := newTestServer(t)
+	s.signUp("alice")
+
+	recorder := s.expect(http.StatusConflict, request{Method: http.MethodPost, Path: "/api/signup", Body: gin.H{
+		"username":   "alice",
+		"email":      "alice.again@play4good.test",
+		"password":   "another password",
+		"first_name": "Alice",
+		"last_name":  "Again",
+	}})
+	if code := problemCode(t, recorder); code != problem.CodeAlreadyExists {
+		t.Errorf("code = %q, want %q", code, problem.CodeAlreadyExists)
+	}
+}
+
+func TestSignUpValidatesBody(t *testing.T) {
+	s := newTestServer(t)
+
+	s.expect(http.StatusBadRequest, request{Method: http.MethodPost, Path: "/api/signup", Body: gin.H{
+		"username": "al",
+		"email":    "not-an-email",
+		"password": "short",
+	}})
+}
+
+func TestLogin(t *testing.T) {
+	s := newTestServer(t)
+	userID, signUpToken := s.signUp("alice")
+
+	recorder := s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/login", Body: gin.H{
+		"email":    "alice@play4good.test",
+		"password": "correct horse",
+	}})
+	var login struct {
+		ID    int32  `json:"id"`
+		Token string `json:"token"`
+	}
+	decode(t, recorder, &login)
+	if login.ID != userID {
+		t.Errorf("logged in as %d, want %d", login.ID, userID)
+	}
+	// A session that is still valid is reused
+	if login.Token != signUpToken {
+		t.Errorf("login issued a new token while the sign-up session is valid")
+	}
+}
+
+func TestLoginRejectsInvalidCredentials(t *testing.T) {
+	s := newTestServer(t)
+	s.signUp("alice")
+
+	for _, body := range []gin.H{
+		{"email": "alice@play4good.test", "password": "wrong password"},
+		{"email": "nobody@play4good.test", "password": "correct horse"},
+	} {
+		recorder := s.expect(http.StatusUnauthorized, request{Method: http.MethodPost, Path: "/api/login", Body: body})
+		if code := problemCode(t, recorder); code != problem.CodeInvalidCredentials {
+			t.Errorf("login as %s: code = %q, want %q", body["email"], code, problem.CodeInvalidCredentials)
+		}
+	}
+}
+
+func TestRequestsNeedASession(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, token := range []string{"", "not-a-token"} {
+		s.expect(http.StatusUnauthorized, request{Method: http.MethodGet, Path: "/api/teams/1", Token: token})
+	}
+}
+
+func TestTeamLifecycle(t *testing.T) {
+	s := newTestServer(t)
+	_, token := s.signUp("alice")
+
+	recorder := s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/teams", Token: token, Body: gin.H{
+		"name":        "Green Runners",
+		"description": "We run for trees",
+	}})
+	var team struct {
+		ID          int32  `json:"id"`
+		Name        string `json:"name"`
+		Description struct {
+			String string `json:"String"`
+		} `json:"description"`
+	}
+	decode(t, recorder, &team)
+	etag := recorder.Header().Get("ETag")
+	path := fmt.Sprintf("/api/teams/%d", team.ID)
+
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodGet, Path: path, Token: token})
+	decode(t, recorder, &team)
+	if team.Name != "Green Runners" || recorder.Header().Get("ETag") != etag {
+		t.Errorf("GET %s = %+v with ETag %q, want the created team with ETag %q", path, team, recorder.Header().Get("ETag"), etag)
+	}
+
+	update := gin.H{"name": "Green Sprinters", "description": "Faster now"}
+	s.expect(http.StatusPreconditionRequired, request{Method: http.MethodPut, Path: path, Token: token, Body: update})
+	s.expect(http.StatusPreconditionFailed, request{Method: http.MethodPut, Path: path, Token: token, Body: update,
+		Header: map[string]string{"If-Match": `"999"`}})
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPut, Path: path, Token: token, Body: update,
+		Header: map[string]string{"If-Match": etag}})
+	decode(t, recorder, &team)
+	if team.Name != "Green Sprinters" {
+		t.Errorf("updated name = %q", team.Name)
+	}
+
+	// The old ETag no longer matches
+	s.expect(http.StatusPreconditionFailed, request{Method: http.MethodPatch, Path: path, Token: token,
+		Body: gin.H{"description": nil}, Header: map[string]string{"If-Match": etag}})
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPatch, Path: path, Token: token,
+		Body: gin.H{"description": nil}, Header: map[string]string{"If-Match": recorder.Header().Get("ETag")}})
+	decode(t, recorder, &team)
+	if team.Name != "Green Sprinters" || team.Description.String != "" {
+		t.Errorf("patched team = %+v, want the name kept and the description cleared", team)
+	}
+
+	s.expect(http.StatusOK, request{Method: http.MethodDelete, Path: path, Token: token})
+	s.expect(http.StatusNotFound, request{Method: http.MethodGet, Path: path, Token: token})
+	s.expect(http.StatusNotFound, request{Method: http.MethodDelete, Path: path, Token: token})
+
+	if got := s.audited(audit.EntityTeam); !equalActions(got, audit.ActionCreate, audit.ActionUpdate, audit.ActionUpdate, audit.ActionDelete) {
+		t.Errorf("team audit actions = %v", got)
+	}
+}
+
+func TestListTeams(t *testing.T) {
+	s := newTestServer(t)
+	_, token := s.signUp("alice")
+
+	for _, name := range []string{"Team One", "Team Two", "Team Three"} {
+		s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/teams", Token: token, Body: gin.H{"name": name}})
+	}
+
+	recorder := s.expect(http.StatusOK, request{Method: http.MethodGet, Path: "/api/listTeams", Token: token,
+		Body: gin.H{"limit": 2, "offset": 1}})
+	var teams []struct {
+		Name string `json:"name"`
+	}
+	decode(t, recorder, &teams)
+	if len(teams) != 2 || teams[0].Name != "Team Two" || teams[1].Name != "Team Three" {
+		t.Errorf("listTeams = %+v, want the second and third teams", teams)
+	}
+
+	s.expect(http.StatusBadRequest, request{Method: http.MethodGet, Path: "/api/listTeams", Token: token, Body: gin.H{}})
+}
+
+func TestTeamMembership(t *testing.T) {
+	s := newTestServer(t)
+	aliceID, token := s.signUp("alice")
+
+	recorder := s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/teams", Token: token, Body: gin.H{"name": "Green Runners"}})
+	var team struct {
+		ID int32 `json:"id"`
+	}
+	decode(t, recorder, &team)
+
+	s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/user-team", Token: token, Body: gin.H{
+		"user_id": aliceID,
+		"team_id": team.ID,
+		"role":    "member",
+	}})
+	s.expect(http.StatusBadRequest, request{Method: http.MethodPost, Path: "/api/user-team", Token: token, Body: gin.H{
+		"user_id": aliceID,
+		"team_id": team.ID,
+		"role":    "owner",
+	}})
+
+	path := fmt.Sprintf("/api/user-team/%d/%d", aliceID, team.ID)
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPut, Path: path, Token: token, Body: gin.H{"role": "admin"}})
+	var member struct {
+		Role string `json:"role"`
+	}
+	decode(t, recorder, &member)
+	if member.Role != "admin" {
+		t.Errorf("role = %q, want admin", member.Role)
+	}
+
+	s.expect(http.StatusOK, request{Method: http.MethodDelete, Path: path, Token: token})
+	if recorder := s.do(request{Method: http.MethodDelete, Path: path, Token: token}); recorder.Code == http.StatusOK {
+		t.Errorf("removing a membership twice succeeded")
+	}
+
+	if got := s.audited(audit.EntityTeamMember); !equalActions(got, audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete) {
+		t.Errorf("membership audit actions = %v", got)
+	}
+}
+
+func TestCauseLifecycle(t *testing.T) {
+	s := newTestServer(t)
+	aliceID, token := s.signUp("alice")
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cause := gin.H{
+		"name":       "Clean Rivers",
+		"goal":       5000,
+		"start_date": start,
+		"end_date":   start.AddDate(0, 6, 0),
+		"status":     "active",
+	}
+
+	// Only verified accounts may create causes
+	s.expect(http.StatusForbidden, request{Method: http.MethodPost, Path: "/api/causes", Token: token, Body: cause})
+	s.verify(aliceID)
+	recorder := s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/causes", Token: token, Body: cause})
+
+	var created struct {
+		ID      int32 `json:"id"`
+		OwnerID struct {
+			Int32 int32 `json:"Int32"`
+		} `json:"owner_id"`
+		CurrentAmount struct {
+			String string `json:"String"`
+		} `json:"current_amount"`
+	}
+	decode(t, recorder, &created)
+	if created.OwnerID.Int32 != aliceID {
+		t.Errorf("owner = %d, want %d", created.OwnerID.Int32, aliceID)
+	}
+	path := fmt.Sprintf("/api/causes/%d", created.ID)
+	etag := recorder.Header().Get("ETag")
+
+	// A patch may not move the end before the start
+	s.expect(http.StatusBadRequest, request{Method: http.MethodPatch, Path: path, Token: token,
+		Body: gin.H{"end_date": start.AddDate(0, -1, 0)}, Header: map[string]string{"If-Match": etag}})
+
+	cause["name"] = "Cleaner Rivers"
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPut, Path: path, Token: token, Body: cause,
+		Header: map[string]string{"If-Match": etag}})
+	var updated struct {
+		Name          string `json:"name"`
+		CurrentAmount struct {
+			String string `json:"String"`
+		} `json:"current_amount"`
+	}
+	decode(t, recorder, &updated)
+	if updated.Name != "Cleaner Rivers" || updated.CurrentAmount.String != created.CurrentAmount.String {
+		t.Errorf("updated cause = %+v, want the new name and the raised amount kept", updated)
+	}
+
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/listCauses", Token: token, Body: gin.H{"limit": 10}})
+	var causes []struct{}
+	decode(t, recorder, &causes)
+	if len(causes) != 1 {
+		t.Errorf("listCauses returned %d causes, want 1", len(causes))
+	}
+
+	s.expect(http.StatusOK, request{Method: http.MethodDelete, Path: path, Token: token})
+	s.expect(http.StatusNotFound, request{Method: http.MethodGet, Path: path, Token: token})
+
+	if got := s.audited(audit.EntityCause); !equalActions(got, audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete) {
+		t.Errorf("cause audit actions = %v", got)
+	}
+}
+
+func TestDonations(t *testing.T) {
+	s := newTestServer(t)
+	aliceID, token := s.signUp("alice")
+	s.verify(aliceID)
+
+	start := time.Now().Add(-time.Hour)
+	recorder := s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/causes", Token: token, Body: gin.H{
+		"name":       "Clean Rivers",
+		"goal":       5000,
+		"start_date": start,
+		"end_date":   start.AddDate(0, 1, 0),
+		"status":     "active",
+	}})
+	var cause struct {
+		ID int32 `json:"id"`
+	}
+	decode(t, recorder, &cause)
+
+	bobID, bobToken := s.signUp("bob")
+	donation := gin.H{"user_id": bobID, "cause_id": cause.ID, "amount": 25, "donation_type": "money"}
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/donations", Token: bobToken, Body: donation})
+	var created struct {
+		ID     int32 `json:"id"`
+		TeamID struct {
+			Valid bool `json:"Valid"`
+		} `json:"team_id"`
+	}
+	decode(t, recorder, &created)
+	if created.TeamID.Valid {
+		t.Errorf("a donation without a team was stored with one")
+	}
+
+	// Large donations need a verified email address
+	donation["amount"] = testLargeDonation
+	s.expect(http.StatusForbidden, request{Method: http.MethodPost, Path: "/api/donations", Token: bobToken, Body: donation})
+	s.verify(bobID)
+	s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/donations", Token: bobToken, Body: donation})
+
+	donation["donation_type"] = "crypto"
+	s.expect(http.StatusBadRequest, request{Method: http.MethodPost, Path: "/api/donations", Token: bobToken, Body: donation})
+
+	s.expect(http.StatusOK, request{Method: http.MethodGet, Path: fmt.Sprintf("/api/donations/%d", created.ID), Token: token})
+	s.expect(http.StatusNotFound, request{Method: http.MethodGet, Path: "/api/donations/9999", Token: token})
+	s.expect(http.StatusBadRequest, request{Method: http.MethodGet, Path: "/api/donations/abc", Token: token})
+
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodGet, Path: "/api/listDonations", Token: token, Body: gin.H{"limit": 10}})
+	var donations []struct{}
+	decode(t, recorder, &donations)
+	if len(donations) != 2 {
+		t.Errorf("listDonations returned %d donations, want 2", len(donations))
+	}
+
+	if got := s.audited(audit.EntityDonation); !equalActions(got, audit.ActionCreate, audit.ActionCreate) {
+		t.Errorf("donation audit actions = %v", got)
+	}
+}
+
+func TestLeaderboards(t *testing.T) {
+	s := newTestServer(t)
+	aliceID, token := s.signUp("alice")
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder := s.expect(http.StatusOK, request{Method: http.MethodPost, Path: "/api/leaderboards", Token: token, Body: gin.H{
+		"name":       "Spring Challenge",
+		"type":       "individual",
+		"start_date": start,
+		"end_date":   start.AddDate(0, 3, 0),
+	}})
+	var leaderboard struct {
+		ID   int32  `json:"id"`
+		Name string `json:"name"`
+	}
+	decode(t, recorder, &leaderboard)
+	path := fmt.Sprintf("/api/leaderboards/%d", leaderboard.ID)
+
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPatch, Path: path, Token: token,
+		Body: gin.H{"name": "Spring Sprint"}, Header: map[string]string{"If-Match": recorder.Header().Get("ETag")}})
+	decode(t, recorder, &leaderboard)
+	if leaderboard.Name != "Spring Sprint" {
+		t.Errorf("patched name = %q", leaderboard.Name)
+	}
+	s.expect(http.StatusOK, request{Method: http.MethodGet, Path: path, Token: token})
+	s.expect(http.StatusNotFound, request{Method: http.MethodGet, Path: "/api/leaderboards/9999", Token: token})
+
+	for _, score := range []float64{10, 42.5} {
+		recorder = s.expect(http.StatusOK, request{Method: http.MethodPut, Path: path + "/entries", Token: token, Body: gin.H{
+			"leaderboard_id": leaderboard.ID,
+			"user_id":        aliceID,
+			"score":          score,
+		}})
+	}
+	var entry struct {
+		Score struct {
+			String string `json:"String"`
+		} `json:"score"`
+	}
+	decode(t, recorder, &entry)
+	if entry.Score.String != "42.5" {
+		t.Errorf("score = %q, want 42.5", entry.Score.String)
+	}
+
+	recorder = s.expect(http.StatusOK, request{Method: http.MethodPut, Path: "/api/listLeaderBoards", Token: token, Body: gin.H{"limit": 10}})
+	var leaderboards []struct{}
+	decode(t, recorder, &leaderboards)
+	if len(leaderboards) != 1 {
+		t.Errorf("listLeaderBoards returned %d leaderboards, want 1", len(leaderboards))
+	}
+
+	if got := s.audited(audit.EntityLeaderboard); !equalActions(got, audit.ActionCreate, audit.ActionUpdate) {
+		t.Errorf("leaderboard audit actions = %v", got)
+	}
+	// The first score creates the entry and the second replaces it
+	if got := s.audited(audit.EntityLeaderboardEntry); !equalActions(got, audit.ActionCreate, audit.ActionUpdate) {
+		t.Errorf("entry audit actions = %v", got)
+	}
+}
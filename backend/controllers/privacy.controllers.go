@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/privacy"
+	"play4good-backend/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+type dataExportResponse struct {
+	ID          int32      `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+func newDataExportResponse(export db.DataExport) dataExportResponse {
+	res := dataExportResponse{
+		ID:        export.ID,
+		Status:    export.Status,
+		Error:     export.Error.String,
+		CreatedAt: export.CreatedAt,
+	}
+	if export.CompletedAt.Valid {
+		res.CompletedAt = &export.CompletedAt.Time
+	}
+	if export.ExpiresAt.Valid {
+		res.ExpiresAt = &export.ExpiresAt.Time
+	}
+	return res
+}
+
+func erasureResponse(user db.User) gin.H {
+	res := gin.H{"erasure_requested_at": nil, "erasure_scheduled_for": nil}
+	if user.ErasureRequestedAt.Valid {
+		res["erasure_requested_at"] = user.ErasureRequestedAt.Time
+	}
+	if user.ErasureScheduledFor.Valid {
+		res["erasure_scheduled_for"] = user.ErasureScheduledFor.Time
+	}
+	return res
+}
+
+// RequestDataExport queues a ZIP archive of the authenticated user's data
+func (c *Play4GoodController) RequestDataExport(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	export, err := c.db.CreateDataExport(ctx, int32(ctx.GetInt("userID")))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newDataExportResponse(export))
+}
+
+// ListDataExports shows the authenticated user's export jobs
+func (c *Play4GoodController) ListDataExports(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	exports, err := c.db.ListDataExportsByUser(ctx, int32(ctx.GetInt("userID")))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]dataExportResponse, len(exports))
+	for i, export := range exports {
+		res[i] = newDataExportResponse(export)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DownloadDataExport sends a finished export archive to its owner
+func (c *Play4GoodController) DownloadDataExport(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	export, err := c.db.GetDataExport(ctx, db.GetDataExportParams{
+		ID:     int32(id64),
+		UserID: int32(ctx.GetInt("userID")),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondMessage(ctx, http.StatusNotFound, "Export not found")
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if export.Status != privacy.ExportCompleted {
+		problem.Write(ctx, problem.New(http.StatusConflict, problem.CodeExportNotReady, "Export is not ready; its status is "+export.Status))
+		return
+	}
+	if export.ExpiresAt.Valid && export.ExpiresAt.Time.Before(time.Now()) {
+		problem.Write(ctx, problem.New(http.StatusGone, problem.CodeExportExpired, "Export has expired; request a new one"))
+		return
+	}
+
+	archive, err := c.db.GetDataExportArchive(ctx, export.ID)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="play4good-export.zip"`)
+	ctx.Data(http.StatusOK, "application/zip", archive)
+}
+
+// GetErasureStatus reports whether the authenticated user's account is scheduled for erasure
+func (c *Play4GoodController) GetErasureStatus(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	user, err := c.currentUser(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, erasureResponse(user))
+}
+
+// RequestErasure schedules the authenticated user's account to be anonymized after the grace period
+func (c *Play4GoodController) RequestErasure(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	userID := int32(ctx.GetInt("userID"))
+	var user db.User
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		user, err = q.ScheduleUserErasure(ctx, db.ScheduleUserErasureParams{
+			ID:                  userID,
+			ErasureScheduledFor: sql.NullTime{Time: time.Now().Add(c.privacy.GracePeriod()), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionUpdate, audit.EntityUser, userID, before, user)
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, erasureResponse(user))
+}
+
+// CancelErasure withdraws a pending erasure request during the grace period
+func (c *Play4GoodController) CancelErasure(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	userID := int32(ctx.GetInt("userID"))
+	var user db.User
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		user, err = q.CancelUserErasure(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionUpdate, audit.EntityUser, userID, before, user)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondMessage(ctx, http.StatusNotFound, "No erasure is scheduled for this account")
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, erasureResponse(user))
+}
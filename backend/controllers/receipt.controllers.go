@@ -0,0 +1,336 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/receipts"
+	"play4good-backend/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+// firstStatementYear is the earliest year a statement can be asked for
+const firstStatementYear = 2000
+
+// receiptResponse describes an issued receipt without its PDF
+type receiptResponse struct {
+	ID            int32      `json:"id"`
+	Number        string     `json:"number"`
+	Kind          string     `json:"kind"`
+	UserID        int32      `json:"user_id"`
+	DonationID    *int32     `json:"donation_id"`
+	Year          int32      `json:"year"`
+	Amount        string     `json:"amount"`
+	DonationCount int32      `json:"donation_count"`
+	Sha256        string     `json:"sha256"`
+	Replaces      *int32     `json:"replaces"`
+	IssuedAt      time.Time  `json:"issued_at"`
+	VoidedAt      *time.Time `json:"voided_at"`
+	VoidReason    string     `json:"void_reason,omitempty"`
+}
+
+func newReceiptResponse(r db.ListReceiptsByUserRow) receiptResponse {
+	res := receiptResponse{
+		ID:            r.ID,
+		Number:        r.Number,
+		Kind:          r.Kind,
+		UserID:        r.UserID,
+		Year:          r.Year,
+		Amount:        r.Amount,
+		DonationCount: r.DonationCount,
+		Sha256:        r.Sha256,
+		IssuedAt:      r.IssuedAt,
+		VoidReason:    r.VoidReason.String,
+	}
+	if r.DonationID.Valid {
+		res.DonationID = &r.DonationID.Int32
+	}
+	if r.Replaces.Valid {
+		res.Replaces = &r.Replaces.Int32
+	}
+	if r.VoidedAt.Valid {
+		res.VoidedAt = &r.VoidedAt.Time
+	}
+	return res
+}
+
+func receiptSummary(r db.Receipt) receiptResponse {
+	return newReceiptResponse(db.ListReceiptsByUserRow{
+		ID:            r.ID,
+		Number:        r.Number,
+		Kind:          r.Kind,
+		UserID:        r.UserID,
+		DonationID:    r.DonationID,
+		Year:          r.Year,
+		Amount:        r.Amount,
+		DonationCount: r.DonationCount,
+		Sha256:        r.Sha256,
+		Replaces:      r.Replaces,
+		IssuedAt:      r.IssuedAt,
+		VoidedAt:      r.VoidedAt,
+		VoidReason:    r.VoidReason,
+	})
+}
+
+// sendReceipt writes a receipt's PDF exactly as it was issued
+func sendReceipt(ctx *gin.Context, receipt db.Receipt) {
+	ctx.Header("Content-Disposition", `attachment; filename="`+receipt.Number+`.pdf"`)
+	ctx.Header("ETag", `"`+receipt.Sha256+`"`)
+	ctx.Data(http.StatusOK, "application/pdf", receipt.Pdf)
+}
+
+// DownloadDonationReceipt sends the receipt for a donation to its donor, the cause owner or an admin.
+// The first download issues the receipt; later ones get the same PDF.
+func (c *Play4GoodController) DownloadDonationReceipt(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	donation, err := c.donations.Get(ctx.Request.Context(), id)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+
+	userID := int32(ctx.GetInt("userID"))
+	if donation.UserID.Int32 != userID && c.requireAdmin(ctx) != nil {
+		cause, err := c.db.GetReceiptCause(ctx, donation.CauseID.Int32)
+		if err != nil || cause.OwnerID.Int32 != userID {
+			respondMessage(ctx, http.StatusForbidden, "Only the donor, the cause owner and admins can download this receipt")
+			return
+		}
+	}
+
+	receipt, err := c.receipts.DonationReceipt(c.requestContext(ctx), id)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	sendReceipt(ctx, receipt)
+}
+
+// VoidDonationReceipt voids a donation's receipt, for example after a refund
+func (c *Play4GoodController) VoidDonationReceipt(ctx *gin.Context) {
+	id, req, ok := c.receiptAdminRequest(ctx)
+	if !ok {
+		return
+	}
+	receipt, err := c.receipts.Void(c.requestContext(ctx), id, req.Reason)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, receiptSummary(receipt))
+}
+
+// ReissueDonationReceipt voids a donation's receipt and issues a replacement with a new number
+func (c *Play4GoodController) ReissueDonationReceipt(ctx *gin.Context) {
+	id, req, ok := c.receiptAdminRequest(ctx)
+	if !ok {
+		return
+	}
+	receipt, err := c.receipts.Reissue(c.requestContext(ctx), id, req.Reason)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, receiptSummary(receipt))
+}
+
+// receiptAdminRequest checks that an admin is voiding or re-issuing a donation's receipt and reads
+// the donation ID and reason, responding with an error otherwise
+func (c *Play4GoodController) receiptAdminRequest(ctx *gin.Context) (int32, schemas.ReceiptVoidRequest, bool) {
+	var req schemas.ReceiptVoidRequest
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return 0, req, false
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return 0, req, false
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return 0, req, false
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return 0, req, false
+	}
+	return id, req, true
+}
+
+// ListReceipts lists the receipts and statements issued to the authenticated user, voided ones included
+func (c *Play4GoodController) ListReceipts(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	rows, err := c.receipts.List(ctx.Request.Context(), int32(ctx.GetInt("userID")))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	res := make([]receiptResponse, len(rows))
+	for i, row := range rows {
+		res[i] = newReceiptResponse(row)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DownloadStatement sends the authenticated user's statement of their donations in a year
+func (c *Play4GoodController) DownloadStatement(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	c.sendStatement(ctx, int32(ctx.GetInt("userID")))
+}
+
+// DownloadUserStatement sends a donor's yearly statement to an admin
+func (c *Play4GoodController) DownloadUserStatement(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+	userID, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	c.sendStatement(ctx, userID)
+}
+
+func (c *Play4GoodController) sendStatement(ctx *gin.Context, userID int32) {
+	year, err := strconv.Atoi(ctx.Param("year"))
+	if err != nil || year < firstStatementYear || year > time.Now().Year() {
+		respondError(ctx, http.StatusBadRequest, problem.Invalid("year", "range", "must be a year from 2000 to this year"))
+		return
+	}
+	receipt, err := c.receipts.Statement(c.requestContext(ctx), userID, year)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	sendReceipt(ctx, receipt)
+}
+
+// GetReceiptTemplate returns the organization's receipt branding to admins
+func (c *Play4GoodController) GetReceiptTemplate(ctx *gin.Context) {
+	if _, ok := c.receiptTemplateAccess(ctx, false); !ok {
+		return
+	}
+	c.sendReceiptTemplate(ctx, 0)
+}
+
+// UpdateReceiptTemplate replaces the organization's receipt branding
+func (c *Play4GoodController) UpdateReceiptTemplate(ctx *gin.Context) {
+	if _, ok := c.receiptTemplateAccess(ctx, false); !ok {
+		return
+	}
+	c.saveReceiptTemplate(ctx, 0)
+}
+
+// GetCauseReceiptTemplate returns a cause's receipt branding to admins and the cause owner
+func (c *Play4GoodController) GetCauseReceiptTemplate(ctx *gin.Context) {
+	causeID, ok := c.receiptTemplateAccess(ctx, true)
+	if !ok {
+		return
+	}
+	c.sendReceiptTemplate(ctx, causeID)
+}
+
+// UpdateCauseReceiptTemplate replaces the branding on receipts for donations to a cause. Receipts
+// already issued keep the branding they were issued with until they are re-issued.
+func (c *Play4GoodController) UpdateCauseReceiptTemplate(ctx *gin.Context) {
+	causeID, ok := c.receiptTemplateAccess(ctx, true)
+	if !ok {
+		return
+	}
+	c.saveReceiptTemplate(ctx, causeID)
+}
+
+// receiptTemplateAccess checks who may manage a template: admins for the organization's, and admins
+// and the cause owner for a cause's. It returns the cause ID from the path, or 0.
+func (c *Play4GoodController) receiptTemplateAccess(ctx *gin.Context, forCause bool) (int32, bool) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return 0, false
+	}
+	if !forCause {
+		if err := c.requireAdmin(ctx); err != nil {
+			respondError(ctx, http.StatusForbidden, err)
+			return 0, false
+		}
+		return 0, true
+	}
+
+	causeID, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return 0, false
+	}
+	cause, err := c.db.GetCause(ctx, causeID)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return 0, false
+	}
+	if err := c.requireAdmin(ctx); err != nil && cause.OwnerID.Int32 != int32(ctx.GetInt("userID")) {
+		respondMessage(ctx, http.StatusForbidden, "Only admins and the cause owner can manage its receipt template")
+		return 0, false
+	}
+	return causeID, true
+}
+
+func (c *Play4GoodController) sendReceiptTemplate(ctx *gin.Context, causeID int32) {
+	template, err := c.receipts.Template(ctx.Request.Context(), causeID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, template)
+}
+
+func (c *Play4GoodController) saveReceiptTemplate(ctx *gin.Context, causeID int32) {
+	var req schemas.ReceiptTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	template, err := c.receipts.SaveTemplate(c.requestContext(ctx), causeID, receipts.Branding{
+		OrganizationName:   req.OrganizationName,
+		Address:            req.Address,
+		RegistrationNumber: req.RegistrationNumber,
+		ContactEmail:       req.ContactEmail,
+		AccentColor:        req.AccentColor,
+		HeaderText:         req.HeaderText,
+		FooterText:         req.FooterText,
+		Signatory:          req.Signatory,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, template)
+}
@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/reports"
+	"play4good-backend/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type reportExportResponse struct {
+	ID          int32          `json:"id"`
+	Dataset     string         `json:"dataset"`
+	Format      string         `json:"format"`
+	Columns     []string       `json:"columns"`
+	Filters     reports.Filter `json:"filters"`
+	Status      string         `json:"status"`
+	RowCount    int32          `json:"row_count"`
+	Error       string         `json:"error,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	CompletedAt *time.Time     `json:"completed_at"`
+	ExpiresAt   *time.Time     `json:"expires_at"`
+}
+
+func newReportExportResponse(export db.ReportExport) reportExportResponse {
+	spec, _ := reports.SpecOf(export)
+	res := reportExportResponse{
+		ID:        export.ID,
+		Dataset:   export.Dataset,
+		Format:    export.Format,
+		Columns:   export.Columns,
+		Filters:   spec.Filter,
+		Status:    export.Status,
+		RowCount:  export.RowCount,
+		Error:     export.Error.String,
+		CreatedAt: export.CreatedAt,
+	}
+	if export.CompletedAt.Valid {
+		res.CompletedAt = &export.CompletedAt.Time
+	}
+	if export.ExpiresAt.Valid {
+		res.ExpiresAt = &export.ExpiresAt.Time
+	}
+	return res
+}
+
+// authorizeReport lets admins export anything. Cause owners may export the donations and donors of
+// one of their own causes.
+func (c *Play4GoodController) authorizeReport(ctx *gin.Context, spec reports.Spec) error {
+	if c.requireAdmin(ctx) == nil {
+		return nil
+	}
+	forbidden := problem.New(http.StatusForbidden, problem.CodeForbidden, "Only admins can export this report; cause owners can export the donations and donors of their own causes by cause_id")
+	if spec.Dataset == reports.DatasetTeams || spec.Filter.CauseID == 0 {
+		return forbidden
+	}
+	cause, err := c.db.GetCause(ctx.Request.Context(), spec.Filter.CauseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return forbidden
+		}
+		return err
+	}
+	if cause.OwnerID.Int32 != int32(ctx.GetInt("userID")) {
+		return forbidden
+	}
+	return nil
+}
+
+// ExportReport streams a report of donations, donors or team stats as CSV, XLSX or JSON Lines.
+// Reports too large to download within a request should be queued with RequestReportExport.
+func (c *Play4GoodController) ExportReport(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var query schemas.ReportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	spec := reports.Spec{
+		Dataset: ctx.Param("dataset"),
+		Format:  query.Format,
+		Filter: reports.Filter{
+			From:    query.From,
+			To:      query.To,
+			CauseID: int32(query.CauseID),
+			TeamID:  int32(query.TeamID),
+			Status:  query.Status,
+		},
+	}
+	if query.Columns != "" {
+		for _, column := range strings.Split(query.Columns, ",") {
+			spec.Columns = append(spec.Columns, strings.TrimSpace(column))
+		}
+	}
+	if err := spec.Validate(); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := c.authorizeReport(ctx, spec); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Content-Type", reports.ContentType(spec.Format))
+	ctx.Header("Content-Disposition", `attachment; filename="`+spec.Filename(time.Now())+`"`)
+	rows, err := reports.Write(ctx.Request.Context(), c.db, ctx.Writer, spec)
+	if err != nil {
+		// Once rows have gone out the status is sent; all that is left is to cut the download short
+		if ctx.Writer.Written() {
+			slog.ErrorContext(ctx, "report export interrupted", "dataset", spec.Dataset, "rows", rows, "error", err)
+			ctx.Abort()
+			return
+		}
+		ctx.Writer.Header().Del("Content-Disposition")
+		respondError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// RequestReportExport queues a report to build in the background
+func (c *Play4GoodController) RequestReportExport(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var req schemas.ReportExportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	spec := reports.Spec{
+		Dataset: req.Dataset,
+		Format:  req.Format,
+		Columns: req.Columns,
+		Filter: reports.Filter{
+			From:    req.From,
+			To:      req.To,
+			CauseID: int32(req.CauseID),
+			TeamID:  int32(req.TeamID),
+			Status:  req.Status,
+		},
+	}
+	if err := spec.Validate(); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := c.authorizeReport(ctx, spec); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	export, err := c.reports.Request(ctx.Request.Context(), int32(ctx.GetInt("userID")), spec)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, newReportExportResponse(export))
+}
+
+// ListReportExports shows the authenticated user's recent report exports
+func (c *Play4GoodController) ListReportExports(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	exports, err := c.db.ListReportExportsByUser(ctx, db.ListReportExportsByUserParams{
+		RequestedBy: int32(ctx.GetInt("userID")),
+		Limit:       50,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]reportExportResponse, len(exports))
+	for i, export := range exports {
+		res[i] = newReportExportResponse(export)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetReportExport shows the status of one of the authenticated user's report exports
+func (c *Play4GoodController) GetReportExport(ctx *gin.Context) {
+	export, ok := c.ownReportExport(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, newReportExportResponse(export))
+}
+
+// DownloadReportExport sends a finished report to the user who requested it
+func (c *Play4GoodController) DownloadReportExport(ctx *gin.Context) {
+	export, ok := c.ownReportExport(ctx)
+	if !ok {
+		return
+	}
+	if export.Status != reports.ExportCompleted {
+		problem.Write(ctx, problem.New(http.StatusConflict, problem.CodeExportNotReady, "Export is not ready; its status is "+export.Status))
+		return
+	}
+
+	if export.ExpiresAt.Valid && export.ExpiresAt.Time.Before(time.Now()) {
+		problem.Write(ctx, problem.New(http.StatusGone, problem.CodeExportExpired, "Export has expired; request a new one"))
+		return
+	}
+
+	content, err := c.db.GetReportExportFile(ctx, export.ID)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	spec := reports.Spec{Dataset: export.Dataset, Format: export.Format}
+	ctx.Header("Content-Disposition", `attachment; filename="`+spec.Filename(export.CompletedAt.Time)+`"`)
+	ctx.Data(http.StatusOK, reports.ContentType(export.Format), content)
+}
+
+// ownReportExport loads the report export named in the path if it belongs to the authenticated user,
+// responding with an error otherwise
+func (c *Play4GoodController) ownReportExport(ctx *gin.Context) (db.ReportExport, bool) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return db.ReportExport{}, false
+	}
+	id, err := paramID(ctx, "id")
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return db.ReportExport{}, false
+	}
+
+	export, err := c.db.GetReportExport(ctx, db.GetReportExportParams{
+		ID:          id,
+		RequestedBy: int32(ctx.GetInt("userID")),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondMessage(ctx, http.StatusNotFound, "Export not found")
+			return db.ReportExport{}, false
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return db.ReportExport{}, false
+	}
+	return export, true
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// restore runs an admin-only restore of a soft-deleted entity and records it in the audit log
+func (c *Play4GoodController) restore(ctx *gin.Context, entityType string, restore func(q db.Querier, id int32) (interface{}, error)) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+	id64, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	id := int32(id64)
+	var restored interface{}
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		restored, err = restore(q, id)
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionRestore, entityType, id, nil, restored)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondMessage(ctx, http.StatusNotFound, "No deleted "+entityType+" with this id")
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, restored)
+}
+
+// RestoreUser brings back a soft-deleted account. Anonymized accounts cannot be restored.
+func (c *Play4GoodController) RestoreUser(ctx *gin.Context) {
+	c.restore(ctx, audit.EntityUser, func(q db.Querier, id int32) (interface{}, error) {
+		return q.RestoreUser(ctx, id)
+	})
+}
+
+// RestoreTeam brings back a soft-deleted team
+func (c *Play4GoodController) RestoreTeam(ctx *gin.Context) {
+	c.restore(ctx, audit.EntityTeam, func(q db.Querier, id int32) (interface{}, error) {
+		return q.RestoreTeam(ctx, id)
+	})
+}
+
+// RestoreCause brings back a soft-deleted cause
+func (c *Play4GoodController) RestoreCause(ctx *gin.Context) {
+	c.restore(ctx, audit.EntityCause, func(q db.Querier, id int32) (interface{}, error) {
+		return q.RestoreCause(ctx, id)
+	})
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/security"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const unlockAccountTokenTTL = 24 * time.Hour
+
+func loginThrottleKeys(ctx *gin.Context, email string) (string, string) {
+	return "account:" + strings.ToLower(strings.TrimSpace(email)), "ip:" + ctx.ClientIP()
+}
+
+// takeLoginAttempt counts a login attempt against the client IP and the account before the
+// password is checked. It responds with 429 and returns false while the account is locked or
+// either key is backing off after failed logins. locked reports whether this attempt locked the account.
+func (c *Play4GoodController) takeLoginAttempt(ctx *gin.Context, accountKey, ipKey string) (allowed, locked bool) {
+	wait, _, err := c.ipThrottle.Take(ctx, ipKey)
+	if err == nil && wait <= 0 {
+		wait, locked, err = c.accountThrottle.Take(ctx, accountKey)
+		if err != nil || wait > 0 {
+			c.releaseLoginAttempt(ctx, ipKey)
+		}
+	}
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Database error")
+		return false, false
+	}
+	if wait <= 0 {
+		return true, locked
+	}
+
+	setRetryAfter(ctx, wait)
+	if locked {
+		problem.Write(ctx, problem.New(http.StatusTooManyRequests, problem.CodeAccountLocked, "Account temporarily locked after too many failed logins; check your email to unlock it"))
+	} else {
+		respondMessage(ctx, http.StatusTooManyRequests, "Too many failed logins, please try again later")
+	}
+	return false, false
+}
+
+// setRetryAfter tells the client how long a throttle asked it to wait, in whole seconds
+func setRetryAfter(ctx *gin.Context, wait time.Duration) {
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
+
+// releaseLoginAttempt gives back the attempt counted against the client IP for a login that did
+// not fail on its credentials. The account's counter is cleared by a successful login instead.
+func (c *Play4GoodController) releaseLoginAttempt(ctx *gin.Context, ipKey string) {
+	if err := c.ipThrottle.Release(ctx, ipKey); err != nil {
+		slog.WarnContext(ctx.Request.Context(), "could not release login attempt", "key", ipKey, "error", err)
+	}
+}
+
+// recordLoginFailure logs a failed login against an existing account and emails an unlock link
+// when the attempt locked it. The attempt itself was already counted by takeLoginAttempt, keyed by
+// email whether or not an account exists, so responses do not reveal which emails are registered.
+func (c *Play4GoodController) recordLoginFailure(ctx *gin.Context, user *db.User, locked bool) {
+	if user == nil {
+		return
+	}
+
+	c.recordSecurityEvent(ctx, user.ID, security.EventLoginFailure)
+	if locked {
+		c.recordSecurityEvent(ctx, user.ID, security.EventAccountLocked)
+		if err := c.sendUnlockEmail(ctx, *user); err != nil {
+			slog.WarnContext(ctx.Request.Context(), "could not send unlock email", "user_id", user.ID, "error", err)
+		}
+	}
+}
+
+// recordSecurityEvent appends to the user's security log; failures are logged rather than failing the request
+func (c *Play4GoodController) recordSecurityEvent(ctx *gin.Context, userID int32, eventType string) {
+	err := c.db.CreateSecurityEvent(ctx, db.CreateSecurityEventParams{
+		UserID:    sql.NullInt32{Int32: userID, Valid: true},
+		EventType: eventType,
+		Ip:        sql.NullString{String: ctx.ClientIP(), Valid: ctx.ClientIP() != ""},
+		UserAgent: sql.NullString{String: ctx.Request.UserAgent(), Valid: ctx.Request.UserAgent() != ""},
+	})
+	if err != nil {
+		slog.WarnContext(ctx.Request.Context(), "could not record security event", "event_type", eventType, "user_id", userID, "error", err)
+	}
+}
+
+func (c *Play4GoodController) sendUnlockEmail(ctx *gin.Context, user db.User) error {
+	token, err := c.issueActionToken(ctx, user.ID, util.TokenPurposeUnlockAccount, unlockAccountTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/unlock-account?token=%s", c.config.FrontendURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\nYour account was temporarily locked after several failed sign-in attempts. If this was you, open the link below to unlock it now:\n\n%s\n\nIf it was not you, consider resetting your password.", user.Username, link)
+	return c.mailer.Send(user.Email, "Your Play4Good account has been locked", body)
+}
+
+// UnlockAccount clears a lockout using the link emailed when the account was locked
+func (c *Play4GoodController) UnlockAccount(ctx *gin.Context) {
+	var payload *schemas.UnlockAccountRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := c.consumeActionToken(ctx, payload.Token, util.TokenPurposeUnlockAccount)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := c.db.GetUser(ctx, userID)
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to fetch user data")
+		return
+	}
+
+	accountKey, _ := loginThrottleKeys(ctx, user.Email)
+	if err := c.accountThrottle.Succeed(ctx, accountKey); err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to unlock account")
+		return
+	}
+	c.recordSecurityEvent(ctx, user.ID, security.EventAccountUnlocked)
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Account unlocked"})
+}
+
+// ListSecurityEvents returns the authenticated user's recent sign-in and security activity
+func (c *Play4GoodController) ListSecurityEvents(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var query schemas.ListSecurityEventsRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	userID, _ := ctx.Get("userID")
+	events, err := c.db.ListSecurityEventsByUser(ctx, db.ListSecurityEventsByUserParams{
+		UserID: sql.NullInt32{Int32: int32(userID.(int)), Valid: true},
+		Limit:  int32(query.Limit),
+		Offset: int32(query.Offset),
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
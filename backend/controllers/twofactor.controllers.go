@@ -0,0 +1,446 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/security"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	totpIssuer        = "Play4Good"
+	loginChallengeTTL = 5 * time.Minute
+	recoveryCodeCount = 10
+	adminRole         = "admin"
+)
+
+// currentUser loads the user stored in the context by validateToken
+func (c *Play4GoodController) currentUser(ctx *gin.Context) (db.User, error) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return db.User{}, fmt.Errorf("authentication required")
+	}
+	return c.db.GetUser(ctx, int32(userID.(int)))
+}
+
+// requireAdmin checks that the authenticated user has the admin role
+func (c *Play4GoodController) requireAdmin(ctx *gin.Context) error {
+	user, err := c.currentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("authentication required")
+	}
+	if user.UserRole.String != adminRole {
+		return fmt.Errorf("admin access required")
+	}
+	return nil
+}
+
+// checkTOTPCode validates a code for the user and records its time step so it cannot be used again
+func (c *Play4GoodController) checkTOTPCode(ctx *gin.Context, totp db.UserTotp, code string) error {
+	step, ok := util.ValidateTOTP(totp.Secret, code, time.Now())
+	if !ok {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	updated, err := c.db.UpdateUserTOTPLastUsedStep(ctx, db.UpdateUserTOTPLastUsedStepParams{
+		UserID:       totp.UserID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("two-factor code already used")
+	}
+	return nil
+}
+
+// replaceRecoveryCodes discards the user's recovery codes and returns a fresh set, storing only their hashes
+func (c *Play4GoodController) replaceRecoveryCodes(ctx *gin.Context, userID int32) ([]string, error) {
+	codes, err := util.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.db.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		err := c.db.CreateRecoveryCode(ctx, db.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: util.HashToken(code),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+// GetTwoFactorStatus reports whether two-factor is enabled or required for the authenticated user
+func (c *Play4GoodController) GetTwoFactorStatus(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	status, err := c.db.GetTwoFactorStatus(ctx, int32(userID.(int)))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	remaining, err := c.db.CountUnusedRecoveryCodes(ctx, int32(userID.(int)))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"enabled":                  status.Enabled,
+		"required":                 status.Required,
+		"recovery_codes_remaining": remaining,
+	})
+}
+
+// EnrollTwoFactor generates a new TOTP secret and returns the provisioning URI to show as a QR code.
+// Two-factor stays off until the user confirms a code with EnableTwoFactor.
+func (c *Play4GoodController) EnrollTwoFactor(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	user, err := c.currentUser(ctx)
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to fetch user data")
+		return
+	}
+
+	existing, err := c.db.GetUserTOTP(ctx, user.ID)
+	if err == nil && existing.EnabledAt.Valid {
+		respondMessage(ctx, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to generate secret")
+		return
+	}
+
+	_, err = c.db.UpsertUserTOTP(ctx, db.UpsertUserTOTPParams{
+		UserID: user.ID,
+		Secret: secret,
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"secret":     secret,
+		"qr_payload": util.TOTPProvisioningURI(totpIssuer, user.Email, secret),
+	})
+}
+
+// EnableTwoFactor turns two-factor on once the user proves their app produces valid codes,
+// and returns the one-time recovery codes. They are only shown in this response.
+func (c *Play4GoodController) EnableTwoFactor(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var payload *schemas.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	totp, err := c.db.GetUserTOTP(ctx, int32(userID.(int)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondMessage(ctx, http.StatusBadRequest, "Start enrolment before enabling two-factor authentication")
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if totp.EnabledAt.Valid {
+		respondMessage(ctx, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+
+	step, ok := util.ValidateTOTP(totp.Secret, payload.Code, time.Now())
+	if !ok {
+		problem.Write(ctx, problem.New(http.StatusBadRequest, problem.CodeInvalidTwoFactorCode, "Invalid two-factor code"))
+		return
+	}
+
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		enabled, err := q.EnableUserTOTP(ctx, db.EnableUserTOTPParams{
+			UserID:       totp.UserID,
+			LastUsedStep: step,
+		})
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionCreate, audit.EntityTwoFactor, totp.UserID, nil, enabled)
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	codes, err := c.replaceRecoveryCodes(ctx, totp.UserID)
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to generate recovery codes")
+		return
+	}
+	c.recordSecurityEvent(ctx, totp.UserID, security.EventTwoFactorEnabled)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor turns two-factor off after re-checking the password and a current code
+func (c *Play4GoodController) DisableTwoFactor(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var payload *schemas.TwoFactorDisableRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := c.currentUser(ctx)
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to fetch user data")
+		return
+	}
+
+	status, err := c.db.GetTwoFactorStatus(ctx, user.ID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if status.Required {
+		problem.Write(ctx, problem.New(http.StatusForbidden, problem.CodeTwoFactorRequired, "Two-factor authentication is required for your role"))
+		return
+	}
+
+	if err := util.CheckPassword(payload.Password, user.PasswordHash); err != nil {
+		problem.Write(ctx, problem.New(http.StatusUnauthorized, problem.CodeInvalidCredentials, "Invalid credentials"))
+		return
+	}
+
+	totp, err := c.db.GetUserTOTP(ctx, user.ID)
+	if err != nil || !totp.EnabledAt.Valid {
+		respondMessage(ctx, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		return
+	}
+
+	if err := c.checkTOTPCode(ctx, totp, payload.Code); err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		if err := q.DeleteUserTOTP(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := q.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionDelete, audit.EntityTwoFactor, user.ID, totp, nil)
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.recordSecurityEvent(ctx, user.ID, security.EventTwoFactorDisabled)
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a current code
+func (c *Play4GoodController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	var payload *schemas.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	totp, err := c.db.GetUserTOTP(ctx, int32(userID.(int)))
+	if err != nil || !totp.EnabledAt.Valid {
+		respondMessage(ctx, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		return
+	}
+
+	if err := c.checkTOTPCode(ctx, totp, payload.Code); err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	codes, err := c.replaceRecoveryCodes(ctx, totp.UserID)
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Failed to generate recovery codes")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// VerifyLoginTwoFactor completes a login started by LoginUser for an account with two-factor enabled
+func (c *Play4GoodController) VerifyLoginTwoFactor(ctx *gin.Context) {
+	var payload *schemas.LoginTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := util.ParseActionToken(payload.ChallengeToken, util.TokenPurposeLoginChallenge)
+	if err != nil {
+		problem.Write(ctx, problem.New(http.StatusUnauthorized, problem.CodeLoginChallengeExpired, "Login challenge expired, please sign in again"))
+		return
+	}
+
+	if wait, _, err := c.twoFactorThrottle.Take(ctx, fmt.Sprintf("2fa:%d", userID)); err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Database error")
+		return
+	} else if wait > 0 {
+		setRetryAfter(ctx, wait)
+		respondMessage(ctx, http.StatusTooManyRequests, "Too many attempts, please try again later")
+		return
+	}
+
+	totp, err := c.db.GetUserTOTP(ctx, int32(userID))
+	if err != nil || !totp.EnabledAt.Valid {
+		problem.Write(ctx, problem.New(http.StatusUnauthorized, problem.CodeInvalidCredentials, "Invalid credentials"))
+		return
+	}
+
+	if payload.Code != "" {
+		if err := c.checkTOTPCode(ctx, totp, payload.Code); err != nil {
+			c.recordSecurityEvent(ctx, totp.UserID, security.EventTwoFactorFailure)
+			respondError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+	} else {
+		used, err := c.db.ConsumeRecoveryCode(ctx, db.ConsumeRecoveryCodeParams{
+			UserID:   totp.UserID,
+			CodeHash: util.HashToken(util.NormalizeRecoveryCode(payload.RecoveryCode)),
+		})
+		if err != nil {
+			respondError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		if used == 0 {
+			c.recordSecurityEvent(ctx, totp.UserID, security.EventTwoFactorFailure)
+			problem.Write(ctx, problem.New(http.StatusUnauthorized, problem.CodeInvalidTwoFactorCode, "Invalid recovery code"))
+			return
+		}
+	}
+
+	// The challenge is single use: consume it only once the second factor checks out
+	if _, err := c.consumeActionToken(ctx, payload.ChallengeToken, util.TokenPurposeLoginChallenge); err != nil {
+		problem.Write(ctx, problem.New(http.StatusUnauthorized, problem.CodeLoginChallengeExpired, "Login challenge expired, please sign in again"))
+		return
+	}
+
+	user, err := c.db.GetUser(ctx, totp.UserID)
+	if err != nil {
+		respondMessage(ctx, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	c.completeLogin(ctx, user, false)
+}
+
+// ListRolePolicies returns the security policy for every role that has one
+func (c *Play4GoodController) ListRolePolicies(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	policies, err := c.db.ListRolePolicies(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policies)
+}
+
+// UpdateRolePolicy lets admins require two-factor authentication for a user_role
+func (c *Play4GoodController) UpdateRolePolicy(ctx *gin.Context) {
+	err := c.validateToken(ctx)
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+	if err := c.requireAdmin(ctx); err != nil {
+		respondError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	var payload *schemas.RolePolicyUpdateRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var policy db.RolePolicy
+	err = c.db.ExecTx(ctx, func(q db.Querier) error {
+		policy, err = q.UpsertRolePolicy(ctx, db.UpsertRolePolicyParams{
+			UserRole:   ctx.Param("role"),
+			Require2fa: *payload.RequireTwoFactor,
+		})
+		if err != nil {
+			return err
+		}
+		return c.audit(ctx, q, audit.ActionUpdate, audit.EntityRolePolicy, policy.UserRole, nil, policy)
+	})
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
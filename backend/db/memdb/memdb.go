@@ -0,0 +1,744 @@
+// Package memdb is an in-memory db.Repository for unit tests. It implements the queries behind
+// sign-up, login, sessions, linked identities, API keys, teams, causes, donations and leaderboards with the same soft-delete,
+// uniqueness and version rules as the SQL. Any other query panics, which points a test at the one
+// it needs to add.
+package memdb
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the Postgres error code for a duplicate key
+const uniqueViolation = "23505"
+
+type memberKey struct{ teamID, userID int32 }
+
+type entryKey struct{ leaderboardID, userID, teamID int32 }
+
+type tables struct {
+	nextID         int32
+	users          map[int32]db.User
+	tokens         []db.UserToken
+	actionTokens   []db.UserActionToken
+	securityEvents []db.CreateSecurityEventParams
+	auditLog       []db.AuditLog
+	identities     []db.UserIdentity
+	apiKeys        map[int32]db.ApiKey
+	teams          map[int32]db.Team
+	members        map[memberKey]db.UserTeam
+	causes         map[int32]db.Cause
+	donations      map[int32]db.Donation
+	leaderboards   map[int32]db.Leaderboard
+	entries        map[entryKey]db.LeaderboardEntry
+}
+
+// Store keeps every table in maps. It is safe for concurrent use; transactions run one at a time
+// and roll back by restoring a copy of the tables.
+type Store struct {
+	// Nil: queries without an in-memory version panic
+	db.Querier
+
+	tx sync.Mutex
+	mu sync.Mutex
+	t  tables
+}
+
+var _ db.Repository = (*Store)(nil)
+
+func New() *Store {
+	return &Store{t: tables{
+		users:        map[int32]db.User{},
+		apiKeys:      map[int32]db.ApiKey{},
+		teams:        map[int32]db.Team{},
+		members:      map[memberKey]db.UserTeam{},
+		causes:       map[int32]db.Cause{},
+		donations:    map[int32]db.Donation{},
+		leaderboards: map[int32]db.Leaderboard{},
+		entries:      map[entryKey]db.LeaderboardEntry{},
+	}}
+}
+
+// ExecTx runs fn against the store, undoing its writes if it returns an error
+func (s *Store) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
+	s.tx.Lock()
+	defer s.tx.Unlock()
+
+	s.mu.Lock()
+	saved := s.t.clone()
+	s.mu.Unlock()
+
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		s.t = saved
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// AuditLog returns every audit entry recorded so far, oldest first
+func (s *Store) AuditLog() []db.AuditLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]db.AuditLog(nil), s.t.auditLog...)
+}
+
+func (t tables) clone() tables {
+	c := t
+	c.users = cloneMap(t.users)
+	c.tokens = append([]db.UserToken(nil), t.tokens...)
+	c.actionTokens = append([]db.UserActionToken(nil), t.actionTokens...)
+	c.securityEvents = append([]db.CreateSecurityEventParams(nil), t.securityEvents...)
+	c.auditLog = append([]db.AuditLog(nil), t.auditLog...)
+	c.identities = append([]db.UserIdentity(nil), t.identities...)
+	c.apiKeys = cloneMap(t.apiKeys)
+	c.teams = cloneMap(t.teams)
+	c.members = cloneMap(t.members)
+	c.causes = cloneMap(t.causes)
+	c.donations = cloneMap(t.donations)
+	c.leaderboards = cloneMap(t.leaderboards)
+	c.entries = cloneMap(t.entries)
+	return c
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func (s *Store) id() int32 {
+	s.t.nextID++
+	return s.t.nextID
+}
+
+func now() sql.NullTime {
+	return sql.NullTime{Time: time.Now(), Valid: true}
+}
+
+// page returns rows sorted by id, limited and offset like the List queries
+func page[T any](rows map[int32]T, keep func(T) bool, limit, offset int32) []T {
+	ids := make([]int32, 0, len(rows))
+	for id, row := range rows {
+		if keep(row) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	out := []T{}
+	for i := int(offset); i < len(ids) && len(out) < int(limit); i++ {
+		out = append(out, rows[ids[i]])
+	}
+	return out
+}
+
+func duplicate(constraint string) error {
+	return &pq.Error{Code: uniqueViolation, Constraint: constraint}
+}
+
+// Users and sessions
+
+func (s *Store) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.t.users {
+		if u.Email == arg.Email {
+			return db.User{}, duplicate("users_email_key")
+		}
+		if u.Username == arg.Username {
+			return db.User{}, duplicate("users_username_key")
+		}
+	}
+	user := db.User{
+		ID:           s.id(),
+		Username:     arg.Username,
+		Email:        arg.Email,
+		PasswordHash: arg.PasswordHash,
+		FirstName:    arg.FirstName,
+		LastName:     arg.LastName,
+		AvatarUrl:    arg.AvatarUrl,
+		UserRole:     arg.UserRole,
+		CreatedAt:    now(),
+		UpdatedAt:    now(),
+		Version:      1,
+	}
+	s.t.users[user.ID] = user
+	return user, nil
+}
+
+func (s *Store) GetUser(ctx context.Context, id int32) (db.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.t.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return db.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.t.users {
+		if user.Email == email && !user.DeletedAt.Valid {
+			return user, nil
+		}
+	}
+	return db.User{}, sql.ErrNoRows
+}
+
+func (s *Store) MarkUserEmailVerified(ctx context.Context, id int32) (db.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.t.users[id]
+	if !ok {
+		return db.User{}, sql.ErrNoRows
+	}
+	if !user.EmailVerifiedAt.Valid {
+		user.EmailVerifiedAt = now()
+	}
+	user.Version++
+	s.t.users[id] = user
+	return user, nil
+}
+
+// GetTwoFactorStatus reports two-factor as neither required nor enabled; the fake has no role
+// policies or authenticators
+func (s *Store) GetTwoFactorStatus(ctx context.Context, id int32) (db.GetTwoFactorStatusRow, error) {
+	if _, err := s.GetUser(ctx, id); err != nil {
+		return db.GetTwoFactorStatusRow{}, err
+	}
+	return db.GetTwoFactorStatusRow{}, nil
+}
+
+func (s *Store) CreateUserToken(ctx context.Context, arg db.CreateUserTokenParams) (db.UserToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := db.UserToken{
+		ID:        s.id(),
+		UserID:    arg.UserID,
+		Token:     arg.Token,
+		Expiry:    arg.Expiry,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	s.t.tokens = append(s.t.tokens, token)
+	return token, nil
+}
+
+func (s *Store) GetUserTokenByUserID(ctx context.Context, userID sql.NullInt32) (db.UserToken, error) {
+	return s.latestToken(func(t db.UserToken) bool { return t.UserID == userID })
+}
+
+func (s *Store) GetUserTokenByToken(ctx context.Context, token string) (db.UserToken, error) {
+	return s.latestToken(func(t db.UserToken) bool { return t.Token == token })
+}
+
+func (s *Store) latestToken(match func(db.UserToken) bool) (db.UserToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.t.tokens) - 1; i >= 0; i-- {
+		if match(s.t.tokens[i]) {
+			return s.t.tokens[i], nil
+		}
+	}
+	return db.UserToken{}, sql.ErrNoRows
+}
+
+func (s *Store) InvalidateUserActionTokens(ctx context.Context, arg db.InvalidateUserActionTokensParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, t := range s.t.actionTokens {
+		if t.UserID == arg.UserID && t.Purpose == arg.Purpose && !t.UsedAt.Valid {
+			s.t.actionTokens[i].UsedAt = now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateUserActionToken(ctx context.Context, arg db.CreateUserActionTokenParams) (db.UserActionToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := db.UserActionToken{
+		ID:        s.id(),
+		UserID:    arg.UserID,
+		Purpose:   arg.Purpose,
+		TokenHash: arg.TokenHash,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	s.t.actionTokens = append(s.t.actionTokens, token)
+	return token, nil
+}
+
+func (s *Store) CreateSecurityEvent(ctx context.Context, arg db.CreateSecurityEventParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.t.securityEvents = append(s.t.securityEvents, arg)
+	return nil
+}
+
+func (s *Store) CreateAuditLogEntry(ctx context.Context, arg db.CreateAuditLogEntryParams) (db.AuditLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := db.AuditLog{
+		ID:         int64(s.id()),
+		ActorID:    arg.ActorID,
+		Action:     arg.Action,
+		EntityType: arg.EntityType,
+		EntityID:   arg.EntityID,
+		Before:     arg.Before,
+		After:      arg.After,
+		Diff:       arg.Diff,
+		RequestID:  arg.RequestID,
+		Ip:         arg.Ip,
+		CreatedAt:  time.Now(),
+	}
+	s.t.auditLog = append(s.t.auditLog, entry)
+	return entry, nil
+}
+
+// Linked identities
+
+func (s *Store) GetUserIdentity(ctx context.Context, arg db.GetUserIdentityParams) (db.UserIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, identity := range s.t.identities {
+		if identity.Provider == arg.Provider && identity.Subject == arg.Subject {
+			return identity, nil
+		}
+	}
+	return db.UserIdentity{}, sql.ErrNoRows
+}
+
+func (s *Store) CreateUserIdentity(ctx context.Context, arg db.CreateUserIdentityParams) (db.UserIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, identity := range s.t.identities {
+		if identity.Provider == arg.Provider && identity.Subject == arg.Subject {
+			return db.UserIdentity{}, duplicate("user_identities_provider_subject_key")
+		}
+	}
+	identity := db.UserIdentity{
+		ID:          s.id(),
+		UserID:      arg.UserID,
+		Provider:    arg.Provider,
+		Subject:     arg.Subject,
+		Email:       arg.Email,
+		CreatedAt:   time.Now(),
+		LastLoginAt: time.Now(),
+	}
+	s.t.identities = append(s.t.identities, identity)
+	return identity, nil
+}
+
+// API keys
+
+func (s *Store) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := db.ApiKey{
+		ID:        s.id(),
+		UserID:    arg.UserID,
+		TeamID:    arg.TeamID,
+		Name:      arg.Name,
+		Prefix:    arg.Prefix,
+		KeyHash:   arg.KeyHash,
+		Scopes:    arg.Scopes,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	s.t.apiKeys[key.ID] = key
+	return key, nil
+}
+
+// GetActiveAPIKeyByHash skips revoked keys and keys of deleted users or teams, like the SQL
+func (s *Store) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (db.ApiKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range s.t.apiKeys {
+		if key.KeyHash != keyHash || key.RevokedAt.Valid {
+			continue
+		}
+		if user, ok := s.t.users[key.UserID]; !ok || user.DeletedAt.Valid {
+			continue
+		}
+		if team, ok := s.t.teams[key.TeamID.Int32]; key.TeamID.Valid && ok && team.DeletedAt.Valid {
+			continue
+		}
+		return key, nil
+	}
+	return db.ApiKey{}, sql.ErrNoRows
+}
+
+func (s *Store) TouchAPIKey(ctx context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.t.apiKeys[id]; ok {
+		key.LastUsedAt = now()
+		s.t.apiKeys[id] = key
+	}
+	return nil
+}
+
+// Teams
+
+func (s *Store) CreateTeam(ctx context.Context, arg db.CreateTeamParams) (db.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team := db.Team{
+		ID:          s.id(),
+		Name:        arg.Name,
+		Description: arg.Description,
+		AvatarUrl:   arg.AvatarUrl,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+		Version:     1,
+	}
+	s.t.teams[team.ID] = team
+	return team, nil
+}
+
+func (s *Store) GetTeam(ctx context.Context, id int32) (db.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, ok := s.t.teams[id]
+	if !ok || team.DeletedAt.Valid {
+		return db.Team{}, sql.ErrNoRows
+	}
+	return team, nil
+}
+
+func (s *Store) ListTeams(ctx context.Context, arg db.ListTeamsParams) ([]db.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return page(s.t.teams, func(t db.Team) bool { return !t.DeletedAt.Valid }, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) UpdateTeam(ctx context.Context, arg db.UpdateTeamParams) (db.Team, error) {
+	return s.updateTeam(arg.ID, arg.Version, func(team *db.Team) {
+		team.Name, team.Description, team.AvatarUrl = arg.Name, arg.Description, arg.AvatarUrl
+	})
+}
+
+func (s *Store) PatchTeam(ctx context.Context, arg db.PatchTeamParams) (db.Team, error) {
+	return s.updateTeam(arg.ID, arg.Version, func(team *db.Team) {
+		if arg.SetName {
+			team.Name = arg.Name
+		}
+		if arg.SetDescription {
+			team.Description = arg.Description
+		}
+		if arg.SetAvatarUrl {
+			team.AvatarUrl = arg.AvatarUrl
+		}
+	})
+}
+
+// updateTeam applies change to a live team at version, bumping the version like the SQL
+func (s *Store) updateTeam(id, version int32, change func(*db.Team)) (db.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, ok := s.t.teams[id]
+	if !ok || team.DeletedAt.Valid || team.Version != version {
+		return db.Team{}, sql.ErrNoRows
+	}
+	change(&team)
+	team.UpdatedAt = now()
+	team.Version++
+	s.t.teams[id] = team
+	return team, nil
+}
+
+func (s *Store) DeleteTeam(ctx context.Context, id int32) (db.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, ok := s.t.teams[id]
+	if !ok || team.DeletedAt.Valid {
+		return db.Team{}, sql.ErrNoRows
+	}
+	team.DeletedAt = now()
+	team.Version++
+	s.t.teams[id] = team
+	return team, nil
+}
+
+func (s *Store) AddUserToTeam(ctx context.Context, arg db.AddUserToTeamParams) (db.UserTeam, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := memberKey{arg.TeamID, arg.UserID}
+	if _, ok := s.t.members[key]; ok {
+		return db.UserTeam{}, duplicate("user_team_pkey")
+	}
+	member := db.UserTeam{UserID: arg.UserID, TeamID: arg.TeamID, Role: arg.Role, JoinedAt: time.Now()}
+	s.t.members[key] = member
+	return member, nil
+}
+
+func (s *Store) GetUserTeam(ctx context.Context, arg db.GetUserTeamParams) (db.UserTeam, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	member, ok := s.t.members[memberKey{arg.TeamID, arg.UserID}]
+	if !ok {
+		return db.UserTeam{}, sql.ErrNoRows
+	}
+	return member, nil
+}
+
+func (s *Store) UpdateUserTeamRole(ctx context.Context, arg db.UpdateUserTeamRoleParams) (db.UserTeam, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := memberKey{arg.TeamID, arg.UserID}
+	member, ok := s.t.members[key]
+	if !ok {
+		return db.UserTeam{}, sql.ErrNoRows
+	}
+	member.Role = arg.Role
+	s.t.members[key] = member
+	return member, nil
+}
+
+func (s *Store) RemoveUserFromTeam(ctx context.Context, arg db.RemoveUserFromTeamParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.t.members, memberKey{arg.TeamID, arg.UserID})
+	return nil
+}
+
+// Causes
+
+func (s *Store) CreateCause(ctx context.Context, arg db.CreateCauseParams) (db.Cause, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cause := db.Cause{
+		ID:            s.id(),
+		Name:          arg.Name,
+		Description:   arg.Description,
+		Goal:          arg.Goal,
+		CurrentAmount: sql.NullString{String: "0", Valid: true},
+		StartDate:     arg.StartDate,
+		EndDate:       arg.EndDate,
+		Status:        arg.Status,
+		CreatedAt:     now(),
+		UpdatedAt:     now(),
+		Image:         arg.Image,
+		Category:      arg.Category,
+		OwnerID:       arg.OwnerID,
+		Version:       1,
+	}
+	s.t.causes[cause.ID] = cause
+	return cause, nil
+}
+
+func (s *Store) GetCause(ctx context.Context, id int32) (db.Cause, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cause, ok := s.t.causes[id]
+	if !ok || cause.DeletedAt.Valid {
+		return db.Cause{}, sql.ErrNoRows
+	}
+	return cause, nil
+}
+
+func (s *Store) ListCauses(ctx context.Context, arg db.ListCausesParams) ([]db.Cause, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return page(s.t.causes, func(c db.Cause) bool { return !c.DeletedAt.Valid }, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) UpdateCause(ctx context.Context, arg db.UpdateCauseParams) (db.Cause, error) {
+	return s.updateCause(arg.ID, arg.Version, func(cause *db.Cause) {
+		cause.Name = arg.Name
+		cause.Description = arg.Description
+		cause.Goal = arg.Goal
+		cause.CurrentAmount = arg.CurrentAmount
+		cause.StartDate = arg.StartDate
+		cause.EndDate = arg.EndDate
+		cause.Status = arg.Status
+		cause.Image = arg.Image
+		cause.Category = arg.Category
+	})
+}
+
+func (s *Store) PatchCause(ctx context.Context, arg db.PatchCauseParams) (db.Cause, error) {
+	return s.updateCause(arg.ID, arg.Version, func(cause *db.Cause) {
+		if arg.SetName {
+			cause.Name = arg.Name
+		}
+		if arg.SetDescription {
+			cause.Description = arg.Description
+		}
+		if arg.SetGoal {
+			cause.Goal = arg.Goal
+		}
+		if arg.SetStartDate {
+			cause.StartDate = arg.StartDate
+		}
+		if arg.SetEndDate {
+			cause.EndDate = arg.EndDate
+		}
+		if arg.SetStatus {
+			cause.Status = arg.Status
+		}
+		if arg.SetImage {
+			cause.Image = arg.Image
+		}
+		if arg.SetCategory {
+			cause.Category = arg.Category
+		}
+	})
+}
+
+func (s *Store) updateCause(id, version int32, change func(*db.Cause)) (db.Cause, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cause, ok := s.t.causes[id]
+	if !ok || cause.DeletedAt.Valid || cause.Version != version {
+		return db.Cause{}, sql.ErrNoRows
+	}
+	change(&cause)
+	cause.UpdatedAt = now()
+	cause.Version++
+	s.t.causes[id] = cause
+	return cause, nil
+}
+
+func (s *Store) DeleteCause(ctx context.Context, id int32) (db.Cause, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cause, ok := s.t.causes[id]
+	if !ok || cause.DeletedAt.Valid {
+		return db.Cause{}, sql.ErrNoRows
+	}
+	cause.DeletedAt = now()
+	cause.Version++
+	s.t.causes[id] = cause
+	return cause, nil
+}
+
+// Donations
+
+func (s *Store) CreateDonation(ctx context.Context, arg db.CreateDonationParams) (db.Donation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	donation := db.Donation{
+		ID:           s.id(),
+		UserID:       arg.UserID,
+		CauseID:      arg.CauseID,
+		TeamID:       arg.TeamID,
+		Amount:       arg.Amount,
+		DonationType: arg.DonationType,
+		Status:       arg.Status,
+		CreatedAt:    now(),
+	}
+	s.t.donations[donation.ID] = donation
+	return donation, nil
+}
+
+func (s *Store) GetDonation(ctx context.Context, id int32) (db.Donation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	donation, ok := s.t.donations[id]
+	if !ok {
+		return db.Donation{}, sql.ErrNoRows
+	}
+	return donation, nil
+}
+
+func (s *Store) ListDonations(ctx context.Context, arg db.ListDonationsParams) ([]db.Donation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return page(s.t.donations, func(db.Donation) bool { return true }, arg.Limit, arg.Offset), nil
+}
+
+// Leaderboards
+
+func (s *Store) CreateLeaderboard(ctx context.Context, arg db.CreateLeaderboardParams) (db.Leaderboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	leaderboard := db.Leaderboard{
+		ID:        s.id(),
+		Name:      arg.Name,
+		Type:      arg.Type,
+		StartDate: arg.StartDate,
+		EndDate:   arg.EndDate,
+		Version:   1,
+	}
+	s.t.leaderboards[leaderboard.ID] = leaderboard
+	return leaderboard, nil
+}
+
+func (s *Store) GetLeaderboard(ctx context.Context, id int32) (db.Leaderboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	leaderboard, ok := s.t.leaderboards[id]
+	if !ok {
+		return db.Leaderboard{}, sql.ErrNoRows
+	}
+	return leaderboard, nil
+}
+
+func (s *Store) ListLeaderboards(ctx context.Context, arg db.ListLeaderboardsParams) ([]db.Leaderboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return page(s.t.leaderboards, func(db.Leaderboard) bool { return true }, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) PatchLeaderboard(ctx context.Context, arg db.PatchLeaderboardParams) (db.Leaderboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	leaderboard, ok := s.t.leaderboards[arg.ID]
+	if !ok || leaderboard.Version != arg.Version {
+		return db.Leaderboard{}, sql.ErrNoRows
+	}
+	if arg.SetName {
+		leaderboard.Name = arg.Name
+	}
+	if arg.SetType {
+		leaderboard.Type = arg.Type
+	}
+	if arg.SetStartDate {
+		leaderboard.StartDate = arg.StartDate
+	}
+	if arg.SetEndDate {
+		leaderboard.EndDate = arg.EndDate
+	}
+	leaderboard.Version++
+	s.t.leaderboards[arg.ID] = leaderboard
+	return leaderboard, nil
+}
+
+func (s *Store) GetLeaderboardEntry(ctx context.Context, arg db.GetLeaderboardEntryParams) (db.LeaderboardEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.t.entries[entryKey{arg.LeaderboardID, arg.UserID, arg.TeamID}]
+	if !ok {
+		return db.LeaderboardEntry{}, sql.ErrNoRows
+	}
+	return entry, nil
+}
+
+func (s *Store) UpdateLeaderboardEntry(ctx context.Context, arg db.UpdateLeaderboardEntryParams) (db.LeaderboardEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := db.LeaderboardEntry{
+		LeaderboardID: arg.LeaderboardID,
+		UserID:        arg.UserID,
+		TeamID:        arg.TeamID,
+		Score:         arg.Score,
+		Rank:          arg.Rank,
+	}
+	s.t.entries[entryKey{arg.LeaderboardID, arg.UserID, arg.TeamID}] = entry
+	return entry, nil
+}
@@ -0,0 +1,5 @@
+-- Down Migration: Remove action tokens and email verification tracking
+DROP TABLE IF EXISTS user_action_tokens;
+
+ALTER TABLE users
+DROP COLUMN email_verified_at;
@@ -0,0 +1,15 @@
+-- Migration: Track email verification and add single-use action tokens
+ALTER TABLE users
+ADD COLUMN email_verified_at TIMESTAMP;
+
+CREATE TABLE user_action_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX user_action_tokens_user_id_purpose_idx ON user_action_tokens (user_id, purpose);
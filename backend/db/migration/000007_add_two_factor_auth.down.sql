@@ -0,0 +1,4 @@
+-- Down Migration: Remove two-factor authentication tables
+DROP TABLE IF EXISTS role_policies;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
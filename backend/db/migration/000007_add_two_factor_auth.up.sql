@@ -0,0 +1,24 @@
+-- Migration: TOTP two-factor authentication, recovery codes and per-role policies
+CREATE TABLE user_totp (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX user_recovery_codes_user_id_idx ON user_recovery_codes (user_id);
+
+CREATE TABLE role_policies (
+    user_role VARCHAR(100) PRIMARY KEY,
+    require_2fa BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
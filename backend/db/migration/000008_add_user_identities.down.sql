@@ -0,0 +1,2 @@
+-- Down Migration: Remove external identities
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,13 @@
+-- Migration: Link users to identities from external OIDC/OAuth providers
+CREATE TABLE user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX user_identities_user_id_idx ON user_identities (user_id);
@@ -0,0 +1,2 @@
+-- Down Migration: Remove API keys
+DROP TABLE IF EXISTS api_keys;
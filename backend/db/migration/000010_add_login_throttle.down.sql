@@ -0,0 +1,3 @@
+-- Down Migration: Remove login throttling and security events
+DROP TABLE IF EXISTS security_events;
+DROP TABLE IF EXISTS login_throttle;
@@ -0,0 +1,18 @@
+-- Migration: Failed login counters shared across instances, and a per-user security event log
+CREATE TABLE login_throttle (
+    key VARCHAR(255) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL DEFAULT now(),
+    locked_until TIMESTAMP
+);
+
+CREATE TABLE security_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    ip VARCHAR(64),
+    user_agent TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX security_events_user_id_created_at_idx ON security_events (user_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Down Migration: Remove the audit trail
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,19 @@
+-- Migration: Audit trail of every create, update and delete made through the API.
+-- actor_id is deliberately not a foreign key so entries outlive the users they mention.
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    before JSONB,
+    after JSONB,
+    diff JSONB NOT NULL DEFAULT '{}',
+    request_id VARCHAR(64),
+    ip VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id, created_at DESC);
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id, created_at DESC);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at DESC);
@@ -0,0 +1,6 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS anonymized_at,
+DROP COLUMN IF EXISTS erasure_scheduled_for,
+DROP COLUMN IF EXISTS erasure_requested_at;
+
+DROP TABLE IF EXISTS data_exports;
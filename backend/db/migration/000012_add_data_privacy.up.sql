@@ -0,0 +1,20 @@
+-- Migration: Self-service data exports, and account erasure scheduled after a grace period
+CREATE TABLE data_exports (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_path TEXT,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX data_exports_user_id_idx ON data_exports (user_id, created_at DESC);
+CREATE INDEX data_exports_pending_idx ON data_exports (created_at) WHERE status = 'pending';
+
+ALTER TABLE users
+ADD COLUMN erasure_requested_at TIMESTAMP,
+ADD COLUMN erasure_scheduled_for TIMESTAMP,
+ADD COLUMN anonymized_at TIMESTAMP;
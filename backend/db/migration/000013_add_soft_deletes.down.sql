@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS causes_deleted_at_idx;
+DROP INDEX IF EXISTS teams_deleted_at_idx;
+DROP INDEX IF EXISTS users_deleted_at_idx;
+
+ALTER TABLE causes
+DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE teams
+DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,14 @@
+-- Migration: Soft deletes for users, teams and causes. Rows are purged after a retention period
+-- unless donations still reference them.
+ALTER TABLE users
+ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE teams
+ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE causes
+ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX teams_deleted_at_idx ON teams (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX causes_deleted_at_idx ON causes (deleted_at) WHERE deleted_at IS NOT NULL;
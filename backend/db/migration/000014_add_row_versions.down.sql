@@ -0,0 +1,8 @@
+ALTER TABLE causes
+DROP COLUMN IF EXISTS version;
+
+ALTER TABLE teams
+DROP COLUMN IF EXISTS version;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS version;
@@ -0,0 +1,9 @@
+-- Migration: Row versions for optimistic concurrency; each update bumps the version and clients send it back in If-Match
+ALTER TABLE users
+ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE teams
+ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE causes
+ADD COLUMN version INT NOT NULL DEFAULT 1;
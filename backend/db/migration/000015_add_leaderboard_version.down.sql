@@ -0,0 +1,2 @@
+ALTER TABLE leaderboards
+DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- Migration: Leaderboards can now be edited, so they get a row version for If-Match like users, teams and causes
+ALTER TABLE leaderboards
+ADD COLUMN version INT NOT NULL DEFAULT 1;
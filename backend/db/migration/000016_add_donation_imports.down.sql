@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS donations_import_id_idx;
+
+ALTER TABLE donations
+DROP COLUMN IF EXISTS import_id,
+DROP COLUMN IF EXISTS source;
+
+DROP TABLE IF EXISTS donation_import_rows;
+DROP TABLE IF EXISTS donation_imports;
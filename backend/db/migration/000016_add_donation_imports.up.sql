@@ -0,0 +1,38 @@
+-- Migration: Donations imported in batches from CSV or XLSX files. Validated rows are staged until the
+-- batch is committed; imported donations carry the batch's source and ID so the batch can be rolled back.
+CREATE TABLE donation_imports (
+    id SERIAL PRIMARY KEY,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    source VARCHAR(100) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'validated',
+    total_rows INT NOT NULL,
+    valid_rows INT NOT NULL,
+    errors JSONB NOT NULL DEFAULT '[]',
+    -- Commit progress: rows up to imported_through have been imported
+    imported_through INT NOT NULL DEFAULT 0,
+    imported_rows INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    completed_at TIMESTAMP,
+    rolled_back_at TIMESTAMP
+);
+
+CREATE INDEX donation_imports_created_at_idx ON donation_imports (created_at DESC);
+
+CREATE TABLE donation_import_rows (
+    import_id INT NOT NULL REFERENCES donation_imports(id) ON DELETE CASCADE,
+    row_number INT NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id),
+    cause_id INT NOT NULL REFERENCES causes(id),
+    team_id INT REFERENCES teams(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    donation_type VARCHAR(20) NOT NULL,
+    donated_at TIMESTAMP,
+    PRIMARY KEY (import_id, row_number)
+);
+
+ALTER TABLE donations
+ADD COLUMN source VARCHAR(100),
+ADD COLUMN import_id INT REFERENCES donation_imports(id);
+
+CREATE INDEX donations_import_id_idx ON donations (import_id) WHERE import_id IS NOT NULL;
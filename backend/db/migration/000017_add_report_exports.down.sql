@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS donations_created_at_idx;
+
+DROP TABLE IF EXISTS report_exports;
@@ -0,0 +1,23 @@
+-- Migration: Report exports of donations, donors and team stats built in the background for download
+CREATE TABLE report_exports (
+    id SERIAL PRIMARY KEY,
+    requested_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dataset VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    columns TEXT[] NOT NULL DEFAULT '{}',
+    filters JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    row_count INT NOT NULL DEFAULT 0,
+    file_path TEXT,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX report_exports_requested_by_idx ON report_exports (requested_by, created_at DESC);
+CREATE INDEX report_exports_pending_idx ON report_exports (created_at) WHERE status = 'pending';
+
+-- Reports page through donations by ID and filter them by date
+CREATE INDEX donations_created_at_idx ON donations (created_at);
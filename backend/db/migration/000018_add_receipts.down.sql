@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS receipts;
+DROP FUNCTION IF EXISTS protect_receipts();
+DROP TABLE IF EXISTS receipt_counters;
+DROP TABLE IF EXISTS receipt_templates;
@@ -0,0 +1,69 @@
+-- Migration: Tax receipts for single donations and yearly statements per donor. Numbers are gapless
+-- per series and year. A receipt's content never changes once issued: corrections void it and issue
+-- a replacement, and receipts are never deleted.
+CREATE TABLE receipt_templates (
+    id SERIAL PRIMARY KEY,
+    -- NULL is the organization's template; a cause's template overrides the fields it sets
+    cause_id INT UNIQUE REFERENCES causes(id) ON DELETE CASCADE,
+    organization_name VARCHAR(200) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    registration_number VARCHAR(100) NOT NULL DEFAULT '',
+    contact_email VARCHAR(100) NOT NULL DEFAULT '',
+    accent_color VARCHAR(7) NOT NULL DEFAULT '',
+    header_text TEXT NOT NULL DEFAULT '',
+    footer_text TEXT NOT NULL DEFAULT '',
+    signatory VARCHAR(200) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX receipt_templates_organization_idx ON receipt_templates ((cause_id IS NULL)) WHERE cause_id IS NULL;
+
+CREATE TABLE receipt_counters (
+    series VARCHAR(4) NOT NULL,
+    year INT NOT NULL,
+    last_number INT NOT NULL,
+    PRIMARY KEY (series, year)
+);
+
+CREATE TABLE receipts (
+    id SERIAL PRIMARY KEY,
+    number VARCHAR(30) UNIQUE NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id),
+    -- Not a foreign key: receipts outlive donations removed by an import rollback
+    donation_id INT,
+    year INT NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL,
+    donation_count INT NOT NULL,
+    pdf BYTEA NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    replaces INT REFERENCES receipts(id),
+    issued_at TIMESTAMP NOT NULL DEFAULT now(),
+    voided_at TIMESTAMP,
+    void_reason TEXT
+);
+
+CREATE UNIQUE INDEX receipts_donation_current_idx ON receipts (donation_id) WHERE kind = 'donation' AND voided_at IS NULL;
+CREATE UNIQUE INDEX receipts_statement_current_idx ON receipts (user_id, year) WHERE kind = 'statement' AND voided_at IS NULL;
+CREATE INDEX receipts_user_id_idx ON receipts (user_id, issued_at DESC);
+
+-- Only voiding may change a receipt, and only once
+CREATE FUNCTION protect_receipts() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        RAISE EXCEPTION 'receipt % cannot be deleted; void it instead', OLD.number;
+    END IF;
+    IF OLD.voided_at IS NOT NULL THEN
+        RAISE EXCEPTION 'receipt % is void', OLD.number;
+    END IF;
+    IF (NEW.id, NEW.number, NEW.kind, NEW.user_id, NEW.donation_id, NEW.year, NEW.amount, NEW.donation_count, NEW.pdf, NEW.sha256, NEW.replaces, NEW.issued_at)
+        IS DISTINCT FROM (OLD.id, OLD.number, OLD.kind, OLD.user_id, OLD.donation_id, OLD.year, OLD.amount, OLD.donation_count, OLD.pdf, OLD.sha256, OLD.replaces, OLD.issued_at) THEN
+        RAISE EXCEPTION 'receipt % cannot be changed; void it and issue a replacement', OLD.number;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER receipts_immutable
+BEFORE UPDATE OR DELETE ON receipts
+FOR EACH ROW EXECUTE FUNCTION protect_receipts();
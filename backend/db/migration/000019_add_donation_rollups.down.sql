@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS donations_rollup_delete ON donations;
+DROP TRIGGER IF EXISTS donations_rollup_update ON donations;
+DROP TRIGGER IF EXISTS donations_rollup_insert ON donations;
+DROP FUNCTION IF EXISTS mark_donation_rollups_dirty();
+DROP TABLE IF EXISTS donation_rollup_dirty;
+DROP TABLE IF EXISTS donation_rollups;
+DROP FUNCTION IF EXISTS donation_rollup_bucket(TIMESTAMP);
@@ -0,0 +1,65 @@
+-- Migration: Donation totals pre-aggregated into 15 minute buckets, so analytics can be bucketed by
+-- day, week or month in any time zone without reading donations. Every change to donations marks its
+-- buckets dirty; a background job rebuilds dirty buckets from donations.
+CREATE FUNCTION donation_rollup_bucket(ts TIMESTAMP) RETURNS TIMESTAMP AS $$
+    SELECT date_trunc('hour', ts) + floor(date_part('minute', ts) / 15) * INTERVAL '15 minutes';
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE TABLE donation_rollups (
+    bucket TIMESTAMP NOT NULL,
+    -- 0 and '' stand for donations without a cause, team or type
+    cause_id INT NOT NULL,
+    team_id INT NOT NULL,
+    donation_type VARCHAR(20) NOT NULL,
+    donation_count INT NOT NULL,
+    amount_sum DECIMAL(14, 2) NOT NULL,
+    -- Distinct donors in the bucket, so unique donors can be counted over any range
+    donor_ids INT[] NOT NULL,
+    PRIMARY KEY (bucket, cause_id, team_id, donation_type)
+);
+
+CREATE TABLE donation_rollup_dirty (
+    bucket TIMESTAMP PRIMARY KEY,
+    -- Bumped by every write; the row lock it takes keeps the refresh off buckets with writes in flight
+    changes INT NOT NULL DEFAULT 1
+);
+
+CREATE FUNCTION mark_donation_rollups_dirty() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        INSERT INTO donation_rollup_dirty (bucket)
+        SELECT DISTINCT donation_rollup_bucket(created_at) FROM old_donations
+        WHERE created_at IS NOT NULL
+        ORDER BY 1
+        ON CONFLICT (bucket) DO UPDATE SET changes = donation_rollup_dirty.changes + 1;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO donation_rollup_dirty (bucket)
+        SELECT DISTINCT donation_rollup_bucket(created_at) FROM new_donations
+        WHERE created_at IS NOT NULL
+        ORDER BY 1
+        ON CONFLICT (bucket) DO UPDATE SET changes = donation_rollup_dirty.changes + 1;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER donations_rollup_insert
+AFTER INSERT ON donations
+REFERENCING NEW TABLE AS new_donations
+FOR EACH STATEMENT EXECUTE FUNCTION mark_donation_rollups_dirty();
+
+CREATE TRIGGER donations_rollup_update
+AFTER UPDATE ON donations
+REFERENCING OLD TABLE AS old_donations NEW TABLE AS new_donations
+FOR EACH STATEMENT EXECUTE FUNCTION mark_donation_rollups_dirty();
+
+CREATE TRIGGER donations_rollup_delete
+AFTER DELETE ON donations
+REFERENCING OLD TABLE AS old_donations
+FOR EACH STATEMENT EXECUTE FUNCTION mark_donation_rollups_dirty();
+
+-- Existing donations are rolled up by the first refresh
+INSERT INTO donation_rollup_dirty (bucket)
+SELECT DISTINCT donation_rollup_bucket(created_at) FROM donations
+WHERE created_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS donations_user_created_idx;
+
+ALTER TABLE user_team
+DROP COLUMN IF EXISTS joined_at;
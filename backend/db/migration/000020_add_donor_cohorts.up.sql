@@ -0,0 +1,20 @@
+-- Migration: Team join dates and a per-donor donation index for donor cohort analytics.
+-- Memberships from before this migration have no recorded join date; they are given the earliest
+-- time the member could have joined, when both the user and the team existed.
+ALTER TABLE user_team
+ADD COLUMN joined_at TIMESTAMP;
+
+UPDATE user_team ut
+SET joined_at = GREATEST(u.created_at, t.created_at)
+FROM users u, teams t
+WHERE u.id = ut.user_id AND t.id = ut.team_id;
+
+UPDATE user_team
+SET joined_at = CURRENT_TIMESTAMP
+WHERE joined_at IS NULL;
+
+ALTER TABLE user_team
+ALTER COLUMN joined_at SET DEFAULT CURRENT_TIMESTAMP,
+ALTER COLUMN joined_at SET NOT NULL;
+
+CREATE INDEX donations_user_created_idx ON donations (user_id, created_at) WHERE user_id IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE donation_import_rows
+DROP CONSTRAINT donation_import_rows_user_id_fkey,
+DROP CONSTRAINT donation_import_rows_cause_id_fkey,
+DROP CONSTRAINT donation_import_rows_team_id_fkey,
+ADD CONSTRAINT donation_import_rows_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id),
+ADD CONSTRAINT donation_import_rows_cause_id_fkey FOREIGN KEY (cause_id) REFERENCES causes(id),
+ADD CONSTRAINT donation_import_rows_team_id_fkey FOREIGN KEY (team_id) REFERENCES teams(id);
@@ -0,0 +1,14 @@
+-- Migration: Staged import rows no longer hold on to the users, causes and teams they name. Rows of
+-- completed and rolled back batches are dropped, and purging an entity drops the staged rows that
+-- still refer to it, so the retention purge is never blocked by an import.
+DELETE FROM donation_import_rows r
+USING donation_imports i
+WHERE i.id = r.import_id AND i.status IN ('completed', 'rolled_back');
+
+ALTER TABLE donation_import_rows
+DROP CONSTRAINT donation_import_rows_user_id_fkey,
+DROP CONSTRAINT donation_import_rows_cause_id_fkey,
+DROP CONSTRAINT donation_import_rows_team_id_fkey,
+ADD CONSTRAINT donation_import_rows_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+ADD CONSTRAINT donation_import_rows_cause_id_fkey FOREIGN KEY (cause_id) REFERENCES causes(id) ON DELETE CASCADE,
+ADD CONSTRAINT donation_import_rows_team_id_fkey FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE;
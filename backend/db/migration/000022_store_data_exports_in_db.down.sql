@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS data_exports_running_idx;
+
+ALTER TABLE data_exports
+ADD COLUMN file_path TEXT;
+
+-- Archives only lived in the database; completed exports have nothing left to download
+UPDATE data_exports
+SET expires_at = now()
+WHERE status = 'completed';
+
+DROP TABLE IF EXISTS data_export_archives;
@@ -0,0 +1,17 @@
+-- Migration: Export archives are kept in the database rather than on the disk of the instance that
+-- built them, so any instance can serve the download. Archives built before this change are on one
+-- instance's disk only; their exports expire now and are removed by the cleanup job.
+CREATE TABLE data_export_archives (
+    export_id INT PRIMARY KEY REFERENCES data_exports(id) ON DELETE CASCADE,
+    content BYTEA NOT NULL
+);
+
+UPDATE data_exports
+SET expires_at = now()
+WHERE status = 'completed';
+
+ALTER TABLE data_exports
+DROP COLUMN file_path;
+
+-- Claims look for pending exports and for running ones whose worker stopped
+CREATE INDEX data_exports_running_idx ON data_exports (started_at) WHERE status = 'running';
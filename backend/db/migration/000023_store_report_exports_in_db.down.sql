@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS report_exports_running_idx;
+
+ALTER TABLE report_exports
+ADD COLUMN file_path TEXT;
+
+-- Files only lived in the database; completed reports have nothing left to download
+UPDATE report_exports
+SET expires_at = now()
+WHERE status = 'completed';
+
+DROP TABLE IF EXISTS report_export_files;
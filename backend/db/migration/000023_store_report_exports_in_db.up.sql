@@ -0,0 +1,17 @@
+-- Migration: Queued report files are kept in the database, like data export archives, so any
+-- instance can serve the download. Reports built before this change are on one instance's disk
+-- only; they expire now and are removed by the cleanup job.
+CREATE TABLE report_export_files (
+    export_id INT PRIMARY KEY REFERENCES report_exports(id) ON DELETE CASCADE,
+    content BYTEA NOT NULL
+);
+
+UPDATE report_exports
+SET expires_at = now()
+WHERE status = 'completed';
+
+ALTER TABLE report_exports
+DROP COLUMN file_path;
+
+-- Claims look for pending reports and for running ones whose worker stopped
+CREATE INDEX report_exports_running_idx ON report_exports (started_at) WHERE status = 'running';
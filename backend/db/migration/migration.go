@@ -0,0 +1,152 @@
+// Package migration embeds the schema migrations in the binary and applies them with golang-migrate
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// File is one embedded migration, identified by its version and the name after the version prefix
+type File struct {
+	Version uint
+	Name    string
+}
+
+// Files lists the embedded migrations in version order
+func Files() ([]File, error) {
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	var list []File
+	version, err := src.First()
+	for err == nil {
+		r, name, rerr := src.ReadUp(version)
+		if rerr != nil {
+			return nil, fmt.Errorf("migration %d: %w", version, rerr)
+		}
+		r.Close()
+		list = append(list, File{Version: version, Name: name})
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Latest is the version of the newest embedded migration: the schema this build's queries expect
+func Latest() uint {
+	list, err := Files()
+	if err != nil || len(list) == 0 {
+		// The migrations are compiled in, so this only happens with a broken build
+		panic(fmt.Sprintf("no embedded migrations: %v", err))
+	}
+	return list[len(list)-1].Version
+}
+
+// New returns a migrator for the embedded files on a connection of its own from db. Closing it
+// returns that connection to db, which stays open. Every change it makes holds a Postgres advisory
+// lock, so instances starting at the same time take turns instead of racing.
+func New(db *sql.DB) (*migrate.Migrate, error) {
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("could not read embedded migrations: %w", err)
+	}
+	// WithInstance would close db along with the migrator; a connection of its own keeps it apart
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not open migration connection: %w", err)
+	}
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("could not open migration driver: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	m.Log = logger{}
+	return m, nil
+}
+
+// Up applies every pending migration. An up-to-date database is not an error.
+func Up(db *sql.DB) error {
+	m, err := New(db)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Version reads the version recorded in schema_migrations. A database that has never been migrated is
+// at version 0. Unlike New it does not create the table, so it is cheap enough for health checks.
+func Version(ctx context.Context, db *sql.DB) (version uint, dirty bool, err error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return 0, false, fmt.Errorf("could not read schema version: %w", err)
+	}
+	if !exists {
+		return 0, false, nil
+	}
+
+	err = db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("could not read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// Check fails unless the database is cleanly at version expected
+func Check(ctx context.Context, db *sql.DB, expected uint) error {
+	version, dirty, err := Version(ctx, db)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty; fix it and run migrate force", version)
+	}
+	if version != expected {
+		return fmt.Errorf("schema is at version %d, expected %d", version, expected)
+	}
+	return nil
+}
+
+// logger sends golang-migrate's progress messages to slog
+type logger struct{}
+
+func (logger) Printf(format string, v ...interface{}) {
+	slog.Info("migrate: " + strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (logger) Verbose() bool {
+	return false
+}
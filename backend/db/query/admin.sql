@@ -0,0 +1,52 @@
+-- name: SetUserRole :one
+UPDATE users
+SET user_role = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RecomputeCauseTotals :many
+-- Failed, refunded and cancelled donations do not count towards a cause
+WITH totals AS (
+    SELECT c.id, COALESCE(SUM(d.amount) FILTER (WHERE d.status IS NULL OR d.status NOT IN ('failed', 'refunded', 'cancelled')), 0) AS total
+    FROM causes c
+    LEFT JOIN donations d ON d.cause_id = c.id
+    WHERE c.deleted_at IS NULL
+    GROUP BY c.id
+)
+UPDATE causes
+SET current_amount = totals.total, updated_at = CURRENT_TIMESTAMP, version = version + 1
+FROM totals
+WHERE causes.id = totals.id AND causes.current_amount IS DISTINCT FROM totals.total
+RETURNING causes.*;
+
+-- name: ListLeaderboardIDs :many
+SELECT id FROM leaderboards
+ORDER BY id;
+
+-- name: DeleteLeaderboardEntriesByLeaderboard :exec
+DELETE FROM leaderboard_entries
+WHERE leaderboard_id = $1;
+
+-- name: RebuildLeaderboardEntries :execrows
+-- Scores are the donations made inside the leaderboard's dates by a donor for a team. Individual
+-- boards rank each donor and team pair; team boards give every member the team's total and rank.
+WITH board AS (
+    SELECT * FROM leaderboards WHERE leaderboards.id = $1
+), scores AS (
+    SELECT d.user_id, d.team_id, SUM(d.amount) AS score
+    FROM donations d, board b
+    WHERE d.user_id IS NOT NULL AND d.team_id IS NOT NULL AND d.amount IS NOT NULL
+    AND (d.status IS NULL OR d.status NOT IN ('failed', 'refunded', 'cancelled'))
+    AND (b.start_date IS NULL OR d.created_at >= b.start_date)
+    AND (b.end_date IS NULL OR d.created_at < b.end_date + 1)
+    GROUP BY d.user_id, d.team_id
+), team_scores AS (
+    SELECT scores.*, SUM(score) OVER (PARTITION BY team_id) AS team_score
+    FROM scores
+)
+INSERT INTO leaderboard_entries (leaderboard_id, user_id, team_id, score, rank)
+SELECT b.id, t.user_id, t.team_id,
+    CASE WHEN b.type = 'team' THEN t.team_score ELSE t.score END,
+    CASE WHEN b.type = 'team' THEN dense_rank() OVER (ORDER BY t.team_score DESC)
+         ELSE rank() OVER (ORDER BY t.score DESC) END
+FROM team_scores t, board b;
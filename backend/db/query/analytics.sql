@@ -0,0 +1,138 @@
+-- name: ClaimDirtyRollupBuckets :many
+-- Takes up to limit dirty buckets, skipping any that an open transaction is still writing to
+DELETE FROM donation_rollup_dirty
+WHERE bucket IN (
+    SELECT bucket FROM donation_rollup_dirty
+    ORDER BY bucket
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING bucket;
+
+-- name: DeleteDonationRollups :exec
+DELETE FROM donation_rollups
+WHERE bucket = ANY(sqlc.arg(buckets)::timestamp[]);
+
+-- name: RebuildDonationRollups :execrows
+INSERT INTO donation_rollups (bucket, cause_id, team_id, donation_type, donation_count, amount_sum, donor_ids)
+SELECT b.bucket, COALESCE(d.cause_id, 0), COALESCE(d.team_id, 0), COALESCE(d.donation_type, ''),
+    COUNT(*), COALESCE(SUM(d.amount), 0),
+    COALESCE(array_agg(DISTINCT d.user_id) FILTER (WHERE d.user_id IS NOT NULL), '{}')
+FROM unnest(sqlc.arg(buckets)::timestamp[]) AS b(bucket)
+JOIN donations d ON d.created_at >= b.bucket AND d.created_at < b.bucket + INTERVAL '15 minutes'
+GROUP BY 1, 2, 3, 4;
+
+-- name: CountDirtyRollupBuckets :one
+SELECT COUNT(*) FROM donation_rollup_dirty
+WHERE bucket >= sqlc.arg(bucket_from)::timestamp AND bucket < sqlc.arg(bucket_to)::timestamp;
+
+-- name: DonationAnalytics :many
+-- Totals rollups per group, and per period when bucketed. Periods are truncated in time_zone and
+-- returned as local times; weeks start on Monday.
+WITH matched AS (
+    SELECT
+        CASE WHEN sqlc.arg(bucketed)::bool
+            THEN date_trunc(sqlc.arg(period)::text, (r.bucket AT TIME ZONE 'UTC') AT TIME ZONE sqlc.arg(time_zone)::text)
+            ELSE TIMESTAMP 'epoch'
+        END AS period_start,
+        CASE sqlc.arg(group_by)::text
+            WHEN 'cause' THEN r.cause_id::text
+            WHEN 'team' THEN r.team_id::text
+            WHEN 'donation_type' THEN r.donation_type::text
+            WHEN 'category' THEN COALESCE(c.category, '')::text
+            ELSE ''
+        END AS group_key,
+        CASE sqlc.arg(group_by)::text
+            WHEN 'cause' THEN COALESCE(c.name, '')::text
+            WHEN 'team' THEN COALESCE(t.name, '')::text
+            ELSE ''
+        END AS group_label,
+        r.donation_count, r.amount_sum, r.donor_ids
+    FROM donation_rollups r
+    LEFT JOIN causes c ON c.id = r.cause_id
+    LEFT JOIN teams t ON t.id = r.team_id
+    WHERE r.bucket >= sqlc.arg(bucket_from)::timestamp AND r.bucket < sqlc.arg(bucket_to)::timestamp
+    AND (sqlc.narg(cause_id)::int IS NULL OR r.cause_id = sqlc.narg(cause_id))
+    AND (sqlc.narg(team_id)::int IS NULL OR r.team_id = sqlc.narg(team_id))
+    AND (sqlc.narg(donation_type)::text IS NULL OR r.donation_type = sqlc.narg(donation_type))
+), totals AS (
+    SELECT m.period_start, m.group_key, MAX(m.group_label) AS group_label,
+        SUM(m.donation_count) AS donation_count, SUM(m.amount_sum) AS amount_sum
+    FROM matched m
+    GROUP BY m.period_start, m.group_key
+), donors AS (
+    SELECT m.period_start, m.group_key, COUNT(DISTINCT donor.user_id) AS unique_donors
+    FROM matched m, unnest(m.donor_ids) AS donor(user_id)
+    GROUP BY m.period_start, m.group_key
+)
+SELECT totals.period_start::timestamp AS period_start,
+    totals.group_key::text AS group_key,
+    totals.group_label::text AS group_label,
+    totals.donation_count::bigint AS donation_count,
+    totals.amount_sum::numeric AS amount_sum,
+    COALESCE(ROUND(totals.amount_sum / NULLIF(totals.donation_count, 0), 2), 0)::numeric AS average_gift,
+    COALESCE(donors.unique_donors, 0)::bigint AS unique_donors
+FROM totals
+LEFT JOIN donors ON donors.period_start = totals.period_start AND donors.group_key = totals.group_key
+ORDER BY totals.group_key, totals.period_start;
+
+-- name: DonorCohorts :many
+-- Groups donors by the month of their first donation and totals each cohort's donations per month
+-- since; month_offset 0 is the first month. Months are calendar months in time_zone.
+WITH gifts AS (
+    SELECT d.user_id, COALESCE(d.amount, 0) AS amount,
+        date_trunc('month', (d.created_at AT TIME ZONE 'UTC') AT TIME ZONE sqlc.arg(time_zone)::text) AS month
+    FROM donations d
+    WHERE d.user_id IS NOT NULL AND d.created_at IS NOT NULL
+), cohorts AS (
+    SELECT gifts.user_id, MIN(gifts.month) AS cohort
+    FROM gifts
+    GROUP BY gifts.user_id
+)
+SELECT c.cohort::timestamp AS cohort,
+    ((EXTRACT(YEAR FROM g.month) - EXTRACT(YEAR FROM c.cohort)) * 12
+        + EXTRACT(MONTH FROM g.month) - EXTRACT(MONTH FROM c.cohort))::int AS month_offset,
+    COUNT(DISTINCT g.user_id)::bigint AS donors,
+    COUNT(*)::bigint AS donation_count,
+    SUM(g.amount)::numeric AS amount_sum
+FROM cohorts c
+JOIN gifts g ON g.user_id = c.user_id
+WHERE c.cohort >= sqlc.arg(cohort_from)::timestamp AND c.cohort < sqlc.arg(cohort_to)::timestamp
+GROUP BY 1, 2
+ORDER BY 1, 2;
+
+-- name: ListDonorSummaries :many
+-- One row per donor whose first donation falls in the cohort range, or every donor without one.
+-- Active months are the calendar months in time_zone with at least one donation, split at the
+-- donor's first team join.
+WITH gifts AS (
+    SELECT d.user_id, d.created_at, COALESCE(d.amount, 0) AS amount,
+        date_trunc('month', (d.created_at AT TIME ZONE 'UTC') AT TIME ZONE sqlc.arg(time_zone)::text) AS month
+    FROM donations d
+    WHERE d.user_id IS NOT NULL AND d.created_at IS NOT NULL
+), memberships AS (
+    SELECT ut.user_id, MIN(ut.joined_at) AS joined_at
+    FROM user_team ut
+    GROUP BY ut.user_id
+)
+SELECT g.user_id::int AS user_id,
+    u.username,
+    u.email,
+    (u.deleted_at IS NULL AND u.anonymized_at IS NULL)::bool AS active,
+    MIN(g.month)::timestamp AS cohort,
+    MIN(g.created_at)::timestamp AS first_donation,
+    MAX(g.created_at)::timestamp AS last_donation,
+    COUNT(*)::bigint AS donation_count,
+    SUM(g.amount)::numeric AS amount_sum,
+    COUNT(DISTINCT g.month)::bigint AS active_months,
+    (m.joined_at IS NOT NULL)::bool AS in_team,
+    COALESCE(m.joined_at, TIMESTAMP 'epoch')::timestamp AS joined_team_at,
+    COUNT(DISTINCT g.month) FILTER (WHERE g.created_at < m.joined_at)::bigint AS active_months_before_join,
+    COUNT(DISTINCT g.month) FILTER (WHERE g.created_at >= m.joined_at)::bigint AS active_months_after_join
+FROM gifts g
+JOIN users u ON u.id = g.user_id
+LEFT JOIN memberships m ON m.user_id = g.user_id
+GROUP BY g.user_id, u.username, u.email, u.deleted_at, u.anonymized_at, m.joined_at
+HAVING (sqlc.narg(cohort_from)::timestamp IS NULL OR MIN(g.month) >= sqlc.narg(cohort_from)::timestamp)
+    AND (sqlc.narg(cohort_to)::timestamp IS NULL OR MIN(g.month) < sqlc.narg(cohort_to)::timestamp)
+ORDER BY g.user_id;
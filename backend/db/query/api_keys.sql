@@ -0,0 +1,36 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, team_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT api_keys.* FROM api_keys
+JOIN users ON users.id = api_keys.user_id
+LEFT JOIN teams ON teams.id = api_keys.team_id
+WHERE api_keys.key_hash = $1
+AND api_keys.revoked_at IS NULL
+AND users.deleted_at IS NULL
+AND teams.deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListAPIKeysByUser :many
+SELECT * FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1
+AND user_id = $2
+AND revoked_at IS NULL
+RETURNING *;
+
+-- name: GetUserTeam :one
+SELECT * FROM user_team
+WHERE user_id = $1 AND team_id = $2 LIMIT 1;
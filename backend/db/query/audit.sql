@@ -0,0 +1,22 @@
+-- name: CreateAuditLogEntry :one
+INSERT INTO audit_log (actor_id, action, entity_type, entity_id, before, after, diff, request_id, ip)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: ListAuditLog :many
+SELECT * FROM audit_log
+WHERE (sqlc.narg(actor_id)::int IS NULL OR actor_id = sqlc.narg(actor_id))
+AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type))
+AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id))
+AND (sqlc.narg(request_id)::text IS NULL OR request_id = sqlc.narg(request_id))
+AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListAuditLogByEntity :many
+SELECT * FROM audit_log
+WHERE entity_type = $1 AND entity_id = $2
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $4;
@@ -0,0 +1,35 @@
+-- name: CreateUserActionToken :one
+INSERT INTO user_action_tokens (user_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ConsumeUserActionToken :one
+UPDATE user_action_tokens
+SET used_at = now()
+WHERE token_hash = $1
+AND purpose = $2
+AND used_at IS NULL
+AND expires_at > now()
+RETURNING *;
+
+-- name: InvalidateUserActionTokens :exec
+UPDATE user_action_tokens
+SET used_at = now()
+WHERE user_id = $1
+AND purpose = $2
+AND used_at IS NULL;
+
+-- name: MarkUserEmailVerified :one
+UPDATE users
+SET email_verified_at = now(), updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1;
+
+-- name: DeleteUserTokensByUserID :exec
+DELETE FROM user_tokens
+WHERE user_id = $1;
@@ -0,0 +1,92 @@
+-- name: CreateDonationImport :one
+INSERT INTO donation_imports (created_by, source, filename, total_rows, valid_rows, errors)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: CreateDonationImportRows :execrows
+-- Stages a batch's valid rows. The arrays are parallel, one element per row; a team ID of 0 and an
+-- empty date mean none.
+INSERT INTO donation_import_rows (import_id, row_number, user_id, cause_id, team_id, amount, donation_type, donated_at)
+SELECT @import_id::int,
+    unnest(@row_numbers::int[]),
+    unnest(@user_ids::int[]),
+    unnest(@cause_ids::int[]),
+    NULLIF(unnest(@team_ids::int[]), 0),
+    unnest(@amounts::numeric[]),
+    unnest(@donation_types::text[]),
+    NULLIF(unnest(@donated_at::text[]), '')::timestamp;
+
+-- name: GetDonationImport :one
+SELECT * FROM donation_imports
+WHERE id = $1 LIMIT 1;
+
+-- name: LockDonationImport :one
+-- Commits and rollbacks of one batch take turns
+SELECT * FROM donation_imports
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListDonationImports :many
+SELECT * FROM donation_imports
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: SummarizeDonationImportChunk :many
+-- Counts and sums, by donation type, the rows ImportDonationChunk will import next
+SELECT chunk.donation_type, COUNT(*) AS donations, SUM(chunk.amount)::float8 AS amount
+FROM (
+    SELECT r.donation_type, r.amount
+    FROM donation_import_rows r
+    JOIN donation_imports i ON i.id = r.import_id
+    WHERE r.import_id = @import_id AND r.row_number > i.imported_through
+    ORDER BY r.row_number
+    LIMIT @chunk_size
+) chunk
+GROUP BY chunk.donation_type
+ORDER BY chunk.donation_type;
+
+-- name: ImportDonationChunk :one
+-- Imports the next chunk of staged rows in row order and records how far the batch has got, so an
+-- interrupted commit carries on where it stopped
+WITH chunk AS (
+    SELECT r.*
+    FROM donation_import_rows r
+    JOIN donation_imports i ON i.id = r.import_id
+    WHERE r.import_id = @import_id AND r.row_number > i.imported_through
+    ORDER BY r.row_number
+    LIMIT @chunk_size
+), inserted AS (
+    INSERT INTO donations (user_id, cause_id, team_id, amount, donation_type, created_at, source, import_id)
+    SELECT chunk.user_id, chunk.cause_id, chunk.team_id, chunk.amount, chunk.donation_type,
+        COALESCE(chunk.donated_at, now()), i.source, i.id
+    FROM chunk
+    JOIN donation_imports i ON i.id = chunk.import_id
+    RETURNING id
+)
+UPDATE donation_imports
+SET status = 'importing',
+    imported_through = COALESCE((SELECT MAX(chunk.row_number) FROM chunk), imported_through),
+    imported_rows = imported_rows + (SELECT COUNT(*) FROM inserted)
+WHERE donation_imports.id = @import_id
+RETURNING *;
+
+-- name: CompleteDonationImport :one
+UPDATE donation_imports
+SET status = 'completed', completed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteDonationImportRows :exec
+-- Drops a batch's staged rows once it is completed or rolled back; nothing reads them afterwards
+DELETE FROM donation_import_rows
+WHERE import_id = $1;
+
+-- name: DeleteImportedDonations :execrows
+DELETE FROM donations
+WHERE import_id = $1;
+
+-- name: RollBackDonationImport :one
+UPDATE donation_imports
+SET status = 'rolled_back', rolled_back_at = now(), imported_rows = 0, imported_through = 0
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,49 @@
+-- Partial updates for JSON merge patches: each column changes only when its set_ flag is true
+
+-- name: PatchUser :one
+UPDATE users
+SET username = CASE WHEN sqlc.arg(set_username)::bool THEN sqlc.arg(username)::varchar ELSE username END,
+    email = CASE WHEN sqlc.arg(set_email)::bool THEN sqlc.arg(email)::varchar ELSE email END,
+    email_verified_at = CASE WHEN sqlc.arg(set_email)::bool AND sqlc.arg(email)::varchar <> email THEN NULL ELSE email_verified_at END,
+    first_name = CASE WHEN sqlc.arg(set_first_name)::bool THEN sqlc.narg(first_name)::varchar ELSE first_name END,
+    last_name = CASE WHEN sqlc.arg(set_last_name)::bool THEN sqlc.narg(last_name)::varchar ELSE last_name END,
+    avatar_url = CASE WHEN sqlc.arg(set_avatar_url)::bool THEN sqlc.narg(avatar_url)::text ELSE avatar_url END,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND version = sqlc.arg(version)
+RETURNING *;
+
+-- name: PatchTeam :one
+UPDATE teams
+SET name = CASE WHEN sqlc.arg(set_name)::bool THEN sqlc.arg(name)::varchar ELSE name END,
+    description = CASE WHEN sqlc.arg(set_description)::bool THEN sqlc.narg(description)::text ELSE description END,
+    avatar_url = CASE WHEN sqlc.arg(set_avatar_url)::bool THEN sqlc.narg(avatar_url)::text ELSE avatar_url END,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND version = sqlc.arg(version)
+RETURNING *;
+
+-- name: PatchCause :one
+UPDATE causes
+SET name = CASE WHEN sqlc.arg(set_name)::bool THEN sqlc.arg(name)::varchar ELSE name END,
+    description = CASE WHEN sqlc.arg(set_description)::bool THEN sqlc.narg(description)::text ELSE description END,
+    goal = CASE WHEN sqlc.arg(set_goal)::bool THEN sqlc.narg(goal)::numeric ELSE goal END,
+    start_date = CASE WHEN sqlc.arg(set_start_date)::bool THEN sqlc.narg(start_date)::date ELSE start_date END,
+    end_date = CASE WHEN sqlc.arg(set_end_date)::bool THEN sqlc.narg(end_date)::date ELSE end_date END,
+    status = CASE WHEN sqlc.arg(set_status)::bool THEN sqlc.narg(status)::varchar ELSE status END,
+    image = CASE WHEN sqlc.arg(set_image)::bool THEN sqlc.narg(image)::text ELSE image END,
+    category = CASE WHEN sqlc.arg(set_category)::bool THEN sqlc.narg(category)::varchar ELSE category END,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND version = sqlc.arg(version)
+RETURNING *;
+
+-- name: PatchLeaderboard :one
+UPDATE leaderboards
+SET name = CASE WHEN sqlc.arg(set_name)::bool THEN sqlc.arg(name)::varchar ELSE name END,
+    type = CASE WHEN sqlc.arg(set_type)::bool THEN sqlc.narg(type)::varchar ELSE type END,
+    start_date = CASE WHEN sqlc.arg(set_start_date)::bool THEN sqlc.narg(start_date)::date ELSE start_date END,
+    end_date = CASE WHEN sqlc.arg(set_end_date)::bool THEN sqlc.narg(end_date)::date ELSE end_date END,
+    version = version + 1
+WHERE id = sqlc.arg(id) AND version = sqlc.arg(version)
+RETURNING *;
@@ -5,26 +5,29 @@ RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: GetUserByEmail :one
 SELECT * FROM users
-WHERE email = $1 LIMIT 1;
+WHERE email = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListUsers :many
 SELECT * FROM users
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2;
 
 -- name: UpdateUser :one
 UPDATE users
-SET username = $2, email = $3, first_name = $4, last_name = $5, avatar_url = $6, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+SET username = $2, email = $3, first_name = $4, last_name = $5, avatar_url = $6, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL AND version = $7
 RETURNING *;
 
--- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1;
+-- name: DeleteUser :one
+UPDATE users
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
 
 -- name: CreateTeam :one
 INSERT INTO teams (name, description, avatar_url)
@@ -33,22 +36,25 @@ RETURNING *;
 
 -- name: GetTeam :one
 SELECT * FROM teams
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListTeams :many
 SELECT * FROM teams
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2;
 
 -- name: UpdateTeam :one
 UPDATE teams
-SET name = $2, description = $3, avatar_url = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+SET name = $2, description = $3, avatar_url = $4, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL AND version = $5
 RETURNING *;
 
--- name: DeleteTeam :exec
-DELETE FROM teams
-WHERE id = $1;
+-- name: DeleteTeam :one
+UPDATE teams
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
 
 -- name: AddUserToTeam :one
 INSERT INTO user_team (user_id, team_id, role)
@@ -72,22 +78,25 @@ RETURNING *;
 
 -- name: GetCause :one
 SELECT * FROM causes
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListCauses :many
 SELECT * FROM causes
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2;
 
 -- name: UpdateCause :one
 UPDATE causes
-SET name = $2, description = $3, goal = $4, current_amount = $5, start_date = $6, end_date = $7, status = $8, image = $9, category = $10, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+SET name = $2, description = $3, goal = $4, current_amount = $5, start_date = $6, end_date = $7, status = $8, image = $9, category = $10, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL AND version = $11
 RETURNING *;
 
--- name: DeleteCause :exec
-DELETE FROM causes
-WHERE id = $1;
+-- name: DeleteCause :one
+UPDATE causes
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
 
 
 -- name: CreateDonation :one
@@ -162,6 +171,10 @@ DELETE FROM user_tokens
 WHERE user_id = $1
 AND token = $2;
 
--- name: DeleteExpiredTokens :exec
+-- name: DeleteExpiredTokens :execrows
 DELETE FROM user_tokens
 WHERE expiry < now();
+
+-- name: GetLeaderboardEntry :one
+SELECT * FROM leaderboard_entries
+WHERE leaderboard_id = $1 AND user_id = $2 AND team_id = $3 LIMIT 1;
@@ -0,0 +1,144 @@
+-- name: CreateDataExport :one
+INSERT INTO data_exports (user_id)
+VALUES ($1)
+RETURNING *;
+
+-- name: GetDataExport :one
+SELECT * FROM data_exports
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListDataExportsByUser :many
+SELECT * FROM data_exports
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ClaimPendingDataExport :one
+-- Claims the oldest pending export, or a running one whose worker has not finished it within
+-- stale_seconds and is taken to have stopped
+UPDATE data_exports
+SET status = 'running', started_at = now()
+WHERE id = (
+    SELECT id FROM data_exports
+    WHERE status = 'pending' OR (status = 'running' AND started_at < now() - make_interval(secs => @stale_seconds::float8))
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteDataExport :execrows
+-- Completes an export if it is still held by the claim that started it at started_at; a worker
+-- whose claim went stale and was taken over updates nothing
+UPDATE data_exports
+SET status = 'completed', completed_at = now(), expires_at = $3
+WHERE id = $1 AND status = 'running' AND started_at = $2;
+
+-- name: CreateDataExportArchive :exec
+INSERT INTO data_export_archives (export_id, content)
+VALUES ($1, $2);
+
+-- name: GetDataExportArchive :one
+SELECT content FROM data_export_archives
+WHERE export_id = $1;
+
+-- name: FailDataExport :exec
+UPDATE data_exports
+SET status = 'failed', error = $3, completed_at = now()
+WHERE id = $1 AND status = 'running' AND started_at = $2;
+
+-- name: DeleteExpiredDataExports :execrows
+-- Deletes completed exports past their download window, with their archives
+DELETE FROM data_exports
+WHERE status = 'completed' AND expires_at < now();
+
+-- name: DeleteDataExportsByUser :exec
+DELETE FROM data_exports
+WHERE user_id = $1;
+
+-- name: ListDonationsByUser :many
+SELECT * FROM donations
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: ListTeamsByUser :many
+SELECT t.id, t.name, ut.role, ut.joined_at
+FROM user_team ut
+JOIN teams t ON t.id = ut.team_id
+WHERE ut.user_id = $1
+ORDER BY t.id;
+
+-- name: ListLeaderboardEntriesByUser :many
+SELECT l.id AS leaderboard_id, l.name AS leaderboard_name, le.team_id, le.score, le.rank
+FROM leaderboard_entries le
+JOIN leaderboards l ON l.id = le.leaderboard_id
+WHERE le.user_id = $1
+ORDER BY l.id;
+
+-- name: ListUserTokensByUser :many
+SELECT id, created_at, expiry FROM user_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ScheduleUserErasure :one
+UPDATE users
+SET erasure_requested_at = now(), erasure_scheduled_for = $2, version = version + 1
+WHERE id = $1 AND anonymized_at IS NULL
+RETURNING *;
+
+-- name: CancelUserErasure :one
+UPDATE users
+SET erasure_requested_at = NULL, erasure_scheduled_for = NULL, version = version + 1
+WHERE id = $1 AND erasure_scheduled_for IS NOT NULL AND anonymized_at IS NULL
+RETURNING *;
+
+-- name: ListDueErasures :many
+SELECT id FROM users
+WHERE erasure_scheduled_for <= now() AND anonymized_at IS NULL
+ORDER BY erasure_scheduled_for;
+
+-- name: AnonymizeUser :one
+UPDATE users
+SET username = 'deleted-user-' || id,
+    email = 'deleted-user-' || id || '@invalid',
+    password_hash = '',
+    first_name = NULL,
+    last_name = NULL,
+    avatar_url = NULL,
+    email_verified_at = NULL,
+    erasure_scheduled_for = NULL,
+    anonymized_at = now(),
+    updated_at = now(),
+    version = version + 1
+WHERE id = $1 AND anonymized_at IS NULL
+RETURNING *;
+
+-- name: DeleteUserPersonalData :exec
+WITH tokens AS (
+    DELETE FROM user_tokens WHERE user_tokens.user_id = $1
+), action_tokens AS (
+    DELETE FROM user_action_tokens WHERE user_action_tokens.user_id = $1
+), totp AS (
+    DELETE FROM user_totp WHERE user_totp.user_id = $1
+), recovery AS (
+    DELETE FROM user_recovery_codes WHERE user_recovery_codes.user_id = $1
+), identities AS (
+    DELETE FROM user_identities WHERE user_identities.user_id = $1
+), keys AS (
+    DELETE FROM api_keys WHERE api_keys.user_id = $1
+), events AS (
+    DELETE FROM security_events WHERE security_events.user_id = $1
+)
+DELETE FROM user_team WHERE user_team.user_id = $1;
+
+-- name: DeleteLoginThrottleForEmail :exec
+DELETE FROM login_throttle
+WHERE key = 'account:' || lower(sqlc.arg(email)::text);
+
+-- name: ScrubAuditLogForUser :exec
+UPDATE audit_log
+SET before = before - '{username,email,first_name,last_name,avatar_url}'::text[],
+    after = after - '{username,email,first_name,last_name,avatar_url}'::text[],
+    diff = diff - '{username,email,first_name,last_name,avatar_url}'::text[],
+    ip = CASE WHEN actor_id = sqlc.arg(user_id)::int THEN NULL ELSE ip END
+WHERE (entity_type = 'user' AND entity_id = sqlc.arg(user_id)::int::text)
+   OR actor_id = sqlc.arg(user_id)::int;
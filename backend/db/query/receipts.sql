@@ -0,0 +1,107 @@
+-- name: NextReceiptNumber :one
+INSERT INTO receipt_counters (series, year, last_number)
+VALUES ($1, $2, 1)
+ON CONFLICT (series, year) DO UPDATE
+SET last_number = receipt_counters.last_number + 1
+RETURNING last_number;
+
+-- name: CreateReceipt :one
+INSERT INTO receipts (number, kind, user_id, donation_id, year, amount, donation_count, pdf, sha256, replaces, issued_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetReceipt :one
+SELECT * FROM receipts
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLatestDonationReceipt :one
+SELECT * FROM receipts
+WHERE donation_id = $1 AND kind = 'donation'
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetLatestStatement :one
+SELECT * FROM receipts
+WHERE user_id = $1 AND year = $2 AND kind = 'statement'
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListReceiptsByUser :many
+SELECT id, number, kind, user_id, donation_id, year, amount, donation_count, sha256, replaces, issued_at, voided_at, void_reason
+FROM receipts
+WHERE user_id = $1
+ORDER BY issued_at DESC, id DESC;
+
+-- name: VoidReceipt :one
+UPDATE receipts
+SET voided_at = now(), void_reason = $2
+WHERE id = $1 AND voided_at IS NULL
+RETURNING *;
+
+-- name: VoidImportedDonationReceipts :execrows
+UPDATE receipts
+SET voided_at = now(), void_reason = $2
+WHERE kind = 'donation' AND voided_at IS NULL
+AND donation_id IN (SELECT d.id FROM donations d WHERE d.import_id = $1);
+
+-- name: LockDonation :one
+SELECT * FROM donations
+WHERE id = $1
+FOR UPDATE;
+
+-- name: LockStatement :exec
+SELECT pg_advisory_xact_lock(sqlc.arg(user_id)::int, sqlc.arg(year)::int);
+
+-- name: GetReceiptDonor :one
+SELECT id, username, email, first_name, last_name FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: ListStatementDonations :many
+-- Failed, refunded and cancelled donations are left off statements, as in RecomputeCauseTotals
+SELECT d.id, d.cause_id, d.amount, d.donation_type, d.created_at, COALESCE(c.name, '')::text AS cause_name
+FROM donations d
+LEFT JOIN causes c ON c.id = d.cause_id
+WHERE d.user_id = sqlc.arg(user_id)::int
+AND d.amount IS NOT NULL
+AND (d.status IS NULL OR d.status NOT IN ('failed', 'refunded', 'cancelled'))
+AND d.created_at >= sqlc.arg(year_start)::timestamp AND d.created_at < sqlc.arg(year_end)::timestamp
+ORDER BY d.created_at, d.id;
+
+-- name: ListReceiptTemplates :many
+SELECT * FROM receipt_templates
+WHERE cause_id IS NULL OR cause_id = sqlc.narg(cause_id)
+ORDER BY cause_id NULLS FIRST;
+
+-- name: SaveOrganizationReceiptTemplate :one
+INSERT INTO receipt_templates (cause_id, organization_name, address, registration_number, contact_email, accent_color, header_text, footer_text, signatory)
+VALUES (NULL, $1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT ((cause_id IS NULL)) WHERE cause_id IS NULL DO UPDATE
+SET organization_name = EXCLUDED.organization_name,
+    address = EXCLUDED.address,
+    registration_number = EXCLUDED.registration_number,
+    contact_email = EXCLUDED.contact_email,
+    accent_color = EXCLUDED.accent_color,
+    header_text = EXCLUDED.header_text,
+    footer_text = EXCLUDED.footer_text,
+    signatory = EXCLUDED.signatory,
+    updated_at = now()
+RETURNING *;
+
+-- name: SaveCauseReceiptTemplate :one
+INSERT INTO receipt_templates (cause_id, organization_name, address, registration_number, contact_email, accent_color, header_text, footer_text, signatory)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (cause_id) DO UPDATE
+SET organization_name = EXCLUDED.organization_name,
+    address = EXCLUDED.address,
+    registration_number = EXCLUDED.registration_number,
+    contact_email = EXCLUDED.contact_email,
+    accent_color = EXCLUDED.accent_color,
+    header_text = EXCLUDED.header_text,
+    footer_text = EXCLUDED.footer_text,
+    signatory = EXCLUDED.signatory,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetReceiptCause :one
+SELECT id, name, owner_id FROM causes
+WHERE id = $1 LIMIT 1;
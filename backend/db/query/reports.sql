@@ -0,0 +1,112 @@
+-- name: ExportDonations :many
+-- Reads donations for reports one page at a time in ID order; pass the last ID of a page as after_id
+-- to read the next
+SELECT d.id, d.created_at, d.amount, d.donation_type, d.status, d.source, d.import_id,
+    d.user_id, u.username AS donor_username, u.email AS donor_email,
+    d.cause_id, c.name AS cause_name,
+    d.team_id, t.name AS team_name
+FROM donations d
+LEFT JOIN users u ON u.id = d.user_id
+LEFT JOIN causes c ON c.id = d.cause_id
+LEFT JOIN teams t ON t.id = d.team_id
+WHERE d.id > sqlc.arg(after_id)::int
+AND (sqlc.narg(created_from)::timestamp IS NULL OR d.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR d.created_at < sqlc.narg(created_to))
+AND (sqlc.narg(cause_id)::int IS NULL OR d.cause_id = sqlc.narg(cause_id))
+AND (sqlc.narg(team_id)::int IS NULL OR d.team_id = sqlc.narg(team_id))
+AND (sqlc.narg(status)::text IS NULL OR d.status = sqlc.narg(status))
+ORDER BY d.id
+LIMIT sqlc.arg('limit');
+
+-- name: ExportDonors :many
+-- Totals the matching donations of each donor, one page of donors at a time in ID order
+SELECT u.id, u.username, u.email, u.first_name, u.last_name,
+    COUNT(d.id) AS donation_count,
+    COALESCE(SUM(d.amount), 0)::numeric AS total_amount,
+    MIN(d.created_at)::timestamp AS first_donation_at,
+    MAX(d.created_at)::timestamp AS last_donation_at
+FROM users u
+JOIN donations d ON d.user_id = u.id
+WHERE u.id > sqlc.arg(after_id)::int
+AND (sqlc.narg(created_from)::timestamp IS NULL OR d.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR d.created_at < sqlc.narg(created_to))
+AND (sqlc.narg(cause_id)::int IS NULL OR d.cause_id = sqlc.narg(cause_id))
+AND (sqlc.narg(team_id)::int IS NULL OR d.team_id = sqlc.narg(team_id))
+AND (sqlc.narg(status)::text IS NULL OR d.status = sqlc.narg(status))
+GROUP BY u.id
+ORDER BY u.id
+LIMIT sqlc.arg('limit');
+
+-- name: ExportTeamStats :many
+-- Totals the matching donations made through each team, one page of teams at a time in ID order.
+-- Teams without donations are included with zero totals.
+SELECT t.id, t.name, t.created_at,
+    (SELECT COUNT(*) FROM user_team ut WHERE ut.team_id = t.id) AS member_count,
+    COUNT(d.id) AS donation_count,
+    COUNT(DISTINCT d.user_id) AS donor_count,
+    COALESCE(SUM(d.amount), 0)::numeric AS total_amount
+FROM teams t
+LEFT JOIN donations d ON d.team_id = t.id
+    AND (sqlc.narg(created_from)::timestamp IS NULL OR d.created_at >= sqlc.narg(created_from))
+    AND (sqlc.narg(created_to)::timestamp IS NULL OR d.created_at < sqlc.narg(created_to))
+    AND (sqlc.narg(cause_id)::int IS NULL OR d.cause_id = sqlc.narg(cause_id))
+    AND (sqlc.narg(status)::text IS NULL OR d.status = sqlc.narg(status))
+WHERE t.deleted_at IS NULL
+AND t.id > sqlc.arg(after_id)::int
+AND (sqlc.narg(team_id)::int IS NULL OR t.id = sqlc.narg(team_id))
+GROUP BY t.id
+ORDER BY t.id
+LIMIT sqlc.arg('limit');
+
+-- name: CreateReportExport :one
+INSERT INTO report_exports (requested_by, dataset, format, columns, filters)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetReportExport :one
+SELECT * FROM report_exports
+WHERE id = $1 AND requested_by = $2 LIMIT 1;
+
+-- name: ListReportExportsByUser :many
+SELECT * FROM report_exports
+WHERE requested_by = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: ClaimPendingReportExport :one
+-- Claims the oldest pending report, or a running one whose worker has not finished it within
+-- stale_seconds and is taken to have stopped
+UPDATE report_exports
+SET status = 'running', started_at = now()
+WHERE id = (
+    SELECT id FROM report_exports
+    WHERE status = 'pending' OR (status = 'running' AND started_at < now() - make_interval(secs => @stale_seconds::float8))
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteReportExport :execrows
+-- Completes a report if it is still held by the claim that started it at started_at
+UPDATE report_exports
+SET status = 'completed', row_count = $3, completed_at = now(), expires_at = $4
+WHERE id = $1 AND status = 'running' AND started_at = $2;
+
+-- name: FailReportExport :exec
+UPDATE report_exports
+SET status = 'failed', error = $3, completed_at = now()
+WHERE id = $1 AND status = 'running' AND started_at = $2;
+
+-- name: CreateReportExportFile :exec
+INSERT INTO report_export_files (export_id, content)
+VALUES ($1, $2);
+
+-- name: GetReportExportFile :one
+SELECT content FROM report_export_files
+WHERE export_id = $1;
+
+-- name: DeleteExpiredReportExports :execrows
+-- Deletes completed reports past their download window, with their files
+DELETE FROM report_exports
+WHERE status = 'completed' AND expires_at < now();
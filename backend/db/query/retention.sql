@@ -0,0 +1,88 @@
+-- name: GetUserIncludingDeleted :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL
+RETURNING *;
+
+-- name: RestoreTeam :one
+UPDATE teams
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: RestoreCause :one
+UPDATE causes
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListPurgeableUsers :many
+SELECT * FROM users u
+WHERE u.deleted_at < $1
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.user_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM receipts r WHERE r.user_id = u.id)
+ORDER BY u.deleted_at;
+
+-- name: ListExpiredDeletedDonors :many
+SELECT u.id FROM users u
+WHERE u.deleted_at < $1
+AND u.anonymized_at IS NULL
+AND (EXISTS (SELECT 1 FROM donations d WHERE d.user_id = u.id)
+    OR EXISTS (SELECT 1 FROM receipts r WHERE r.user_id = u.id))
+ORDER BY u.deleted_at;
+
+-- name: ListPurgeableTeams :many
+SELECT * FROM teams t
+WHERE t.deleted_at < $1
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.team_id = t.id)
+ORDER BY t.deleted_at;
+
+-- name: ListPurgeableCauses :many
+SELECT * FROM causes c
+WHERE c.deleted_at < $1
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.cause_id = c.id)
+ORDER BY c.deleted_at;
+
+-- name: DeleteTeamMembershipsByUser :exec
+DELETE FROM user_team
+WHERE user_id = $1;
+
+-- name: DeleteTeamMembershipsByTeam :exec
+DELETE FROM user_team
+WHERE team_id = $1;
+
+-- name: DeleteLeaderboardEntriesByUser :exec
+DELETE FROM leaderboard_entries
+WHERE user_id = $1;
+
+-- name: DeleteLeaderboardEntriesByTeam :exec
+DELETE FROM leaderboard_entries
+WHERE team_id = $1;
+
+-- name: ClearCauseOwner :exec
+UPDATE causes
+SET owner_id = NULL, version = version + 1
+WHERE owner_id = $1;
+
+-- name: PurgeUser :execrows
+DELETE FROM users u
+WHERE u.id = $1
+AND u.deleted_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.user_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM receipts r WHERE r.user_id = u.id);
+
+-- name: PurgeTeam :execrows
+DELETE FROM teams t
+WHERE t.id = $1
+AND t.deleted_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.team_id = t.id);
+
+-- name: PurgeCause :execrows
+DELETE FROM causes c
+WHERE c.id = $1
+AND c.deleted_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.cause_id = c.id);
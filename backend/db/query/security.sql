@@ -0,0 +1,56 @@
+-- name: GetLoginThrottle :one
+SELECT * FROM login_throttle
+WHERE key = $1 LIMIT 1;
+
+-- name: TakeLoginAttempt :one
+-- Counts an attempt in the same statement that checks it is allowed, so concurrent attempts cannot
+-- all pass the check first. Nothing is returned while the key is locked or still backing off: the
+-- delay after a failure doubles from base_delay_seconds for each failure beyond free_attempts, up
+-- to max_delay_seconds. The attempt that reaches lockout_after locks the key for lockout_seconds.
+INSERT INTO login_throttle (key, failures, last_failure_at, locked_until)
+VALUES (
+    sqlc.arg(key), 1, now(),
+    CASE WHEN sqlc.arg(lockout_after)::int = 1 THEN now() + make_interval(secs => sqlc.arg(lockout_seconds)::float8) END
+)
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+        WHEN login_throttle.last_failure_at < now() - make_interval(secs => sqlc.arg(reset_after_seconds)::float8) THEN 1
+        ELSE login_throttle.failures + 1
+    END,
+    last_failure_at = now(),
+    locked_until = CASE
+        WHEN sqlc.arg(lockout_after)::int > 0 AND CASE
+                WHEN login_throttle.last_failure_at < now() - make_interval(secs => sqlc.arg(reset_after_seconds)::float8) THEN 1
+                ELSE login_throttle.failures + 1
+            END >= sqlc.arg(lockout_after)::int
+        THEN now() + make_interval(secs => sqlc.arg(lockout_seconds)::float8)
+    END
+WHERE (login_throttle.locked_until IS NULL OR login_throttle.locked_until <= now())
+    AND (
+        login_throttle.last_failure_at < now() - make_interval(secs => sqlc.arg(reset_after_seconds)::float8)
+        OR login_throttle.failures <= sqlc.arg(free_attempts)::int
+        OR login_throttle.last_failure_at + make_interval(secs => least(
+            sqlc.arg(max_delay_seconds)::float8,
+            sqlc.arg(base_delay_seconds)::float8 * power(2::float8, login_throttle.failures - sqlc.arg(free_attempts)::int - 1)
+        )) <= now()
+    )
+RETURNING *;
+
+-- name: ReleaseLoginAttempt :exec
+UPDATE login_throttle
+SET failures = greatest(failures - 1, 0)
+WHERE key = $1;
+
+-- name: DeleteLoginThrottle :exec
+DELETE FROM login_throttle
+WHERE key = $1;
+
+-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (user_id, event_type, ip, user_agent)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListSecurityEventsByUser :many
+SELECT * FROM security_events
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,5 @@
+-- name: CreateSeedDonation :one
+-- Like CreateDonation but backdated, so generated data spreads over time
+INSERT INTO donations (user_id, cause_id, team_id, amount, donation_type, status, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
@@ -0,0 +1,66 @@
+-- name: UpsertUserTOTP :one
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, enabled_at = NULL, last_used_step = 0, created_at = now()
+RETURNING *;
+
+-- name: GetUserTOTP :one
+SELECT * FROM user_totp
+WHERE user_id = $1 LIMIT 1;
+
+-- name: EnableUserTOTP :one
+UPDATE user_totp
+SET enabled_at = now(), last_used_step = $2
+WHERE user_id = $1
+RETURNING *;
+
+-- name: UpdateUserTOTPLastUsedStep :execrows
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1
+AND last_used_step < $2;
+
+-- name: DeleteUserTOTP :exec
+DELETE FROM user_totp
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: ConsumeRecoveryCode :execrows
+UPDATE user_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+AND code_hash = $2
+AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM user_recovery_codes
+WHERE user_id = $1
+AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes
+WHERE user_id = $1;
+
+-- name: GetTwoFactorStatus :one
+SELECT
+    COALESCE(rp.require_2fa, false)::boolean AS required,
+    (ut.enabled_at IS NOT NULL)::boolean AS enabled
+FROM users u
+LEFT JOIN role_policies rp ON rp.user_role = u.user_role
+LEFT JOIN user_totp ut ON ut.user_id = u.id
+WHERE u.id = $1;
+
+-- name: UpsertRolePolicy :one
+INSERT INTO role_policies (user_role, require_2fa)
+VALUES ($1, $2)
+ON CONFLICT (user_role) DO UPDATE
+SET require_2fa = EXCLUDED.require_2fa, updated_at = now()
+RETURNING *;
+
+-- name: ListRolePolicies :many
+SELECT * FROM role_policies
+ORDER BY user_role;
@@ -0,0 +1,173 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: admin.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const deleteLeaderboardEntriesByLeaderboard = `-- name: DeleteLeaderboardEntriesByLeaderboard :exec
+DELETE FROM leaderboard_entries
+WHERE leaderboard_id = $1
+`
+
+func (q *Queries) DeleteLeaderboardEntriesByLeaderboard(ctx context.Context, leaderboardID int32) error {
+	_, err := q.exec(ctx, q.deleteLeaderboardEntriesByLeaderboardStmt, deleteLeaderboardEntriesByLeaderboard, leaderboardID)
+	return err
+}
+
+const listLeaderboardIDs = `-- name: ListLeaderboardIDs :many
+SELECT id FROM leaderboards
+ORDER BY id
+`
+
+func (q *Queries) ListLeaderboardIDs(ctx context.Context) ([]int32, error) {
+	rows, err := q.query(ctx, q.listLeaderboardIDsStmt, listLeaderboardIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rebuildLeaderboardEntries = `-- name: RebuildLeaderboardEntries :execrows
+WITH board AS (
+    SELECT id, name, type, start_date, end_date, version FROM leaderboards WHERE leaderboards.id = $1
+), scores AS (
+    SELECT d.user_id, d.team_id, SUM(d.amount) AS score
+    FROM donations d, board b
+    WHERE d.user_id IS NOT NULL AND d.team_id IS NOT NULL AND d.amount IS NOT NULL
+    AND (d.status IS NULL OR d.status NOT IN ('failed', 'refunded', 'cancelled'))
+    AND (b.start_date IS NULL OR d.created_at >= b.start_date)
+    AND (b.end_date IS NULL OR d.created_at < b.end_date + 1)
+    GROUP BY d.user_id, d.team_id
+), team_scores AS (
+    SELECT scores.user_id, scores.team_id, scores.score, SUM(score) OVER (PARTITION BY team_id) AS team_score
+    FROM scores
+)
+INSERT INTO leaderboard_entries (leaderboard_id, user_id, team_id, score, rank)
+SELECT b.id, t.user_id, t.team_id,
+    CASE WHEN b.type = 'team' THEN t.team_score ELSE t.score END,
+    CASE WHEN b.type = 'team' THEN dense_rank() OVER (ORDER BY t.team_score DESC)
+         ELSE rank() OVER (ORDER BY t.score DESC) END
+FROM team_scores t, board b
+`
+
+// Scores are the donations made inside the leaderboard's dates by a donor for a team. Individual
+// boards rank each donor and team pair; team boards give every member the team's total and rank.
+func (q *Queries) RebuildLeaderboardEntries(ctx context.Context, id int32) (int64, error) {
+	result, err := q.exec(ctx, q.rebuildLeaderboardEntriesStmt, rebuildLeaderboardEntries, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recomputeCauseTotals = `-- name: RecomputeCauseTotals :many
+WITH totals AS (
+    SELECT c.id, COALESCE(SUM(d.amount) FILTER (WHERE d.status IS NULL OR d.status NOT IN ('failed', 'refunded', 'cancelled')), 0) AS total
+    FROM causes c
+    LEFT JOIN donations d ON d.cause_id = c.id
+    WHERE c.deleted_at IS NULL
+    GROUP BY c.id
+)
+UPDATE causes
+SET current_amount = totals.total, updated_at = CURRENT_TIMESTAMP, version = version + 1
+FROM totals
+WHERE causes.id = totals.id AND causes.current_amount IS DISTINCT FROM totals.total
+RETURNING causes.id, causes.name, causes.description, causes.goal, causes.current_amount, causes.start_date, causes.end_date, causes.status, causes.created_at, causes.updated_at, causes.image, causes.category, causes.owner_id, causes.deleted_at, causes.version
+`
+
+// Failed, refunded and cancelled donations do not count towards a cause
+func (q *Queries) RecomputeCauseTotals(ctx context.Context) ([]Cause, error) {
+	rows, err := q.query(ctx, q.recomputeCauseTotalsStmt, recomputeCauseTotals)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Cause{}
+	for rows.Next() {
+		var i Cause
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Goal,
+			&i.CurrentAmount,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Image,
+			&i.Category,
+			&i.OwnerID,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserRole = `-- name: SetUserRole :one
+UPDATE users
+SET user_role = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
+`
+
+type SetUserRoleParams struct {
+	ID       int32          `json:"id"`
+	UserRole sql.NullString `json:"user_role"`
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error) {
+	row := q.queryRow(ctx, q.setUserRoleStmt, setUserRole, arg.ID, arg.UserRole)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
@@ -0,0 +1,375 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: analytics.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimDirtyRollupBuckets = `-- name: ClaimDirtyRollupBuckets :many
+DELETE FROM donation_rollup_dirty
+WHERE bucket IN (
+    SELECT bucket FROM donation_rollup_dirty
+    ORDER BY bucket
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING bucket
+`
+
+// Takes up to limit dirty buckets, skipping any that an open transaction is still writing to
+func (q *Queries) ClaimDirtyRollupBuckets(ctx context.Context, limit int32) ([]time.Time, error) {
+	rows, err := q.query(ctx, q.claimDirtyRollupBucketsStmt, claimDirtyRollupBuckets, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []time.Time{}
+	for rows.Next() {
+		var bucket time.Time
+		if err := rows.Scan(&bucket); err != nil {
+			return nil, err
+		}
+		items = append(items, bucket)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countDirtyRollupBuckets = `-- name: CountDirtyRollupBuckets :one
+SELECT COUNT(*) FROM donation_rollup_dirty
+WHERE bucket >= $1::timestamp AND bucket < $2::timestamp
+`
+
+type CountDirtyRollupBucketsParams struct {
+	BucketFrom time.Time `json:"bucket_from"`
+	BucketTo   time.Time `json:"bucket_to"`
+}
+
+func (q *Queries) CountDirtyRollupBuckets(ctx context.Context, arg CountDirtyRollupBucketsParams) (int64, error) {
+	row := q.queryRow(ctx, q.countDirtyRollupBucketsStmt, countDirtyRollupBuckets, arg.BucketFrom, arg.BucketTo)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteDonationRollups = `-- name: DeleteDonationRollups :exec
+DELETE FROM donation_rollups
+WHERE bucket = ANY($1::timestamp[])
+`
+
+func (q *Queries) DeleteDonationRollups(ctx context.Context, buckets []time.Time) error {
+	_, err := q.exec(ctx, q.deleteDonationRollupsStmt, deleteDonationRollups, pq.Array(buckets))
+	return err
+}
+
+const donationAnalytics = `-- name: DonationAnalytics :many
+WITH matched AS (
+    SELECT
+        CASE WHEN $1::bool
+            THEN date_trunc($2::text, (r.bucket AT TIME ZONE 'UTC') AT TIME ZONE $3::text)
+            ELSE TIMESTAMP 'epoch'
+        END AS period_start,
+        CASE $4::text
+            WHEN 'cause' THEN r.cause_id::text
+            WHEN 'team' THEN r.team_id::text
+            WHEN 'donation_type' THEN r.donation_type::text
+            WHEN 'category' THEN COALESCE(c.category, '')::text
+            ELSE ''
+        END AS group_key,
+        CASE $4::text
+            WHEN 'cause' THEN COALESCE(c.name, '')::text
+            WHEN 'team' THEN COALESCE(t.name, '')::text
+            ELSE ''
+        END AS group_label,
+        r.donation_count, r.amount_sum, r.donor_ids
+    FROM donation_rollups r
+    LEFT JOIN causes c ON c.id = r.cause_id
+    LEFT JOIN teams t ON t.id = r.team_id
+    WHERE r.bucket >= $5::timestamp AND r.bucket < $6::timestamp
+    AND ($7::int IS NULL OR r.cause_id = $7)
+    AND ($8::int IS NULL OR r.team_id = $8)
+    AND ($9::text IS NULL OR r.donation_type = $9)
+), totals AS (
+    SELECT m.period_start, m.group_key, MAX(m.group_label) AS group_label,
+        SUM(m.donation_count) AS donation_count, SUM(m.amount_sum) AS amount_sum
+    FROM matched m
+    GROUP BY m.period_start, m.group_key
+), donors AS (
+    SELECT m.period_start, m.group_key, COUNT(DISTINCT donor.user_id) AS unique_donors
+    FROM matched m, unnest(m.donor_ids) AS donor(user_id)
+    GROUP BY m.period_start, m.group_key
+)
+SELECT totals.period_start::timestamp AS period_start,
+    totals.group_key::text AS group_key,
+    totals.group_label::text AS group_label,
+    totals.donation_count::bigint AS donation_count,
+    totals.amount_sum::numeric AS amount_sum,
+    COALESCE(ROUND(totals.amount_sum / NULLIF(totals.donation_count, 0), 2), 0)::numeric AS average_gift,
+    COALESCE(donors.unique_donors, 0)::bigint AS unique_donors
+FROM totals
+LEFT JOIN donors ON donors.period_start = totals.period_start AND donors.group_key = totals.group_key
+ORDER BY totals.group_key, totals.period_start
+`
+
+type DonationAnalyticsParams struct {
+	Bucketed     bool           `json:"bucketed"`
+	Period       string         `json:"period"`
+	TimeZone     string         `json:"time_zone"`
+	GroupBy      string         `json:"group_by"`
+	BucketFrom   time.Time      `json:"bucket_from"`
+	BucketTo     time.Time      `json:"bucket_to"`
+	CauseID      sql.NullInt32  `json:"cause_id"`
+	TeamID       sql.NullInt32  `json:"team_id"`
+	DonationType sql.NullString `json:"donation_type"`
+}
+
+type DonationAnalyticsRow struct {
+	PeriodStart   time.Time `json:"period_start"`
+	GroupKey      string    `json:"group_key"`
+	GroupLabel    string    `json:"group_label"`
+	DonationCount int64     `json:"donation_count"`
+	AmountSum     string    `json:"amount_sum"`
+	AverageGift   string    `json:"average_gift"`
+	UniqueDonors  int64     `json:"unique_donors"`
+}
+
+// Totals rollups per group, and per period when bucketed. Periods are truncated in time_zone and
+// returned as local times; weeks start on Monday.
+func (q *Queries) DonationAnalytics(ctx context.Context, arg DonationAnalyticsParams) ([]DonationAnalyticsRow, error) {
+	rows, err := q.query(ctx, q.donationAnalyticsStmt, donationAnalytics,
+		arg.Bucketed,
+		arg.Period,
+		arg.TimeZone,
+		arg.GroupBy,
+		arg.BucketFrom,
+		arg.BucketTo,
+		arg.CauseID,
+		arg.TeamID,
+		arg.DonationType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DonationAnalyticsRow{}
+	for rows.Next() {
+		var i DonationAnalyticsRow
+		if err := rows.Scan(
+			&i.PeriodStart,
+			&i.GroupKey,
+			&i.GroupLabel,
+			&i.DonationCount,
+			&i.AmountSum,
+			&i.AverageGift,
+			&i.UniqueDonors,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const donorCohorts = `-- name: DonorCohorts :many
+WITH gifts AS (
+    SELECT d.user_id, COALESCE(d.amount, 0) AS amount,
+        date_trunc('month', (d.created_at AT TIME ZONE 'UTC') AT TIME ZONE $3::text) AS month
+    FROM donations d
+    WHERE d.user_id IS NOT NULL AND d.created_at IS NOT NULL
+), cohorts AS (
+    SELECT gifts.user_id, MIN(gifts.month) AS cohort
+    FROM gifts
+    GROUP BY gifts.user_id
+)
+SELECT c.cohort::timestamp AS cohort,
+    ((EXTRACT(YEAR FROM g.month) - EXTRACT(YEAR FROM c.cohort)) * 12
+        + EXTRACT(MONTH FROM g.month) - EXTRACT(MONTH FROM c.cohort))::int AS month_offset,
+    COUNT(DISTINCT g.user_id)::bigint AS donors,
+    COUNT(*)::bigint AS donation_count,
+    SUM(g.amount)::numeric AS amount_sum
+FROM cohorts c
+JOIN gifts g ON g.user_id = c.user_id
+WHERE c.cohort >= $1::timestamp AND c.cohort < $2::timestamp
+GROUP BY 1, 2
+ORDER BY 1, 2
+`
+
+type DonorCohortsParams struct {
+	CohortFrom time.Time `json:"cohort_from"`
+	CohortTo   time.Time `json:"cohort_to"`
+	TimeZone   string    `json:"time_zone"`
+}
+
+type DonorCohortsRow struct {
+	Cohort        time.Time `json:"cohort"`
+	MonthOffset   int32     `json:"month_offset"`
+	Donors        int64     `json:"donors"`
+	DonationCount int64     `json:"donation_count"`
+	AmountSum     string    `json:"amount_sum"`
+}
+
+// Groups donors by the month of their first donation and totals each cohort's donations per month
+// since; month_offset 0 is the first month. Months are calendar months in time_zone.
+func (q *Queries) DonorCohorts(ctx context.Context, arg DonorCohortsParams) ([]DonorCohortsRow, error) {
+	rows, err := q.query(ctx, q.donorCohortsStmt, donorCohorts, arg.CohortFrom, arg.CohortTo, arg.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DonorCohortsRow{}
+	for rows.Next() {
+		var i DonorCohortsRow
+		if err := rows.Scan(
+			&i.Cohort,
+			&i.MonthOffset,
+			&i.Donors,
+			&i.DonationCount,
+			&i.AmountSum,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDonorSummaries = `-- name: ListDonorSummaries :many
+WITH gifts AS (
+    SELECT d.user_id, d.created_at, COALESCE(d.amount, 0) AS amount,
+        date_trunc('month', (d.created_at AT TIME ZONE 'UTC') AT TIME ZONE $3::text) AS month
+    FROM donations d
+    WHERE d.user_id IS NOT NULL AND d.created_at IS NOT NULL
+), memberships AS (
+    SELECT ut.user_id, MIN(ut.joined_at) AS joined_at
+    FROM user_team ut
+    GROUP BY ut.user_id
+)
+SELECT g.user_id::int AS user_id,
+    u.username,
+    u.email,
+    (u.deleted_at IS NULL AND u.anonymized_at IS NULL)::bool AS active,
+    MIN(g.month)::timestamp AS cohort,
+    MIN(g.created_at)::timestamp AS first_donation,
+    MAX(g.created_at)::timestamp AS last_donation,
+    COUNT(*)::bigint AS donation_count,
+    SUM(g.amount)::numeric AS amount_sum,
+    COUNT(DISTINCT g.month)::bigint AS active_months,
+    (m.joined_at IS NOT NULL)::bool AS in_team,
+    COALESCE(m.joined_at, TIMESTAMP 'epoch')::timestamp AS joined_team_at,
+    COUNT(DISTINCT g.month) FILTER (WHERE g.created_at < m.joined_at)::bigint AS active_months_before_join,
+    COUNT(DISTINCT g.month) FILTER (WHERE g.created_at >= m.joined_at)::bigint AS active_months_after_join
+FROM gifts g
+JOIN users u ON u.id = g.user_id
+LEFT JOIN memberships m ON m.user_id = g.user_id
+GROUP BY g.user_id, u.username, u.email, u.deleted_at, u.anonymized_at, m.joined_at
+HAVING ($1::timestamp IS NULL OR MIN(g.month) >= $1::timestamp)
+    AND ($2::timestamp IS NULL OR MIN(g.month) < $2::timestamp)
+ORDER BY g.user_id
+`
+
+type ListDonorSummariesParams struct {
+	CohortFrom sql.NullTime `json:"cohort_from"`
+	CohortTo   sql.NullTime `json:"cohort_to"`
+	TimeZone   string       `json:"time_zone"`
+}
+
+type ListDonorSummariesRow struct {
+	UserID                 int32     `json:"user_id"`
+	Username               string    `json:"username"`
+	Email                  string    `json:"email"`
+	Active                 bool      `json:"active"`
+	Cohort                 time.Time `json:"cohort"`
+	FirstDonation          time.Time `json:"first_donation"`
+	LastDonation           time.Time `json:"last_donation"`
+	DonationCount          int64     `json:"donation_count"`
+	AmountSum              string    `json:"amount_sum"`
+	ActiveMonths           int64     `json:"active_months"`
+	InTeam                 bool      `json:"in_team"`
+	JoinedTeamAt           time.Time `json:"joined_team_at"`
+	ActiveMonthsBeforeJoin int64     `json:"active_months_before_join"`
+	ActiveMonthsAfterJoin  int64     `json:"active_months_after_join"`
+}
+
+// One row per donor whose first donation falls in the cohort range, or every donor without one.
+// Active months are the calendar months in time_zone with at least one donation, split at the
+// donor's first team join.
+func (q *Queries) ListDonorSummaries(ctx context.Context, arg ListDonorSummariesParams) ([]ListDonorSummariesRow, error) {
+	rows, err := q.query(ctx, q.listDonorSummariesStmt, listDonorSummaries, arg.CohortFrom, arg.CohortTo, arg.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDonorSummariesRow{}
+	for rows.Next() {
+		var i ListDonorSummariesRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.Email,
+			&i.Active,
+			&i.Cohort,
+			&i.FirstDonation,
+			&i.LastDonation,
+			&i.DonationCount,
+			&i.AmountSum,
+			&i.ActiveMonths,
+			&i.InTeam,
+			&i.JoinedTeamAt,
+			&i.ActiveMonthsBeforeJoin,
+			&i.ActiveMonthsAfterJoin,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rebuildDonationRollups = `-- name: RebuildDonationRollups :execrows
+INSERT INTO donation_rollups (bucket, cause_id, team_id, donation_type, donation_count, amount_sum, donor_ids)
+SELECT b.bucket, COALESCE(d.cause_id, 0), COALESCE(d.team_id, 0), COALESCE(d.donation_type, ''),
+    COUNT(*), COALESCE(SUM(d.amount), 0),
+    COALESCE(array_agg(DISTINCT d.user_id) FILTER (WHERE d.user_id IS NOT NULL), '{}')
+FROM unnest($1::timestamp[]) AS b(bucket)
+JOIN donations d ON d.created_at >= b.bucket AND d.created_at < b.bucket + INTERVAL '15 minutes'
+GROUP BY 1, 2, 3, 4
+`
+
+func (q *Queries) RebuildDonationRollups(ctx context.Context, buckets []time.Time) (int64, error) {
+	result, err := q.exec(ctx, q.rebuildDonationRollupsStmt, rebuildDonationRollups, pq.Array(buckets))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
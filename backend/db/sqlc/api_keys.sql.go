@@ -0,0 +1,193 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: api_keys.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, team_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, team_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+`
+
+type CreateAPIKeyParams struct {
+	UserID    int32         `json:"user_id"`
+	TeamID    sql.NullInt32 `json:"team_id"`
+	Name      string        `json:"name"`
+	Prefix    string        `json:"prefix"`
+	KeyHash   string        `json:"key_hash"`
+	Scopes    []string      `json:"scopes"`
+	ExpiresAt sql.NullTime  `json:"expires_at"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.queryRow(ctx, q.createAPIKeyStmt, createAPIKey,
+		arg.UserID,
+		arg.TeamID,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		pq.Array(arg.Scopes),
+		arg.ExpiresAt,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TeamID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		pq.Array(&i.Scopes),
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getActiveAPIKeyByHash = `-- name: GetActiveAPIKeyByHash :one
+SELECT api_keys.id, api_keys.user_id, api_keys.team_id, api_keys.name, api_keys.prefix, api_keys.key_hash, api_keys.scopes, api_keys.expires_at, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at FROM api_keys
+JOIN users ON users.id = api_keys.user_id
+LEFT JOIN teams ON teams.id = api_keys.team_id
+WHERE api_keys.key_hash = $1
+AND api_keys.revoked_at IS NULL
+AND users.deleted_at IS NULL
+AND teams.deleted_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error) {
+	row := q.queryRow(ctx, q.getActiveAPIKeyByHashStmt, getActiveAPIKeyByHash, keyHash)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TeamID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		pq.Array(&i.Scopes),
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTeam = `-- name: GetUserTeam :one
+SELECT user_id, team_id, role, joined_at FROM user_team
+WHERE user_id = $1 AND team_id = $2 LIMIT 1
+`
+
+type GetUserTeamParams struct {
+	UserID int32 `json:"user_id"`
+	TeamID int32 `json:"team_id"`
+}
+
+func (q *Queries) GetUserTeam(ctx context.Context, arg GetUserTeamParams) (UserTeam, error) {
+	row := q.queryRow(ctx, q.getUserTeamStmt, getUserTeam, arg.UserID, arg.TeamID)
+	var i UserTeam
+	err := row.Scan(
+		&i.UserID,
+		&i.TeamID,
+		&i.Role,
+		&i.JoinedAt,
+	)
+	return i, err
+}
+
+const listAPIKeysByUser = `-- name: ListAPIKeysByUser :many
+SELECT id, user_id, team_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListAPIKeysByUser(ctx context.Context, userID int32) ([]ApiKey, error) {
+	rows, err := q.query(ctx, q.listAPIKeysByUserStmt, listAPIKeysByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiKey{}
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.TeamID,
+			&i.Name,
+			&i.Prefix,
+			&i.KeyHash,
+			pq.Array(&i.Scopes),
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1
+AND user_id = $2
+AND revoked_at IS NULL
+RETURNING id, user_id, team_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+`
+
+type RevokeAPIKeyParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error) {
+	row := q.queryRow(ctx, q.revokeAPIKeyStmt, revokeAPIKey, arg.ID, arg.UserID)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TeamID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		pq.Array(&i.Scopes),
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.touchAPIKeyStmt, touchAPIKey, id)
+	return err
+}
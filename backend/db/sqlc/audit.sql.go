@@ -0,0 +1,185 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: audit.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/sqlc-dev/pqtype"
+)
+
+const createAuditLogEntry = `-- name: CreateAuditLogEntry :one
+INSERT INTO audit_log (actor_id, action, entity_type, entity_id, before, after, diff, request_id, ip)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, actor_id, action, entity_type, entity_id, before, after, diff, request_id, ip, created_at
+`
+
+type CreateAuditLogEntryParams struct {
+	ActorID    sql.NullInt32         `json:"actor_id"`
+	Action     string                `json:"action"`
+	EntityType string                `json:"entity_type"`
+	EntityID   string                `json:"entity_id"`
+	Before     pqtype.NullRawMessage `json:"before"`
+	After      pqtype.NullRawMessage `json:"after"`
+	Diff       json.RawMessage       `json:"diff"`
+	RequestID  sql.NullString        `json:"request_id"`
+	Ip         sql.NullString        `json:"ip"`
+}
+
+func (q *Queries) CreateAuditLogEntry(ctx context.Context, arg CreateAuditLogEntryParams) (AuditLog, error) {
+	row := q.queryRow(ctx, q.createAuditLogEntryStmt, createAuditLogEntry,
+		arg.ActorID,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Before,
+		arg.After,
+		arg.Diff,
+		arg.RequestID,
+		arg.Ip,
+	)
+	var i AuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.ActorID,
+		&i.Action,
+		&i.EntityType,
+		&i.EntityID,
+		&i.Before,
+		&i.After,
+		&i.Diff,
+		&i.RequestID,
+		&i.Ip,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAuditLog = `-- name: ListAuditLog :many
+SELECT id, actor_id, action, entity_type, entity_id, before, after, diff, request_id, ip, created_at FROM audit_log
+WHERE ($1::int IS NULL OR actor_id = $1)
+AND ($2::text IS NULL OR action = $2)
+AND ($3::text IS NULL OR entity_type = $3)
+AND ($4::text IS NULL OR entity_id = $4)
+AND ($5::text IS NULL OR request_id = $5)
+AND ($6::timestamp IS NULL OR created_at >= $6)
+AND ($7::timestamp IS NULL OR created_at < $7)
+ORDER BY created_at DESC, id DESC
+LIMIT $9 OFFSET $8
+`
+
+type ListAuditLogParams struct {
+	ActorID     sql.NullInt32  `json:"actor_id"`
+	Action      sql.NullString `json:"action"`
+	EntityType  sql.NullString `json:"entity_type"`
+	EntityID    sql.NullString `json:"entity_id"`
+	RequestID   sql.NullString `json:"request_id"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	Offset      int32          `json:"offset"`
+	Limit       int32          `json:"limit"`
+}
+
+func (q *Queries) ListAuditLog(ctx context.Context, arg ListAuditLogParams) ([]AuditLog, error) {
+	rows, err := q.query(ctx, q.listAuditLogStmt, listAuditLog,
+		arg.ActorID,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.RequestID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditLog{}
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Before,
+			&i.After,
+			&i.Diff,
+			&i.RequestID,
+			&i.Ip,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditLogByEntity = `-- name: ListAuditLogByEntity :many
+SELECT id, actor_id, action, entity_type, entity_id, before, after, diff, request_id, ip, created_at FROM audit_log
+WHERE entity_type = $1 AND entity_id = $2
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListAuditLogByEntityParams struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Limit      int32  `json:"limit"`
+	Offset     int32  `json:"offset"`
+}
+
+func (q *Queries) ListAuditLogByEntity(ctx context.Context, arg ListAuditLogByEntityParams) ([]AuditLog, error) {
+	rows, err := q.query(ctx, q.listAuditLogByEntityStmt, listAuditLogByEntity,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditLog{}
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Before,
+			&i.After,
+			&i.Diff,
+			&i.RequestID,
+			&i.Ip,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,150 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: auth.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const consumeUserActionToken = `-- name: ConsumeUserActionToken :one
+UPDATE user_action_tokens
+SET used_at = now()
+WHERE token_hash = $1
+AND purpose = $2
+AND used_at IS NULL
+AND expires_at > now()
+RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at
+`
+
+type ConsumeUserActionTokenParams struct {
+	TokenHash string `json:"token_hash"`
+	Purpose   string `json:"purpose"`
+}
+
+func (q *Queries) ConsumeUserActionToken(ctx context.Context, arg ConsumeUserActionTokenParams) (UserActionToken, error) {
+	row := q.queryRow(ctx, q.consumeUserActionTokenStmt, consumeUserActionToken, arg.TokenHash, arg.Purpose)
+	var i UserActionToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createUserActionToken = `-- name: CreateUserActionToken :one
+INSERT INTO user_action_tokens (user_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at
+`
+
+type CreateUserActionTokenParams struct {
+	UserID    int32     `json:"user_id"`
+	Purpose   string    `json:"purpose"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateUserActionToken(ctx context.Context, arg CreateUserActionTokenParams) (UserActionToken, error) {
+	row := q.queryRow(ctx, q.createUserActionTokenStmt, createUserActionToken,
+		arg.UserID,
+		arg.Purpose,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var i UserActionToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteUserTokensByUserID = `-- name: DeleteUserTokensByUserID :exec
+DELETE FROM user_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserTokensByUserID(ctx context.Context, userID sql.NullInt32) error {
+	_, err := q.exec(ctx, q.deleteUserTokensByUserIDStmt, deleteUserTokensByUserID, userID)
+	return err
+}
+
+const invalidateUserActionTokens = `-- name: InvalidateUserActionTokens :exec
+UPDATE user_action_tokens
+SET used_at = now()
+WHERE user_id = $1
+AND purpose = $2
+AND used_at IS NULL
+`
+
+type InvalidateUserActionTokensParams struct {
+	UserID  int32  `json:"user_id"`
+	Purpose string `json:"purpose"`
+}
+
+func (q *Queries) InvalidateUserActionTokens(ctx context.Context, arg InvalidateUserActionTokensParams) error {
+	_, err := q.exec(ctx, q.invalidateUserActionTokensStmt, invalidateUserActionTokens, arg.UserID, arg.Purpose)
+	return err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :one
+UPDATE users
+SET email_verified_at = now(), updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id int32) (User, error) {
+	row := q.queryRow(ctx, q.markUserEmailVerifiedStmt, markUserEmailVerified, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           int32  `json:"id"`
+	PasswordHash string `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.exec(ctx, q.updateUserPasswordStmt, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
@@ -27,84 +27,462 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.addUserToTeamStmt, err = db.PrepareContext(ctx, addUserToTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query AddUserToTeam: %w", err)
 	}
+	if q.anonymizeUserStmt, err = db.PrepareContext(ctx, anonymizeUser); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUser: %w", err)
+	}
+	if q.cancelUserErasureStmt, err = db.PrepareContext(ctx, cancelUserErasure); err != nil {
+		return nil, fmt.Errorf("error preparing query CancelUserErasure: %w", err)
+	}
+	if q.claimDirtyRollupBucketsStmt, err = db.PrepareContext(ctx, claimDirtyRollupBuckets); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimDirtyRollupBuckets: %w", err)
+	}
+	if q.claimPendingDataExportStmt, err = db.PrepareContext(ctx, claimPendingDataExport); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimPendingDataExport: %w", err)
+	}
+	if q.claimPendingReportExportStmt, err = db.PrepareContext(ctx, claimPendingReportExport); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimPendingReportExport: %w", err)
+	}
+	if q.clearCauseOwnerStmt, err = db.PrepareContext(ctx, clearCauseOwner); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearCauseOwner: %w", err)
+	}
+	if q.completeDataExportStmt, err = db.PrepareContext(ctx, completeDataExport); err != nil {
+		return nil, fmt.Errorf("error preparing query CompleteDataExport: %w", err)
+	}
+	if q.completeDonationImportStmt, err = db.PrepareContext(ctx, completeDonationImport); err != nil {
+		return nil, fmt.Errorf("error preparing query CompleteDonationImport: %w", err)
+	}
+	if q.completeReportExportStmt, err = db.PrepareContext(ctx, completeReportExport); err != nil {
+		return nil, fmt.Errorf("error preparing query CompleteReportExport: %w", err)
+	}
+	if q.consumeRecoveryCodeStmt, err = db.PrepareContext(ctx, consumeRecoveryCode); err != nil {
+		return nil, fmt.Errorf("error preparing query ConsumeRecoveryCode: %w", err)
+	}
+	if q.consumeUserActionTokenStmt, err = db.PrepareContext(ctx, consumeUserActionToken); err != nil {
+		return nil, fmt.Errorf("error preparing query ConsumeUserActionToken: %w", err)
+	}
+	if q.countDirtyRollupBucketsStmt, err = db.PrepareContext(ctx, countDirtyRollupBuckets); err != nil {
+		return nil, fmt.Errorf("error preparing query CountDirtyRollupBuckets: %w", err)
+	}
+	if q.countUnusedRecoveryCodesStmt, err = db.PrepareContext(ctx, countUnusedRecoveryCodes); err != nil {
+		return nil, fmt.Errorf("error preparing query CountUnusedRecoveryCodes: %w", err)
+	}
+	if q.createAPIKeyStmt, err = db.PrepareContext(ctx, createAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAPIKey: %w", err)
+	}
+	if q.createAuditLogEntryStmt, err = db.PrepareContext(ctx, createAuditLogEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAuditLogEntry: %w", err)
+	}
 	if q.createCauseStmt, err = db.PrepareContext(ctx, createCause); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateCause: %w", err)
 	}
+	if q.createDataExportStmt, err = db.PrepareContext(ctx, createDataExport); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDataExport: %w", err)
+	}
+	if q.createDataExportArchiveStmt, err = db.PrepareContext(ctx, createDataExportArchive); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDataExportArchive: %w", err)
+	}
 	if q.createDonationStmt, err = db.PrepareContext(ctx, createDonation); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateDonation: %w", err)
 	}
+	if q.createDonationImportStmt, err = db.PrepareContext(ctx, createDonationImport); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDonationImport: %w", err)
+	}
+	if q.createDonationImportRowsStmt, err = db.PrepareContext(ctx, createDonationImportRows); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDonationImportRows: %w", err)
+	}
 	if q.createLeaderboardStmt, err = db.PrepareContext(ctx, createLeaderboard); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateLeaderboard: %w", err)
 	}
+	if q.createReceiptStmt, err = db.PrepareContext(ctx, createReceipt); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateReceipt: %w", err)
+	}
+	if q.createRecoveryCodeStmt, err = db.PrepareContext(ctx, createRecoveryCode); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRecoveryCode: %w", err)
+	}
+	if q.createReportExportStmt, err = db.PrepareContext(ctx, createReportExport); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateReportExport: %w", err)
+	}
+	if q.createReportExportFileStmt, err = db.PrepareContext(ctx, createReportExportFile); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateReportExportFile: %w", err)
+	}
+	if q.createSecurityEventStmt, err = db.PrepareContext(ctx, createSecurityEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSecurityEvent: %w", err)
+	}
+	if q.createSeedDonationStmt, err = db.PrepareContext(ctx, createSeedDonation); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSeedDonation: %w", err)
+	}
 	if q.createTeamStmt, err = db.PrepareContext(ctx, createTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTeam: %w", err)
 	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
+	if q.createUserActionTokenStmt, err = db.PrepareContext(ctx, createUserActionToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserActionToken: %w", err)
+	}
+	if q.createUserIdentityStmt, err = db.PrepareContext(ctx, createUserIdentity); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserIdentity: %w", err)
+	}
 	if q.createUserTokenStmt, err = db.PrepareContext(ctx, createUserToken); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUserToken: %w", err)
 	}
 	if q.deleteCauseStmt, err = db.PrepareContext(ctx, deleteCause); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteCause: %w", err)
 	}
+	if q.deleteDataExportsByUserStmt, err = db.PrepareContext(ctx, deleteDataExportsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDataExportsByUser: %w", err)
+	}
+	if q.deleteDonationImportRowsStmt, err = db.PrepareContext(ctx, deleteDonationImportRows); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDonationImportRows: %w", err)
+	}
+	if q.deleteDonationRollupsStmt, err = db.PrepareContext(ctx, deleteDonationRollups); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDonationRollups: %w", err)
+	}
+	if q.deleteExpiredDataExportsStmt, err = db.PrepareContext(ctx, deleteExpiredDataExports); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredDataExports: %w", err)
+	}
+	if q.deleteExpiredReportExportsStmt, err = db.PrepareContext(ctx, deleteExpiredReportExports); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredReportExports: %w", err)
+	}
 	if q.deleteExpiredTokensStmt, err = db.PrepareContext(ctx, deleteExpiredTokens); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteExpiredTokens: %w", err)
 	}
+	if q.deleteImportedDonationsStmt, err = db.PrepareContext(ctx, deleteImportedDonations); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteImportedDonations: %w", err)
+	}
+	if q.deleteLeaderboardEntriesByLeaderboardStmt, err = db.PrepareContext(ctx, deleteLeaderboardEntriesByLeaderboard); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteLeaderboardEntriesByLeaderboard: %w", err)
+	}
+	if q.deleteLeaderboardEntriesByTeamStmt, err = db.PrepareContext(ctx, deleteLeaderboardEntriesByTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteLeaderboardEntriesByTeam: %w", err)
+	}
+	if q.deleteLeaderboardEntriesByUserStmt, err = db.PrepareContext(ctx, deleteLeaderboardEntriesByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteLeaderboardEntriesByUser: %w", err)
+	}
+	if q.deleteLoginThrottleStmt, err = db.PrepareContext(ctx, deleteLoginThrottle); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteLoginThrottle: %w", err)
+	}
+	if q.deleteLoginThrottleForEmailStmt, err = db.PrepareContext(ctx, deleteLoginThrottleForEmail); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteLoginThrottleForEmail: %w", err)
+	}
+	if q.deleteRecoveryCodesStmt, err = db.PrepareContext(ctx, deleteRecoveryCodes); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteRecoveryCodes: %w", err)
+	}
 	if q.deleteTeamStmt, err = db.PrepareContext(ctx, deleteTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteTeam: %w", err)
 	}
+	if q.deleteTeamMembershipsByTeamStmt, err = db.PrepareContext(ctx, deleteTeamMembershipsByTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTeamMembershipsByTeam: %w", err)
+	}
+	if q.deleteTeamMembershipsByUserStmt, err = db.PrepareContext(ctx, deleteTeamMembershipsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTeamMembershipsByUser: %w", err)
+	}
 	if q.deleteUserStmt, err = db.PrepareContext(ctx, deleteUser); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUser: %w", err)
 	}
+	if q.deleteUserPersonalDataStmt, err = db.PrepareContext(ctx, deleteUserPersonalData); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserPersonalData: %w", err)
+	}
+	if q.deleteUserTOTPStmt, err = db.PrepareContext(ctx, deleteUserTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserTOTP: %w", err)
+	}
 	if q.deleteUserTokenStmt, err = db.PrepareContext(ctx, deleteUserToken); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUserToken: %w", err)
 	}
+	if q.deleteUserTokensByUserIDStmt, err = db.PrepareContext(ctx, deleteUserTokensByUserID); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserTokensByUserID: %w", err)
+	}
+	if q.donationAnalyticsStmt, err = db.PrepareContext(ctx, donationAnalytics); err != nil {
+		return nil, fmt.Errorf("error preparing query DonationAnalytics: %w", err)
+	}
+	if q.donorCohortsStmt, err = db.PrepareContext(ctx, donorCohorts); err != nil {
+		return nil, fmt.Errorf("error preparing query DonorCohorts: %w", err)
+	}
+	if q.enableUserTOTPStmt, err = db.PrepareContext(ctx, enableUserTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query EnableUserTOTP: %w", err)
+	}
+	if q.exportDonationsStmt, err = db.PrepareContext(ctx, exportDonations); err != nil {
+		return nil, fmt.Errorf("error preparing query ExportDonations: %w", err)
+	}
+	if q.exportDonorsStmt, err = db.PrepareContext(ctx, exportDonors); err != nil {
+		return nil, fmt.Errorf("error preparing query ExportDonors: %w", err)
+	}
+	if q.exportTeamStatsStmt, err = db.PrepareContext(ctx, exportTeamStats); err != nil {
+		return nil, fmt.Errorf("error preparing query ExportTeamStats: %w", err)
+	}
+	if q.failDataExportStmt, err = db.PrepareContext(ctx, failDataExport); err != nil {
+		return nil, fmt.Errorf("error preparing query FailDataExport: %w", err)
+	}
+	if q.failReportExportStmt, err = db.PrepareContext(ctx, failReportExport); err != nil {
+		return nil, fmt.Errorf("error preparing query FailReportExport: %w", err)
+	}
+	if q.getActiveAPIKeyByHashStmt, err = db.PrepareContext(ctx, getActiveAPIKeyByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveAPIKeyByHash: %w", err)
+	}
 	if q.getCauseStmt, err = db.PrepareContext(ctx, getCause); err != nil {
 		return nil, fmt.Errorf("error preparing query GetCause: %w", err)
 	}
+	if q.getDataExportStmt, err = db.PrepareContext(ctx, getDataExport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDataExport: %w", err)
+	}
+	if q.getDataExportArchiveStmt, err = db.PrepareContext(ctx, getDataExportArchive); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDataExportArchive: %w", err)
+	}
 	if q.getDonationStmt, err = db.PrepareContext(ctx, getDonation); err != nil {
 		return nil, fmt.Errorf("error preparing query GetDonation: %w", err)
 	}
+	if q.getDonationImportStmt, err = db.PrepareContext(ctx, getDonationImport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDonationImport: %w", err)
+	}
+	if q.getLatestDonationReceiptStmt, err = db.PrepareContext(ctx, getLatestDonationReceipt); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestDonationReceipt: %w", err)
+	}
+	if q.getLatestStatementStmt, err = db.PrepareContext(ctx, getLatestStatement); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestStatement: %w", err)
+	}
 	if q.getLeaderboardStmt, err = db.PrepareContext(ctx, getLeaderboard); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLeaderboard: %w", err)
 	}
 	if q.getLeaderboardEntriesStmt, err = db.PrepareContext(ctx, getLeaderboardEntries); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLeaderboardEntries: %w", err)
 	}
+	if q.getLeaderboardEntryStmt, err = db.PrepareContext(ctx, getLeaderboardEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLeaderboardEntry: %w", err)
+	}
+	if q.getLoginThrottleStmt, err = db.PrepareContext(ctx, getLoginThrottle); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLoginThrottle: %w", err)
+	}
+	if q.getReceiptStmt, err = db.PrepareContext(ctx, getReceipt); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReceipt: %w", err)
+	}
+	if q.getReceiptCauseStmt, err = db.PrepareContext(ctx, getReceiptCause); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReceiptCause: %w", err)
+	}
+	if q.getReceiptDonorStmt, err = db.PrepareContext(ctx, getReceiptDonor); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReceiptDonor: %w", err)
+	}
+	if q.getReportExportStmt, err = db.PrepareContext(ctx, getReportExport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReportExport: %w", err)
+	}
+	if q.getReportExportFileStmt, err = db.PrepareContext(ctx, getReportExportFile); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReportExportFile: %w", err)
+	}
 	if q.getTeamStmt, err = db.PrepareContext(ctx, getTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTeam: %w", err)
 	}
+	if q.getTwoFactorStatusStmt, err = db.PrepareContext(ctx, getTwoFactorStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTwoFactorStatus: %w", err)
+	}
 	if q.getUserStmt, err = db.PrepareContext(ctx, getUser); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUser: %w", err)
 	}
 	if q.getUserByEmailStmt, err = db.PrepareContext(ctx, getUserByEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByEmail: %w", err)
 	}
+	if q.getUserByUsernameStmt, err = db.PrepareContext(ctx, getUserByUsername); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByUsername: %w", err)
+	}
+	if q.getUserIdentityStmt, err = db.PrepareContext(ctx, getUserIdentity); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserIdentity: %w", err)
+	}
+	if q.getUserIncludingDeletedStmt, err = db.PrepareContext(ctx, getUserIncludingDeleted); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserIncludingDeleted: %w", err)
+	}
+	if q.getUserTOTPStmt, err = db.PrepareContext(ctx, getUserTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserTOTP: %w", err)
+	}
+	if q.getUserTeamStmt, err = db.PrepareContext(ctx, getUserTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserTeam: %w", err)
+	}
 	if q.getUserTokenByTokenStmt, err = db.PrepareContext(ctx, getUserTokenByToken); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserTokenByToken: %w", err)
 	}
 	if q.getUserTokenByUserIDStmt, err = db.PrepareContext(ctx, getUserTokenByUserID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserTokenByUserID: %w", err)
 	}
+	if q.importDonationChunkStmt, err = db.PrepareContext(ctx, importDonationChunk); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportDonationChunk: %w", err)
+	}
+	if q.invalidateUserActionTokensStmt, err = db.PrepareContext(ctx, invalidateUserActionTokens); err != nil {
+		return nil, fmt.Errorf("error preparing query InvalidateUserActionTokens: %w", err)
+	}
+	if q.listAPIKeysByUserStmt, err = db.PrepareContext(ctx, listAPIKeysByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAPIKeysByUser: %w", err)
+	}
+	if q.listAuditLogStmt, err = db.PrepareContext(ctx, listAuditLog); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAuditLog: %w", err)
+	}
+	if q.listAuditLogByEntityStmt, err = db.PrepareContext(ctx, listAuditLogByEntity); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAuditLogByEntity: %w", err)
+	}
 	if q.listCausesStmt, err = db.PrepareContext(ctx, listCauses); err != nil {
 		return nil, fmt.Errorf("error preparing query ListCauses: %w", err)
 	}
+	if q.listDataExportsByUserStmt, err = db.PrepareContext(ctx, listDataExportsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDataExportsByUser: %w", err)
+	}
+	if q.listDonationImportsStmt, err = db.PrepareContext(ctx, listDonationImports); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDonationImports: %w", err)
+	}
 	if q.listDonationsStmt, err = db.PrepareContext(ctx, listDonations); err != nil {
 		return nil, fmt.Errorf("error preparing query ListDonations: %w", err)
 	}
+	if q.listDonationsByUserStmt, err = db.PrepareContext(ctx, listDonationsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDonationsByUser: %w", err)
+	}
+	if q.listDonorSummariesStmt, err = db.PrepareContext(ctx, listDonorSummaries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDonorSummaries: %w", err)
+	}
+	if q.listDueErasuresStmt, err = db.PrepareContext(ctx, listDueErasures); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDueErasures: %w", err)
+	}
+	if q.listExpiredDeletedDonorsStmt, err = db.PrepareContext(ctx, listExpiredDeletedDonors); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExpiredDeletedDonors: %w", err)
+	}
+	if q.listLeaderboardEntriesByUserStmt, err = db.PrepareContext(ctx, listLeaderboardEntriesByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLeaderboardEntriesByUser: %w", err)
+	}
+	if q.listLeaderboardIDsStmt, err = db.PrepareContext(ctx, listLeaderboardIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLeaderboardIDs: %w", err)
+	}
 	if q.listLeaderboardsStmt, err = db.PrepareContext(ctx, listLeaderboards); err != nil {
 		return nil, fmt.Errorf("error preparing query ListLeaderboards: %w", err)
 	}
+	if q.listPurgeableCausesStmt, err = db.PrepareContext(ctx, listPurgeableCauses); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPurgeableCauses: %w", err)
+	}
+	if q.listPurgeableTeamsStmt, err = db.PrepareContext(ctx, listPurgeableTeams); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPurgeableTeams: %w", err)
+	}
+	if q.listPurgeableUsersStmt, err = db.PrepareContext(ctx, listPurgeableUsers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPurgeableUsers: %w", err)
+	}
+	if q.listReceiptTemplatesStmt, err = db.PrepareContext(ctx, listReceiptTemplates); err != nil {
+		return nil, fmt.Errorf("error preparing query ListReceiptTemplates: %w", err)
+	}
+	if q.listReceiptsByUserStmt, err = db.PrepareContext(ctx, listReceiptsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListReceiptsByUser: %w", err)
+	}
+	if q.listReportExportsByUserStmt, err = db.PrepareContext(ctx, listReportExportsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListReportExportsByUser: %w", err)
+	}
+	if q.listRolePoliciesStmt, err = db.PrepareContext(ctx, listRolePolicies); err != nil {
+		return nil, fmt.Errorf("error preparing query ListRolePolicies: %w", err)
+	}
+	if q.listSecurityEventsByUserStmt, err = db.PrepareContext(ctx, listSecurityEventsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSecurityEventsByUser: %w", err)
+	}
+	if q.listStatementDonationsStmt, err = db.PrepareContext(ctx, listStatementDonations); err != nil {
+		return nil, fmt.Errorf("error preparing query ListStatementDonations: %w", err)
+	}
 	if q.listTeamsStmt, err = db.PrepareContext(ctx, listTeams); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTeams: %w", err)
 	}
+	if q.listTeamsByUserStmt, err = db.PrepareContext(ctx, listTeamsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTeamsByUser: %w", err)
+	}
+	if q.listUserIdentitiesStmt, err = db.PrepareContext(ctx, listUserIdentities); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserIdentities: %w", err)
+	}
+	if q.listUserTokensByUserStmt, err = db.PrepareContext(ctx, listUserTokensByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserTokensByUser: %w", err)
+	}
 	if q.listUsersStmt, err = db.PrepareContext(ctx, listUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query ListUsers: %w", err)
 	}
+	if q.lockDonationStmt, err = db.PrepareContext(ctx, lockDonation); err != nil {
+		return nil, fmt.Errorf("error preparing query LockDonation: %w", err)
+	}
+	if q.lockDonationImportStmt, err = db.PrepareContext(ctx, lockDonationImport); err != nil {
+		return nil, fmt.Errorf("error preparing query LockDonationImport: %w", err)
+	}
+	if q.lockStatementStmt, err = db.PrepareContext(ctx, lockStatement); err != nil {
+		return nil, fmt.Errorf("error preparing query LockStatement: %w", err)
+	}
+	if q.markUserEmailVerifiedStmt, err = db.PrepareContext(ctx, markUserEmailVerified); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkUserEmailVerified: %w", err)
+	}
+	if q.nextReceiptNumberStmt, err = db.PrepareContext(ctx, nextReceiptNumber); err != nil {
+		return nil, fmt.Errorf("error preparing query NextReceiptNumber: %w", err)
+	}
+	if q.patchCauseStmt, err = db.PrepareContext(ctx, patchCause); err != nil {
+		return nil, fmt.Errorf("error preparing query PatchCause: %w", err)
+	}
+	if q.patchLeaderboardStmt, err = db.PrepareContext(ctx, patchLeaderboard); err != nil {
+		return nil, fmt.Errorf("error preparing query PatchLeaderboard: %w", err)
+	}
+	if q.patchTeamStmt, err = db.PrepareContext(ctx, patchTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query PatchTeam: %w", err)
+	}
+	if q.patchUserStmt, err = db.PrepareContext(ctx, patchUser); err != nil {
+		return nil, fmt.Errorf("error preparing query PatchUser: %w", err)
+	}
+	if q.purgeCauseStmt, err = db.PrepareContext(ctx, purgeCause); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeCause: %w", err)
+	}
+	if q.purgeTeamStmt, err = db.PrepareContext(ctx, purgeTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeTeam: %w", err)
+	}
+	if q.purgeUserStmt, err = db.PrepareContext(ctx, purgeUser); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeUser: %w", err)
+	}
+	if q.rebuildDonationRollupsStmt, err = db.PrepareContext(ctx, rebuildDonationRollups); err != nil {
+		return nil, fmt.Errorf("error preparing query RebuildDonationRollups: %w", err)
+	}
+	if q.rebuildLeaderboardEntriesStmt, err = db.PrepareContext(ctx, rebuildLeaderboardEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query RebuildLeaderboardEntries: %w", err)
+	}
+	if q.recomputeCauseTotalsStmt, err = db.PrepareContext(ctx, recomputeCauseTotals); err != nil {
+		return nil, fmt.Errorf("error preparing query RecomputeCauseTotals: %w", err)
+	}
+	if q.releaseLoginAttemptStmt, err = db.PrepareContext(ctx, releaseLoginAttempt); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseLoginAttempt: %w", err)
+	}
 	if q.removeUserFromTeamStmt, err = db.PrepareContext(ctx, removeUserFromTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query RemoveUserFromTeam: %w", err)
 	}
+	if q.restoreCauseStmt, err = db.PrepareContext(ctx, restoreCause); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreCause: %w", err)
+	}
+	if q.restoreTeamStmt, err = db.PrepareContext(ctx, restoreTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTeam: %w", err)
+	}
+	if q.restoreUserStmt, err = db.PrepareContext(ctx, restoreUser); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreUser: %w", err)
+	}
+	if q.revokeAPIKeyStmt, err = db.PrepareContext(ctx, revokeAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAPIKey: %w", err)
+	}
+	if q.rollBackDonationImportStmt, err = db.PrepareContext(ctx, rollBackDonationImport); err != nil {
+		return nil, fmt.Errorf("error preparing query RollBackDonationImport: %w", err)
+	}
+	if q.saveCauseReceiptTemplateStmt, err = db.PrepareContext(ctx, saveCauseReceiptTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveCauseReceiptTemplate: %w", err)
+	}
+	if q.saveOrganizationReceiptTemplateStmt, err = db.PrepareContext(ctx, saveOrganizationReceiptTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveOrganizationReceiptTemplate: %w", err)
+	}
+	if q.scheduleUserErasureStmt, err = db.PrepareContext(ctx, scheduleUserErasure); err != nil {
+		return nil, fmt.Errorf("error preparing query ScheduleUserErasure: %w", err)
+	}
+	if q.scrubAuditLogForUserStmt, err = db.PrepareContext(ctx, scrubAuditLogForUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ScrubAuditLogForUser: %w", err)
+	}
+	if q.setUserRoleStmt, err = db.PrepareContext(ctx, setUserRole); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserRole: %w", err)
+	}
+	if q.summarizeDonationImportChunkStmt, err = db.PrepareContext(ctx, summarizeDonationImportChunk); err != nil {
+		return nil, fmt.Errorf("error preparing query SummarizeDonationImportChunk: %w", err)
+	}
+	if q.takeLoginAttemptStmt, err = db.PrepareContext(ctx, takeLoginAttempt); err != nil {
+		return nil, fmt.Errorf("error preparing query TakeLoginAttempt: %w", err)
+	}
+	if q.touchAPIKeyStmt, err = db.PrepareContext(ctx, touchAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchAPIKey: %w", err)
+	}
+	if q.touchUserIdentityStmt, err = db.PrepareContext(ctx, touchUserIdentity); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchUserIdentity: %w", err)
+	}
 	if q.updateCauseStmt, err = db.PrepareContext(ctx, updateCause); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateCause: %w", err)
 	}
@@ -120,9 +498,27 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateUserStmt, err = db.PrepareContext(ctx, updateUser); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUser: %w", err)
 	}
+	if q.updateUserPasswordStmt, err = db.PrepareContext(ctx, updateUserPassword); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserPassword: %w", err)
+	}
+	if q.updateUserTOTPLastUsedStepStmt, err = db.PrepareContext(ctx, updateUserTOTPLastUsedStep); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserTOTPLastUsedStep: %w", err)
+	}
 	if q.updateUserTeamRoleStmt, err = db.PrepareContext(ctx, updateUserTeamRole); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserTeamRole: %w", err)
 	}
+	if q.upsertRolePolicyStmt, err = db.PrepareContext(ctx, upsertRolePolicy); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertRolePolicy: %w", err)
+	}
+	if q.upsertUserTOTPStmt, err = db.PrepareContext(ctx, upsertUserTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertUserTOTP: %w", err)
+	}
+	if q.voidImportedDonationReceiptsStmt, err = db.PrepareContext(ctx, voidImportedDonationReceipts); err != nil {
+		return nil, fmt.Errorf("error preparing query VoidImportedDonationReceipts: %w", err)
+	}
+	if q.voidReceiptStmt, err = db.PrepareContext(ctx, voidReceipt); err != nil {
+		return nil, fmt.Errorf("error preparing query VoidReceipt: %w", err)
+	}
 	return &q, nil
 }
 
@@ -133,21 +529,146 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing addUserToTeamStmt: %w", cerr)
 		}
 	}
+	if q.anonymizeUserStmt != nil {
+		if cerr := q.anonymizeUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserStmt: %w", cerr)
+		}
+	}
+	if q.cancelUserErasureStmt != nil {
+		if cerr := q.cancelUserErasureStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cancelUserErasureStmt: %w", cerr)
+		}
+	}
+	if q.claimDirtyRollupBucketsStmt != nil {
+		if cerr := q.claimDirtyRollupBucketsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimDirtyRollupBucketsStmt: %w", cerr)
+		}
+	}
+	if q.claimPendingDataExportStmt != nil {
+		if cerr := q.claimPendingDataExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimPendingDataExportStmt: %w", cerr)
+		}
+	}
+	if q.claimPendingReportExportStmt != nil {
+		if cerr := q.claimPendingReportExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimPendingReportExportStmt: %w", cerr)
+		}
+	}
+	if q.clearCauseOwnerStmt != nil {
+		if cerr := q.clearCauseOwnerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearCauseOwnerStmt: %w", cerr)
+		}
+	}
+	if q.completeDataExportStmt != nil {
+		if cerr := q.completeDataExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing completeDataExportStmt: %w", cerr)
+		}
+	}
+	if q.completeDonationImportStmt != nil {
+		if cerr := q.completeDonationImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing completeDonationImportStmt: %w", cerr)
+		}
+	}
+	if q.completeReportExportStmt != nil {
+		if cerr := q.completeReportExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing completeReportExportStmt: %w", cerr)
+		}
+	}
+	if q.consumeRecoveryCodeStmt != nil {
+		if cerr := q.consumeRecoveryCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing consumeRecoveryCodeStmt: %w", cerr)
+		}
+	}
+	if q.consumeUserActionTokenStmt != nil {
+		if cerr := q.consumeUserActionTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing consumeUserActionTokenStmt: %w", cerr)
+		}
+	}
+	if q.countDirtyRollupBucketsStmt != nil {
+		if cerr := q.countDirtyRollupBucketsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countDirtyRollupBucketsStmt: %w", cerr)
+		}
+	}
+	if q.countUnusedRecoveryCodesStmt != nil {
+		if cerr := q.countUnusedRecoveryCodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countUnusedRecoveryCodesStmt: %w", cerr)
+		}
+	}
+	if q.createAPIKeyStmt != nil {
+		if cerr := q.createAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.createAuditLogEntryStmt != nil {
+		if cerr := q.createAuditLogEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAuditLogEntryStmt: %w", cerr)
+		}
+	}
 	if q.createCauseStmt != nil {
 		if cerr := q.createCauseStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createCauseStmt: %w", cerr)
 		}
 	}
+	if q.createDataExportStmt != nil {
+		if cerr := q.createDataExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDataExportStmt: %w", cerr)
+		}
+	}
+	if q.createDataExportArchiveStmt != nil {
+		if cerr := q.createDataExportArchiveStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDataExportArchiveStmt: %w", cerr)
+		}
+	}
 	if q.createDonationStmt != nil {
 		if cerr := q.createDonationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createDonationStmt: %w", cerr)
 		}
 	}
+	if q.createDonationImportStmt != nil {
+		if cerr := q.createDonationImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDonationImportStmt: %w", cerr)
+		}
+	}
+	if q.createDonationImportRowsStmt != nil {
+		if cerr := q.createDonationImportRowsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDonationImportRowsStmt: %w", cerr)
+		}
+	}
 	if q.createLeaderboardStmt != nil {
 		if cerr := q.createLeaderboardStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createLeaderboardStmt: %w", cerr)
 		}
 	}
+	if q.createReceiptStmt != nil {
+		if cerr := q.createReceiptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createReceiptStmt: %w", cerr)
+		}
+	}
+	if q.createRecoveryCodeStmt != nil {
+		if cerr := q.createRecoveryCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRecoveryCodeStmt: %w", cerr)
+		}
+	}
+	if q.createReportExportStmt != nil {
+		if cerr := q.createReportExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createReportExportStmt: %w", cerr)
+		}
+	}
+	if q.createReportExportFileStmt != nil {
+		if cerr := q.createReportExportFileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createReportExportFileStmt: %w", cerr)
+		}
+	}
+	if q.createSecurityEventStmt != nil {
+		if cerr := q.createSecurityEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSecurityEventStmt: %w", cerr)
+		}
+	}
+	if q.createSeedDonationStmt != nil {
+		if cerr := q.createSeedDonationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSeedDonationStmt: %w", cerr)
+		}
+	}
 	if q.createTeamStmt != nil {
 		if cerr := q.createTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createTeamStmt: %w", cerr)
@@ -158,6 +679,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
 		}
 	}
+	if q.createUserActionTokenStmt != nil {
+		if cerr := q.createUserActionTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserActionTokenStmt: %w", cerr)
+		}
+	}
+	if q.createUserIdentityStmt != nil {
+		if cerr := q.createUserIdentityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserIdentityStmt: %w", cerr)
+		}
+	}
 	if q.createUserTokenStmt != nil {
 		if cerr := q.createUserTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createUserTokenStmt: %w", cerr)
@@ -168,36 +699,191 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteCauseStmt: %w", cerr)
 		}
 	}
+	if q.deleteDataExportsByUserStmt != nil {
+		if cerr := q.deleteDataExportsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDataExportsByUserStmt: %w", cerr)
+		}
+	}
+	if q.deleteDonationImportRowsStmt != nil {
+		if cerr := q.deleteDonationImportRowsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDonationImportRowsStmt: %w", cerr)
+		}
+	}
+	if q.deleteDonationRollupsStmt != nil {
+		if cerr := q.deleteDonationRollupsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDonationRollupsStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredDataExportsStmt != nil {
+		if cerr := q.deleteExpiredDataExportsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredDataExportsStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredReportExportsStmt != nil {
+		if cerr := q.deleteExpiredReportExportsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredReportExportsStmt: %w", cerr)
+		}
+	}
 	if q.deleteExpiredTokensStmt != nil {
 		if cerr := q.deleteExpiredTokensStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteExpiredTokensStmt: %w", cerr)
 		}
 	}
+	if q.deleteImportedDonationsStmt != nil {
+		if cerr := q.deleteImportedDonationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteImportedDonationsStmt: %w", cerr)
+		}
+	}
+	if q.deleteLeaderboardEntriesByLeaderboardStmt != nil {
+		if cerr := q.deleteLeaderboardEntriesByLeaderboardStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteLeaderboardEntriesByLeaderboardStmt: %w", cerr)
+		}
+	}
+	if q.deleteLeaderboardEntriesByTeamStmt != nil {
+		if cerr := q.deleteLeaderboardEntriesByTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteLeaderboardEntriesByTeamStmt: %w", cerr)
+		}
+	}
+	if q.deleteLeaderboardEntriesByUserStmt != nil {
+		if cerr := q.deleteLeaderboardEntriesByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteLeaderboardEntriesByUserStmt: %w", cerr)
+		}
+	}
+	if q.deleteLoginThrottleStmt != nil {
+		if cerr := q.deleteLoginThrottleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteLoginThrottleStmt: %w", cerr)
+		}
+	}
+	if q.deleteLoginThrottleForEmailStmt != nil {
+		if cerr := q.deleteLoginThrottleForEmailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteLoginThrottleForEmailStmt: %w", cerr)
+		}
+	}
+	if q.deleteRecoveryCodesStmt != nil {
+		if cerr := q.deleteRecoveryCodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteRecoveryCodesStmt: %w", cerr)
+		}
+	}
 	if q.deleteTeamStmt != nil {
 		if cerr := q.deleteTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteTeamStmt: %w", cerr)
 		}
 	}
+	if q.deleteTeamMembershipsByTeamStmt != nil {
+		if cerr := q.deleteTeamMembershipsByTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTeamMembershipsByTeamStmt: %w", cerr)
+		}
+	}
+	if q.deleteTeamMembershipsByUserStmt != nil {
+		if cerr := q.deleteTeamMembershipsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTeamMembershipsByUserStmt: %w", cerr)
+		}
+	}
 	if q.deleteUserStmt != nil {
 		if cerr := q.deleteUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteUserStmt: %w", cerr)
 		}
 	}
+	if q.deleteUserPersonalDataStmt != nil {
+		if cerr := q.deleteUserPersonalDataStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserPersonalDataStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserTOTPStmt != nil {
+		if cerr := q.deleteUserTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserTOTPStmt: %w", cerr)
+		}
+	}
 	if q.deleteUserTokenStmt != nil {
 		if cerr := q.deleteUserTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteUserTokenStmt: %w", cerr)
 		}
 	}
+	if q.deleteUserTokensByUserIDStmt != nil {
+		if cerr := q.deleteUserTokensByUserIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserTokensByUserIDStmt: %w", cerr)
+		}
+	}
+	if q.donationAnalyticsStmt != nil {
+		if cerr := q.donationAnalyticsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing donationAnalyticsStmt: %w", cerr)
+		}
+	}
+	if q.donorCohortsStmt != nil {
+		if cerr := q.donorCohortsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing donorCohortsStmt: %w", cerr)
+		}
+	}
+	if q.enableUserTOTPStmt != nil {
+		if cerr := q.enableUserTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing enableUserTOTPStmt: %w", cerr)
+		}
+	}
+	if q.exportDonationsStmt != nil {
+		if cerr := q.exportDonationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing exportDonationsStmt: %w", cerr)
+		}
+	}
+	if q.exportDonorsStmt != nil {
+		if cerr := q.exportDonorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing exportDonorsStmt: %w", cerr)
+		}
+	}
+	if q.exportTeamStatsStmt != nil {
+		if cerr := q.exportTeamStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing exportTeamStatsStmt: %w", cerr)
+		}
+	}
+	if q.failDataExportStmt != nil {
+		if cerr := q.failDataExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failDataExportStmt: %w", cerr)
+		}
+	}
+	if q.failReportExportStmt != nil {
+		if cerr := q.failReportExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failReportExportStmt: %w", cerr)
+		}
+	}
+	if q.getActiveAPIKeyByHashStmt != nil {
+		if cerr := q.getActiveAPIKeyByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveAPIKeyByHashStmt: %w", cerr)
+		}
+	}
 	if q.getCauseStmt != nil {
 		if cerr := q.getCauseStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getCauseStmt: %w", cerr)
 		}
 	}
+	if q.getDataExportStmt != nil {
+		if cerr := q.getDataExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDataExportStmt: %w", cerr)
+		}
+	}
+	if q.getDataExportArchiveStmt != nil {
+		if cerr := q.getDataExportArchiveStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDataExportArchiveStmt: %w", cerr)
+		}
+	}
 	if q.getDonationStmt != nil {
 		if cerr := q.getDonationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getDonationStmt: %w", cerr)
 		}
 	}
+	if q.getDonationImportStmt != nil {
+		if cerr := q.getDonationImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDonationImportStmt: %w", cerr)
+		}
+	}
+	if q.getLatestDonationReceiptStmt != nil {
+		if cerr := q.getLatestDonationReceiptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestDonationReceiptStmt: %w", cerr)
+		}
+	}
+	if q.getLatestStatementStmt != nil {
+		if cerr := q.getLatestStatementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestStatementStmt: %w", cerr)
+		}
+	}
 	if q.getLeaderboardStmt != nil {
 		if cerr := q.getLeaderboardStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getLeaderboardStmt: %w", cerr)
@@ -208,11 +894,51 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getLeaderboardEntriesStmt: %w", cerr)
 		}
 	}
+	if q.getLeaderboardEntryStmt != nil {
+		if cerr := q.getLeaderboardEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLeaderboardEntryStmt: %w", cerr)
+		}
+	}
+	if q.getLoginThrottleStmt != nil {
+		if cerr := q.getLoginThrottleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLoginThrottleStmt: %w", cerr)
+		}
+	}
+	if q.getReceiptStmt != nil {
+		if cerr := q.getReceiptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReceiptStmt: %w", cerr)
+		}
+	}
+	if q.getReceiptCauseStmt != nil {
+		if cerr := q.getReceiptCauseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReceiptCauseStmt: %w", cerr)
+		}
+	}
+	if q.getReceiptDonorStmt != nil {
+		if cerr := q.getReceiptDonorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReceiptDonorStmt: %w", cerr)
+		}
+	}
+	if q.getReportExportStmt != nil {
+		if cerr := q.getReportExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReportExportStmt: %w", cerr)
+		}
+	}
+	if q.getReportExportFileStmt != nil {
+		if cerr := q.getReportExportFileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReportExportFileStmt: %w", cerr)
+		}
+	}
 	if q.getTeamStmt != nil {
 		if cerr := q.getTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTeamStmt: %w", cerr)
 		}
 	}
+	if q.getTwoFactorStatusStmt != nil {
+		if cerr := q.getTwoFactorStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTwoFactorStatusStmt: %w", cerr)
+		}
+	}
 	if q.getUserStmt != nil {
 		if cerr := q.getUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserStmt: %w", cerr)
@@ -223,6 +949,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUserByEmailStmt: %w", cerr)
 		}
 	}
+	if q.getUserByUsernameStmt != nil {
+		if cerr := q.getUserByUsernameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByUsernameStmt: %w", cerr)
+		}
+	}
+	if q.getUserIdentityStmt != nil {
+		if cerr := q.getUserIdentityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserIdentityStmt: %w", cerr)
+		}
+	}
+	if q.getUserIncludingDeletedStmt != nil {
+		if cerr := q.getUserIncludingDeletedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserIncludingDeletedStmt: %w", cerr)
+		}
+	}
+	if q.getUserTOTPStmt != nil {
+		if cerr := q.getUserTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserTOTPStmt: %w", cerr)
+		}
+	}
+	if q.getUserTeamStmt != nil {
+		if cerr := q.getUserTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserTeamStmt: %w", cerr)
+		}
+	}
 	if q.getUserTokenByTokenStmt != nil {
 		if cerr := q.getUserTokenByTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserTokenByTokenStmt: %w", cerr)
@@ -233,36 +984,311 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUserTokenByUserIDStmt: %w", cerr)
 		}
 	}
+	if q.importDonationChunkStmt != nil {
+		if cerr := q.importDonationChunkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importDonationChunkStmt: %w", cerr)
+		}
+	}
+	if q.invalidateUserActionTokensStmt != nil {
+		if cerr := q.invalidateUserActionTokensStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing invalidateUserActionTokensStmt: %w", cerr)
+		}
+	}
+	if q.listAPIKeysByUserStmt != nil {
+		if cerr := q.listAPIKeysByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAPIKeysByUserStmt: %w", cerr)
+		}
+	}
+	if q.listAuditLogStmt != nil {
+		if cerr := q.listAuditLogStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAuditLogStmt: %w", cerr)
+		}
+	}
+	if q.listAuditLogByEntityStmt != nil {
+		if cerr := q.listAuditLogByEntityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAuditLogByEntityStmt: %w", cerr)
+		}
+	}
 	if q.listCausesStmt != nil {
 		if cerr := q.listCausesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listCausesStmt: %w", cerr)
 		}
 	}
+	if q.listDataExportsByUserStmt != nil {
+		if cerr := q.listDataExportsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDataExportsByUserStmt: %w", cerr)
+		}
+	}
+	if q.listDonationImportsStmt != nil {
+		if cerr := q.listDonationImportsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDonationImportsStmt: %w", cerr)
+		}
+	}
 	if q.listDonationsStmt != nil {
 		if cerr := q.listDonationsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listDonationsStmt: %w", cerr)
 		}
 	}
+	if q.listDonationsByUserStmt != nil {
+		if cerr := q.listDonationsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDonationsByUserStmt: %w", cerr)
+		}
+	}
+	if q.listDonorSummariesStmt != nil {
+		if cerr := q.listDonorSummariesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDonorSummariesStmt: %w", cerr)
+		}
+	}
+	if q.listDueErasuresStmt != nil {
+		if cerr := q.listDueErasuresStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDueErasuresStmt: %w", cerr)
+		}
+	}
+	if q.listExpiredDeletedDonorsStmt != nil {
+		if cerr := q.listExpiredDeletedDonorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExpiredDeletedDonorsStmt: %w", cerr)
+		}
+	}
+	if q.listLeaderboardEntriesByUserStmt != nil {
+		if cerr := q.listLeaderboardEntriesByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLeaderboardEntriesByUserStmt: %w", cerr)
+		}
+	}
+	if q.listLeaderboardIDsStmt != nil {
+		if cerr := q.listLeaderboardIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLeaderboardIDsStmt: %w", cerr)
+		}
+	}
 	if q.listLeaderboardsStmt != nil {
 		if cerr := q.listLeaderboardsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listLeaderboardsStmt: %w", cerr)
 		}
 	}
+	if q.listPurgeableCausesStmt != nil {
+		if cerr := q.listPurgeableCausesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPurgeableCausesStmt: %w", cerr)
+		}
+	}
+	if q.listPurgeableTeamsStmt != nil {
+		if cerr := q.listPurgeableTeamsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPurgeableTeamsStmt: %w", cerr)
+		}
+	}
+	if q.listPurgeableUsersStmt != nil {
+		if cerr := q.listPurgeableUsersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPurgeableUsersStmt: %w", cerr)
+		}
+	}
+	if q.listReceiptTemplatesStmt != nil {
+		if cerr := q.listReceiptTemplatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listReceiptTemplatesStmt: %w", cerr)
+		}
+	}
+	if q.listReceiptsByUserStmt != nil {
+		if cerr := q.listReceiptsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listReceiptsByUserStmt: %w", cerr)
+		}
+	}
+	if q.listReportExportsByUserStmt != nil {
+		if cerr := q.listReportExportsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listReportExportsByUserStmt: %w", cerr)
+		}
+	}
+	if q.listRolePoliciesStmt != nil {
+		if cerr := q.listRolePoliciesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listRolePoliciesStmt: %w", cerr)
+		}
+	}
+	if q.listSecurityEventsByUserStmt != nil {
+		if cerr := q.listSecurityEventsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSecurityEventsByUserStmt: %w", cerr)
+		}
+	}
+	if q.listStatementDonationsStmt != nil {
+		if cerr := q.listStatementDonationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listStatementDonationsStmt: %w", cerr)
+		}
+	}
 	if q.listTeamsStmt != nil {
 		if cerr := q.listTeamsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listTeamsStmt: %w", cerr)
 		}
 	}
+	if q.listTeamsByUserStmt != nil {
+		if cerr := q.listTeamsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTeamsByUserStmt: %w", cerr)
+		}
+	}
+	if q.listUserIdentitiesStmt != nil {
+		if cerr := q.listUserIdentitiesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserIdentitiesStmt: %w", cerr)
+		}
+	}
+	if q.listUserTokensByUserStmt != nil {
+		if cerr := q.listUserTokensByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserTokensByUserStmt: %w", cerr)
+		}
+	}
 	if q.listUsersStmt != nil {
 		if cerr := q.listUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listUsersStmt: %w", cerr)
 		}
 	}
+	if q.lockDonationStmt != nil {
+		if cerr := q.lockDonationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockDonationStmt: %w", cerr)
+		}
+	}
+	if q.lockDonationImportStmt != nil {
+		if cerr := q.lockDonationImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockDonationImportStmt: %w", cerr)
+		}
+	}
+	if q.lockStatementStmt != nil {
+		if cerr := q.lockStatementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockStatementStmt: %w", cerr)
+		}
+	}
+	if q.markUserEmailVerifiedStmt != nil {
+		if cerr := q.markUserEmailVerifiedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markUserEmailVerifiedStmt: %w", cerr)
+		}
+	}
+	if q.nextReceiptNumberStmt != nil {
+		if cerr := q.nextReceiptNumberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing nextReceiptNumberStmt: %w", cerr)
+		}
+	}
+	if q.patchCauseStmt != nil {
+		if cerr := q.patchCauseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing patchCauseStmt: %w", cerr)
+		}
+	}
+	if q.patchLeaderboardStmt != nil {
+		if cerr := q.patchLeaderboardStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing patchLeaderboardStmt: %w", cerr)
+		}
+	}
+	if q.patchTeamStmt != nil {
+		if cerr := q.patchTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing patchTeamStmt: %w", cerr)
+		}
+	}
+	if q.patchUserStmt != nil {
+		if cerr := q.patchUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing patchUserStmt: %w", cerr)
+		}
+	}
+	if q.purgeCauseStmt != nil {
+		if cerr := q.purgeCauseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeCauseStmt: %w", cerr)
+		}
+	}
+	if q.purgeTeamStmt != nil {
+		if cerr := q.purgeTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeTeamStmt: %w", cerr)
+		}
+	}
+	if q.purgeUserStmt != nil {
+		if cerr := q.purgeUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeUserStmt: %w", cerr)
+		}
+	}
+	if q.rebuildDonationRollupsStmt != nil {
+		if cerr := q.rebuildDonationRollupsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rebuildDonationRollupsStmt: %w", cerr)
+		}
+	}
+	if q.rebuildLeaderboardEntriesStmt != nil {
+		if cerr := q.rebuildLeaderboardEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rebuildLeaderboardEntriesStmt: %w", cerr)
+		}
+	}
+	if q.recomputeCauseTotalsStmt != nil {
+		if cerr := q.recomputeCauseTotalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing recomputeCauseTotalsStmt: %w", cerr)
+		}
+	}
+	if q.releaseLoginAttemptStmt != nil {
+		if cerr := q.releaseLoginAttemptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseLoginAttemptStmt: %w", cerr)
+		}
+	}
 	if q.removeUserFromTeamStmt != nil {
 		if cerr := q.removeUserFromTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing removeUserFromTeamStmt: %w", cerr)
 		}
 	}
+	if q.restoreCauseStmt != nil {
+		if cerr := q.restoreCauseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreCauseStmt: %w", cerr)
+		}
+	}
+	if q.restoreTeamStmt != nil {
+		if cerr := q.restoreTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTeamStmt: %w", cerr)
+		}
+	}
+	if q.restoreUserStmt != nil {
+		if cerr := q.restoreUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreUserStmt: %w", cerr)
+		}
+	}
+	if q.revokeAPIKeyStmt != nil {
+		if cerr := q.revokeAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.rollBackDonationImportStmt != nil {
+		if cerr := q.rollBackDonationImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rollBackDonationImportStmt: %w", cerr)
+		}
+	}
+	if q.saveCauseReceiptTemplateStmt != nil {
+		if cerr := q.saveCauseReceiptTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveCauseReceiptTemplateStmt: %w", cerr)
+		}
+	}
+	if q.saveOrganizationReceiptTemplateStmt != nil {
+		if cerr := q.saveOrganizationReceiptTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveOrganizationReceiptTemplateStmt: %w", cerr)
+		}
+	}
+	if q.scheduleUserErasureStmt != nil {
+		if cerr := q.scheduleUserErasureStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing scheduleUserErasureStmt: %w", cerr)
+		}
+	}
+	if q.scrubAuditLogForUserStmt != nil {
+		if cerr := q.scrubAuditLogForUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing scrubAuditLogForUserStmt: %w", cerr)
+		}
+	}
+	if q.setUserRoleStmt != nil {
+		if cerr := q.setUserRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserRoleStmt: %w", cerr)
+		}
+	}
+	if q.summarizeDonationImportChunkStmt != nil {
+		if cerr := q.summarizeDonationImportChunkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing summarizeDonationImportChunkStmt: %w", cerr)
+		}
+	}
+	if q.takeLoginAttemptStmt != nil {
+		if cerr := q.takeLoginAttemptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing takeLoginAttemptStmt: %w", cerr)
+		}
+	}
+	if q.touchAPIKeyStmt != nil {
+		if cerr := q.touchAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.touchUserIdentityStmt != nil {
+		if cerr := q.touchUserIdentityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchUserIdentityStmt: %w", cerr)
+		}
+	}
 	if q.updateCauseStmt != nil {
 		if cerr := q.updateCauseStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateCauseStmt: %w", cerr)
@@ -288,11 +1314,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateUserStmt: %w", cerr)
 		}
 	}
+	if q.updateUserPasswordStmt != nil {
+		if cerr := q.updateUserPasswordStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserPasswordStmt: %w", cerr)
+		}
+	}
+	if q.updateUserTOTPLastUsedStepStmt != nil {
+		if cerr := q.updateUserTOTPLastUsedStepStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserTOTPLastUsedStepStmt: %w", cerr)
+		}
+	}
 	if q.updateUserTeamRoleStmt != nil {
 		if cerr := q.updateUserTeamRoleStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateUserTeamRoleStmt: %w", cerr)
 		}
 	}
+	if q.upsertRolePolicyStmt != nil {
+		if cerr := q.upsertRolePolicyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertRolePolicyStmt: %w", cerr)
+		}
+	}
+	if q.upsertUserTOTPStmt != nil {
+		if cerr := q.upsertUserTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertUserTOTPStmt: %w", cerr)
+		}
+	}
+	if q.voidImportedDonationReceiptsStmt != nil {
+		if cerr := q.voidImportedDonationReceiptsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing voidImportedDonationReceiptsStmt: %w", cerr)
+		}
+	}
+	if q.voidReceiptStmt != nil {
+		if cerr := q.voidReceiptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing voidReceiptStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -330,79 +1386,343 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                         DBTX
-	tx                         *sql.Tx
-	addUserToTeamStmt          *sql.Stmt
-	createCauseStmt            *sql.Stmt
-	createDonationStmt         *sql.Stmt
-	createLeaderboardStmt      *sql.Stmt
-	createTeamStmt             *sql.Stmt
-	createUserStmt             *sql.Stmt
-	createUserTokenStmt        *sql.Stmt
-	deleteCauseStmt            *sql.Stmt
-	deleteExpiredTokensStmt    *sql.Stmt
-	deleteTeamStmt             *sql.Stmt
-	deleteUserStmt             *sql.Stmt
-	deleteUserTokenStmt        *sql.Stmt
-	getCauseStmt               *sql.Stmt
-	getDonationStmt            *sql.Stmt
-	getLeaderboardStmt         *sql.Stmt
-	getLeaderboardEntriesStmt  *sql.Stmt
-	getTeamStmt                *sql.Stmt
-	getUserStmt                *sql.Stmt
-	getUserByEmailStmt         *sql.Stmt
-	getUserTokenByTokenStmt    *sql.Stmt
-	getUserTokenByUserIDStmt   *sql.Stmt
-	listCausesStmt             *sql.Stmt
-	listDonationsStmt          *sql.Stmt
-	listLeaderboardsStmt       *sql.Stmt
-	listTeamsStmt              *sql.Stmt
-	listUsersStmt              *sql.Stmt
-	removeUserFromTeamStmt     *sql.Stmt
-	updateCauseStmt            *sql.Stmt
-	updateDonationStatusStmt   *sql.Stmt
-	updateLeaderboardEntryStmt *sql.Stmt
-	updateTeamStmt             *sql.Stmt
-	updateUserStmt             *sql.Stmt
-	updateUserTeamRoleStmt     *sql.Stmt
+	db                                        DBTX
+	tx                                        *sql.Tx
+	addUserToTeamStmt                         *sql.Stmt
+	anonymizeUserStmt                         *sql.Stmt
+	cancelUserErasureStmt                     *sql.Stmt
+	claimDirtyRollupBucketsStmt               *sql.Stmt
+	claimPendingDataExportStmt                *sql.Stmt
+	claimPendingReportExportStmt              *sql.Stmt
+	clearCauseOwnerStmt                       *sql.Stmt
+	completeDataExportStmt                    *sql.Stmt
+	completeDonationImportStmt                *sql.Stmt
+	completeReportExportStmt                  *sql.Stmt
+	consumeRecoveryCodeStmt                   *sql.Stmt
+	consumeUserActionTokenStmt                *sql.Stmt
+	countDirtyRollupBucketsStmt               *sql.Stmt
+	countUnusedRecoveryCodesStmt              *sql.Stmt
+	createAPIKeyStmt                          *sql.Stmt
+	createAuditLogEntryStmt                   *sql.Stmt
+	createCauseStmt                           *sql.Stmt
+	createDataExportStmt                      *sql.Stmt
+	createDataExportArchiveStmt               *sql.Stmt
+	createDonationStmt                        *sql.Stmt
+	createDonationImportStmt                  *sql.Stmt
+	createDonationImportRowsStmt              *sql.Stmt
+	createLeaderboardStmt                     *sql.Stmt
+	createReceiptStmt                         *sql.Stmt
+	createRecoveryCodeStmt                    *sql.Stmt
+	createReportExportStmt                    *sql.Stmt
+	createReportExportFileStmt                *sql.Stmt
+	createSecurityEventStmt                   *sql.Stmt
+	createSeedDonationStmt                    *sql.Stmt
+	createTeamStmt                            *sql.Stmt
+	createUserStmt                            *sql.Stmt
+	createUserActionTokenStmt                 *sql.Stmt
+	createUserIdentityStmt                    *sql.Stmt
+	createUserTokenStmt                       *sql.Stmt
+	deleteCauseStmt                           *sql.Stmt
+	deleteDataExportsByUserStmt               *sql.Stmt
+	deleteDonationImportRowsStmt              *sql.Stmt
+	deleteDonationRollupsStmt                 *sql.Stmt
+	deleteExpiredDataExportsStmt              *sql.Stmt
+	deleteExpiredReportExportsStmt            *sql.Stmt
+	deleteExpiredTokensStmt                   *sql.Stmt
+	deleteImportedDonationsStmt               *sql.Stmt
+	deleteLeaderboardEntriesByLeaderboardStmt *sql.Stmt
+	deleteLeaderboardEntriesByTeamStmt        *sql.Stmt
+	deleteLeaderboardEntriesByUserStmt        *sql.Stmt
+	deleteLoginThrottleStmt                   *sql.Stmt
+	deleteLoginThrottleForEmailStmt           *sql.Stmt
+	deleteRecoveryCodesStmt                   *sql.Stmt
+	deleteTeamStmt                            *sql.Stmt
+	deleteTeamMembershipsByTeamStmt           *sql.Stmt
+	deleteTeamMembershipsByUserStmt           *sql.Stmt
+	deleteUserStmt                            *sql.Stmt
+	deleteUserPersonalDataStmt                *sql.Stmt
+	deleteUserTOTPStmt                        *sql.Stmt
+	deleteUserTokenStmt                       *sql.Stmt
+	deleteUserTokensByUserIDStmt              *sql.Stmt
+	donationAnalyticsStmt                     *sql.Stmt
+	donorCohortsStmt                          *sql.Stmt
+	enableUserTOTPStmt                        *sql.Stmt
+	exportDonationsStmt                       *sql.Stmt
+	exportDonorsStmt                          *sql.Stmt
+	exportTeamStatsStmt                       *sql.Stmt
+	failDataExportStmt                        *sql.Stmt
+	failReportExportStmt                      *sql.Stmt
+	getActiveAPIKeyByHashStmt                 *sql.Stmt
+	getCauseStmt                              *sql.Stmt
+	getDataExportStmt                         *sql.Stmt
+	getDataExportArchiveStmt                  *sql.Stmt
+	getDonationStmt                           *sql.Stmt
+	getDonationImportStmt                     *sql.Stmt
+	getLatestDonationReceiptStmt              *sql.Stmt
+	getLatestStatementStmt                    *sql.Stmt
+	getLeaderboardStmt                        *sql.Stmt
+	getLeaderboardEntriesStmt                 *sql.Stmt
+	getLeaderboardEntryStmt                   *sql.Stmt
+	getLoginThrottleStmt                      *sql.Stmt
+	getReceiptStmt                            *sql.Stmt
+	getReceiptCauseStmt                       *sql.Stmt
+	getReceiptDonorStmt                       *sql.Stmt
+	getReportExportStmt                       *sql.Stmt
+	getReportExportFileStmt                   *sql.Stmt
+	getTeamStmt                               *sql.Stmt
+	getTwoFactorStatusStmt                    *sql.Stmt
+	getUserStmt                               *sql.Stmt
+	getUserByEmailStmt                        *sql.Stmt
+	getUserByUsernameStmt                     *sql.Stmt
+	getUserIdentityStmt                       *sql.Stmt
+	getUserIncludingDeletedStmt               *sql.Stmt
+	getUserTOTPStmt                           *sql.Stmt
+	getUserTeamStmt                           *sql.Stmt
+	getUserTokenByTokenStmt                   *sql.Stmt
+	getUserTokenByUserIDStmt                  *sql.Stmt
+	importDonationChunkStmt                   *sql.Stmt
+	invalidateUserActionTokensStmt            *sql.Stmt
+	listAPIKeysByUserStmt                     *sql.Stmt
+	listAuditLogStmt                          *sql.Stmt
+	listAuditLogByEntityStmt                  *sql.Stmt
+	listCausesStmt                            *sql.Stmt
+	listDataExportsByUserStmt                 *sql.Stmt
+	listDonationImportsStmt                   *sql.Stmt
+	listDonationsStmt                         *sql.Stmt
+	listDonationsByUserStmt                   *sql.Stmt
+	listDonorSummariesStmt                    *sql.Stmt
+	listDueErasuresStmt                       *sql.Stmt
+	listExpiredDeletedDonorsStmt              *sql.Stmt
+	listLeaderboardEntriesByUserStmt          *sql.Stmt
+	listLeaderboardIDsStmt                    *sql.Stmt
+	listLeaderboardsStmt                      *sql.Stmt
+	listPurgeableCausesStmt                   *sql.Stmt
+	listPurgeableTeamsStmt                    *sql.Stmt
+	listPurgeableUsersStmt                    *sql.Stmt
+	listReceiptTemplatesStmt                  *sql.Stmt
+	listReceiptsByUserStmt                    *sql.Stmt
+	listReportExportsByUserStmt               *sql.Stmt
+	listRolePoliciesStmt                      *sql.Stmt
+	listSecurityEventsByUserStmt              *sql.Stmt
+	listStatementDonationsStmt                *sql.Stmt
+	listTeamsStmt                             *sql.Stmt
+	listTeamsByUserStmt                       *sql.Stmt
+	listUserIdentitiesStmt                    *sql.Stmt
+	listUserTokensByUserStmt                  *sql.Stmt
+	listUsersStmt                             *sql.Stmt
+	lockDonationStmt                          *sql.Stmt
+	lockDonationImportStmt                    *sql.Stmt
+	lockStatementStmt                         *sql.Stmt
+	markUserEmailVerifiedStmt                 *sql.Stmt
+	nextReceiptNumberStmt                     *sql.Stmt
+	patchCauseStmt                            *sql.Stmt
+	patchLeaderboardStmt                      *sql.Stmt
+	patchTeamStmt                             *sql.Stmt
+	patchUserStmt                             *sql.Stmt
+	purgeCauseStmt                            *sql.Stmt
+	purgeTeamStmt                             *sql.Stmt
+	purgeUserStmt                             *sql.Stmt
+	rebuildDonationRollupsStmt                *sql.Stmt
+	rebuildLeaderboardEntriesStmt             *sql.Stmt
+	recomputeCauseTotalsStmt                  *sql.Stmt
+	releaseLoginAttemptStmt                   *sql.Stmt
+	removeUserFromTeamStmt                    *sql.Stmt
+	restoreCauseStmt                          *sql.Stmt
+	restoreTeamStmt                           *sql.Stmt
+	restoreUserStmt                           *sql.Stmt
+	revokeAPIKeyStmt                          *sql.Stmt
+	rollBackDonationImportStmt                *sql.Stmt
+	saveCauseReceiptTemplateStmt              *sql.Stmt
+	saveOrganizationReceiptTemplateStmt       *sql.Stmt
+	scheduleUserErasureStmt                   *sql.Stmt
+	scrubAuditLogForUserStmt                  *sql.Stmt
+	setUserRoleStmt                           *sql.Stmt
+	summarizeDonationImportChunkStmt          *sql.Stmt
+	takeLoginAttemptStmt                      *sql.Stmt
+	touchAPIKeyStmt                           *sql.Stmt
+	touchUserIdentityStmt                     *sql.Stmt
+	updateCauseStmt                           *sql.Stmt
+	updateDonationStatusStmt                  *sql.Stmt
+	updateLeaderboardEntryStmt                *sql.Stmt
+	updateTeamStmt                            *sql.Stmt
+	updateUserStmt                            *sql.Stmt
+	updateUserPasswordStmt                    *sql.Stmt
+	updateUserTOTPLastUsedStepStmt            *sql.Stmt
+	updateUserTeamRoleStmt                    *sql.Stmt
+	upsertRolePolicyStmt                      *sql.Stmt
+	upsertUserTOTPStmt                        *sql.Stmt
+	voidImportedDonationReceiptsStmt          *sql.Stmt
+	voidReceiptStmt                           *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                         tx,
-		tx:                         tx,
-		addUserToTeamStmt:          q.addUserToTeamStmt,
-		createCauseStmt:            q.createCauseStmt,
-		createDonationStmt:         q.createDonationStmt,
-		createLeaderboardStmt:      q.createLeaderboardStmt,
-		createTeamStmt:             q.createTeamStmt,
-		createUserStmt:             q.createUserStmt,
-		createUserTokenStmt:        q.createUserTokenStmt,
-		deleteCauseStmt:            q.deleteCauseStmt,
-		deleteExpiredTokensStmt:    q.deleteExpiredTokensStmt,
-		deleteTeamStmt:             q.deleteTeamStmt,
-		deleteUserStmt:             q.deleteUserStmt,
-		deleteUserTokenStmt:        q.deleteUserTokenStmt,
-		getCauseStmt:               q.getCauseStmt,
-		getDonationStmt:            q.getDonationStmt,
-		getLeaderboardStmt:         q.getLeaderboardStmt,
-		getLeaderboardEntriesStmt:  q.getLeaderboardEntriesStmt,
-		getTeamStmt:                q.getTeamStmt,
-		getUserStmt:                q.getUserStmt,
-		getUserByEmailStmt:         q.getUserByEmailStmt,
-		getUserTokenByTokenStmt:    q.getUserTokenByTokenStmt,
-		getUserTokenByUserIDStmt:   q.getUserTokenByUserIDStmt,
-		listCausesStmt:             q.listCausesStmt,
-		listDonationsStmt:          q.listDonationsStmt,
-		listLeaderboardsStmt:       q.listLeaderboardsStmt,
-		listTeamsStmt:              q.listTeamsStmt,
-		listUsersStmt:              q.listUsersStmt,
-		removeUserFromTeamStmt:     q.removeUserFromTeamStmt,
-		updateCauseStmt:            q.updateCauseStmt,
-		updateDonationStatusStmt:   q.updateDonationStatusStmt,
-		updateLeaderboardEntryStmt: q.updateLeaderboardEntryStmt,
-		updateTeamStmt:             q.updateTeamStmt,
-		updateUserStmt:             q.updateUserStmt,
-		updateUserTeamRoleStmt:     q.updateUserTeamRoleStmt,
+		db:                                        tx,
+		tx:                                        tx,
+		addUserToTeamStmt:                         q.addUserToTeamStmt,
+		anonymizeUserStmt:                         q.anonymizeUserStmt,
+		cancelUserErasureStmt:                     q.cancelUserErasureStmt,
+		claimDirtyRollupBucketsStmt:               q.claimDirtyRollupBucketsStmt,
+		claimPendingDataExportStmt:                q.claimPendingDataExportStmt,
+		claimPendingReportExportStmt:              q.claimPendingReportExportStmt,
+		clearCauseOwnerStmt:                       q.clearCauseOwnerStmt,
+		completeDataExportStmt:                    q.completeDataExportStmt,
+		completeDonationImportStmt:                q.completeDonationImportStmt,
+		completeReportExportStmt:                  q.completeReportExportStmt,
+		consumeRecoveryCodeStmt:                   q.consumeRecoveryCodeStmt,
+		consumeUserActionTokenStmt:                q.consumeUserActionTokenStmt,
+		countDirtyRollupBucketsStmt:               q.countDirtyRollupBucketsStmt,
+		countUnusedRecoveryCodesStmt:              q.countUnusedRecoveryCodesStmt,
+		createAPIKeyStmt:                          q.createAPIKeyStmt,
+		createAuditLogEntryStmt:                   q.createAuditLogEntryStmt,
+		createCauseStmt:                           q.createCauseStmt,
+		createDataExportStmt:                      q.createDataExportStmt,
+		createDataExportArchiveStmt:               q.createDataExportArchiveStmt,
+		createDonationStmt:                        q.createDonationStmt,
+		createDonationImportStmt:                  q.createDonationImportStmt,
+		createDonationImportRowsStmt:              q.createDonationImportRowsStmt,
+		createLeaderboardStmt:                     q.createLeaderboardStmt,
+		createReceiptStmt:                         q.createReceiptStmt,
+		createRecoveryCodeStmt:                    q.createRecoveryCodeStmt,
+		createReportExportStmt:                    q.createReportExportStmt,
+		createReportExportFileStmt:                q.createReportExportFileStmt,
+		createSecurityEventStmt:                   q.createSecurityEventStmt,
+		createSeedDonationStmt:                    q.createSeedDonationStmt,
+		createTeamStmt:                            q.createTeamStmt,
+		createUserStmt:                            q.createUserStmt,
+		createUserActionTokenStmt:                 q.createUserActionTokenStmt,
+		createUserIdentityStmt:                    q.createUserIdentityStmt,
+		createUserTokenStmt:                       q.createUserTokenStmt,
+		deleteCauseStmt:                           q.deleteCauseStmt,
+		deleteDataExportsByUserStmt:               q.deleteDataExportsByUserStmt,
+		deleteDonationImportRowsStmt:              q.deleteDonationImportRowsStmt,
+		deleteDonationRollupsStmt:                 q.deleteDonationRollupsStmt,
+		deleteExpiredDataExportsStmt:              q.deleteExpiredDataExportsStmt,
+		deleteExpiredReportExportsStmt:            q.deleteExpiredReportExportsStmt,
+		deleteExpiredTokensStmt:                   q.deleteExpiredTokensStmt,
+		deleteImportedDonationsStmt:               q.deleteImportedDonationsStmt,
+		deleteLeaderboardEntriesByLeaderboardStmt: q.deleteLeaderboardEntriesByLeaderboardStmt,
+		deleteLeaderboardEntriesByTeamStmt:        q.deleteLeaderboardEntriesByTeamStmt,
+		deleteLeaderboardEntriesByUserStmt:        q.deleteLeaderboardEntriesByUserStmt,
+		deleteLoginThrottleStmt:                   q.deleteLoginThrottleStmt,
+		deleteLoginThrottleForEmailStmt:           q.deleteLoginThrottleForEmailStmt,
+		deleteRecoveryCodesStmt:                   q.deleteRecoveryCodesStmt,
+		deleteTeamStmt:                            q.deleteTeamStmt,
+		deleteTeamMembershipsByTeamStmt:           q.deleteTeamMembershipsByTeamStmt,
+		deleteTeamMembershipsByUserStmt:           q.deleteTeamMembershipsByUserStmt,
+		deleteUserStmt:                            q.deleteUserStmt,
+		deleteUserPersonalDataStmt:                q.deleteUserPersonalDataStmt,
+		deleteUserTOTPStmt:                        q.deleteUserTOTPStmt,
+		deleteUserTokenStmt:                       q.deleteUserTokenStmt,
+		deleteUserTokensByUserIDStmt:              q.deleteUserTokensByUserIDStmt,
+		donationAnalyticsStmt:                     q.donationAnalyticsStmt,
+		donorCohortsStmt:                          q.donorCohortsStmt,
+		enableUserTOTPStmt:                        q.enableUserTOTPStmt,
+		exportDonationsStmt:                       q.exportDonationsStmt,
+		exportDonorsStmt:                          q.exportDonorsStmt,
+		exportTeamStatsStmt:                       q.exportTeamStatsStmt,
+		failDataExportStmt:                        q.failDataExportStmt,
+		failReportExportStmt:                      q.failReportExportStmt,
+		getActiveAPIKeyByHashStmt:                 q.getActiveAPIKeyByHashStmt,
+		getCauseStmt:                              q.getCauseStmt,
+		getDataExportStmt:                         q.getDataExportStmt,
+		getDataExportArchiveStmt:                  q.getDataExportArchiveStmt,
+		getDonationStmt:                           q.getDonationStmt,
+		getDonationImportStmt:                     q.getDonationImportStmt,
+		getLatestDonationReceiptStmt:              q.getLatestDonationReceiptStmt,
+		getLatestStatementStmt:                    q.getLatestStatementStmt,
+		getLeaderboardStmt:                        q.getLeaderboardStmt,
+		getLeaderboardEntriesStmt:                 q.getLeaderboardEntriesStmt,
+		getLeaderboardEntryStmt:                   q.getLeaderboardEntryStmt,
+		getLoginThrottleStmt:                      q.getLoginThrottleStmt,
+		getReceiptStmt:                            q.getReceiptStmt,
+		getReceiptCauseStmt:                       q.getReceiptCauseStmt,
+		getReceiptDonorStmt:                       q.getReceiptDonorStmt,
+		getReportExportStmt:                       q.getReportExportStmt,
+		getReportExportFileStmt:                   q.getReportExportFileStmt,
+		getTeamStmt:                               q.getTeamStmt,
+		getTwoFactorStatusStmt:                    q.getTwoFactorStatusStmt,
+		getUserStmt:                               q.getUserStmt,
+		getUserByEmailStmt:                        q.getUserByEmailStmt,
+		getUserByUsernameStmt:                     q.getUserByUsernameStmt,
+		getUserIdentityStmt:                       q.getUserIdentityStmt,
+		getUserIncludingDeletedStmt:               q.getUserIncludingDeletedStmt,
+		getUserTOTPStmt:                           q.getUserTOTPStmt,
+		getUserTeamStmt:                           q.getUserTeamStmt,
+		getUserTokenByTokenStmt:                   q.getUserTokenByTokenStmt,
+		getUserTokenByUserIDStmt:                  q.getUserTokenByUserIDStmt,
+		importDonationChunkStmt:                   q.importDonationChunkStmt,
+		invalidateUserActionTokensStmt:            q.invalidateUserActionTokensStmt,
+		listAPIKeysByUserStmt:                     q.listAPIKeysByUserStmt,
+		listAuditLogStmt:                          q.listAuditLogStmt,
+		listAuditLogByEntityStmt:                  q.listAuditLogByEntityStmt,
+		listCausesStmt:                            q.listCausesStmt,
+		listDataExportsByUserStmt:                 q.listDataExportsByUserStmt,
+		listDonationImportsStmt:                   q.listDonationImportsStmt,
+		listDonationsStmt:                         q.listDonationsStmt,
+		listDonationsByUserStmt:                   q.listDonationsByUserStmt,
+		listDonorSummariesStmt:                    q.listDonorSummariesStmt,
+		listDueErasuresStmt:                       q.listDueErasuresStmt,
+		listExpiredDeletedDonorsStmt:              q.listExpiredDeletedDonorsStmt,
+		listLeaderboardEntriesByUserStmt:          q.listLeaderboardEntriesByUserStmt,
+		listLeaderboardIDsStmt:                    q.listLeaderboardIDsStmt,
+		listLeaderboardsStmt:                      q.listLeaderboardsStmt,
+		listPurgeableCausesStmt:                   q.listPurgeableCausesStmt,
+		listPurgeableTeamsStmt:                    q.listPurgeableTeamsStmt,
+		listPurgeableUsersStmt:                    q.listPurgeableUsersStmt,
+		listReceiptTemplatesStmt:                  q.listReceiptTemplatesStmt,
+		listReceiptsByUserStmt:                    q.listReceiptsByUserStmt,
+		listReportExportsByUserStmt:               q.listReportExportsByUserStmt,
+		listRolePoliciesStmt:                      q.listRolePoliciesStmt,
+		listSecurityEventsByUserStmt:              q.listSecurityEventsByUserStmt,
+		listStatementDonationsStmt:                q.listStatementDonationsStmt,
+		listTeamsStmt:                             q.listTeamsStmt,
+		listTeamsByUserStmt:                       q.listTeamsByUserStmt,
+		listUserIdentitiesStmt:                    q.listUserIdentitiesStmt,
+		listUserTokensByUserStmt:                  q.listUserTokensByUserStmt,
+		listUsersStmt:                             q.listUsersStmt,
+		lockDonationStmt:                          q.lockDonationStmt,
+		lockDonationImportStmt:                    q.lockDonationImportStmt,
+		lockStatementStmt:                         q.lockStatementStmt,
+		markUserEmailVerifiedStmt:                 q.markUserEmailVerifiedStmt,
+		nextReceiptNumberStmt:                     q.nextReceiptNumberStmt,
+		patchCauseStmt:                            q.patchCauseStmt,
+		patchLeaderboardStmt:                      q.patchLeaderboardStmt,
+		patchTeamStmt:                             q.patchTeamStmt,
+		patchUserStmt:                             q.patchUserStmt,
+		purgeCauseStmt:                            q.purgeCauseStmt,
+		purgeTeamStmt:                             q.purgeTeamStmt,
+		purgeUserStmt:                             q.purgeUserStmt,
+		rebuildDonationRollupsStmt:                q.rebuildDonationRollupsStmt,
+		rebuildLeaderboardEntriesStmt:             q.rebuildLeaderboardEntriesStmt,
+		recomputeCauseTotalsStmt:                  q.recomputeCauseTotalsStmt,
+		releaseLoginAttemptStmt:                   q.releaseLoginAttemptStmt,
+		removeUserFromTeamStmt:                    q.removeUserFromTeamStmt,
+		restoreCauseStmt:                          q.restoreCauseStmt,
+		restoreTeamStmt:                           q.restoreTeamStmt,
+		restoreUserStmt:                           q.restoreUserStmt,
+		revokeAPIKeyStmt:                          q.revokeAPIKeyStmt,
+		rollBackDonationImportStmt:                q.rollBackDonationImportStmt,
+		saveCauseReceiptTemplateStmt:              q.saveCauseReceiptTemplateStmt,
+		saveOrganizationReceiptTemplateStmt:       q.saveOrganizationReceiptTemplateStmt,
+		scheduleUserErasureStmt:                   q.scheduleUserErasureStmt,
+		scrubAuditLogForUserStmt:                  q.scrubAuditLogForUserStmt,
+		setUserRoleStmt:                           q.setUserRoleStmt,
+		summarizeDonationImportChunkStmt:          q.summarizeDonationImportChunkStmt,
+		takeLoginAttemptStmt:                      q.takeLoginAttemptStmt,
+		touchAPIKeyStmt:                           q.touchAPIKeyStmt,
+		touchUserIdentityStmt:                     q.touchUserIdentityStmt,
+		updateCauseStmt:                           q.updateCauseStmt,
+		updateDonationStatusStmt:                  q.updateDonationStatusStmt,
+		updateLeaderboardEntryStmt:                q.updateLeaderboardEntryStmt,
+		updateTeamStmt:                            q.updateTeamStmt,
+		updateUserStmt:                            q.updateUserStmt,
+		updateUserPasswordStmt:                    q.updateUserPasswordStmt,
+		updateUserTOTPLastUsedStepStmt:            q.updateUserTOTPLastUsedStepStmt,
+		updateUserTeamRoleStmt:                    q.updateUserTeamRoleStmt,
+		upsertRolePolicyStmt:                      q.upsertRolePolicyStmt,
+		upsertUserTOTPStmt:                        q.upsertUserTOTPStmt,
+		voidImportedDonationReceiptsStmt:          q.voidImportedDonationReceiptsStmt,
+		voidReceiptStmt:                           q.voidReceiptStmt,
 	}
 }
@@ -0,0 +1,151 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: identities.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, provider, subject, email, created_at, last_login_at
+`
+
+type CreateUserIdentityParams struct {
+	UserID   int32          `json:"user_id"`
+	Provider string         `json:"provider"`
+	Subject  string         `json:"subject"`
+	Email    sql.NullString `json:"email"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.queryRow(ctx, q.createUserIdentityStmt, createUserIdentity,
+		arg.UserID,
+		arg.Provider,
+		arg.Subject,
+		arg.Email,
+	)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+		&i.LastLoginAt,
+	)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version FROM users
+WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.queryRow(ctx, q.getUserByUsernameStmt, getUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getUserIdentity = `-- name: GetUserIdentity :one
+SELECT id, user_id, provider, subject, email, created_at, last_login_at FROM user_identities
+WHERE provider = $1 AND subject = $2 LIMIT 1
+`
+
+type GetUserIdentityParams struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+func (q *Queries) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	row := q.queryRow(ctx, q.getUserIdentityStmt, getUserIdentity, arg.Provider, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+		&i.LastLoginAt,
+	)
+	return i, err
+}
+
+const listUserIdentities = `-- name: ListUserIdentities :many
+SELECT id, user_id, provider, subject, email, created_at, last_login_at FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListUserIdentities(ctx context.Context, userID int32) ([]UserIdentity, error) {
+	rows, err := q.query(ctx, q.listUserIdentitiesStmt, listUserIdentities, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserIdentity{}
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.Subject,
+			&i.Email,
+			&i.CreatedAt,
+			&i.LastLoginAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchUserIdentity = `-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = now(), email = $2
+WHERE id = $1
+`
+
+type TouchUserIdentityParams struct {
+	ID    int32          `json:"id"`
+	Email sql.NullString `json:"email"`
+}
+
+func (q *Queries) TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error {
+	_, err := q.exec(ctx, q.touchUserIdentityStmt, touchUserIdentity, arg.ID, arg.Email)
+	return err
+}
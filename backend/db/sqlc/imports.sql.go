@@ -0,0 +1,382 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: imports.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
+)
+
+const completeDonationImport = `-- name: CompleteDonationImport :one
+UPDATE donation_imports
+SET status = 'completed', completed_at = now()
+WHERE id = $1
+RETURNING id, created_by, source, filename, status, total_rows, valid_rows, errors, imported_through, imported_rows, created_at, completed_at, rolled_back_at
+`
+
+func (q *Queries) CompleteDonationImport(ctx context.Context, id int32) (DonationImport, error) {
+	row := q.queryRow(ctx, q.completeDonationImportStmt, completeDonationImport, id)
+	var i DonationImport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.Source,
+		&i.Filename,
+		&i.Status,
+		&i.TotalRows,
+		&i.ValidRows,
+		&i.Errors,
+		&i.ImportedThrough,
+		&i.ImportedRows,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.RolledBackAt,
+	)
+	return i, err
+}
+
+const createDonationImport = `-- name: CreateDonationImport :one
+INSERT INTO donation_imports (created_by, source, filename, total_rows, valid_rows, errors)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_by, source, filename, status, total_rows, valid_rows, errors, imported_through, imported_rows, created_at, completed_at, rolled_back_at
+`
+
+type CreateDonationImportParams struct {
+	CreatedBy sql.NullInt32   `json:"created_by"`
+	Source    string          `json:"source"`
+	Filename  string          `json:"filename"`
+	TotalRows int32           `json:"total_rows"`
+	ValidRows int32           `json:"valid_rows"`
+	Errors    json.RawMessage `json:"errors"`
+}
+
+func (q *Queries) CreateDonationImport(ctx context.Context, arg CreateDonationImportParams) (DonationImport, error) {
+	row := q.queryRow(ctx, q.createDonationImportStmt, createDonationImport,
+		arg.CreatedBy,
+		arg.Source,
+		arg.Filename,
+		arg.TotalRows,
+		arg.ValidRows,
+		arg.Errors,
+	)
+	var i DonationImport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.Source,
+		&i.Filename,
+		&i.Status,
+		&i.TotalRows,
+		&i.ValidRows,
+		&i.Errors,
+		&i.ImportedThrough,
+		&i.ImportedRows,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.RolledBackAt,
+	)
+	return i, err
+}
+
+const createDonationImportRows = `-- name: CreateDonationImportRows :execrows
+INSERT INTO donation_import_rows (import_id, row_number, user_id, cause_id, team_id, amount, donation_type, donated_at)
+SELECT $1::int,
+    unnest($2::int[]),
+    unnest($3::int[]),
+    unnest($4::int[]),
+    NULLIF(unnest($5::int[]), 0),
+    unnest($6::numeric[]),
+    unnest($7::text[]),
+    NULLIF(unnest($8::text[]), '')::timestamp
+`
+
+type CreateDonationImportRowsParams struct {
+	ImportID      int32    `json:"import_id"`
+	RowNumbers    []int32  `json:"row_numbers"`
+	UserIds       []int32  `json:"user_ids"`
+	CauseIds      []int32  `json:"cause_ids"`
+	TeamIds       []int32  `json:"team_ids"`
+	Amounts       []string `json:"amounts"`
+	DonationTypes []string `json:"donation_types"`
+	DonatedAt     []string `json:"donated_at"`
+}
+
+// Stages a batch's valid rows. The arrays are parallel, one element per row; a team ID of 0 and an
+// empty date mean none.
+func (q *Queries) CreateDonationImportRows(ctx context.Context, arg CreateDonationImportRowsParams) (int64, error) {
+	result, err := q.exec(ctx, q.createDonationImportRowsStmt, createDonationImportRows,
+		arg.ImportID,
+		pq.Array(arg.RowNumbers),
+		pq.Array(arg.UserIds),
+		pq.Array(arg.CauseIds),
+		pq.Array(arg.TeamIds),
+		pq.Array(arg.Amounts),
+		pq.Array(arg.DonationTypes),
+		pq.Array(arg.DonatedAt),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteDonationImportRows = `-- name: DeleteDonationImportRows :exec
+DELETE FROM donation_import_rows
+WHERE import_id = $1
+`
+
+// Drops a batch's staged rows once it is completed or rolled back; nothing reads them afterwards
+func (q *Queries) DeleteDonationImportRows(ctx context.Context, importID int32) error {
+	_, err := q.exec(ctx, q.deleteDonationImportRowsStmt, deleteDonationImportRows, importID)
+	return err
+}
+
+const deleteImportedDonations = `-- name: DeleteImportedDonations :execrows
+DELETE FROM donations
+WHERE import_id = $1
+`
+
+func (q *Queries) DeleteImportedDonations(ctx context.Context, importID sql.NullInt32) (int64, error) {
+	result, err := q.exec(ctx, q.deleteImportedDonationsStmt, deleteImportedDonations, importID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDonationImport = `-- name: GetDonationImport :one
+SELECT id, created_by, source, filename, status, total_rows, valid_rows, errors, imported_through, imported_rows, created_at, completed_at, rolled_back_at FROM donation_imports
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetDonationImport(ctx context.Context, id int32) (DonationImport, error) {
+	row := q.queryRow(ctx, q.getDonationImportStmt, getDonationImport, id)
+	var i DonationImport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.Source,
+		&i.Filename,
+		&i.Status,
+		&i.TotalRows,
+		&i.ValidRows,
+		&i.Errors,
+		&i.ImportedThrough,
+		&i.ImportedRows,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.RolledBackAt,
+	)
+	return i, err
+}
+
+const importDonationChunk = `-- name: ImportDonationChunk :one
+WITH chunk AS (
+    SELECT r.import_id, r.row_number, r.user_id, r.cause_id, r.team_id, r.amount, r.donation_type, r.donated_at
+    FROM donation_import_rows r
+    JOIN donation_imports i ON i.id = r.import_id
+    WHERE r.import_id = $1 AND r.row_number > i.imported_through
+    ORDER BY r.row_number
+    LIMIT $2
+), inserted AS (
+    INSERT INTO donations (user_id, cause_id, team_id, amount, donation_type, created_at, source, import_id)
+    SELECT chunk.user_id, chunk.cause_id, chunk.team_id, chunk.amount, chunk.donation_type,
+        COALESCE(chunk.donated_at, now()), i.source, i.id
+    FROM chunk
+    JOIN donation_imports i ON i.id = chunk.import_id
+    RETURNING id
+)
+UPDATE donation_imports
+SET status = 'importing',
+    imported_through = COALESCE((SELECT MAX(chunk.row_number) FROM chunk), imported_through),
+    imported_rows = imported_rows + (SELECT COUNT(*) FROM inserted)
+WHERE donation_imports.id = $1
+RETURNING id, created_by, source, filename, status, total_rows, valid_rows, errors, imported_through, imported_rows, created_at, completed_at, rolled_back_at
+`
+
+type ImportDonationChunkParams struct {
+	ImportID  int32 `json:"import_id"`
+	ChunkSize int32 `json:"chunk_size"`
+}
+
+// Imports the next chunk of staged rows in row order and records how far the batch has got, so an
+// interrupted commit carries on where it stopped
+func (q *Queries) ImportDonationChunk(ctx context.Context, arg ImportDonationChunkParams) (DonationImport, error) {
+	row := q.queryRow(ctx, q.importDonationChunkStmt, importDonationChunk, arg.ImportID, arg.ChunkSize)
+	var i DonationImport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.Source,
+		&i.Filename,
+		&i.Status,
+		&i.TotalRows,
+		&i.ValidRows,
+		&i.Errors,
+		&i.ImportedThrough,
+		&i.ImportedRows,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.RolledBackAt,
+	)
+	return i, err
+}
+
+const listDonationImports = `-- name: ListDonationImports :many
+SELECT id, created_by, source, filename, status, total_rows, valid_rows, errors, imported_through, imported_rows, created_at, completed_at, rolled_back_at FROM donation_imports
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListDonationImportsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListDonationImports(ctx context.Context, arg ListDonationImportsParams) ([]DonationImport, error) {
+	rows, err := q.query(ctx, q.listDonationImportsStmt, listDonationImports, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DonationImport{}
+	for rows.Next() {
+		var i DonationImport
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedBy,
+			&i.Source,
+			&i.Filename,
+			&i.Status,
+			&i.TotalRows,
+			&i.ValidRows,
+			&i.Errors,
+			&i.ImportedThrough,
+			&i.ImportedRows,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.RolledBackAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDonationImport = `-- name: LockDonationImport :one
+SELECT id, created_by, source, filename, status, total_rows, valid_rows, errors, imported_through, imported_rows, created_at, completed_at, rolled_back_at FROM donation_imports
+WHERE id = $1
+FOR UPDATE
+`
+
+// Commits and rollbacks of one batch take turns
+func (q *Queries) LockDonationImport(ctx context.Context, id int32) (DonationImport, error) {
+	row := q.queryRow(ctx, q.lockDonationImportStmt, lockDonationImport, id)
+	var i DonationImport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.Source,
+		&i.Filename,
+		&i.Status,
+		&i.TotalRows,
+		&i.ValidRows,
+		&i.Errors,
+		&i.ImportedThrough,
+		&i.ImportedRows,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.RolledBackAt,
+	)
+	return i, err
+}
+
+const rollBackDonationImport = `-- name: RollBackDonationImport :one
+UPDATE donation_imports
+SET status = 'rolled_back', rolled_back_at = now(), imported_rows = 0, imported_through = 0
+WHERE id = $1
+RETURNING id, created_by, source, filename, status, total_rows, valid_rows, errors, imported_through, imported_rows, created_at, completed_at, rolled_back_at
+`
+
+func (q *Queries) RollBackDonationImport(ctx context.Context, id int32) (DonationImport, error) {
+	row := q.queryRow(ctx, q.rollBackDonationImportStmt, rollBackDonationImport, id)
+	var i DonationImport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.Source,
+		&i.Filename,
+		&i.Status,
+		&i.TotalRows,
+		&i.ValidRows,
+		&i.Errors,
+		&i.ImportedThrough,
+		&i.ImportedRows,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.RolledBackAt,
+	)
+	return i, err
+}
+
+const summarizeDonationImportChunk = `-- name: SummarizeDonationImportChunk :many
+SELECT chunk.donation_type, COUNT(*) AS donations, SUM(chunk.amount)::float8 AS amount
+FROM (
+    SELECT r.donation_type, r.amount
+    FROM donation_import_rows r
+    JOIN donation_imports i ON i.id = r.import_id
+    WHERE r.import_id = $1 AND r.row_number > i.imported_through
+    ORDER BY r.row_number
+    LIMIT $2
+) chunk
+GROUP BY chunk.donation_type
+ORDER BY chunk.donation_type
+`
+
+type SummarizeDonationImportChunkParams struct {
+	ImportID  int32 `json:"import_id"`
+	ChunkSize int32 `json:"chunk_size"`
+}
+
+type SummarizeDonationImportChunkRow struct {
+	DonationType string  `json:"donation_type"`
+	Donations    int64   `json:"donations"`
+	Amount       float64 `json:"amount"`
+}
+
+// Counts and sums, by donation type, the rows ImportDonationChunk will import next
+func (q *Queries) SummarizeDonationImportChunk(ctx context.Context, arg SummarizeDonationImportChunkParams) ([]SummarizeDonationImportChunkRow, error) {
+	rows, err := q.query(ctx, q.summarizeDonationImportChunkStmt, summarizeDonationImportChunk, arg.ImportID, arg.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SummarizeDonationImportChunkRow{}
+	for rows.Next() {
+		var i SummarizeDonationImportChunkRow
+		if err := rows.Scan(&i.DonationType, &i.Donations, &i.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -6,9 +6,40 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/sqlc-dev/pqtype"
 )
 
+type ApiKey struct {
+	ID         int32         `json:"id"`
+	UserID     int32         `json:"user_id"`
+	TeamID     sql.NullInt32 `json:"team_id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	KeyHash    string        `json:"key_hash"`
+	Scopes     []string      `json:"scopes"`
+	ExpiresAt  sql.NullTime  `json:"expires_at"`
+	LastUsedAt sql.NullTime  `json:"last_used_at"`
+	RevokedAt  sql.NullTime  `json:"revoked_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type AuditLog struct {
+	ID         int64                 `json:"id"`
+	ActorID    sql.NullInt32         `json:"actor_id"`
+	Action     string                `json:"action"`
+	EntityType string                `json:"entity_type"`
+	EntityID   string                `json:"entity_id"`
+	Before     pqtype.NullRawMessage `json:"before"`
+	After      pqtype.NullRawMessage `json:"after"`
+	Diff       json.RawMessage       `json:"diff"`
+	RequestID  sql.NullString        `json:"request_id"`
+	Ip         sql.NullString        `json:"ip"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
 type Cause struct {
 	ID            int32          `json:"id"`
 	Name          string         `json:"name"`
@@ -23,6 +54,24 @@ type Cause struct {
 	Image         sql.NullString `json:"image"`
 	Category      sql.NullString `json:"category"`
 	OwnerID       sql.NullInt32  `json:"owner_id"`
+	DeletedAt     sql.NullTime   `json:"deleted_at"`
+	Version       int32          `json:"version"`
+}
+
+type DataExport struct {
+	ID          int32          `json:"id"`
+	UserID      int32          `json:"user_id"`
+	Status      string         `json:"status"`
+	Error       sql.NullString `json:"error"`
+	CreatedAt   time.Time      `json:"created_at"`
+	StartedAt   sql.NullTime   `json:"started_at"`
+	CompletedAt sql.NullTime   `json:"completed_at"`
+	ExpiresAt   sql.NullTime   `json:"expires_at"`
+}
+
+type DataExportArchive struct {
+	ExportID int32  `json:"export_id"`
+	Content  []byte `json:"content"`
 }
 
 type Donation struct {
@@ -34,6 +83,50 @@ type Donation struct {
 	DonationType sql.NullString `json:"donation_type"`
 	Status       sql.NullString `json:"status"`
 	CreatedAt    sql.NullTime   `json:"created_at"`
+	Source       sql.NullString `json:"source"`
+	ImportID     sql.NullInt32  `json:"import_id"`
+}
+
+type DonationImport struct {
+	ID              int32           `json:"id"`
+	CreatedBy       sql.NullInt32   `json:"created_by"`
+	Source          string          `json:"source"`
+	Filename        string          `json:"filename"`
+	Status          string          `json:"status"`
+	TotalRows       int32           `json:"total_rows"`
+	ValidRows       int32           `json:"valid_rows"`
+	Errors          json.RawMessage `json:"errors"`
+	ImportedThrough int32           `json:"imported_through"`
+	ImportedRows    int32           `json:"imported_rows"`
+	CreatedAt       time.Time       `json:"created_at"`
+	CompletedAt     sql.NullTime    `json:"completed_at"`
+	RolledBackAt    sql.NullTime    `json:"rolled_back_at"`
+}
+
+type DonationImportRow struct {
+	ImportID     int32         `json:"import_id"`
+	RowNumber    int32         `json:"row_number"`
+	UserID       int32         `json:"user_id"`
+	CauseID      int32         `json:"cause_id"`
+	TeamID       sql.NullInt32 `json:"team_id"`
+	Amount       string        `json:"amount"`
+	DonationType string        `json:"donation_type"`
+	DonatedAt    sql.NullTime  `json:"donated_at"`
+}
+
+type DonationRollup struct {
+	Bucket        time.Time `json:"bucket"`
+	CauseID       int32     `json:"cause_id"`
+	TeamID        int32     `json:"team_id"`
+	DonationType  string    `json:"donation_type"`
+	DonationCount int32     `json:"donation_count"`
+	AmountSum     string    `json:"amount_sum"`
+	DonorIds      []int32   `json:"donor_ids"`
+}
+
+type DonationRollupDirty struct {
+	Bucket  time.Time `json:"bucket"`
+	Changes int32     `json:"changes"`
 }
 
 type Leaderboard struct {
@@ -42,6 +135,7 @@ type Leaderboard struct {
 	Type      sql.NullString `json:"type"`
 	StartDate sql.NullTime   `json:"start_date"`
 	EndDate   sql.NullTime   `json:"end_date"`
+	Version   int32          `json:"version"`
 }
 
 type LeaderboardEntry struct {
@@ -52,6 +146,86 @@ type LeaderboardEntry struct {
 	Rank          sql.NullInt32  `json:"rank"`
 }
 
+type LoginThrottle struct {
+	Key           string       `json:"key"`
+	Failures      int32        `json:"failures"`
+	LastFailureAt time.Time    `json:"last_failure_at"`
+	LockedUntil   sql.NullTime `json:"locked_until"`
+}
+
+type Receipt struct {
+	ID            int32          `json:"id"`
+	Number        string         `json:"number"`
+	Kind          string         `json:"kind"`
+	UserID        int32          `json:"user_id"`
+	DonationID    sql.NullInt32  `json:"donation_id"`
+	Year          int32          `json:"year"`
+	Amount        string         `json:"amount"`
+	DonationCount int32          `json:"donation_count"`
+	Pdf           []byte         `json:"pdf"`
+	Sha256        string         `json:"sha256"`
+	Replaces      sql.NullInt32  `json:"replaces"`
+	IssuedAt      time.Time      `json:"issued_at"`
+	VoidedAt      sql.NullTime   `json:"voided_at"`
+	VoidReason    sql.NullString `json:"void_reason"`
+}
+
+type ReceiptCounter struct {
+	Series     string `json:"series"`
+	Year       int32  `json:"year"`
+	LastNumber int32  `json:"last_number"`
+}
+
+type ReceiptTemplate struct {
+	ID                 int32         `json:"id"`
+	CauseID            sql.NullInt32 `json:"cause_id"`
+	OrganizationName   string        `json:"organization_name"`
+	Address            string        `json:"address"`
+	RegistrationNumber string        `json:"registration_number"`
+	ContactEmail       string        `json:"contact_email"`
+	AccentColor        string        `json:"accent_color"`
+	HeaderText         string        `json:"header_text"`
+	FooterText         string        `json:"footer_text"`
+	Signatory          string        `json:"signatory"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+}
+
+type ReportExport struct {
+	ID          int32           `json:"id"`
+	RequestedBy int32           `json:"requested_by"`
+	Dataset     string          `json:"dataset"`
+	Format      string          `json:"format"`
+	Columns     []string        `json:"columns"`
+	Filters     json.RawMessage `json:"filters"`
+	Status      string          `json:"status"`
+	RowCount    int32           `json:"row_count"`
+	Error       sql.NullString  `json:"error"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   sql.NullTime    `json:"started_at"`
+	CompletedAt sql.NullTime    `json:"completed_at"`
+	ExpiresAt   sql.NullTime    `json:"expires_at"`
+}
+
+type ReportExportFile struct {
+	ExportID int32  `json:"export_id"`
+	Content  []byte `json:"content"`
+}
+
+type RolePolicy struct {
+	UserRole   string    `json:"user_role"`
+	Require2fa bool      `json:"require_2fa"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type SecurityEvent struct {
+	ID        int64          `json:"id"`
+	UserID    sql.NullInt32  `json:"user_id"`
+	EventType string         `json:"event_type"`
+	Ip        sql.NullString `json:"ip"`
+	UserAgent sql.NullString `json:"user_agent"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
 type Team struct {
 	ID          int32          `json:"id"`
 	Name        string         `json:"name"`
@@ -59,25 +233,62 @@ type Team struct {
 	AvatarUrl   sql.NullString `json:"avatar_url"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
 	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	DeletedAt   sql.NullTime   `json:"deleted_at"`
+	Version     int32          `json:"version"`
 }
 
 type User struct {
-	ID           int32          `json:"id"`
-	Username     string         `json:"username"`
-	Email        string         `json:"email"`
-	PasswordHash string         `json:"password_hash"`
-	FirstName    sql.NullString `json:"first_name"`
-	LastName     sql.NullString `json:"last_name"`
-	AvatarUrl    sql.NullString `json:"avatar_url"`
-	CreatedAt    sql.NullTime   `json:"created_at"`
-	UpdatedAt    sql.NullTime   `json:"updated_at"`
-	UserRole     sql.NullString `json:"user_role"`
+	ID                  int32          `json:"id"`
+	Username            string         `json:"username"`
+	Email               string         `json:"email"`
+	PasswordHash        string         `json:"password_hash"`
+	FirstName           sql.NullString `json:"first_name"`
+	LastName            sql.NullString `json:"last_name"`
+	AvatarUrl           sql.NullString `json:"avatar_url"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	UserRole            sql.NullString `json:"user_role"`
+	EmailVerifiedAt     sql.NullTime   `json:"email_verified_at"`
+	ErasureRequestedAt  sql.NullTime   `json:"erasure_requested_at"`
+	ErasureScheduledFor sql.NullTime   `json:"erasure_scheduled_for"`
+	AnonymizedAt        sql.NullTime   `json:"anonymized_at"`
+	DeletedAt           sql.NullTime   `json:"deleted_at"`
+	Version             int32          `json:"version"`
+}
+
+type UserActionToken struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	Purpose   string       `json:"purpose"`
+	TokenHash string       `json:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type UserIdentity struct {
+	ID          int32          `json:"id"`
+	UserID      int32          `json:"user_id"`
+	Provider    string         `json:"provider"`
+	Subject     string         `json:"subject"`
+	Email       sql.NullString `json:"email"`
+	CreatedAt   time.Time      `json:"created_at"`
+	LastLoginAt time.Time      `json:"last_login_at"`
+}
+
+type UserRecoveryCode struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	CodeHash  string       `json:"code_hash"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 type UserTeam struct {
-	UserID int32  `json:"user_id"`
-	TeamID int32  `json:"team_id"`
-	Role   string `json:"role"`
+	UserID   int32     `json:"user_id"`
+	TeamID   int32     `json:"team_id"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
 }
 
 type UserToken struct {
@@ -88,3 +299,11 @@ type UserToken struct {
 	CreatedAt time.Time     `json:"created_at"`
 	UpdatedAt time.Time     `json:"updated_at"`
 }
+
+type UserTotp struct {
+	UserID       int32        `json:"user_id"`
+	Secret       string       `json:"secret"`
+	EnabledAt    sql.NullTime `json:"enabled_at"`
+	LastUsedStep int64        `json:"last_used_step"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
@@ -0,0 +1,254 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: patch.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const patchCause = `-- name: PatchCause :one
+UPDATE causes
+SET name = CASE WHEN $1::bool THEN $2::varchar ELSE name END,
+    description = CASE WHEN $3::bool THEN $4::text ELSE description END,
+    goal = CASE WHEN $5::bool THEN $6::numeric ELSE goal END,
+    start_date = CASE WHEN $7::bool THEN $8::date ELSE start_date END,
+    end_date = CASE WHEN $9::bool THEN $10::date ELSE end_date END,
+    status = CASE WHEN $11::bool THEN $12::varchar ELSE status END,
+    image = CASE WHEN $13::bool THEN $14::text ELSE image END,
+    category = CASE WHEN $15::bool THEN $16::varchar ELSE category END,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = $17 AND deleted_at IS NULL AND version = $18
+RETURNING id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version
+`
+
+type PatchCauseParams struct {
+	SetName        bool           `json:"set_name"`
+	Name           string         `json:"name"`
+	SetDescription bool           `json:"set_description"`
+	Description    sql.NullString `json:"description"`
+	SetGoal        bool           `json:"set_goal"`
+	Goal           sql.NullString `json:"goal"`
+	SetStartDate   bool           `json:"set_start_date"`
+	StartDate      sql.NullTime   `json:"start_date"`
+	SetEndDate     bool           `json:"set_end_date"`
+	EndDate        sql.NullTime   `json:"end_date"`
+	SetStatus      bool           `json:"set_status"`
+	Status         sql.NullString `json:"status"`
+	SetImage       bool           `json:"set_image"`
+	Image          sql.NullString `json:"image"`
+	SetCategory    bool           `json:"set_category"`
+	Category       sql.NullString `json:"category"`
+	ID             int32          `json:"id"`
+	Version        int32          `json:"version"`
+}
+
+func (q *Queries) PatchCause(ctx context.Context, arg PatchCauseParams) (Cause, error) {
+	row := q.queryRow(ctx, q.patchCauseStmt, patchCause,
+		arg.SetName,
+		arg.Name,
+		arg.SetDescription,
+		arg.Description,
+		arg.SetGoal,
+		arg.Goal,
+		arg.SetStartDate,
+		arg.StartDate,
+		arg.SetEndDate,
+		arg.EndDate,
+		arg.SetStatus,
+		arg.Status,
+		arg.SetImage,
+		arg.Image,
+		arg.SetCategory,
+		arg.Category,
+		arg.ID,
+		arg.Version,
+	)
+	var i Cause
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Goal,
+		&i.CurrentAmount,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Image,
+		&i.Category,
+		&i.OwnerID,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const patchLeaderboard = `-- name: PatchLeaderboard :one
+UPDATE leaderboards
+SET name = CASE WHEN $1::bool THEN $2::varchar ELSE name END,
+    type = CASE WHEN $3::bool THEN $4::varchar ELSE type END,
+    start_date = CASE WHEN $5::bool THEN $6::date ELSE start_date END,
+    end_date = CASE WHEN $7::bool THEN $8::date ELSE end_date END,
+    version = version + 1
+WHERE id = $9 AND version = $10
+RETURNING id, name, type, start_date, end_date, version
+`
+
+type PatchLeaderboardParams struct {
+	SetName      bool           `json:"set_name"`
+	Name         string         `json:"name"`
+	SetType      bool           `json:"set_type"`
+	Type         sql.NullString `json:"type"`
+	SetStartDate bool           `json:"set_start_date"`
+	StartDate    sql.NullTime   `json:"start_date"`
+	SetEndDate   bool           `json:"set_end_date"`
+	EndDate      sql.NullTime   `json:"end_date"`
+	ID           int32          `json:"id"`
+	Version      int32          `json:"version"`
+}
+
+func (q *Queries) PatchLeaderboard(ctx context.Context, arg PatchLeaderboardParams) (Leaderboard, error) {
+	row := q.queryRow(ctx, q.patchLeaderboardStmt, patchLeaderboard,
+		arg.SetName,
+		arg.Name,
+		arg.SetType,
+		arg.Type,
+		arg.SetStartDate,
+		arg.StartDate,
+		arg.SetEndDate,
+		arg.EndDate,
+		arg.ID,
+		arg.Version,
+	)
+	var i Leaderboard
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Type,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const patchTeam = `-- name: PatchTeam :one
+UPDATE teams
+SET name = CASE WHEN $1::bool THEN $2::varchar ELSE name END,
+    description = CASE WHEN $3::bool THEN $4::text ELSE description END,
+    avatar_url = CASE WHEN $5::bool THEN $6::text ELSE avatar_url END,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = $7 AND deleted_at IS NULL AND version = $8
+RETURNING id, name, description, avatar_url, created_at, updated_at, deleted_at, version
+`
+
+type PatchTeamParams struct {
+	SetName        bool           `json:"set_name"`
+	Name           string         `json:"name"`
+	SetDescription bool           `json:"set_description"`
+	Description    sql.NullString `json:"description"`
+	SetAvatarUrl   bool           `json:"set_avatar_url"`
+	AvatarUrl      sql.NullString `json:"avatar_url"`
+	ID             int32          `json:"id"`
+	Version        int32          `json:"version"`
+}
+
+func (q *Queries) PatchTeam(ctx context.Context, arg PatchTeamParams) (Team, error) {
+	row := q.queryRow(ctx, q.patchTeamStmt, patchTeam,
+		arg.SetName,
+		arg.Name,
+		arg.SetDescription,
+		arg.Description,
+		arg.SetAvatarUrl,
+		arg.AvatarUrl,
+		arg.ID,
+		arg.Version,
+	)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const patchUser = `-- name: PatchUser :one
+
+UPDATE users
+SET username = CASE WHEN $1::bool THEN $2::varchar ELSE username END,
+    email = CASE WHEN $3::bool THEN $4::varchar ELSE email END,
+    email_verified_at = CASE WHEN $3::bool AND $4::varchar <> email THEN NULL ELSE email_verified_at END,
+    first_name = CASE WHEN $5::bool THEN $6::varchar ELSE first_name END,
+    last_name = CASE WHEN $7::bool THEN $8::varchar ELSE last_name END,
+    avatar_url = CASE WHEN $9::bool THEN $10::text ELSE avatar_url END,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = $11 AND deleted_at IS NULL AND version = $12
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
+`
+
+type PatchUserParams struct {
+	SetUsername  bool           `json:"set_username"`
+	Username     string         `json:"username"`
+	SetEmail     bool           `json:"set_email"`
+	Email        string         `json:"email"`
+	SetFirstName bool           `json:"set_first_name"`
+	FirstName    sql.NullString `json:"first_name"`
+	SetLastName  bool           `json:"set_last_name"`
+	LastName     sql.NullString `json:"last_name"`
+	SetAvatarUrl bool           `json:"set_avatar_url"`
+	AvatarUrl    sql.NullString `json:"avatar_url"`
+	ID           int32          `json:"id"`
+	Version      int32          `json:"version"`
+}
+
+// Partial updates for JSON merge patches: each column changes only when its set_ flag is true
+func (q *Queries) PatchUser(ctx context.Context, arg PatchUserParams) (User, error) {
+	row := q.queryRow(ctx, q.patchUserStmt, patchUser,
+		arg.SetUsername,
+		arg.Username,
+		arg.SetEmail,
+		arg.Email,
+		arg.SetFirstName,
+		arg.FirstName,
+		arg.SetLastName,
+		arg.LastName,
+		arg.SetAvatarUrl,
+		arg.AvatarUrl,
+		arg.ID,
+		arg.Version,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
@@ -14,7 +14,7 @@ import (
 const addUserToTeam = `-- name: AddUserToTeam :one
 INSERT INTO user_team (user_id, team_id, role)
 VALUES ($1, $2, $3)
-RETURNING user_id, team_id, role
+RETURNING user_id, team_id, role, joined_at
 `
 
 type AddUserToTeamParams struct {
@@ -26,14 +26,19 @@ type AddUserToTeamParams struct {
 func (q *Queries) AddUserToTeam(ctx context.Context, arg AddUserToTeamParams) (UserTeam, error) {
 	row := q.queryRow(ctx, q.addUserToTeamStmt, addUserToTeam, arg.UserID, arg.TeamID, arg.Role)
 	var i UserTeam
-	err := row.Scan(&i.UserID, &i.TeamID, &i.Role)
+	err := row.Scan(
+		&i.UserID,
+		&i.TeamID,
+		&i.Role,
+		&i.JoinedAt,
+	)
 	return i, err
 }
 
 const createCause = `-- name: CreateCause :one
 INSERT INTO causes (name, description, goal, start_date, end_date, status, image, category, owner_id)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id
+RETURNING id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version
 `
 
 type CreateCauseParams struct {
@@ -75,6 +80,8 @@ func (q *Queries) CreateCause(ctx context.Context, arg CreateCauseParams) (Cause
 		&i.Image,
 		&i.Category,
 		&i.OwnerID,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -82,7 +89,7 @@ func (q *Queries) CreateCause(ctx context.Context, arg CreateCauseParams) (Cause
 const createDonation = `-- name: CreateDonation :one
 INSERT INTO donations (user_id, cause_id, team_id, amount, donation_type, status)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, user_id, cause_id, team_id, amount, donation_type, status, created_at
+RETURNING id, user_id, cause_id, team_id, amount, donation_type, status, created_at, source, import_id
 `
 
 type CreateDonationParams struct {
@@ -113,6 +120,8 @@ func (q *Queries) CreateDonation(ctx context.Context, arg CreateDonationParams)
 		&i.DonationType,
 		&i.Status,
 		&i.CreatedAt,
+		&i.Source,
+		&i.ImportID,
 	)
 	return i, err
 }
@@ -120,7 +129,7 @@ func (q *Queries) CreateDonation(ctx context.Context, arg CreateDonationParams)
 const createLeaderboard = `-- name: CreateLeaderboard :one
 INSERT INTO leaderboards (name, type, start_date, end_date)
 VALUES ($1, $2, $3, $4)
-RETURNING id, name, type, start_date, end_date
+RETURNING id, name, type, start_date, end_date, version
 `
 
 type CreateLeaderboardParams struct {
@@ -144,6 +153,7 @@ func (q *Queries) CreateLeaderboard(ctx context.Context, arg CreateLeaderboardPa
 		&i.Type,
 		&i.StartDate,
 		&i.EndDate,
+		&i.Version,
 	)
 	return i, err
 }
@@ -151,7 +161,7 @@ func (q *Queries) CreateLeaderboard(ctx context.Context, arg CreateLeaderboardPa
 const createTeam = `-- name: CreateTeam :one
 INSERT INTO teams (name, description, avatar_url)
 VALUES ($1, $2, $3)
-RETURNING id, name, description, avatar_url, created_at, updated_at
+RETURNING id, name, description, avatar_url, created_at, updated_at, deleted_at, version
 `
 
 type CreateTeamParams struct {
@@ -170,6 +180,8 @@ func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, e
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -177,7 +189,7 @@ func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, e
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (username, email, password_hash, first_name, last_name, avatar_url, user_role)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
 `
 
 type CreateUserParams struct {
@@ -212,6 +224,12 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -242,44 +260,101 @@ func (q *Queries) CreateUserToken(ctx context.Context, arg CreateUserTokenParams
 	return i, err
 }
 
-const deleteCause = `-- name: DeleteCause :exec
-DELETE FROM causes
-WHERE id = $1
+const deleteCause = `-- name: DeleteCause :one
+UPDATE causes
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version
 `
 
-func (q *Queries) DeleteCause(ctx context.Context, id int32) error {
-	_, err := q.exec(ctx, q.deleteCauseStmt, deleteCause, id)
-	return err
+func (q *Queries) DeleteCause(ctx context.Context, id int32) (Cause, error) {
+	row := q.queryRow(ctx, q.deleteCauseStmt, deleteCause, id)
+	var i Cause
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Goal,
+		&i.CurrentAmount,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Image,
+		&i.Category,
+		&i.OwnerID,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
-const deleteExpiredTokens = `-- name: DeleteExpiredTokens :exec
+const deleteExpiredTokens = `-- name: DeleteExpiredTokens :execrows
 DELETE FROM user_tokens
 WHERE expiry < now()
 `
 
-func (q *Queries) DeleteExpiredTokens(ctx context.Context) error {
-	_, err := q.exec(ctx, q.deleteExpiredTokensStmt, deleteExpiredTokens)
-	return err
+func (q *Queries) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	result, err := q.exec(ctx, q.deleteExpiredTokensStmt, deleteExpiredTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteTeam = `-- name: DeleteTeam :exec
-DELETE FROM teams
-WHERE id = $1
+const deleteTeam = `-- name: DeleteTeam :one
+UPDATE teams
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, description, avatar_url, created_at, updated_at, deleted_at, version
 `
 
-func (q *Queries) DeleteTeam(ctx context.Context, id int32) error {
-	_, err := q.exec(ctx, q.deleteTeamStmt, deleteTeam, id)
-	return err
+func (q *Queries) DeleteTeam(ctx context.Context, id int32) (Team, error) {
+	row := q.queryRow(ctx, q.deleteTeamStmt, deleteTeam, id)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1
+const deleteUser = `-- name: DeleteUser :one
+UPDATE users
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
-	_, err := q.exec(ctx, q.deleteUserStmt, deleteUser, id)
-	return err
+func (q *Queries) DeleteUser(ctx context.Context, id int32) (User, error) {
+	row := q.queryRow(ctx, q.deleteUserStmt, deleteUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
 const deleteUserToken = `-- name: DeleteUserToken :exec
@@ -299,8 +374,8 @@ func (q *Queries) DeleteUserToken(ctx context.Context, arg DeleteUserTokenParams
 }
 
 const getCause = `-- name: GetCause :one
-SELECT id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id FROM causes
-WHERE id = $1 LIMIT 1
+SELECT id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version FROM causes
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetCause(ctx context.Context, id int32) (Cause, error) {
@@ -320,12 +395,14 @@ func (q *Queries) GetCause(ctx context.Context, id int32) (Cause, error) {
 		&i.Image,
 		&i.Category,
 		&i.OwnerID,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getDonation = `-- name: GetDonation :one
-SELECT id, user_id, cause_id, team_id, amount, donation_type, status, created_at FROM donations
+SELECT id, user_id, cause_id, team_id, amount, donation_type, status, created_at, source, import_id FROM donations
 WHERE id = $1 LIMIT 1
 `
 
@@ -341,12 +418,14 @@ func (q *Queries) GetDonation(ctx context.Context, id int32) (Donation, error) {
 		&i.DonationType,
 		&i.Status,
 		&i.CreatedAt,
+		&i.Source,
+		&i.ImportID,
 	)
 	return i, err
 }
 
 const getLeaderboard = `-- name: GetLeaderboard :one
-SELECT id, name, type, start_date, end_date FROM leaderboards
+SELECT id, name, type, start_date, end_date, version FROM leaderboards
 WHERE id = $1 LIMIT 1
 `
 
@@ -359,6 +438,7 @@ func (q *Queries) GetLeaderboard(ctx context.Context, id int32) (Leaderboard, er
 		&i.Type,
 		&i.StartDate,
 		&i.EndDate,
+		&i.Version,
 	)
 	return i, err
 }
@@ -405,9 +485,33 @@ func (q *Queries) GetLeaderboardEntries(ctx context.Context, arg GetLeaderboardE
 	return items, nil
 }
 
+const getLeaderboardEntry = `-- name: GetLeaderboardEntry :one
+SELECT leaderboard_id, user_id, team_id, score, rank FROM leaderboard_entries
+WHERE leaderboard_id = $1 AND user_id = $2 AND team_id = $3 LIMIT 1
+`
+
+type GetLeaderboardEntryParams struct {
+	LeaderboardID int32 `json:"leaderboard_id"`
+	UserID        int32 `json:"user_id"`
+	TeamID        int32 `json:"team_id"`
+}
+
+func (q *Queries) GetLeaderboardEntry(ctx context.Context, arg GetLeaderboardEntryParams) (LeaderboardEntry, error) {
+	row := q.queryRow(ctx, q.getLeaderboardEntryStmt, getLeaderboardEntry, arg.LeaderboardID, arg.UserID, arg.TeamID)
+	var i LeaderboardEntry
+	err := row.Scan(
+		&i.LeaderboardID,
+		&i.UserID,
+		&i.TeamID,
+		&i.Score,
+		&i.Rank,
+	)
+	return i, err
+}
+
 const getTeam = `-- name: GetTeam :one
-SELECT id, name, description, avatar_url, created_at, updated_at FROM teams
-WHERE id = $1 LIMIT 1
+SELECT id, name, description, avatar_url, created_at, updated_at, deleted_at, version FROM teams
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetTeam(ctx context.Context, id int32) (Team, error) {
@@ -420,13 +524,15 @@ func (q *Queries) GetTeam(ctx context.Context, id int32) (Team, error) {
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role FROM users
-WHERE id = $1 LIMIT 1
+SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version FROM users
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
@@ -443,13 +549,19 @@ func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role FROM users
-WHERE email = $1 LIMIT 1
+SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version FROM users
+WHERE email = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -466,6 +578,12 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -515,7 +633,8 @@ func (q *Queries) GetUserTokenByUserID(ctx context.Context, userID sql.NullInt32
 }
 
 const listCauses = `-- name: ListCauses :many
-SELECT id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id FROM causes
+SELECT id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version FROM causes
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2
 `
@@ -548,6 +667,8 @@ func (q *Queries) ListCauses(ctx context.Context, arg ListCausesParams) ([]Cause
 			&i.Image,
 			&i.Category,
 			&i.OwnerID,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -563,7 +684,7 @@ func (q *Queries) ListCauses(ctx context.Context, arg ListCausesParams) ([]Cause
 }
 
 const listDonations = `-- name: ListDonations :many
-SELECT id, user_id, cause_id, team_id, amount, donation_type, status, created_at FROM donations
+SELECT id, user_id, cause_id, team_id, amount, donation_type, status, created_at, source, import_id FROM donations
 ORDER BY id
 LIMIT $1 OFFSET $2
 `
@@ -591,6 +712,8 @@ func (q *Queries) ListDonations(ctx context.Context, arg ListDonationsParams) ([
 			&i.DonationType,
 			&i.Status,
 			&i.CreatedAt,
+			&i.Source,
+			&i.ImportID,
 		); err != nil {
 			return nil, err
 		}
@@ -606,7 +729,7 @@ func (q *Queries) ListDonations(ctx context.Context, arg ListDonationsParams) ([
 }
 
 const listLeaderboards = `-- name: ListLeaderboards :many
-SELECT id, name, type, start_date, end_date FROM leaderboards
+SELECT id, name, type, start_date, end_date, version FROM leaderboards
 ORDER BY id
 LIMIT $1 OFFSET $2
 `
@@ -631,6 +754,7 @@ func (q *Queries) ListLeaderboards(ctx context.Context, arg ListLeaderboardsPara
 			&i.Type,
 			&i.StartDate,
 			&i.EndDate,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -646,7 +770,8 @@ func (q *Queries) ListLeaderboards(ctx context.Context, arg ListLeaderboardsPara
 }
 
 const listTeams = `-- name: ListTeams :many
-SELECT id, name, description, avatar_url, created_at, updated_at FROM teams
+SELECT id, name, description, avatar_url, created_at, updated_at, deleted_at, version FROM teams
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2
 `
@@ -672,6 +797,8 @@ func (q *Queries) ListTeams(ctx context.Context, arg ListTeamsParams) ([]Team, e
 			&i.AvatarUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -687,7 +814,8 @@ func (q *Queries) ListTeams(ctx context.Context, arg ListTeamsParams) ([]Team, e
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role FROM users
+SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version FROM users
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2
 `
@@ -717,6 +845,12 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.UserRole,
+			&i.EmailVerifiedAt,
+			&i.ErasureRequestedAt,
+			&i.ErasureScheduledFor,
+			&i.AnonymizedAt,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -748,9 +882,9 @@ func (q *Queries) RemoveUserFromTeam(ctx context.Context, arg RemoveUserFromTeam
 
 const updateCause = `-- name: UpdateCause :one
 UPDATE causes
-SET name = $2, description = $3, goal = $4, current_amount = $5, start_date = $6, end_date = $7, status = $8, image = $9, category = $10, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id
+SET name = $2, description = $3, goal = $4, current_amount = $5, start_date = $6, end_date = $7, status = $8, image = $9, category = $10, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL AND version = $11
+RETURNING id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version
 `
 
 type UpdateCauseParams struct {
@@ -764,6 +898,7 @@ type UpdateCauseParams struct {
 	Status        sql.NullString `json:"status"`
 	Image         sql.NullString `json:"image"`
 	Category      sql.NullString `json:"category"`
+	Version       int32          `json:"version"`
 }
 
 func (q *Queries) UpdateCause(ctx context.Context, arg UpdateCauseParams) (Cause, error) {
@@ -778,6 +913,7 @@ func (q *Queries) UpdateCause(ctx context.Context, arg UpdateCauseParams) (Cause
 		arg.Status,
 		arg.Image,
 		arg.Category,
+		arg.Version,
 	)
 	var i Cause
 	err := row.Scan(
@@ -794,6 +930,8 @@ func (q *Queries) UpdateCause(ctx context.Context, arg UpdateCauseParams) (Cause
 		&i.Image,
 		&i.Category,
 		&i.OwnerID,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -802,7 +940,7 @@ const updateDonationStatus = `-- name: UpdateDonationStatus :one
 UPDATE donations
 SET status = $2
 WHERE id = $1
-RETURNING id, user_id, cause_id, team_id, amount, donation_type, status, created_at
+RETURNING id, user_id, cause_id, team_id, amount, donation_type, status, created_at, source, import_id
 `
 
 type UpdateDonationStatusParams struct {
@@ -822,6 +960,8 @@ func (q *Queries) UpdateDonationStatus(ctx context.Context, arg UpdateDonationSt
 		&i.DonationType,
 		&i.Status,
 		&i.CreatedAt,
+		&i.Source,
+		&i.ImportID,
 	)
 	return i, err
 }
@@ -863,9 +1003,9 @@ func (q *Queries) UpdateLeaderboardEntry(ctx context.Context, arg UpdateLeaderbo
 
 const updateTeam = `-- name: UpdateTeam :one
 UPDATE teams
-SET name = $2, description = $3, avatar_url = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, name, description, avatar_url, created_at, updated_at
+SET name = $2, description = $3, avatar_url = $4, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL AND version = $5
+RETURNING id, name, description, avatar_url, created_at, updated_at, deleted_at, version
 `
 
 type UpdateTeamParams struct {
@@ -873,6 +1013,7 @@ type UpdateTeamParams struct {
 	Name        string         `json:"name"`
 	Description sql.NullString `json:"description"`
 	AvatarUrl   sql.NullString `json:"avatar_url"`
+	Version     int32          `json:"version"`
 }
 
 func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error) {
@@ -881,6 +1022,7 @@ func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, e
 		arg.Name,
 		arg.Description,
 		arg.AvatarUrl,
+		arg.Version,
 	)
 	var i Team
 	err := row.Scan(
@@ -890,15 +1032,17 @@ func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, e
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET username = $2, email = $3, first_name = $4, last_name = $5, avatar_url = $6, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role
+SET username = $2, email = $3, first_name = $4, last_name = $5, avatar_url = $6, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL AND version = $7
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
 `
 
 type UpdateUserParams struct {
@@ -908,6 +1052,7 @@ type UpdateUserParams struct {
 	FirstName sql.NullString `json:"first_name"`
 	LastName  sql.NullString `json:"last_name"`
 	AvatarUrl sql.NullString `json:"avatar_url"`
+	Version   int32          `json:"version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -918,6 +1063,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.FirstName,
 		arg.LastName,
 		arg.AvatarUrl,
+		arg.Version,
 	)
 	var i User
 	err := row.Scan(
@@ -931,6 +1077,12 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -939,7 +1091,7 @@ const updateUserTeamRole = `-- name: UpdateUserTeamRole :one
 UPDATE user_team
 SET role = $3
 WHERE user_id = $1 AND team_id = $2
-RETURNING user_id, team_id, role
+RETURNING user_id, team_id, role, joined_at
 `
 
 type UpdateUserTeamRoleParams struct {
@@ -951,6 +1103,11 @@ type UpdateUserTeamRoleParams struct {
 func (q *Queries) UpdateUserTeamRole(ctx context.Context, arg UpdateUserTeamRoleParams) (UserTeam, error) {
 	row := q.queryRow(ctx, q.updateUserTeamRoleStmt, updateUserTeamRole, arg.UserID, arg.TeamID, arg.Role)
 	var i UserTeam
-	err := row.Scan(&i.UserID, &i.TeamID, &i.Role)
+	err := row.Scan(
+		&i.UserID,
+		&i.TeamID,
+		&i.Role,
+		&i.JoinedAt,
+	)
 	return i, err
 }
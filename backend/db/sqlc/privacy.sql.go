@@ -0,0 +1,568 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: privacy.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const anonymizeUser = `-- name: AnonymizeUser :one
+UPDATE users
+SET username = 'deleted-user-' || id,
+    email = 'deleted-user-' || id || '@invalid',
+    password_hash = '',
+    first_name = NULL,
+    last_name = NULL,
+    avatar_url = NULL,
+    email_verified_at = NULL,
+    erasure_scheduled_for = NULL,
+    anonymized_at = now(),
+    updated_at = now(),
+    version = version + 1
+WHERE id = $1 AND anonymized_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
+`
+
+func (q *Queries) AnonymizeUser(ctx context.Context, id int32) (User, error) {
+	row := q.queryRow(ctx, q.anonymizeUserStmt, anonymizeUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const cancelUserErasure = `-- name: CancelUserErasure :one
+UPDATE users
+SET erasure_requested_at = NULL, erasure_scheduled_for = NULL, version = version + 1
+WHERE id = $1 AND erasure_scheduled_for IS NOT NULL AND anonymized_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
+`
+
+func (q *Queries) CancelUserErasure(ctx context.Context, id int32) (User, error) {
+	row := q.queryRow(ctx, q.cancelUserErasureStmt, cancelUserErasure, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const claimPendingDataExport = `-- name: ClaimPendingDataExport :one
+UPDATE data_exports
+SET status = 'running', started_at = now()
+WHERE id = (
+    SELECT id FROM data_exports
+    WHERE status = 'pending' OR (status = 'running' AND started_at < now() - make_interval(secs => $1::float8))
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, status, error, created_at, started_at, completed_at, expires_at
+`
+
+// Claims the oldest pending export, or a running one whose worker has not finished it within
+// stale_seconds and is taken to have stopped
+func (q *Queries) ClaimPendingDataExport(ctx context.Context, staleSeconds float64) (DataExport, error) {
+	row := q.queryRow(ctx, q.claimPendingDataExportStmt, claimPendingDataExport, staleSeconds)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const completeDataExport = `-- name: CompleteDataExport :execrows
+UPDATE data_exports
+SET status = 'completed', completed_at = now(), expires_at = $3
+WHERE id = $1 AND status = 'running' AND started_at = $2
+`
+
+type CompleteDataExportParams struct {
+	ID        int32        `json:"id"`
+	StartedAt sql.NullTime `json:"started_at"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
+}
+
+// Completes an export if it is still held by the claim that started it at started_at; a worker
+// whose claim went stale and was taken over updates nothing
+func (q *Queries) CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) (int64, error) {
+	result, err := q.exec(ctx, q.completeDataExportStmt, completeDataExport, arg.ID, arg.StartedAt, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createDataExport = `-- name: CreateDataExport :one
+INSERT INTO data_exports (user_id)
+VALUES ($1)
+RETURNING id, user_id, status, error, created_at, started_at, completed_at, expires_at
+`
+
+func (q *Queries) CreateDataExport(ctx context.Context, userID int32) (DataExport, error) {
+	row := q.queryRow(ctx, q.createDataExportStmt, createDataExport, userID)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const createDataExportArchive = `-- name: CreateDataExportArchive :exec
+INSERT INTO data_export_archives (export_id, content)
+VALUES ($1, $2)
+`
+
+type CreateDataExportArchiveParams struct {
+	ExportID int32  `json:"export_id"`
+	Content  []byte `json:"content"`
+}
+
+func (q *Queries) CreateDataExportArchive(ctx context.Context, arg CreateDataExportArchiveParams) error {
+	_, err := q.exec(ctx, q.createDataExportArchiveStmt, createDataExportArchive, arg.ExportID, arg.Content)
+	return err
+}
+
+const deleteDataExportsByUser = `-- name: DeleteDataExportsByUser :exec
+DELETE FROM data_exports
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteDataExportsByUser(ctx context.Context, userID int32) error {
+	_, err := q.exec(ctx, q.deleteDataExportsByUserStmt, deleteDataExportsByUser, userID)
+	return err
+}
+
+const deleteExpiredDataExports = `-- name: DeleteExpiredDataExports :execrows
+DELETE FROM data_exports
+WHERE status = 'completed' AND expires_at < now()
+`
+
+// Deletes completed exports past their download window, with their archives
+func (q *Queries) DeleteExpiredDataExports(ctx context.Context) (int64, error) {
+	result, err := q.exec(ctx, q.deleteExpiredDataExportsStmt, deleteExpiredDataExports)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLoginThrottleForEmail = `-- name: DeleteLoginThrottleForEmail :exec
+DELETE FROM login_throttle
+WHERE key = 'account:' || lower($1::text)
+`
+
+func (q *Queries) DeleteLoginThrottleForEmail(ctx context.Context, email string) error {
+	_, err := q.exec(ctx, q.deleteLoginThrottleForEmailStmt, deleteLoginThrottleForEmail, email)
+	return err
+}
+
+const deleteUserPersonalData = `-- name: DeleteUserPersonalData :exec
+WITH tokens AS (
+    DELETE FROM user_tokens WHERE user_tokens.user_id = $1
+), action_tokens AS (
+    DELETE FROM user_action_tokens WHERE user_action_tokens.user_id = $1
+), totp AS (
+    DELETE FROM user_totp WHERE user_totp.user_id = $1
+), recovery AS (
+    DELETE FROM user_recovery_codes WHERE user_recovery_codes.user_id = $1
+), identities AS (
+    DELETE FROM user_identities WHERE user_identities.user_id = $1
+), keys AS (
+    DELETE FROM api_keys WHERE api_keys.user_id = $1
+), events AS (
+    DELETE FROM security_events WHERE security_events.user_id = $1
+)
+DELETE FROM user_team WHERE user_team.user_id = $1
+`
+
+func (q *Queries) DeleteUserPersonalData(ctx context.Context, userID int32) error {
+	_, err := q.exec(ctx, q.deleteUserPersonalDataStmt, deleteUserPersonalData, userID)
+	return err
+}
+
+const failDataExport = `-- name: FailDataExport :exec
+UPDATE data_exports
+SET status = 'failed', error = $3, completed_at = now()
+WHERE id = $1 AND status = 'running' AND started_at = $2
+`
+
+type FailDataExportParams struct {
+	ID        int32          `json:"id"`
+	StartedAt sql.NullTime   `json:"started_at"`
+	Error     sql.NullString `json:"error"`
+}
+
+func (q *Queries) FailDataExport(ctx context.Context, arg FailDataExportParams) error {
+	_, err := q.exec(ctx, q.failDataExportStmt, failDataExport, arg.ID, arg.StartedAt, arg.Error)
+	return err
+}
+
+const getDataExport = `-- name: GetDataExport :one
+SELECT id, user_id, status, error, created_at, started_at, completed_at, expires_at FROM data_exports
+WHERE id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetDataExportParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) GetDataExport(ctx context.Context, arg GetDataExportParams) (DataExport, error) {
+	row := q.queryRow(ctx, q.getDataExportStmt, getDataExport, arg.ID, arg.UserID)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getDataExportArchive = `-- name: GetDataExportArchive :one
+SELECT content FROM data_export_archives
+WHERE export_id = $1
+`
+
+func (q *Queries) GetDataExportArchive(ctx context.Context, exportID int32) ([]byte, error) {
+	row := q.queryRow(ctx, q.getDataExportArchiveStmt, getDataExportArchive, exportID)
+	var content []byte
+	err := row.Scan(&content)
+	return content, err
+}
+
+const listDataExportsByUser = `-- name: ListDataExportsByUser :many
+SELECT id, user_id, status, error, created_at, started_at, completed_at, expires_at FROM data_exports
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListDataExportsByUser(ctx context.Context, userID int32) ([]DataExport, error) {
+	rows, err := q.query(ctx, q.listDataExportsByUserStmt, listDataExportsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DataExport{}
+	for rows.Next() {
+		var i DataExport
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.Error,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDonationsByUser = `-- name: ListDonationsByUser :many
+SELECT id, user_id, cause_id, team_id, amount, donation_type, status, created_at, source, import_id FROM donations
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListDonationsByUser(ctx context.Context, userID sql.NullInt32) ([]Donation, error) {
+	rows, err := q.query(ctx, q.listDonationsByUserStmt, listDonationsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Donation{}
+	for rows.Next() {
+		var i Donation
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CauseID,
+			&i.TeamID,
+			&i.Amount,
+			&i.DonationType,
+			&i.Status,
+			&i.CreatedAt,
+			&i.Source,
+			&i.ImportID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueErasures = `-- name: ListDueErasures :many
+SELECT id FROM users
+WHERE erasure_scheduled_for <= now() AND anonymized_at IS NULL
+ORDER BY erasure_scheduled_for
+`
+
+func (q *Queries) ListDueErasures(ctx context.Context) ([]int32, error) {
+	rows, err := q.query(ctx, q.listDueErasuresStmt, listDueErasures)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLeaderboardEntriesByUser = `-- name: ListLeaderboardEntriesByUser :many
+SELECT l.id AS leaderboard_id, l.name AS leaderboard_name, le.team_id, le.score, le.rank
+FROM leaderboard_entries le
+JOIN leaderboards l ON l.id = le.leaderboard_id
+WHERE le.user_id = $1
+ORDER BY l.id
+`
+
+type ListLeaderboardEntriesByUserRow struct {
+	LeaderboardID   int32          `json:"leaderboard_id"`
+	LeaderboardName string         `json:"leaderboard_name"`
+	TeamID          int32          `json:"team_id"`
+	Score           sql.NullString `json:"score"`
+	Rank            sql.NullInt32  `json:"rank"`
+}
+
+func (q *Queries) ListLeaderboardEntriesByUser(ctx context.Context, userID int32) ([]ListLeaderboardEntriesByUserRow, error) {
+	rows, err := q.query(ctx, q.listLeaderboardEntriesByUserStmt, listLeaderboardEntriesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLeaderboardEntriesByUserRow{}
+	for rows.Next() {
+		var i ListLeaderboardEntriesByUserRow
+		if err := rows.Scan(
+			&i.LeaderboardID,
+			&i.LeaderboardName,
+			&i.TeamID,
+			&i.Score,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamsByUser = `-- name: ListTeamsByUser :many
+SELECT t.id, t.name, ut.role, ut.joined_at
+FROM user_team ut
+JOIN teams t ON t.id = ut.team_id
+WHERE ut.user_id = $1
+ORDER BY t.id
+`
+
+type ListTeamsByUserRow struct {
+	ID       int32     `json:"id"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+func (q *Queries) ListTeamsByUser(ctx context.Context, userID int32) ([]ListTeamsByUserRow, error) {
+	rows, err := q.query(ctx, q.listTeamsByUserStmt, listTeamsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTeamsByUserRow{}
+	for rows.Next() {
+		var i ListTeamsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserTokensByUser = `-- name: ListUserTokensByUser :many
+SELECT id, created_at, expiry FROM user_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+type ListUserTokensByUserRow struct {
+	ID        int32     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Expiry    time.Time `json:"expiry"`
+}
+
+func (q *Queries) ListUserTokensByUser(ctx context.Context, userID sql.NullInt32) ([]ListUserTokensByUserRow, error) {
+	rows, err := q.query(ctx, q.listUserTokensByUserStmt, listUserTokensByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserTokensByUserRow{}
+	for rows.Next() {
+		var i ListUserTokensByUserRow
+		if err := rows.Scan(&i.ID, &i.CreatedAt, &i.Expiry); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const scheduleUserErasure = `-- name: ScheduleUserErasure :one
+UPDATE users
+SET erasure_requested_at = now(), erasure_scheduled_for = $2, version = version + 1
+WHERE id = $1 AND anonymized_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
+`
+
+type ScheduleUserErasureParams struct {
+	ID                  int32        `json:"id"`
+	ErasureScheduledFor sql.NullTime `json:"erasure_scheduled_for"`
+}
+
+func (q *Queries) ScheduleUserErasure(ctx context.Context, arg ScheduleUserErasureParams) (User, error) {
+	row := q.queryRow(ctx, q.scheduleUserErasureStmt, scheduleUserErasure, arg.ID, arg.ErasureScheduledFor)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const scrubAuditLogForUser = `-- name: ScrubAuditLogForUser :exec
+UPDATE audit_log
+SET before = before - '{username,email,first_name,last_name,avatar_url}'::text[],
+    after = after - '{username,email,first_name,last_name,avatar_url}'::text[],
+    diff = diff - '{username,email,first_name,last_name,avatar_url}'::text[],
+    ip = CASE WHEN actor_id = $1::int THEN NULL ELSE ip END
+WHERE (entity_type = 'user' AND entity_id = $1::int::text)
+   OR actor_id = $1::int
+`
+
+func (q *Queries) ScrubAuditLogForUser(ctx context.Context, userID int32) error {
+	_, err := q.exec(ctx, q.scrubAuditLogForUserStmt, scrubAuditLogForUser, userID)
+	return err
+}
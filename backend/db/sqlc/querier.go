@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type Querier interface {
+	AddUserToTeam(ctx context.Context, arg AddUserToTeamParams) (UserTeam, error)
+	AnonymizeUser(ctx context.Context, id int32) (User, error)
+	CancelUserErasure(ctx context.Context, id int32) (User, error)
+	// Takes up to limit dirty buckets, skipping any that an open transaction is still writing to
+	ClaimDirtyRollupBuckets(ctx context.Context, limit int32) ([]time.Time, error)
+	// Claims the oldest pending export, or a running one whose worker has not finished it within
+	// stale_seconds and is taken to have stopped
+	ClaimPendingDataExport(ctx context.Context, staleSeconds float64) (DataExport, error)
+	// Claims the oldest pending report, or a running one whose worker has not finished it within
+	// stale_seconds and is taken to have stopped
+	ClaimPendingReportExport(ctx context.Context, staleSeconds float64) (ReportExport, error)
+	ClearCauseOwner(ctx context.Context, ownerID sql.NullInt32) error
+	// Completes an export if it is still held by the claim that started it at started_at; a worker
+	// whose claim went stale and was taken over updates nothing
+	CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) (int64, error)
+	CompleteDonationImport(ctx context.Context, id int32) (DonationImport, error)
+	// Completes a report if it is still held by the claim that started it at started_at
+	CompleteReportExport(ctx context.Context, arg CompleteReportExportParams) (int64, error)
+	ConsumeRecoveryCode(ctx context.Context, arg ConsumeRecoveryCodeParams) (int64, error)
+	ConsumeUserActionToken(ctx context.Context, arg ConsumeUserActionTokenParams) (UserActionToken, error)
+	CountDirtyRollupBuckets(ctx context.Context, arg CountDirtyRollupBucketsParams) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int32) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditLogEntry(ctx context.Context, arg CreateAuditLogEntryParams) (AuditLog, error)
+	CreateCause(ctx context.Context, arg CreateCauseParams) (Cause, error)
+	CreateDataExport(ctx context.Context, userID int32) (DataExport, error)
+	CreateDataExportArchive(ctx context.Context, arg CreateDataExportArchiveParams) error
+	CreateDonation(ctx context.Context, arg CreateDonationParams) (Donation, error)
+	CreateDonationImport(ctx context.Context, arg CreateDonationImportParams) (DonationImport, error)
+	// Stages a batch's valid rows. The arrays are parallel, one element per row; a team ID of 0 and an
+	// empty date mean none.
+	CreateDonationImportRows(ctx context.Context, arg CreateDonationImportRowsParams) (int64, error)
+	CreateLeaderboard(ctx context.Context, arg CreateLeaderboardParams) (Leaderboard, error)
+	CreateReceipt(ctx context.Context, arg CreateReceiptParams) (Receipt, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateReportExport(ctx context.Context, arg CreateReportExportParams) (ReportExport, error)
+	CreateReportExportFile(ctx context.Context, arg CreateReportExportFileParams) error
+	CreateSecurityEvent(ctx context.Context, arg CreateSecurityEventParams) error
+	// Like CreateDonation but backdated, so generated data spreads over time
+	CreateSeedDonation(ctx context.Context, arg CreateSeedDonationParams) (Donation, error)
+	CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserActionToken(ctx context.Context, arg CreateUserActionTokenParams) (UserActionToken, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	CreateUserToken(ctx context.Context, arg CreateUserTokenParams) (UserToken, error)
+	DeleteCause(ctx context.Context, id int32) (Cause, error)
+	DeleteDataExportsByUser(ctx context.Context, userID int32) error
+	// Drops a batch's staged rows once it is completed or rolled back; nothing reads them afterwards
+	DeleteDonationImportRows(ctx context.Context, importID int32) error
+	DeleteDonationRollups(ctx context.Context, buckets []time.Time) error
+	// Deletes completed exports past their download window, with their archives
+	DeleteExpiredDataExports(ctx context.Context) (int64, error)
+	// Deletes completed reports past their download window, with their files
+	DeleteExpiredReportExports(ctx context.Context) (int64, error)
+	DeleteExpiredTokens(ctx context.Context) (int64, error)
+	DeleteImportedDonations(ctx context.Context, importID sql.NullInt32) (int64, error)
+	DeleteLeaderboardEntriesByLeaderboard(ctx context.Context, leaderboardID int32) error
+	DeleteLeaderboardEntriesByTeam(ctx context.Context, teamID int32) error
+	DeleteLeaderboardEntriesByUser(ctx context.Context, userID int32) error
+	DeleteLoginThrottle(ctx context.Context, key string) error
+	DeleteLoginThrottleForEmail(ctx context.Context, email string) error
+	DeleteRecoveryCodes(ctx context.Context, userID int32) error
+	DeleteTeam(ctx context.Context, id int32) (Team, error)
+	DeleteTeamMembershipsByTeam(ctx context.Context, teamID int32) error
+	DeleteTeamMembershipsByUser(ctx context.Context, userID int32) error
+	DeleteUser(ctx context.Context, id int32) (User, error)
+	DeleteUserPersonalData(ctx context.Context, userID int32) error
+	DeleteUserTOTP(ctx context.Context, userID int32) error
+	DeleteUserToken(ctx context.Context, arg DeleteUserTokenParams) error
+	DeleteUserTokensByUserID(ctx context.Context, userID sql.NullInt32) error
+	// Totals rollups per group, and per period when bucketed. Periods are truncated in time_zone and
+	// returned as local times; weeks start on Monday.
+	DonationAnalytics(ctx context.Context, arg DonationAnalyticsParams) ([]DonationAnalyticsRow, error)
+	// Groups donors by the month of their first donation and totals each cohort's donations per month
+	// since; month_offset 0 is the first month. Months are calendar months in time_zone.
+	DonorCohorts(ctx context.Context, arg DonorCohortsParams) ([]DonorCohortsRow, error)
+	EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (UserTotp, error)
+	// Reads donations for reports one page at a time in ID order; pass the last ID of a page as after_id
+	// to read the next
+	ExportDonations(ctx context.Context, arg ExportDonationsParams) ([]ExportDonationsRow, error)
+	// Totals the matching donations of each donor, one page of donors at a time in ID order
+	ExportDonors(ctx context.Context, arg ExportDonorsParams) ([]ExportDonorsRow, error)
+	// Totals the matching donations made through each team, one page of teams at a time in ID order.
+	// Teams without donations are included with zero totals.
+	ExportTeamStats(ctx context.Context, arg ExportTeamStatsParams) ([]ExportTeamStatsRow, error)
+	FailDataExport(ctx context.Context, arg FailDataExportParams) error
+	FailReportExport(ctx context.Context, arg FailReportExportParams) error
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetCause(ctx context.Context, id int32) (Cause, error)
+	GetDataExport(ctx context.Context, arg GetDataExportParams) (DataExport, error)
+	GetDataExportArchive(ctx context.Context, exportID int32) ([]byte, error)
+	GetDonation(ctx context.Context, id int32) (Donation, error)
+	GetDonationImport(ctx context.Context, id int32) (DonationImport, error)
+	GetLatestDonationReceipt(ctx context.Context, donationID sql.NullInt32) (Receipt, error)
+	GetLatestStatement(ctx context.Context, arg GetLatestStatementParams) (Receipt, error)
+	GetLeaderboard(ctx context.Context, id int32) (Leaderboard, error)
+	GetLeaderboardEntries(ctx context.Context, arg GetLeaderboardEntriesParams) ([]LeaderboardEntry, error)
+	GetLeaderboardEntry(ctx context.Context, arg GetLeaderboardEntryParams) (LeaderboardEntry, error)
+	GetLoginThrottle(ctx context.Context, key string) (LoginThrottle, error)
+	GetReceipt(ctx context.Context, id int32) (Receipt, error)
+	GetReceiptCause(ctx context.Context, id int32) (GetReceiptCauseRow, error)
+	GetReceiptDonor(ctx context.Context, id int32) (GetReceiptDonorRow, error)
+	GetReportExport(ctx context.Context, arg GetReportExportParams) (ReportExport, error)
+	GetReportExportFile(ctx context.Context, exportID int32) ([]byte, error)
+	GetTeam(ctx context.Context, id int32) (Team, error)
+	GetTwoFactorStatus(ctx context.Context, id int32) (GetTwoFactorStatusRow, error)
+	GetUser(ctx context.Context, id int32) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	GetUserIncludingDeleted(ctx context.Context, id int32) (User, error)
+	GetUserTOTP(ctx context.Context, userID int32) (UserTotp, error)
+	GetUserTeam(ctx context.Context, arg GetUserTeamParams) (UserTeam, error)
+	GetUserTokenByToken(ctx context.Context, token string) (UserToken, error)
+	GetUserTokenByUserID(ctx context.Context, userID sql.NullInt32) (UserToken, error)
+	// Imports the next chunk of staged rows in row order and records how far the batch has got, so an
+	// interrupted commit carries on where it stopped
+	ImportDonationChunk(ctx context.Context, arg ImportDonationChunkParams) (DonationImport, error)
+	InvalidateUserActionTokens(ctx context.Context, arg InvalidateUserActionTokensParams) error
+	ListAPIKeysByUser(ctx context.Context, userID int32) ([]ApiKey, error)
+	ListAuditLog(ctx context.Context, arg ListAuditLogParams) ([]AuditLog, error)
+	ListAuditLogByEntity(ctx context.Context, arg ListAuditLogByEntityParams) ([]AuditLog, error)
+	ListCauses(ctx context.Context, arg ListCausesParams) ([]Cause, error)
+	ListDataExportsByUser(ctx context.Context, userID int32) ([]DataExport, error)
+	ListDonationImports(ctx context.Context, arg ListDonationImportsParams) ([]DonationImport, error)
+	ListDonations(ctx context.Context, arg ListDonationsParams) ([]Donation, error)
+	ListDonationsByUser(ctx context.Context, userID sql.NullInt32) ([]Donation, error)
+	// One row per donor whose first donation falls in the cohort range, or every donor without one.
+	// Active months are the calendar months in time_zone with at least one donation, split at the
+	// donor's first team join.
+	ListDonorSummaries(ctx context.Context, arg ListDonorSummariesParams) ([]ListDonorSummariesRow, error)
+	ListDueErasures(ctx context.Context) ([]int32, error)
+	ListExpiredDeletedDonors(ctx context.Context, deletedAt sql.NullTime) ([]int32, error)
+	ListLeaderboardEntriesByUser(ctx context.Context, userID int32) ([]ListLeaderboardEntriesByUserRow, error)
+	ListLeaderboardIDs(ctx context.Context) ([]int32, error)
+	ListLeaderboards(ctx context.Context, arg ListLeaderboardsParams) ([]Leaderboard, error)
+	ListPurgeableCauses(ctx context.Context, deletedAt sql.NullTime) ([]Cause, error)
+	ListPurgeableTeams(ctx context.Context, deletedAt sql.NullTime) ([]Team, error)
+	ListPurgeableUsers(ctx context.Context, deletedAt sql.NullTime) ([]User, error)
+	ListReceiptTemplates(ctx context.Context, causeID sql.NullInt32) ([]ReceiptTemplate, error)
+	ListReceiptsByUser(ctx context.Context, userID int32) ([]ListReceiptsByUserRow, error)
+	ListReportExportsByUser(ctx context.Context, arg ListReportExportsByUserParams) ([]ReportExport, error)
+	ListRolePolicies(ctx context.Context) ([]RolePolicy, error)
+	ListSecurityEventsByUser(ctx context.Context, arg ListSecurityEventsByUserParams) ([]SecurityEvent, error)
+	// Failed, refunded and cancelled donations are left off statements, as in RecomputeCauseTotals
+	ListStatementDonations(ctx context.Context, arg ListStatementDonationsParams) ([]ListStatementDonationsRow, error)
+	ListTeams(ctx context.Context, arg ListTeamsParams) ([]Team, error)
+	ListTeamsByUser(ctx context.Context, userID int32) ([]ListTeamsByUserRow, error)
+	ListUserIdentities(ctx context.Context, userID int32) ([]UserIdentity, error)
+	ListUserTokensByUser(ctx context.Context, userID sql.NullInt32) ([]ListUserTokensByUserRow, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	LockDonation(ctx context.Context, id int32) (Donation, error)
+	// Commits and rollbacks of one batch take turns
+	LockDonationImport(ctx context.Context, id int32) (DonationImport, error)
+	LockStatement(ctx context.Context, arg LockStatementParams) error
+	MarkUserEmailVerified(ctx context.Context, id int32) (User, error)
+	NextReceiptNumber(ctx context.Context, arg NextReceiptNumberParams) (int32, error)
+	PatchCause(ctx context.Context, arg PatchCauseParams) (Cause, error)
+	PatchLeaderboard(ctx context.Context, arg PatchLeaderboardParams) (Leaderboard, error)
+	PatchTeam(ctx context.Context, arg PatchTeamParams) (Team, error)
+	// Partial updates for JSON merge patches: each column changes only when its set_ flag is true
+	PatchUser(ctx context.Context, arg PatchUserParams) (User, error)
+	PurgeCause(ctx context.Context, id int32) (int64, error)
+	PurgeTeam(ctx context.Context, id int32) (int64, error)
+	PurgeUser(ctx context.Context, id int32) (int64, error)
+	RebuildDonationRollups(ctx context.Context, buckets []time.Time) (int64, error)
+	// Scores are the donations made inside the leaderboard's dates by a donor for a team. Individual
+	// boards rank each donor and team pair; team boards give every member the team's total and rank.
+	RebuildLeaderboardEntries(ctx context.Context, id int32) (int64, error)
+	// Failed, refunded and cancelled donations do not count towards a cause
+	RecomputeCauseTotals(ctx context.Context) ([]Cause, error)
+	ReleaseLoginAttempt(ctx context.Context, key string) error
+	RemoveUserFromTeam(ctx context.Context, arg RemoveUserFromTeamParams) error
+	RestoreCause(ctx context.Context, id int32) (Cause, error)
+	RestoreTeam(ctx context.Context, id int32) (Team, error)
+	RestoreUser(ctx context.Context, id int32) (User, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error)
+	RollBackDonationImport(ctx context.Context, id int32) (DonationImport, error)
+	SaveCauseReceiptTemplate(ctx context.Context, arg SaveCauseReceiptTemplateParams) (ReceiptTemplate, error)
+	SaveOrganizationReceiptTemplate(ctx context.Context, arg SaveOrganizationReceiptTemplateParams) (ReceiptTemplate, error)
+	ScheduleUserErasure(ctx context.Context, arg ScheduleUserErasureParams) (User, error)
+	ScrubAuditLogForUser(ctx context.Context, userID int32) error
+	SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error)
+	// Counts and sums, by donation type, the rows ImportDonationChunk will import next
+	SummarizeDonationImportChunk(ctx context.Context, arg SummarizeDonationImportChunkParams) ([]SummarizeDonationImportChunkRow, error)
+	// Counts an attempt in the same statement that checks it is allowed, so concurrent attempts cannot
+	// all pass the check first. Nothing is returned while the key is locked or still backing off: the
+	// delay after a failure doubles from base_delay_seconds for each failure beyond free_attempts, up
+	// to max_delay_seconds. The attempt that reaches lockout_after locks the key for lockout_seconds.
+	TakeLoginAttempt(ctx context.Context, arg TakeLoginAttemptParams) (LoginThrottle, error)
+	TouchAPIKey(ctx context.Context, id int32) error
+	TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error
+	UpdateCause(ctx context.Context, arg UpdateCauseParams) (Cause, error)
+	UpdateDonationStatus(ctx context.Context, arg UpdateDonationStatusParams) (Donation, error)
+	UpdateLeaderboardEntry(ctx context.Context, arg UpdateLeaderboardEntryParams) (LeaderboardEntry, error)
+	UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (int64, error)
+	UpdateUserTeamRole(ctx context.Context, arg UpdateUserTeamRoleParams) (UserTeam, error)
+	UpsertRolePolicy(ctx context.Context, arg UpsertRolePolicyParams) (RolePolicy, error)
+	UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTotp, error)
+	VoidImportedDonationReceipts(ctx context.Context, arg VoidImportedDonationReceiptsParams) (int64, error)
+	VoidReceipt(ctx context.Context, arg VoidReceiptParams) (Receipt, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,582 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: receipts.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createReceipt = `-- name: CreateReceipt :one
+INSERT INTO receipts (number, kind, user_id, donation_id, year, amount, donation_count, pdf, sha256, replaces, issued_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, number, kind, user_id, donation_id, year, amount, donation_count, pdf, sha256, replaces, issued_at, voided_at, void_reason
+`
+
+type CreateReceiptParams struct {
+	Number        string        `json:"number"`
+	Kind          string        `json:"kind"`
+	UserID        int32         `json:"user_id"`
+	DonationID    sql.NullInt32 `json:"donation_id"`
+	Year          int32         `json:"year"`
+	Amount        string        `json:"amount"`
+	DonationCount int32         `json:"donation_count"`
+	Pdf           []byte        `json:"pdf"`
+	Sha256        string        `json:"sha256"`
+	Replaces      sql.NullInt32 `json:"replaces"`
+	IssuedAt      time.Time     `json:"issued_at"`
+}
+
+func (q *Queries) CreateReceipt(ctx context.Context, arg CreateReceiptParams) (Receipt, error) {
+	row := q.queryRow(ctx, q.createReceiptStmt, createReceipt,
+		arg.Number,
+		arg.Kind,
+		arg.UserID,
+		arg.DonationID,
+		arg.Year,
+		arg.Amount,
+		arg.DonationCount,
+		arg.Pdf,
+		arg.Sha256,
+		arg.Replaces,
+		arg.IssuedAt,
+	)
+	var i Receipt
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.UserID,
+		&i.DonationID,
+		&i.Year,
+		&i.Amount,
+		&i.DonationCount,
+		&i.Pdf,
+		&i.Sha256,
+		&i.Replaces,
+		&i.IssuedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
+	)
+	return i, err
+}
+
+const getLatestDonationReceipt = `-- name: GetLatestDonationReceipt :one
+SELECT id, number, kind, user_id, donation_id, year, amount, donation_count, pdf, sha256, replaces, issued_at, voided_at, void_reason FROM receipts
+WHERE donation_id = $1 AND kind = 'donation'
+ORDER BY id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestDonationReceipt(ctx context.Context, donationID sql.NullInt32) (Receipt, error) {
+	row := q.queryRow(ctx, q.getLatestDonationReceiptStmt, getLatestDonationReceipt, donationID)
+	var i Receipt
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.UserID,
+		&i.DonationID,
+		&i.Year,
+		&i.Amount,
+		&i.DonationCount,
+		&i.Pdf,
+		&i.Sha256,
+		&i.Replaces,
+		&i.IssuedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
+	)
+	return i, err
+}
+
+const getLatestStatement = `-- name: GetLatestStatement :one
+SELECT id, number, kind, user_id, donation_id, year, amount, donation_count, pdf, sha256, replaces, issued_at, voided_at, void_reason FROM receipts
+WHERE user_id = $1 AND year = $2 AND kind = 'statement'
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetLatestStatementParams struct {
+	UserID int32 `json:"user_id"`
+	Year   int32 `json:"year"`
+}
+
+func (q *Queries) GetLatestStatement(ctx context.Context, arg GetLatestStatementParams) (Receipt, error) {
+	row := q.queryRow(ctx, q.getLatestStatementStmt, getLatestStatement, arg.UserID, arg.Year)
+	var i Receipt
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.UserID,
+		&i.DonationID,
+		&i.Year,
+		&i.Amount,
+		&i.DonationCount,
+		&i.Pdf,
+		&i.Sha256,
+		&i.Replaces,
+		&i.IssuedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
+	)
+	return i, err
+}
+
+const getReceipt = `-- name: GetReceipt :one
+SELECT id, number, kind, user_id, donation_id, year, amount, donation_count, pdf, sha256, replaces, issued_at, voided_at, void_reason FROM receipts
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetReceipt(ctx context.Context, id int32) (Receipt, error) {
+	row := q.queryRow(ctx, q.getReceiptStmt, getReceipt, id)
+	var i Receipt
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.UserID,
+		&i.DonationID,
+		&i.Year,
+		&i.Amount,
+		&i.DonationCount,
+		&i.Pdf,
+		&i.Sha256,
+		&i.Replaces,
+		&i.IssuedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
+	)
+	return i, err
+}
+
+const getReceiptCause = `-- name: GetReceiptCause :one
+SELECT id, name, owner_id FROM causes
+WHERE id = $1 LIMIT 1
+`
+
+type GetReceiptCauseRow struct {
+	ID      int32         `json:"id"`
+	Name    string        `json:"name"`
+	OwnerID sql.NullInt32 `json:"owner_id"`
+}
+
+func (q *Queries) GetReceiptCause(ctx context.Context, id int32) (GetReceiptCauseRow, error) {
+	row := q.queryRow(ctx, q.getReceiptCauseStmt, getReceiptCause, id)
+	var i GetReceiptCauseRow
+	err := row.Scan(&i.ID, &i.Name, &i.OwnerID)
+	return i, err
+}
+
+const getReceiptDonor = `-- name: GetReceiptDonor :one
+SELECT id, username, email, first_name, last_name FROM users
+WHERE id = $1 LIMIT 1
+`
+
+type GetReceiptDonorRow struct {
+	ID        int32          `json:"id"`
+	Username  string         `json:"username"`
+	Email     string         `json:"email"`
+	FirstName sql.NullString `json:"first_name"`
+	LastName  sql.NullString `json:"last_name"`
+}
+
+func (q *Queries) GetReceiptDonor(ctx context.Context, id int32) (GetReceiptDonorRow, error) {
+	row := q.queryRow(ctx, q.getReceiptDonorStmt, getReceiptDonor, id)
+	var i GetReceiptDonorRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+	)
+	return i, err
+}
+
+const listReceiptTemplates = `-- name: ListReceiptTemplates :many
+SELECT id, cause_id, organization_name, address, registration_number, contact_email, accent_color, header_text, footer_text, signatory, updated_at FROM receipt_templates
+WHERE cause_id IS NULL OR cause_id = $1
+ORDER BY cause_id NULLS FIRST
+`
+
+func (q *Queries) ListReceiptTemplates(ctx context.Context, causeID sql.NullInt32) ([]ReceiptTemplate, error) {
+	rows, err := q.query(ctx, q.listReceiptTemplatesStmt, listReceiptTemplates, causeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ReceiptTemplate{}
+	for rows.Next() {
+		var i ReceiptTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.CauseID,
+			&i.OrganizationName,
+			&i.Address,
+			&i.RegistrationNumber,
+			&i.ContactEmail,
+			&i.AccentColor,
+			&i.HeaderText,
+			&i.FooterText,
+			&i.Signatory,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReceiptsByUser = `-- name: ListReceiptsByUser :many
+SELECT id, number, kind, user_id, donation_id, year, amount, donation_count, sha256, replaces, issued_at, voided_at, void_reason
+FROM receipts
+WHERE user_id = $1
+ORDER BY issued_at DESC, id DESC
+`
+
+type ListReceiptsByUserRow struct {
+	ID            int32          `json:"id"`
+	Number        string         `json:"number"`
+	Kind          string         `json:"kind"`
+	UserID        int32          `json:"user_id"`
+	DonationID    sql.NullInt32  `json:"donation_id"`
+	Year          int32          `json:"year"`
+	Amount        string         `json:"amount"`
+	DonationCount int32          `json:"donation_count"`
+	Sha256        string         `json:"sha256"`
+	Replaces      sql.NullInt32  `json:"replaces"`
+	IssuedAt      time.Time      `json:"issued_at"`
+	VoidedAt      sql.NullTime   `json:"voided_at"`
+	VoidReason    sql.NullString `json:"void_reason"`
+}
+
+func (q *Queries) ListReceiptsByUser(ctx context.Context, userID int32) ([]ListReceiptsByUserRow, error) {
+	rows, err := q.query(ctx, q.listReceiptsByUserStmt, listReceiptsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListReceiptsByUserRow{}
+	for rows.Next() {
+		var i ListReceiptsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Number,
+			&i.Kind,
+			&i.UserID,
+			&i.DonationID,
+			&i.Year,
+			&i.Amount,
+			&i.DonationCount,
+			&i.Sha256,
+			&i.Replaces,
+			&i.IssuedAt,
+			&i.VoidedAt,
+			&i.VoidReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementDonations = `-- name: ListStatementDonations :many
+SELECT d.id, d.cause_id, d.amount, d.donation_type, d.created_at, COALESCE(c.name, '')::text AS cause_name
+FROM donations d
+LEFT JOIN causes c ON c.id = d.cause_id
+WHERE d.user_id = $1::int
+AND d.amount IS NOT NULL
+AND (d.status IS NULL OR d.status NOT IN ('failed', 'refunded', 'cancelled'))
+AND d.created_at >= $2::timestamp AND d.created_at < $3::timestamp
+ORDER BY d.created_at, d.id
+`
+
+type ListStatementDonationsParams struct {
+	UserID    int32     `json:"user_id"`
+	YearStart time.Time `json:"year_start"`
+	YearEnd   time.Time `json:"year_end"`
+}
+
+type ListStatementDonationsRow struct {
+	ID           int32          `json:"id"`
+	CauseID      sql.NullInt32  `json:"cause_id"`
+	Amount       sql.NullString `json:"amount"`
+	DonationType sql.NullString `json:"donation_type"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+	CauseName    string         `json:"cause_name"`
+}
+
+// Failed, refunded and cancelled donations are left off statements, as in RecomputeCauseTotals
+func (q *Queries) ListStatementDonations(ctx context.Context, arg ListStatementDonationsParams) ([]ListStatementDonationsRow, error) {
+	rows, err := q.query(ctx, q.listStatementDonationsStmt, listStatementDonations, arg.UserID, arg.YearStart, arg.YearEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementDonationsRow{}
+	for rows.Next() {
+		var i ListStatementDonationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CauseID,
+			&i.Amount,
+			&i.DonationType,
+			&i.CreatedAt,
+			&i.CauseName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDonation = `-- name: LockDonation :one
+SELECT id, user_id, cause_id, team_id, amount, donation_type, status, created_at, source, import_id FROM donations
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockDonation(ctx context.Context, id int32) (Donation, error) {
+	row := q.queryRow(ctx, q.lockDonationStmt, lockDonation, id)
+	var i Donation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CauseID,
+		&i.TeamID,
+		&i.Amount,
+		&i.DonationType,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Source,
+		&i.ImportID,
+	)
+	return i, err
+}
+
+const lockStatement = `-- name: LockStatement :exec
+SELECT pg_advisory_xact_lock($1::int, $2::int)
+`
+
+type LockStatementParams struct {
+	UserID int32 `json:"user_id"`
+	Year   int32 `json:"year"`
+}
+
+func (q *Queries) LockStatement(ctx context.Context, arg LockStatementParams) error {
+	_, err := q.exec(ctx, q.lockStatementStmt, lockStatement, arg.UserID, arg.Year)
+	return err
+}
+
+const nextReceiptNumber = `-- name: NextReceiptNumber :one
+INSERT INTO receipt_counters (series, year, last_number)
+VALUES ($1, $2, 1)
+ON CONFLICT (series, year) DO UPDATE
+SET last_number = receipt_counters.last_number + 1
+RETURNING last_number
+`
+
+type NextReceiptNumberParams struct {
+	Series string `json:"series"`
+	Year   int32  `json:"year"`
+}
+
+func (q *Queries) NextReceiptNumber(ctx context.Context, arg NextReceiptNumberParams) (int32, error) {
+	row := q.queryRow(ctx, q.nextReceiptNumberStmt, nextReceiptNumber, arg.Series, arg.Year)
+	var last_number int32
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
+const saveCauseReceiptTemplate = `-- name: SaveCauseReceiptTemplate :one
+INSERT INTO receipt_templates (cause_id, organization_name, address, registration_number, contact_email, accent_color, header_text, footer_text, signatory)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (cause_id) DO UPDATE
+SET organization_name = EXCLUDED.organization_name,
+    address = EXCLUDED.address,
+    registration_number = EXCLUDED.registration_number,
+    contact_email = EXCLUDED.contact_email,
+    accent_color = EXCLUDED.accent_color,
+    header_text = EXCLUDED.header_text,
+    footer_text = EXCLUDED.footer_text,
+    signatory = EXCLUDED.signatory,
+    updated_at = now()
+RETURNING id, cause_id, organization_name, address, registration_number, contact_email, accent_color, header_text, footer_text, signatory, updated_at
+`
+
+type SaveCauseReceiptTemplateParams struct {
+	CauseID            sql.NullInt32 `json:"cause_id"`
+	OrganizationName   string        `json:"organization_name"`
+	Address            string        `json:"address"`
+	RegistrationNumber string        `json:"registration_number"`
+	ContactEmail       string        `json:"contact_email"`
+	AccentColor        string        `json:"accent_color"`
+	HeaderText         string        `json:"header_text"`
+	FooterText         string        `json:"footer_text"`
+	Signatory          string        `json:"signatory"`
+}
+
+func (q *Queries) SaveCauseReceiptTemplate(ctx context.Context, arg SaveCauseReceiptTemplateParams) (ReceiptTemplate, error) {
+	row := q.queryRow(ctx, q.saveCauseReceiptTemplateStmt, saveCauseReceiptTemplate,
+		arg.CauseID,
+		arg.OrganizationName,
+		arg.Address,
+		arg.RegistrationNumber,
+		arg.ContactEmail,
+		arg.AccentColor,
+		arg.HeaderText,
+		arg.FooterText,
+		arg.Signatory,
+	)
+	var i ReceiptTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.CauseID,
+		&i.OrganizationName,
+		&i.Address,
+		&i.RegistrationNumber,
+		&i.ContactEmail,
+		&i.AccentColor,
+		&i.HeaderText,
+		&i.FooterText,
+		&i.Signatory,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const saveOrganizationReceiptTemplate = `-- name: SaveOrganizationReceiptTemplate :one
+INSERT INTO receipt_templates (cause_id, organization_name, address, registration_number, contact_email, accent_color, header_text, footer_text, signatory)
+VALUES (NULL, $1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT ((cause_id IS NULL)) WHERE cause_id IS NULL DO UPDATE
+SET organization_name = EXCLUDED.organization_name,
+    address = EXCLUDED.address,
+    registration_number = EXCLUDED.registration_number,
+    contact_email = EXCLUDED.contact_email,
+    accent_color = EXCLUDED.accent_color,
+    header_text = EXCLUDED.header_text,
+    footer_text = EXCLUDED.footer_text,
+    signatory = EXCLUDED.signatory,
+    updated_at = now()
+RETURNING id, cause_id, organization_name, address, registration_number, contact_email, accent_color, header_text, footer_text, signatory, updated_at
+`
+
+type SaveOrganizationReceiptTemplateParams struct {
+	OrganizationName   string `json:"organization_name"`
+	Address            string `json:"address"`
+	RegistrationNumber string `json:"registration_number"`
+	ContactEmail       string `json:"contact_email"`
+	AccentColor        string `json:"accent_color"`
+	HeaderText         string `json:"header_text"`
+	FooterText         string `json:"footer_text"`
+	Signatory          string `json:"signatory"`
+}
+
+func (q *Queries) SaveOrganizationReceiptTemplate(ctx context.Context, arg SaveOrganizationReceiptTemplateParams) (ReceiptTemplate, error) {
+	row := q.queryRow(ctx, q.saveOrganizationReceiptTemplateStmt, saveOrganizationReceiptTemplate,
+		arg.OrganizationName,
+		arg.Address,
+		arg.RegistrationNumber,
+		arg.ContactEmail,
+		arg.AccentColor,
+		arg.HeaderText,
+		arg.FooterText,
+		arg.Signatory,
+	)
+	var i ReceiptTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.CauseID,
+		&i.OrganizationName,
+		&i.Address,
+		&i.RegistrationNumber,
+		&i.ContactEmail,
+		&i.AccentColor,
+		&i.HeaderText,
+		&i.FooterText,
+		&i.Signatory,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const voidImportedDonationReceipts = `-- name: VoidImportedDonationReceipts :execrows
+UPDATE receipts
+SET voided_at = now(), void_reason = $2
+WHERE kind = 'donation' AND voided_at IS NULL
+AND donation_id IN (SELECT d.id FROM donations d WHERE d.import_id = $1)
+`
+
+type VoidImportedDonationReceiptsParams struct {
+	ImportID   sql.NullInt32  `json:"import_id"`
+	VoidReason sql.NullString `json:"void_reason"`
+}
+
+func (q *Queries) VoidImportedDonationReceipts(ctx context.Context, arg VoidImportedDonationReceiptsParams) (int64, error) {
+	result, err := q.exec(ctx, q.voidImportedDonationReceiptsStmt, voidImportedDonationReceipts, arg.ImportID, arg.VoidReason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const voidReceipt = `-- name: VoidReceipt :one
+UPDATE receipts
+SET voided_at = now(), void_reason = $2
+WHERE id = $1 AND voided_at IS NULL
+RETURNING id, number, kind, user_id, donation_id, year, amount, donation_count, pdf, sha256, replaces, issued_at, voided_at, void_reason
+`
+
+type VoidReceiptParams struct {
+	ID         int32          `json:"id"`
+	VoidReason sql.NullString `json:"void_reason"`
+}
+
+func (q *Queries) VoidReceipt(ctx context.Context, arg VoidReceiptParams) (Receipt, error) {
+	row := q.queryRow(ctx, q.voidReceiptStmt, voidReceipt, arg.ID, arg.VoidReason)
+	var i Receipt
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.UserID,
+		&i.DonationID,
+		&i.Year,
+		&i.Amount,
+		&i.DonationCount,
+		&i.Pdf,
+		&i.Sha256,
+		&i.Replaces,
+		&i.IssuedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
+	)
+	return i, err
+}
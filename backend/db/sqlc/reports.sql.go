@@ -0,0 +1,515 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: reports.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimPendingReportExport = `-- name: ClaimPendingReportExport :one
+UPDATE report_exports
+SET status = 'running', started_at = now()
+WHERE id = (
+    SELECT id FROM report_exports
+    WHERE status = 'pending' OR (status = 'running' AND started_at < now() - make_interval(secs => $1::float8))
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, requested_by, dataset, format, columns, filters, status, row_count, error, created_at, started_at, completed_at, expires_at
+`
+
+// Claims the oldest pending report, or a running one whose worker has not finished it within
+// stale_seconds and is taken to have stopped
+func (q *Queries) ClaimPendingReportExport(ctx context.Context, staleSeconds float64) (ReportExport, error) {
+	row := q.queryRow(ctx, q.claimPendingReportExportStmt, claimPendingReportExport, staleSeconds)
+	var i ReportExport
+	err := row.Scan(
+		&i.ID,
+		&i.RequestedBy,
+		&i.Dataset,
+		&i.Format,
+		pq.Array(&i.Columns),
+		&i.Filters,
+		&i.Status,
+		&i.RowCount,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const completeReportExport = `-- name: CompleteReportExport :execrows
+UPDATE report_exports
+SET status = 'completed', row_count = $3, completed_at = now(), expires_at = $4
+WHERE id = $1 AND status = 'running' AND started_at = $2
+`
+
+type CompleteReportExportParams struct {
+	ID        int32        `json:"id"`
+	StartedAt sql.NullTime `json:"started_at"`
+	RowCount  int32        `json:"row_count"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
+}
+
+// Completes a report if it is still held by the claim that started it at started_at
+func (q *Queries) CompleteReportExport(ctx context.Context, arg CompleteReportExportParams) (int64, error) {
+	result, err := q.exec(ctx, q.completeReportExportStmt, completeReportExport,
+		arg.ID,
+		arg.StartedAt,
+		arg.RowCount,
+		arg.ExpiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createReportExport = `-- name: CreateReportExport :one
+INSERT INTO report_exports (requested_by, dataset, format, columns, filters)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, requested_by, dataset, format, columns, filters, status, row_count, error, created_at, started_at, completed_at, expires_at
+`
+
+type CreateReportExportParams struct {
+	RequestedBy int32           `json:"requested_by"`
+	Dataset     string          `json:"dataset"`
+	Format      string          `json:"format"`
+	Columns     []string        `json:"columns"`
+	Filters     json.RawMessage `json:"filters"`
+}
+
+func (q *Queries) CreateReportExport(ctx context.Context, arg CreateReportExportParams) (ReportExport, error) {
+	row := q.queryRow(ctx, q.createReportExportStmt, createReportExport,
+		arg.RequestedBy,
+		arg.Dataset,
+		arg.Format,
+		pq.Array(arg.Columns),
+		arg.Filters,
+	)
+	var i ReportExport
+	err := row.Scan(
+		&i.ID,
+		&i.RequestedBy,
+		&i.Dataset,
+		&i.Format,
+		pq.Array(&i.Columns),
+		&i.Filters,
+		&i.Status,
+		&i.RowCount,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const createReportExportFile = `-- name: CreateReportExportFile :exec
+INSERT INTO report_export_files (export_id, content)
+VALUES ($1, $2)
+`
+
+type CreateReportExportFileParams struct {
+	ExportID int32  `json:"export_id"`
+	Content  []byte `json:"content"`
+}
+
+func (q *Queries) CreateReportExportFile(ctx context.Context, arg CreateReportExportFileParams) error {
+	_, err := q.exec(ctx, q.createReportExportFileStmt, createReportExportFile, arg.ExportID, arg.Content)
+	return err
+}
+
+const deleteExpiredReportExports = `-- name: DeleteExpiredReportExports :execrows
+DELETE FROM report_exports
+WHERE status = 'completed' AND expires_at < now()
+`
+
+// Deletes completed reports past their download window, with their files
+func (q *Queries) DeleteExpiredReportExports(ctx context.Context) (int64, error) {
+	result, err := q.exec(ctx, q.deleteExpiredReportExportsStmt, deleteExpiredReportExports)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const exportDonations = `-- name: ExportDonations :many
+SELECT d.id, d.created_at, d.amount, d.donation_type, d.status, d.source, d.import_id,
+    d.user_id, u.username AS donor_username, u.email AS donor_email,
+    d.cause_id, c.name AS cause_name,
+    d.team_id, t.name AS team_name
+FROM donations d
+LEFT JOIN users u ON u.id = d.user_id
+LEFT JOIN causes c ON c.id = d.cause_id
+LEFT JOIN teams t ON t.id = d.team_id
+WHERE d.id > $1::int
+AND ($2::timestamp IS NULL OR d.created_at >= $2)
+AND ($3::timestamp IS NULL OR d.created_at < $3)
+AND ($4::int IS NULL OR d.cause_id = $4)
+AND ($5::int IS NULL OR d.team_id = $5)
+AND ($6::text IS NULL OR d.status = $6)
+ORDER BY d.id
+LIMIT $7
+`
+
+type ExportDonationsParams struct {
+	AfterID     int32          `json:"after_id"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	CauseID     sql.NullInt32  `json:"cause_id"`
+	TeamID      sql.NullInt32  `json:"team_id"`
+	Status      sql.NullString `json:"status"`
+	Limit       int32          `json:"limit"`
+}
+
+type ExportDonationsRow struct {
+	ID            int32          `json:"id"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	Amount        sql.NullString `json:"amount"`
+	DonationType  sql.NullString `json:"donation_type"`
+	Status        sql.NullString `json:"status"`
+	Source        sql.NullString `json:"source"`
+	ImportID      sql.NullInt32  `json:"import_id"`
+	UserID        sql.NullInt32  `json:"user_id"`
+	DonorUsername sql.NullString `json:"donor_username"`
+	DonorEmail    sql.NullString `json:"donor_email"`
+	CauseID       sql.NullInt32  `json:"cause_id"`
+	CauseName     sql.NullString `json:"cause_name"`
+	TeamID        sql.NullInt32  `json:"team_id"`
+	TeamName      sql.NullString `json:"team_name"`
+}
+
+// Reads donations for reports one page at a time in ID order; pass the last ID of a page as after_id
+// to read the next
+func (q *Queries) ExportDonations(ctx context.Context, arg ExportDonationsParams) ([]ExportDonationsRow, error) {
+	rows, err := q.query(ctx, q.exportDonationsStmt, exportDonations,
+		arg.AfterID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CauseID,
+		arg.TeamID,
+		arg.Status,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportDonationsRow{}
+	for rows.Next() {
+		var i ExportDonationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Amount,
+			&i.DonationType,
+			&i.Status,
+			&i.Source,
+			&i.ImportID,
+			&i.UserID,
+			&i.DonorUsername,
+			&i.DonorEmail,
+			&i.CauseID,
+			&i.CauseName,
+			&i.TeamID,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportDonors = `-- name: ExportDonors :many
+SELECT u.id, u.username, u.email, u.first_name, u.last_name,
+    COUNT(d.id) AS donation_count,
+    COALESCE(SUM(d.amount), 0)::numeric AS total_amount,
+    MIN(d.created_at)::timestamp AS first_donation_at,
+    MAX(d.created_at)::timestamp AS last_donation_at
+FROM users u
+JOIN donations d ON d.user_id = u.id
+WHERE u.id > $1::int
+AND ($2::timestamp IS NULL OR d.created_at >= $2)
+AND ($3::timestamp IS NULL OR d.created_at < $3)
+AND ($4::int IS NULL OR d.cause_id = $4)
+AND ($5::int IS NULL OR d.team_id = $5)
+AND ($6::text IS NULL OR d.status = $6)
+GROUP BY u.id
+ORDER BY u.id
+LIMIT $7
+`
+
+type ExportDonorsParams struct {
+	AfterID     int32          `json:"after_id"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	CauseID     sql.NullInt32  `json:"cause_id"`
+	TeamID      sql.NullInt32  `json:"team_id"`
+	Status      sql.NullString `json:"status"`
+	Limit       int32          `json:"limit"`
+}
+
+type ExportDonorsRow struct {
+	ID              int32          `json:"id"`
+	Username        string         `json:"username"`
+	Email           string         `json:"email"`
+	FirstName       sql.NullString `json:"first_name"`
+	LastName        sql.NullString `json:"last_name"`
+	DonationCount   int64          `json:"donation_count"`
+	TotalAmount     string         `json:"total_amount"`
+	FirstDonationAt time.Time      `json:"first_donation_at"`
+	LastDonationAt  time.Time      `json:"last_donation_at"`
+}
+
+// Totals the matching donations of each donor, one page of donors at a time in ID order
+func (q *Queries) ExportDonors(ctx context.Context, arg ExportDonorsParams) ([]ExportDonorsRow, error) {
+	rows, err := q.query(ctx, q.exportDonorsStmt, exportDonors,
+		arg.AfterID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CauseID,
+		arg.TeamID,
+		arg.Status,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportDonorsRow{}
+	for rows.Next() {
+		var i ExportDonorsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.FirstName,
+			&i.LastName,
+			&i.DonationCount,
+			&i.TotalAmount,
+			&i.FirstDonationAt,
+			&i.LastDonationAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportTeamStats = `-- name: ExportTeamStats :many
+SELECT t.id, t.name, t.created_at,
+    (SELECT COUNT(*) FROM user_team ut WHERE ut.team_id = t.id) AS member_count,
+    COUNT(d.id) AS donation_count,
+    COUNT(DISTINCT d.user_id) AS donor_count,
+    COALESCE(SUM(d.amount), 0)::numeric AS total_amount
+FROM teams t
+LEFT JOIN donations d ON d.team_id = t.id
+    AND ($1::timestamp IS NULL OR d.created_at >= $1)
+    AND ($2::timestamp IS NULL OR d.created_at < $2)
+    AND ($3::int IS NULL OR d.cause_id = $3)
+    AND ($4::text IS NULL OR d.status = $4)
+WHERE t.deleted_at IS NULL
+AND t.id > $5::int
+AND ($6::int IS NULL OR t.id = $6)
+GROUP BY t.id
+ORDER BY t.id
+LIMIT $7
+`
+
+type ExportTeamStatsParams struct {
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	CauseID     sql.NullInt32  `json:"cause_id"`
+	Status      sql.NullString `json:"status"`
+	AfterID     int32          `json:"after_id"`
+	TeamID      sql.NullInt32  `json:"team_id"`
+	Limit       int32          `json:"limit"`
+}
+
+type ExportTeamStatsRow struct {
+	ID            int32        `json:"id"`
+	Name          string       `json:"name"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	MemberCount   int64        `json:"member_count"`
+	DonationCount int64        `json:"donation_count"`
+	DonorCount    int64        `json:"donor_count"`
+	TotalAmount   string       `json:"total_amount"`
+}
+
+// Totals the matching donations made through each team, one page of teams at a time in ID order.
+// Teams without donations are included with zero totals.
+func (q *Queries) ExportTeamStats(ctx context.Context, arg ExportTeamStatsParams) ([]ExportTeamStatsRow, error) {
+	rows, err := q.query(ctx, q.exportTeamStatsStmt, exportTeamStats,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CauseID,
+		arg.Status,
+		arg.AfterID,
+		arg.TeamID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportTeamStatsRow{}
+	for rows.Next() {
+		var i ExportTeamStatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.MemberCount,
+			&i.DonationCount,
+			&i.DonorCount,
+			&i.TotalAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const failReportExport = `-- name: FailReportExport :exec
+UPDATE report_exports
+SET status = 'failed', error = $3, completed_at = now()
+WHERE id = $1 AND status = 'running' AND started_at = $2
+`
+
+type FailReportExportParams struct {
+	ID        int32          `json:"id"`
+	StartedAt sql.NullTime   `json:"started_at"`
+	Error     sql.NullString `json:"error"`
+}
+
+func (q *Queries) FailReportExport(ctx context.Context, arg FailReportExportParams) error {
+	_, err := q.exec(ctx, q.failReportExportStmt, failReportExport, arg.ID, arg.StartedAt, arg.Error)
+	return err
+}
+
+const getReportExport = `-- name: GetReportExport :one
+SELECT id, requested_by, dataset, format, columns, filters, status, row_count, error, created_at, started_at, completed_at, expires_at FROM report_exports
+WHERE id = $1 AND requested_by = $2 LIMIT 1
+`
+
+type GetReportExportParams struct {
+	ID          int32 `json:"id"`
+	RequestedBy int32 `json:"requested_by"`
+}
+
+func (q *Queries) GetReportExport(ctx context.Context, arg GetReportExportParams) (ReportExport, error) {
+	row := q.queryRow(ctx, q.getReportExportStmt, getReportExport, arg.ID, arg.RequestedBy)
+	var i ReportExport
+	err := row.Scan(
+		&i.ID,
+		&i.RequestedBy,
+		&i.Dataset,
+		&i.Format,
+		pq.Array(&i.Columns),
+		&i.Filters,
+		&i.Status,
+		&i.RowCount,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getReportExportFile = `-- name: GetReportExportFile :one
+SELECT content FROM report_export_files
+WHERE export_id = $1
+`
+
+func (q *Queries) GetReportExportFile(ctx context.Context, exportID int32) ([]byte, error) {
+	row := q.queryRow(ctx, q.getReportExportFileStmt, getReportExportFile, exportID)
+	var content []byte
+	err := row.Scan(&content)
+	return content, err
+}
+
+const listReportExportsByUser = `-- name: ListReportExportsByUser :many
+SELECT id, requested_by, dataset, format, columns, filters, status, row_count, error, created_at, started_at, completed_at, expires_at FROM report_exports
+WHERE requested_by = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type ListReportExportsByUserParams struct {
+	RequestedBy int32 `json:"requested_by"`
+	Limit       int32 `json:"limit"`
+}
+
+func (q *Queries) ListReportExportsByUser(ctx context.Context, arg ListReportExportsByUserParams) ([]ReportExport, error) {
+	rows, err := q.query(ctx, q.listReportExportsByUserStmt, listReportExportsByUser, arg.RequestedBy, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ReportExport{}
+	for rows.Next() {
+		var i ReportExport
+		if err := rows.Scan(
+			&i.ID,
+			&i.RequestedBy,
+			&i.Dataset,
+			&i.Format,
+			pq.Array(&i.Columns),
+			&i.Filters,
+			&i.Status,
+			&i.RowCount,
+			&i.Error,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,386 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: retention.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const clearCauseOwner = `-- name: ClearCauseOwner :exec
+UPDATE causes
+SET owner_id = NULL, version = version + 1
+WHERE owner_id = $1
+`
+
+func (q *Queries) ClearCauseOwner(ctx context.Context, ownerID sql.NullInt32) error {
+	_, err := q.exec(ctx, q.clearCauseOwnerStmt, clearCauseOwner, ownerID)
+	return err
+}
+
+const deleteLeaderboardEntriesByTeam = `-- name: DeleteLeaderboardEntriesByTeam :exec
+DELETE FROM leaderboard_entries
+WHERE team_id = $1
+`
+
+func (q *Queries) DeleteLeaderboardEntriesByTeam(ctx context.Context, teamID int32) error {
+	_, err := q.exec(ctx, q.deleteLeaderboardEntriesByTeamStmt, deleteLeaderboardEntriesByTeam, teamID)
+	return err
+}
+
+const deleteLeaderboardEntriesByUser = `-- name: DeleteLeaderboardEntriesByUser :exec
+DELETE FROM leaderboard_entries
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteLeaderboardEntriesByUser(ctx context.Context, userID int32) error {
+	_, err := q.exec(ctx, q.deleteLeaderboardEntriesByUserStmt, deleteLeaderboardEntriesByUser, userID)
+	return err
+}
+
+const deleteTeamMembershipsByTeam = `-- name: DeleteTeamMembershipsByTeam :exec
+DELETE FROM user_team
+WHERE team_id = $1
+`
+
+func (q *Queries) DeleteTeamMembershipsByTeam(ctx context.Context, teamID int32) error {
+	_, err := q.exec(ctx, q.deleteTeamMembershipsByTeamStmt, deleteTeamMembershipsByTeam, teamID)
+	return err
+}
+
+const deleteTeamMembershipsByUser = `-- name: DeleteTeamMembershipsByUser :exec
+DELETE FROM user_team
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteTeamMembershipsByUser(ctx context.Context, userID int32) error {
+	_, err := q.exec(ctx, q.deleteTeamMembershipsByUserStmt, deleteTeamMembershipsByUser, userID)
+	return err
+}
+
+const getUserIncludingDeleted = `-- name: GetUserIncludingDeleted :one
+SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserIncludingDeleted(ctx context.Context, id int32) (User, error) {
+	row := q.queryRow(ctx, q.getUserIncludingDeletedStmt, getUserIncludingDeleted, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const listExpiredDeletedDonors = `-- name: ListExpiredDeletedDonors :many
+SELECT u.id FROM users u
+WHERE u.deleted_at < $1
+AND u.anonymized_at IS NULL
+AND (EXISTS (SELECT 1 FROM donations d WHERE d.user_id = u.id)
+    OR EXISTS (SELECT 1 FROM receipts r WHERE r.user_id = u.id))
+ORDER BY u.deleted_at
+`
+
+func (q *Queries) ListExpiredDeletedDonors(ctx context.Context, deletedAt sql.NullTime) ([]int32, error) {
+	rows, err := q.query(ctx, q.listExpiredDeletedDonorsStmt, listExpiredDeletedDonors, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurgeableCauses = `-- name: ListPurgeableCauses :many
+SELECT id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version FROM causes c
+WHERE c.deleted_at < $1
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.cause_id = c.id)
+ORDER BY c.deleted_at
+`
+
+func (q *Queries) ListPurgeableCauses(ctx context.Context, deletedAt sql.NullTime) ([]Cause, error) {
+	rows, err := q.query(ctx, q.listPurgeableCausesStmt, listPurgeableCauses, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Cause{}
+	for rows.Next() {
+		var i Cause
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Goal,
+			&i.CurrentAmount,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Image,
+			&i.Category,
+			&i.OwnerID,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurgeableTeams = `-- name: ListPurgeableTeams :many
+SELECT id, name, description, avatar_url, created_at, updated_at, deleted_at, version FROM teams t
+WHERE t.deleted_at < $1
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.team_id = t.id)
+ORDER BY t.deleted_at
+`
+
+func (q *Queries) ListPurgeableTeams(ctx context.Context, deletedAt sql.NullTime) ([]Team, error) {
+	rows, err := q.query(ctx, q.listPurgeableTeamsStmt, listPurgeableTeams, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Team{}
+	for rows.Next() {
+		var i Team
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.AvatarUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurgeableUsers = `-- name: ListPurgeableUsers :many
+SELECT id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version FROM users u
+WHERE u.deleted_at < $1
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.user_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM receipts r WHERE r.user_id = u.id)
+ORDER BY u.deleted_at
+`
+
+func (q *Queries) ListPurgeableUsers(ctx context.Context, deletedAt sql.NullTime) ([]User, error) {
+	rows, err := q.query(ctx, q.listPurgeableUsersStmt, listPurgeableUsers, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.AvatarUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserRole,
+			&i.EmailVerifiedAt,
+			&i.ErasureRequestedAt,
+			&i.ErasureScheduledFor,
+			&i.AnonymizedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeCause = `-- name: PurgeCause :execrows
+DELETE FROM causes c
+WHERE c.id = $1
+AND c.deleted_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.cause_id = c.id)
+`
+
+func (q *Queries) PurgeCause(ctx context.Context, id int32) (int64, error) {
+	result, err := q.exec(ctx, q.purgeCauseStmt, purgeCause, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeTeam = `-- name: PurgeTeam :execrows
+DELETE FROM teams t
+WHERE t.id = $1
+AND t.deleted_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.team_id = t.id)
+`
+
+func (q *Queries) PurgeTeam(ctx context.Context, id int32) (int64, error) {
+	result, err := q.exec(ctx, q.purgeTeamStmt, purgeTeam, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeUser = `-- name: PurgeUser :execrows
+DELETE FROM users u
+WHERE u.id = $1
+AND u.deleted_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.user_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM receipts r WHERE r.user_id = u.id)
+`
+
+func (q *Queries) PurgeUser(ctx context.Context, id int32) (int64, error) {
+	result, err := q.exec(ctx, q.purgeUserStmt, purgeUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreCause = `-- name: RestoreCause :one
+UPDATE causes
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, description, goal, current_amount, start_date, end_date, status, created_at, updated_at, image, category, owner_id, deleted_at, version
+`
+
+func (q *Queries) RestoreCause(ctx context.Context, id int32) (Cause, error) {
+	row := q.queryRow(ctx, q.restoreCauseStmt, restoreCause, id)
+	var i Cause
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Goal,
+		&i.CurrentAmount,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Image,
+		&i.Category,
+		&i.OwnerID,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const restoreTeam = `-- name: RestoreTeam :one
+UPDATE teams
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, description, avatar_url, created_at, updated_at, deleted_at, version
+`
+
+func (q *Queries) RestoreTeam(ctx context.Context, id int32) (Team, error) {
+	row := q.queryRow(ctx, q.restoreTeamStmt, restoreTeam, id)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, avatar_url, created_at, updated_at, user_role, email_verified_at, erasure_requested_at, erasure_scheduled_for, anonymized_at, deleted_at, version
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int32) (User, error) {
+	row := q.queryRow(ctx, q.restoreUserStmt, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserRole,
+		&i.EmailVerifiedAt,
+		&i.ErasureRequestedAt,
+		&i.ErasureScheduledFor,
+		&i.AnonymizedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
@@ -0,0 +1,179 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: security.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createSecurityEvent = `-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (user_id, event_type, ip, user_agent)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateSecurityEventParams struct {
+	UserID    sql.NullInt32  `json:"user_id"`
+	EventType string         `json:"event_type"`
+	Ip        sql.NullString `json:"ip"`
+	UserAgent sql.NullString `json:"user_agent"`
+}
+
+func (q *Queries) CreateSecurityEvent(ctx context.Context, arg CreateSecurityEventParams) error {
+	_, err := q.exec(ctx, q.createSecurityEventStmt, createSecurityEvent,
+		arg.UserID,
+		arg.EventType,
+		arg.Ip,
+		arg.UserAgent,
+	)
+	return err
+}
+
+const deleteLoginThrottle = `-- name: DeleteLoginThrottle :exec
+DELETE FROM login_throttle
+WHERE key = $1
+`
+
+func (q *Queries) DeleteLoginThrottle(ctx context.Context, key string) error {
+	_, err := q.exec(ctx, q.deleteLoginThrottleStmt, deleteLoginThrottle, key)
+	return err
+}
+
+const getLoginThrottle = `-- name: GetLoginThrottle :one
+SELECT key, failures, last_failure_at, locked_until FROM login_throttle
+WHERE key = $1 LIMIT 1
+`
+
+func (q *Queries) GetLoginThrottle(ctx context.Context, key string) (LoginThrottle, error) {
+	row := q.queryRow(ctx, q.getLoginThrottleStmt, getLoginThrottle, key)
+	var i LoginThrottle
+	err := row.Scan(
+		&i.Key,
+		&i.Failures,
+		&i.LastFailureAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const listSecurityEventsByUser = `-- name: ListSecurityEventsByUser :many
+SELECT id, user_id, event_type, ip, user_agent, created_at FROM security_events
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListSecurityEventsByUserParams struct {
+	UserID sql.NullInt32 `json:"user_id"`
+	Limit  int32         `json:"limit"`
+	Offset int32         `json:"offset"`
+}
+
+func (q *Queries) ListSecurityEventsByUser(ctx context.Context, arg ListSecurityEventsByUserParams) ([]SecurityEvent, error) {
+	rows, err := q.query(ctx, q.listSecurityEventsByUserStmt, listSecurityEventsByUser, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SecurityEvent{}
+	for rows.Next() {
+		var i SecurityEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventType,
+			&i.Ip,
+			&i.UserAgent,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseLoginAttempt = `-- name: ReleaseLoginAttempt :exec
+UPDATE login_throttle
+SET failures = greatest(failures - 1, 0)
+WHERE key = $1
+`
+
+func (q *Queries) ReleaseLoginAttempt(ctx context.Context, key string) error {
+	_, err := q.exec(ctx, q.releaseLoginAttemptStmt, releaseLoginAttempt, key)
+	return err
+}
+
+const takeLoginAttempt = `-- name: TakeLoginAttempt :one
+INSERT INTO login_throttle (key, failures, last_failure_at, locked_until)
+VALUES (
+    $1, 1, now(),
+    CASE WHEN $2::int = 1 THEN now() + make_interval(secs => $3::float8) END
+)
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+        WHEN login_throttle.last_failure_at < now() - make_interval(secs => $4::float8) THEN 1
+        ELSE login_throttle.failures + 1
+    END,
+    last_failure_at = now(),
+    locked_until = CASE
+        WHEN $2::int > 0 AND CASE
+                WHEN login_throttle.last_failure_at < now() - make_interval(secs => $4::float8) THEN 1
+                ELSE login_throttle.failures + 1
+            END >= $2::int
+        THEN now() + make_interval(secs => $3::float8)
+    END
+WHERE (login_throttle.locked_until IS NULL OR login_throttle.locked_until <= now())
+    AND (
+        login_throttle.last_failure_at < now() - make_interval(secs => $4::float8)
+        OR login_throttle.failures <= $5::int
+        OR login_throttle.last_failure_at + make_interval(secs => least(
+            $6::float8,
+            $7::float8 * power(2::float8, login_throttle.failures - $5::int - 1)
+        )) <= now()
+    )
+RETURNING key, failures, last_failure_at, locked_until
+`
+
+type TakeLoginAttemptParams struct {
+	Key               string  `json:"key"`
+	LockoutAfter      int32   `json:"lockout_after"`
+	LockoutSeconds    float64 `json:"lockout_seconds"`
+	ResetAfterSeconds float64 `json:"reset_after_seconds"`
+	FreeAttempts      int32   `json:"free_attempts"`
+	MaxDelaySeconds   float64 `json:"max_delay_seconds"`
+	BaseDelaySeconds  float64 `json:"base_delay_seconds"`
+}
+
+// Counts an attempt in the same statement that checks it is allowed, so concurrent attempts cannot
+// all pass the check first. Nothing is returned while the key is locked or still backing off: the
+// delay after a failure doubles from base_delay_seconds for each failure beyond free_attempts, up
+// to max_delay_seconds. The attempt that reaches lockout_after locks the key for lockout_seconds.
+func (q *Queries) TakeLoginAttempt(ctx context.Context, arg TakeLoginAttemptParams) (LoginThrottle, error) {
+	row := q.queryRow(ctx, q.takeLoginAttemptStmt, takeLoginAttempt,
+		arg.Key,
+		arg.LockoutAfter,
+		arg.LockoutSeconds,
+		arg.ResetAfterSeconds,
+		arg.FreeAttempts,
+		arg.MaxDelaySeconds,
+		arg.BaseDelaySeconds,
+	)
+	var i LoginThrottle
+	err := row.Scan(
+		&i.Key,
+		&i.Failures,
+		&i.LastFailureAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
@@ -0,0 +1,54 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: seed.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createSeedDonation = `-- name: CreateSeedDonation :one
+INSERT INTO donations (user_id, cause_id, team_id, amount, donation_type, status, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, cause_id, team_id, amount, donation_type, status, created_at, source, import_id
+`
+
+type CreateSeedDonationParams struct {
+	UserID       sql.NullInt32  `json:"user_id"`
+	CauseID      sql.NullInt32  `json:"cause_id"`
+	TeamID       sql.NullInt32  `json:"team_id"`
+	Amount       sql.NullString `json:"amount"`
+	DonationType sql.NullString `json:"donation_type"`
+	Status       sql.NullString `json:"status"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+}
+
+// Like CreateDonation but backdated, so generated data spreads over time
+func (q *Queries) CreateSeedDonation(ctx context.Context, arg CreateSeedDonationParams) (Donation, error) {
+	row := q.queryRow(ctx, q.createSeedDonationStmt, createSeedDonation,
+		arg.UserID,
+		arg.CauseID,
+		arg.TeamID,
+		arg.Amount,
+		arg.DonationType,
+		arg.Status,
+		arg.CreatedAt,
+	)
+	var i Donation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CauseID,
+		&i.TeamID,
+		&i.Amount,
+		&i.DonationType,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Source,
+		&i.ImportID,
+	)
+	return i, err
+}
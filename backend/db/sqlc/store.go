@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Repository is every query plus transactions over them. Store implements it against Postgres;
+// tests substitute an in-memory fake.
+type Repository interface {
+	Querier
+	ExecTx(ctx context.Context, fn func(Querier) error) error
+}
+
+// Store provides all queries plus the ability to run several of them in one transaction
+type Store struct {
+	*Queries
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{
+		db:      db,
+		Queries: New(NewTracedDBTX(db)),
+	}
+}
+
+// ExecTx runs fn inside a database transaction, committing if it returns nil and rolling back otherwise
+func (store *Store) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Not WithTx: that would bypass the tracing wrapper. The store never prepares statements, so
+	// there are none to carry over.
+	q := New(NewTracedDBTX(tx))
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+var _ Repository = (*Store)(nil)
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("play4good-backend/db")
+
+// tracedDBTX starts a client span around every statement. Queries generated by sqlc begin with a
+// "-- name: X :kind" comment, which gives each span the query's name rather than its SQL.
+type tracedDBTX struct {
+	db DBTX
+}
+
+// NewTracedDBTX wraps db so each query run through it is traced as a child of the span in its context
+func NewTracedDBTX(db DBTX) DBTX {
+	return tracedDBTX{db: db}
+}
+
+func (t tracedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startSpan(ctx, query)
+	res, err := t.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		if n, rerr := res.RowsAffected(); rerr == nil {
+			span.SetAttributes(attribute.Int64("db.rows_affected", n))
+		}
+	}
+	endSpan(span, err)
+	return res, err
+}
+
+func (t tracedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := startSpan(ctx, query)
+	stmt, err := t.db.PrepareContext(ctx, query)
+	endSpan(span, err)
+	return stmt, err
+}
+
+func (t tracedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startSpan(ctx, query)
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	endSpan(span, err)
+	return rows, err
+}
+
+// QueryRowContext's span covers sending the query; the row is scanned after it ends
+func (t tracedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startSpan(ctx, query)
+	row := t.db.QueryRowContext(ctx, query, args...)
+	err := row.Err()
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	endSpan(span, err)
+	return row
+}
+
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	name, op := queryName(query)
+	return tracer.Start(ctx, "db."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(op),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// queryName returns the sqlc query name and the SQL verb, e.g. "GetUser" and "SELECT"
+func queryName(query string) (name, op string) {
+	body := query
+	if rest, ok := strings.CutPrefix(query, "-- name: "); ok {
+		line, after, _ := strings.Cut(rest, "\n")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			name = fields[0]
+		}
+		body = after
+	}
+	if fields := strings.Fields(body); len(fields) > 0 {
+		op = strings.ToUpper(fields[0])
+	}
+	if name == "" {
+		name = strings.ToLower(op)
+	}
+	if name == "" {
+		name = "query"
+	}
+	return name, op
+}
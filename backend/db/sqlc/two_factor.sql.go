@@ -0,0 +1,238 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: two_factor.sql
+
+package db
+
+import (
+	"context"
+)
+
+const consumeRecoveryCode = `-- name: ConsumeRecoveryCode :execrows
+UPDATE user_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+AND code_hash = $2
+AND used_at IS NULL
+`
+
+type ConsumeRecoveryCodeParams struct {
+	UserID   int32  `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) ConsumeRecoveryCode(ctx context.Context, arg ConsumeRecoveryCodeParams) (int64, error) {
+	result, err := q.exec(ctx, q.consumeRecoveryCodeStmt, consumeRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countUnusedRecoveryCodes = `-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM user_recovery_codes
+WHERE user_id = $1
+AND used_at IS NULL
+`
+
+func (q *Queries) CountUnusedRecoveryCodes(ctx context.Context, userID int32) (int64, error) {
+	row := q.queryRow(ctx, q.countUnusedRecoveryCodesStmt, countUnusedRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash)
+VALUES ($1, $2)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   int32  `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.exec(ctx, q.createRecoveryCodeStmt, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID int32) error {
+	_, err := q.exec(ctx, q.deleteRecoveryCodesStmt, deleteRecoveryCodes, userID)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :exec
+DELETE FROM user_totp
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserTOTP(ctx context.Context, userID int32) error {
+	_, err := q.exec(ctx, q.deleteUserTOTPStmt, deleteUserTOTP, userID)
+	return err
+}
+
+const enableUserTOTP = `-- name: EnableUserTOTP :one
+UPDATE user_totp
+SET enabled_at = now(), last_used_step = $2
+WHERE user_id = $1
+RETURNING user_id, secret, enabled_at, last_used_step, created_at
+`
+
+type EnableUserTOTPParams struct {
+	UserID       int32 `json:"user_id"`
+	LastUsedStep int64 `json:"last_used_step"`
+}
+
+func (q *Queries) EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (UserTotp, error) {
+	row := q.queryRow(ctx, q.enableUserTOTPStmt, enableUserTOTP, arg.UserID, arg.LastUsedStep)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTwoFactorStatus = `-- name: GetTwoFactorStatus :one
+SELECT
+    COALESCE(rp.require_2fa, false)::boolean AS required,
+    (ut.enabled_at IS NOT NULL)::boolean AS enabled
+FROM users u
+LEFT JOIN role_policies rp ON rp.user_role = u.user_role
+LEFT JOIN user_totp ut ON ut.user_id = u.id
+WHERE u.id = $1
+`
+
+type GetTwoFactorStatusRow struct {
+	Required bool `json:"required"`
+	Enabled  bool `json:"enabled"`
+}
+
+func (q *Queries) GetTwoFactorStatus(ctx context.Context, id int32) (GetTwoFactorStatusRow, error) {
+	row := q.queryRow(ctx, q.getTwoFactorStatusStmt, getTwoFactorStatus, id)
+	var i GetTwoFactorStatusRow
+	err := row.Scan(&i.Required, &i.Enabled)
+	return i, err
+}
+
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT user_id, secret, enabled_at, last_used_step, created_at FROM user_totp
+WHERE user_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserTOTP(ctx context.Context, userID int32) (UserTotp, error) {
+	row := q.queryRow(ctx, q.getUserTOTPStmt, getUserTOTP, userID)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listRolePolicies = `-- name: ListRolePolicies :many
+SELECT user_role, require_2fa, updated_at FROM role_policies
+ORDER BY user_role
+`
+
+func (q *Queries) ListRolePolicies(ctx context.Context) ([]RolePolicy, error) {
+	rows, err := q.query(ctx, q.listRolePoliciesStmt, listRolePolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RolePolicy{}
+	for rows.Next() {
+		var i RolePolicy
+		if err := rows.Scan(&i.UserRole, &i.Require2fa, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserTOTPLastUsedStep = `-- name: UpdateUserTOTPLastUsedStep :execrows
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1
+AND last_used_step < $2
+`
+
+type UpdateUserTOTPLastUsedStepParams struct {
+	UserID       int32 `json:"user_id"`
+	LastUsedStep int64 `json:"last_used_step"`
+}
+
+func (q *Queries) UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateUserTOTPLastUsedStepStmt, updateUserTOTPLastUsedStep, arg.UserID, arg.LastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertRolePolicy = `-- name: UpsertRolePolicy :one
+INSERT INTO role_policies (user_role, require_2fa)
+VALUES ($1, $2)
+ON CONFLICT (user_role) DO UPDATE
+SET require_2fa = EXCLUDED.require_2fa, updated_at = now()
+RETURNING user_role, require_2fa, updated_at
+`
+
+type UpsertRolePolicyParams struct {
+	UserRole   string `json:"user_role"`
+	Require2fa bool   `json:"require_2fa"`
+}
+
+func (q *Queries) UpsertRolePolicy(ctx context.Context, arg UpsertRolePolicyParams) (RolePolicy, error) {
+	row := q.queryRow(ctx, q.upsertRolePolicyStmt, upsertRolePolicy, arg.UserRole, arg.Require2fa)
+	var i RolePolicy
+	err := row.Scan(&i.UserRole, &i.Require2fa, &i.UpdatedAt)
+	return i, err
+}
+
+const upsertUserTOTP = `-- name: UpsertUserTOTP :one
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, enabled_at = NULL, last_used_step = 0, created_at = now()
+RETURNING user_id, secret, enabled_at, last_used_step, created_at
+`
+
+type UpsertUserTOTPParams struct {
+	UserID int32  `json:"user_id"`
+	Secret string `json:"secret"`
+}
+
+func (q *Queries) UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTotp, error) {
+	row := q.queryRow(ctx, q.upsertUserTOTPStmt, upsertUserTOTP, arg.UserID, arg.Secret)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,231 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"play4good-backend/db/memdb"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseCSV(t *testing.T) {
+	file := "\ufeffUser Email,Cause ID,Amount,Donation Type\n" +
+		"alice@play4good.test,1,\"1,250.00\",money\n" +
+		",,,\n" +
+		"bob@play4good.test,1,25,goods\n"
+
+	sheet, err := Parse("gala.CSV", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"User Email", "Cause ID", "Amount", "Donation Type"}; !reflect.DeepEqual(sheet.Header, want) {
+		t.Errorf("header = %q, want %q", sheet.Header, want)
+	}
+	// The blank line is dropped but still counted, so numbers match the file
+	if len(sheet.Rows) != 2 || sheet.Rows[0].Number != 2 || sheet.Rows[1].Number != 4 {
+		t.Fatalf("rows = %+v, want lines 2 and 4", sheet.Rows)
+	}
+	if sheet.Rows[0].Cells[2] != "1,250.00" {
+		t.Errorf("quoted amount = %q", sheet.Rows[0].Cells[2])
+	}
+}
+
+func TestParseXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	for cell, value := range map[string]interface{}{
+		"A1": "user_id", "B1": "cause_id", "C1": "amount", "D1": "donation_type", "E1": "donated_at",
+		"A2": 7, "B2": 3, "C2": 12.5, "D2": "money", "E2": 45383,
+	} {
+		if err := f.SetCellValue(sheet, cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse("gala.xlsx", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Rows) != 1 || parsed.Rows[0].Number != 2 {
+		t.Fatalf("rows = %+v, want row 2", parsed.Rows)
+	}
+	if want := []string{"7", "3", "12.5", "money", "45383"}; !reflect.DeepEqual(parsed.Rows[0].Cells, want) {
+		t.Errorf("cells = %q, want the raw values %q", parsed.Rows[0].Cells, want)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for name, test := range map[string]struct {
+		filename, body string
+		want           error
+	}{
+		"other format":  {"gala.txt", "user_id\n1\n", ErrUnsupportedFormat},
+		"empty file":    {"gala.csv", "\n\n", ErrNoHeader},
+		"too many rows": {"gala.csv", "user_id\n" + strings.Repeat("1\n", MaxRows+1), ErrTooManyRows},
+	} {
+		if _, err := Parse(test.filename, strings.NewReader(test.body)); !errors.Is(err, test.want) {
+			t.Errorf("%s: err = %v, want %v", name, err, test.want)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	header := []string{"Donor", "Cause-ID", "Gift", "donation type"}
+
+	columns, err := Mapping{FieldUserEmail: "donor", FieldAmount: "GIFT"}.Columns(Donations, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{FieldUserEmail: 0, FieldCauseID: 1, FieldAmount: 2, FieldDonationType: 3}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %v, want %v", columns, want)
+	}
+
+	_, err = Mapping{"colour": "Donor", FieldAmount: "Total"}.Columns(Donations, header)
+	var p *problem.Problem
+	if !errors.As(err, &p) {
+		t.Fatalf("err = %v, want a problem", err)
+	}
+	var fields []string
+	for _, fe := range p.Errors {
+		fields = append(fields, fe.Field+":"+fe.Rule)
+	}
+	if want := []string{"mapping:required", "mapping:required", "mapping.amount:exists", "mapping.colour:oneof"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("mapping errors = %v, want %v", fields, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	store := memdb.New()
+	alice, err := store.CreateUser(ctx, db.CreateUserParams{Username: "alice", Email: "alice@play4good.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cause, err := store.CreateCause(ctx, db.CreateCauseParams{Name: "Clean Rivers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := Parse("gala.csv", strings.NewReader(strings.Join([]string{
+		"user_email,cause_id,amount,donation_type,donated_at",
+		"alice@play4good.test," + strconv.Itoa(int(cause.ID)) + ",€25,Money,2024-03-31",
+		"nobody@play4good.test," + strconv.Itoa(int(cause.ID)) + ",10,money,",
+		"alice@play4good.test,9999,ten,cash,yesterday",
+		"alice@play4good.test," + strconv.Itoa(int(cause.ID)) + ",,money,",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, err := Mapping{}.Columns(Donations, sheet.Header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	donations, rowErrs, err := NewValidator(store, columns).Validate(ctx, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(donations) != 1 {
+		t.Fatalf("%d valid rows, want 1", len(donations))
+	}
+	d := donations[0]
+	if d.Row != 2 || d.Request.UserID != int64(alice.ID) || d.Request.Amount != 25 || d.Request.DonationType != "money" ||
+		!d.DonatedAt.Equal(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("donation = %+v", d)
+	}
+
+	got := map[int][]string{}
+	for _, re := range rowErrs {
+		for _, fe := range re.Errors {
+			got[re.Row] = append(got[re.Row], fe.Field+":"+fe.Rule)
+		}
+	}
+	want := map[int][]string{
+		3: {"user_email:exists"},
+		4: {"amount:type", "donated_at:type", "donation_type:oneof", "cause_id:exists"},
+		5: {"amount:required"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("row errors = %v, want %v", got, want)
+	}
+}
+
+func TestValidateCauses(t *testing.T) {
+	sheet, err := Parse("causes.csv", strings.NewReader(strings.Join([]string{
+		"Name,Goal,Start Date,End Date,Status",
+		"Clean Rivers,\"5,000\",2026-01-01,2026-12-31,Active",
+		"CR,lots,2026-03-01,2026-02-01,paused",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, err := Mapping{}.Columns(Causes, sheet.Header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	causes, rowErrs := ValidateCauses(sheet, columns)
+	if len(causes) != 1 {
+		t.Fatalf("%d valid rows, want 1", len(causes))
+	}
+	if c := causes[0].Request; c.Name != "Clean Rivers" || c.Goal != 5000 || c.Status != "active" || !c.EndDate.Equal(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("cause = %+v", c)
+	}
+	var got []string
+	for _, fe := range rowErrs[0].Errors {
+		got = append(got, fe.Field+":"+fe.Rule)
+	}
+	if want := []string{"goal:type", "name:min", "end_date:gtfield", "status:oneof"}; rowErrs[0].Row != 3 || !reflect.DeepEqual(got, want) {
+		t.Errorf("row %d errors = %v, want %v", rowErrs[0].Row, got, want)
+	}
+
+	// A team file lacks the cause columns
+	if _, err := (Mapping{}).Columns(Causes, []string{"name", "description"}); err == nil {
+		t.Error("a file without goal, dates and status was accepted as causes")
+	}
+}
+
+func TestValidateTeams(t *testing.T) {
+	sheet, err := Parse("teams.csv", strings.NewReader("Team,Avatar URL\nRiver Runners,https://play4good.test/a.png\nXY,not a url\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, err := Mapping{FieldName: "team"}.Columns(Teams, sheet.Header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	teams, rowErrs := ValidateTeams(sheet, columns)
+	if len(teams) != 1 || teams[0].Request.AvatarURL != "https://play4good.test/a.png" {
+		t.Errorf("teams = %+v", teams)
+	}
+	if len(rowErrs) != 1 || rowErrs[0].Row != 3 || len(rowErrs[0].Errors) != 2 {
+		t.Errorf("row errors = %+v, want name and avatar_url on row 3", rowErrs)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	for _, s := range []string{"2024-03-31", "2024-03-31 00:00:00", "2024-03-31T00:00:00Z", "2024-03-31T02:00:00+02:00", "45382"} {
+		got, err := parseDate(s)
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", s, err)
+			continue
+		}
+		if !got.Equal(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("parseDate(%q) = %s", s, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package imports
+
+import (
+	"net/http"
+	"strings"
+
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Fields a cause or team column can be mapped onto
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldGoal        = "goal"
+	FieldStartDate   = "start_date"
+	FieldEndDate     = "end_date"
+	FieldStatus      = "status"
+	FieldImage       = "image"
+	FieldCategory    = "category"
+	FieldAvatarURL   = "avatar_url"
+)
+
+// Causes are rows checked with the same rules as POST /causes
+var Causes = Kind{
+	Name:     "cause",
+	Fields:   []string{FieldName, FieldDescription, FieldGoal, FieldStartDate, FieldEndDate, FieldStatus, FieldImage, FieldCategory},
+	Required: [][]string{{FieldName}, {FieldGoal}, {FieldStartDate}, {FieldEndDate}, {FieldStatus}},
+}
+
+// Teams are rows checked with the same rules as POST /teams
+var Teams = Kind{
+	Name:     "team",
+	Fields:   []string{FieldName, FieldDescription, FieldAvatarURL},
+	Required: [][]string{{FieldName}},
+}
+
+// Cause is a cause row that passed every check
+type Cause struct {
+	Row     int
+	Request schemas.CauseCreateRequest
+}
+
+// Team is a team row that passed every check
+type Team struct {
+	Row     int
+	Request schemas.TeamCreateRequest
+}
+
+// ValidateCauses splits the rows of sheet into valid causes and per-row errors, both in row order
+func ValidateCauses(sheet *Sheet, columns map[string]int) ([]Cause, []RowError) {
+	var causes []Cause
+	var rowErrs []RowError
+	for _, row := range sheet.Rows {
+		c := Cause{Row: row.Number}
+		var f fieldErrors
+		c.Request.Name = cell(columns, row, FieldName)
+		c.Request.Description = cell(columns, row, FieldDescription)
+		if s := cell(columns, row, FieldGoal); s != "" {
+			goal, err := parseAmount(s)
+			if err != nil {
+				f.fail(FieldGoal, "type", "must be a number")
+			}
+			c.Request.Goal = goal
+		}
+		if s := cell(columns, row, FieldStartDate); s != "" {
+			at, err := parseDate(s)
+			if err != nil {
+				f.fail(FieldStartDate, "type", "must be a date such as 2024-03-31 or 2024-03-31T14:00:00Z")
+			}
+			c.Request.StartDate = at
+		}
+		if s := cell(columns, row, FieldEndDate); s != "" {
+			at, err := parseDate(s)
+			if err != nil {
+				f.fail(FieldEndDate, "type", "must be a date such as 2024-03-31 or 2024-03-31T14:00:00Z")
+			}
+			c.Request.EndDate = at
+		}
+		c.Request.Status = strings.ToLower(cell(columns, row, FieldStatus))
+		c.Request.Image = cell(columns, row, FieldImage)
+		c.Request.Category = cell(columns, row, FieldCategory)
+
+		if f.validate(&c.Request); len(f.errs) > 0 {
+			rowErrs = append(rowErrs, RowError{Row: row.Number, Errors: f.errs})
+			continue
+		}
+		causes = append(causes, c)
+	}
+	return causes, rowErrs
+}
+
+// ValidateTeams splits the rows of sheet into valid teams and per-row errors, both in row order
+func ValidateTeams(sheet *Sheet, columns map[string]int) ([]Team, []RowError) {
+	var teams []Team
+	var rowErrs []RowError
+	for _, row := range sheet.Rows {
+		t := Team{Row: row.Number}
+		t.Request.Name = cell(columns, row, FieldName)
+		t.Request.Description = cell(columns, row, FieldDescription)
+		t.Request.AvatarURL = cell(columns, row, FieldAvatarURL)
+
+		var f fieldErrors
+		if f.validate(&t.Request); len(f.errs) > 0 {
+			rowErrs = append(rowErrs, RowError{Row: row.Number, Errors: f.errs})
+			continue
+		}
+		teams = append(teams, t)
+	}
+	return teams, rowErrs
+}
+
+// fieldErrors collects what is wrong with one row, at most one error per field
+type fieldErrors struct {
+	errs    []problem.FieldError
+	invalid map[string]bool
+}
+
+func (f *fieldErrors) fail(field, rule, message string) {
+	if f.invalid == nil {
+		f.invalid = make(map[string]bool)
+	}
+	f.errs = append(f.errs, problem.FieldError{Field: field, Rule: rule, Message: message})
+	f.invalid[field] = true
+}
+
+// validate applies the request's own rules to the fields that parsed
+func (f *fieldErrors) validate(req any) {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		for _, fe := range problem.From(err, http.StatusBadRequest).Errors {
+			if !f.invalid[fe.Field] {
+				f.fail(fe.Field, fe.Rule, fe.Message)
+			}
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/xuri/excelize/v2"
+)
+
+// Fields a donation column can be mapped onto. A row names its donor by user_id or by user_email.
+const (
+	FieldUserID       = "user_id"
+	FieldUserEmail    = "user_email"
+	FieldCauseID      = "cause_id"
+	FieldTeamID       = "team_id"
+	FieldAmount       = "amount"
+	FieldDonationType = "donation_type"
+	FieldDonatedAt    = "donated_at"
+)
+
+// Kind is a type of record a file can hold: the fields its columns can be mapped onto and the ones
+// every file needs a column for
+type Kind struct {
+	Name   string
+	Fields []string
+	// Required lists the fields a file must have; a field given as several names needs any one of them
+	Required [][]string
+}
+
+// Donations are rows checked with the same rules as POST /donations
+var Donations = Kind{
+	Name:     "donation",
+	Fields:   []string{FieldUserID, FieldUserEmail, FieldCauseID, FieldTeamID, FieldAmount, FieldDonationType, FieldDonatedAt},
+	Required: [][]string{{FieldCauseID}, {FieldAmount}, {FieldDonationType}, {FieldUserID, FieldUserEmail}},
+}
+
+// Mapping names the column header to read each field from. Fields it leaves out are read from a
+// column named like the field, ignoring case, spaces and dashes.
+type Mapping map[string]string
+
+// Columns resolves m against a header into the index of each of kind's fields' column. Fields with
+// no column are left out.
+func (m Mapping) Columns(kind Kind, header []string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, seen := index[normalize(name)]; !seen {
+			index[normalize(name)] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	var errs []problem.FieldError
+	for field, column := range m {
+		if !kind.known(field) {
+			errs = append(errs, problem.FieldError{Field: "mapping." + field, Rule: "oneof", Message: "must be one of: " + strings.Join(kind.Fields, ", ")})
+			continue
+		}
+		i, ok := index[normalize(column)]
+		if !ok {
+			errs = append(errs, problem.FieldError{Field: "mapping." + field, Rule: "exists", Message: "names column " + strconv.Quote(column) + ", which is not in the file"})
+			continue
+		}
+		columns[field] = i
+	}
+	for _, field := range kind.Fields {
+		if _, mapped := m[field]; mapped {
+			continue
+		}
+		if i, ok := index[normalize(field)]; ok {
+			columns[field] = i
+		}
+	}
+
+	for _, required := range kind.Required {
+		found := false
+		for _, field := range required {
+			_, ok := columns[field]
+			found = found || ok
+		}
+		if !found {
+			errs = append(errs, missingColumn(strings.Join(required, " or ")))
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+		return nil, problem.New(http.StatusUnprocessableEntity, problem.CodeValidationFailed, "The file's columns do not match the "+kind.Name+" fields").WithErrors(errs...)
+	}
+	return columns, nil
+}
+
+func missingColumn(field string) problem.FieldError {
+	return problem.FieldError{Field: "mapping", Rule: "required", Message: "needs a column for " + field}
+}
+
+func (k Kind) known(field string) bool {
+	for _, f := range k.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Donation is a row that passed every check
+type Donation struct {
+	Row       int
+	Request   schemas.DonationCreateRequest
+	DonatedAt time.Time // zero when the file has no date for the row
+}
+
+// RowError lists what is wrong with one row
+type RowError struct {
+	Row    int                  `json:"row"`
+	Errors []problem.FieldError `json:"errors"`
+}
+
+// Validator checks rows against the donation rules and the database. It caches the users, causes
+// and teams it has looked up, so one instance should serve a single file.
+type Validator struct {
+	q       db.Querier
+	columns map[string]int
+	emails  map[string]int32
+	exists  map[string]bool
+}
+
+func NewValidator(q db.Querier, columns map[string]int) *Validator {
+	return &Validator{q: q, columns: columns, emails: make(map[string]int32), exists: make(map[string]bool)}
+}
+
+// Validate splits the rows of sheet into valid donations and per-row errors, both in row order
+func (v *Validator) Validate(ctx context.Context, sheet *Sheet) ([]Donation, []RowError, error) {
+	var donations []Donation
+	var rowErrs []RowError
+	for _, row := range sheet.Rows {
+		donation, errs, err := v.row(ctx, row)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(errs) > 0 {
+			rowErrs = append(rowErrs, RowError{Row: row.Number, Errors: errs})
+			continue
+		}
+		donations = append(donations, donation)
+	}
+	return donations, rowErrs, nil
+}
+
+func (v *Validator) row(ctx context.Context, row Row) (Donation, []problem.FieldError, error) {
+	d := Donation{Row: row.Number}
+	var errs []problem.FieldError
+	invalid := make(map[string]bool)
+	fail := func(field, rule, message string) {
+		errs = append(errs, problem.FieldError{Field: field, Rule: rule, Message: message})
+		invalid[field] = true
+	}
+	integer := func(field string) int64 {
+		s := v.cell(row, field)
+		if s == "" {
+			return 0
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(s, ".0"), 10, 32)
+		if err != nil || n < 0 {
+			fail(field, "type", "must be a whole number")
+			return 0
+		}
+		return n
+	}
+
+	d.Request.UserID = integer(FieldUserID)
+	d.Request.CauseID = integer(FieldCauseID)
+	d.Request.TeamID = integer(FieldTeamID)
+	if s := v.cell(row, FieldAmount); s != "" {
+		amount, err := parseAmount(s)
+		if err != nil {
+			fail(FieldAmount, "type", "must be a number")
+		}
+		d.Request.Amount = amount
+	}
+	d.Request.DonationType = strings.ToLower(v.cell(row, FieldDonationType))
+	if s := v.cell(row, FieldDonatedAt); s != "" {
+		at, err := parseDate(s)
+		if err != nil {
+			fail(FieldDonatedAt, "type", "must be a date such as 2024-03-31 or 2024-03-31T14:00:00Z")
+		}
+		d.DonatedAt = at
+	}
+
+	// A user ID wins over an email address when a row has both
+	if email := v.cell(row, FieldUserEmail); d.Request.UserID == 0 && !invalid[FieldUserID] && email != "" {
+		id, err := v.userByEmail(ctx, email)
+		if err != nil {
+			return Donation{}, nil, err
+		}
+		if id == 0 {
+			fail(FieldUserEmail, "exists", "does not match a user")
+			invalid[FieldUserID] = true
+		}
+		d.Request.UserID = int64(id)
+	}
+
+	// The request's own rules, for fields that parsed
+	if err := binding.Validator.ValidateStruct(&d.Request); err != nil {
+		for _, fe := range problem.From(err, http.StatusBadRequest).Errors {
+			if !invalid[fe.Field] {
+				fail(fe.Field, fe.Rule, fe.Message)
+			}
+		}
+	}
+
+	for _, ref := range []struct {
+		field string
+		id    int64
+		get   func(context.Context, int32) error
+	}{
+		{FieldUserID, d.Request.UserID, func(ctx context.Context, id int32) error { _, err := v.q.GetUser(ctx, id); return err }},
+		{FieldCauseID, d.Request.CauseID, func(ctx context.Context, id int32) error { _, err := v.q.GetCause(ctx, id); return err }},
+		{FieldTeamID, d.Request.TeamID, func(ctx context.Context, id int32) error { _, err := v.q.GetTeam(ctx, id); return err }},
+	} {
+		if ref.id == 0 || invalid[ref.field] {
+			continue
+		}
+		ok, err := v.exist(ctx, ref.field, int32(ref.id), ref.get)
+		if err != nil {
+			return Donation{}, nil, err
+		}
+		if !ok {
+			fail(ref.field, "exists", "does not match a record")
+		}
+	}
+	return d, errs, nil
+}
+
+func (v *Validator) cell(row Row, field string) string {
+	return cell(v.columns, row, field)
+}
+
+func cell(columns map[string]int, row Row, field string) string {
+	i, ok := columns[field]
+	if !ok || i >= len(row.Cells) {
+		return ""
+	}
+	return row.Cells[i]
+}
+
+func (v *Validator) userByEmail(ctx context.Context, email string) (int32, error) {
+	if id, ok := v.emails[email]; ok {
+		return id, nil
+	}
+	user, err := v.q.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	v.emails[email] = user.ID
+	return user.ID, nil
+}
+
+func (v *Validator) exist(ctx context.Context, field string, id int32, get func(context.Context, int32) error) (bool, error) {
+	key := field + ":" + strconv.Itoa(int(id))
+	if ok, cached := v.exists[key]; cached {
+		return ok, nil
+	}
+	err := get(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	v.exists[key] = err == nil
+	return err == nil, nil
+}
+
+// parseAmount reads amounts as people type them, e.g. "1,250.00" or "€25"
+func parseAmount(s string) (float64, error) {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		case r == ',', r == ' ', strings.ContainsRune("$€£¥", r):
+			return -1
+		}
+		return r
+	}, s)
+	return strconv.ParseFloat(s, 64)
+}
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// parseDate reads ISO 8601 dates and times, and Excel date serials as XLSX cells store them
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	serial, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return excelize.ExcelDateToTime(serial, false)
+}
@@ -0,0 +1,136 @@
+// Package imports reads spreadsheets of donations, causes or teams: CSV or XLSX files whose columns
+// are mapped onto a record's fields and checked with the same rules as the API that creates it
+package imports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"play4good-backend/problem"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Limits on one upload
+const (
+	MaxFileSize = 10 << 20
+	MaxRows     = 20000
+)
+
+var (
+	// ErrUnsupportedFormat rejects files that are neither CSV nor XLSX
+	ErrUnsupportedFormat error = problem.New(http.StatusUnsupportedMediaType, problem.CodeUnsupportedMedia, "Upload a .csv or .xlsx file")
+	// ErrNoHeader rejects files without a header row
+	ErrNoHeader error = problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, "The file has no header row")
+	// ErrTooManyRows rejects files with more than MaxRows data rows
+	ErrTooManyRows error = problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, fmt.Sprintf("The file has more than %d rows; split it into several imports", MaxRows))
+)
+
+// Sheet is a parsed file: its header and data rows. Blank rows are left out.
+type Sheet struct {
+	Header []string
+	Rows   []Row
+}
+
+// Row is one data row. Number is its line in the file, counting the header as 1, so it matches what
+// people see in a spreadsheet.
+type Row struct {
+	Number int
+	Cells  []string
+}
+
+// Parse reads a CSV or XLSX file, chosen by filename's extension
+func Parse(filename string, r io.Reader) (*Sheet, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return parseCSV(r)
+	case ".xlsx":
+		return parseXLSX(r)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func parseCSV(r io.Reader) (*Sheet, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	sheet := &Sheet{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, "The file is not valid CSV: "+err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		if err := sheet.add(line, record); err != nil {
+			return nil, err
+		}
+	}
+	return sheet.done()
+}
+
+func parseXLSX(r io.Reader) (*Sheet, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, "The file is not a valid XLSX workbook")
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrNoHeader
+	}
+	// Raw values keep numbers and dates as stored rather than as formatted for display
+	rows, err := f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, "The workbook's first sheet cannot be read")
+	}
+
+	sheet := &Sheet{}
+	for i, cells := range rows {
+		if err := sheet.add(i+1, cells); err != nil {
+			return nil, err
+		}
+	}
+	return sheet.done()
+}
+
+// add takes the first non-blank row as the header and the rest as data
+func (s *Sheet) add(number int, cells []string) error {
+	blank := true
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+		if cells[i] != "" {
+			blank = false
+		}
+	}
+	if blank {
+		return nil
+	}
+	if s.Header == nil {
+		// Spreadsheet programs often save CSV with a byte order mark
+		cells[0] = strings.TrimPrefix(cells[0], "\ufeff")
+		s.Header = cells
+		return nil
+	}
+	if len(s.Rows) == MaxRows {
+		return ErrTooManyRows
+	}
+	s.Rows = append(s.Rows, Row{Number: number, Cells: cells})
+	return nil
+}
+
+func (s *Sheet) done() (*Sheet, error) {
+	if s.Header == nil {
+		return nil, ErrNoHeader
+	}
+	return s, nil
+}
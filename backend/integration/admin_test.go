@@ -0,0 +1,48 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"play4good-backend/admin"
+)
+
+func TestAdminExportRoundTrip(t *testing.T) {
+	s := newSuite(t)
+	organiser, organiserID := s.signUp("organiser")
+	s.verify(organiserID)
+	createCause(s, organiser, "Clean Rivers")
+	createCause(s, organiser, "Open Skies")
+
+	// Rows from before dates were required, or from a spreadsheet import, have none
+	if _, err := s.conn.Exec(`UPDATE causes SET start_date = NULL, end_date = NULL WHERE name = 'Open Skies'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.conn.Exec(`INSERT INTO leaderboards (name, type) VALUES ('Spring League', 'team')`); err != nil {
+		t.Fatal(err)
+	}
+
+	service := admin.NewService(s.store)
+	var export bytes.Buffer
+	if err := service.Export(context.Background(), &export); err != nil {
+		t.Fatal(err)
+	}
+	result, err := service.Import(context.Background(), bytes.NewReader(export.Bytes()), email("organiser"), false)
+	if err != nil {
+		t.Fatalf("importing the export: %v", err)
+	}
+	if result[admin.KindCause] != 2 || result[admin.KindLeaderboard] != 1 {
+		t.Errorf("imported %v, want 2 causes and 1 leaderboard", result)
+	}
+
+	if n := s.count(`SELECT count(*) FROM causes WHERE name = 'Open Skies' AND start_date IS NULL AND end_date IS NULL`); n != 2 {
+		t.Errorf("%d undated Open Skies causes, want the original and its copy", n)
+	}
+	if n := s.count(`SELECT count(*) FROM causes WHERE name = 'Clean Rivers' AND start_date IS NOT NULL AND end_date > start_date`); n != 2 {
+		t.Errorf("%d dated Clean Rivers causes, want the original and its copy", n)
+	}
+	if n := s.count(`SELECT count(*) FROM leaderboards WHERE start_date IS NULL AND end_date IS NULL`); n != 2 {
+		t.Errorf("%d undated leaderboards, want the original and its copy", n)
+	}
+}
@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"play4good-backend/analytics"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDonationAnalytics(t *testing.T) {
+	s := newSuite(t)
+	organiser, organiserID := s.signUp("organiser")
+	s.verify(organiserID)
+	causeID := createCause(s, organiser, "Clean Rivers")
+	otherCauseID := createCause(s, organiser, "Green Parks")
+	donor, donorID := s.signUp("donor")
+	admin, adminID := s.signUp("admin")
+	s.promote(adminID)
+
+	for _, d := range []struct {
+		cause  int32
+		amount float64
+	}{{causeID, 20}, {causeID, 30}, {otherCauseID, 5}} {
+		donor.expect(http.StatusOK, http.MethodPost, "/api/donations", gin.H{"user_id": donorID, "cause_id": d.cause, "amount": d.amount, "donation_type": "money"})
+	}
+
+	type result struct {
+		Refreshing bool `json:"refreshing"`
+		Total      struct {
+			Amount       string `json:"amount"`
+			Count        int64  `json:"count"`
+			UniqueDonors int64  `json:"unique_donors"`
+		} `json:"total"`
+		Series []struct {
+			Key    string `json:"key"`
+			Points []struct {
+				Amount string `json:"amount"`
+			} `json:"points"`
+		} `json:"series"`
+	}
+
+	// Donations count once their buckets have been rolled up
+	var res result
+	admin.expect(http.StatusOK, http.MethodGet, "/api/analytics/donations", nil).decode(t, &res)
+	if !res.Refreshing || res.Total.Count != 0 {
+		t.Errorf("before the refresh = %+v, want nothing yet and a refresh pending", res)
+	}
+	if err := analytics.NewService(s.store).RefreshRollups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	admin.expect(http.StatusOK, http.MethodGet, "/api/analytics/donations?group_by=cause&compare=true&tz=Pacific/Auckland", nil).decode(t, &res)
+	if res.Refreshing || res.Total.Amount != "55.00" || res.Total.Count != 3 || res.Total.UniqueDonors != 1 {
+		t.Errorf("totals = %+v, want 55.00 from 3 donations by one donor", res)
+	}
+	if len(res.Series) != 2 || res.Series[0].Key != fmt.Sprint(causeID) || len(res.Series[0].Points) != 30 {
+		t.Errorf("series = %+v, want 30 days for each cause, largest first", res.Series)
+	}
+
+	// Cause owners only see their own causes
+	today := time.Now().UTC().Format("2006-01-02")
+	organiser.expect(http.StatusForbidden, http.MethodGet, "/api/analytics/donations", nil)
+	donor.expect(http.StatusForbidden, http.MethodGet, fmt.Sprintf("/api/analytics/donations?cause_id=%d", causeID), nil)
+	organiser.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/api/analytics/donations?cause_id=%d&from=%s&to=%s", causeID, today, today), nil).decode(t, &res)
+	if res.Total.Amount != "50.00" || len(res.Series) != 1 || len(res.Series[0].Points) != 1 {
+		t.Errorf("cause analytics = %+v, want today's 50.00", res)
+	}
+	admin.expect(http.StatusBadRequest, http.MethodGet, "/api/analytics/donations?tz=Nowhere/Special", nil)
+
+	// Changes to donations are rolled up again
+	if _, err := s.conn.Exec(`DELETE FROM donations WHERE cause_id = $1`, otherCauseID); err != nil {
+		t.Fatal(err)
+	}
+	if err := analytics.NewService(s.store).RefreshRollups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	admin.expect(http.StatusOK, http.MethodGet, "/api/analytics/donations", nil).decode(t, &res)
+	if res.Total.Amount != "50.00" || res.Total.Count != 2 {
+		t.Errorf("after deleting a donation = %+v, want 50.00", res.Total)
+	}
+}
+
+func TestDonorCohorts(t *testing.T) {
+	s := newSuite(t)
+	organiser, organiserID := s.signUp("organiser")
+	s.verify(organiserID)
+	causeID := createCause(s, organiser, "Clean Rivers")
+	_, loyalID := s.signUp("loyal")
+	_, lapsedID := s.signUp("lapsed")
+	admin, adminID := s.signUp("admin")
+	s.promote(adminID)
+
+	// The loyal donor gives every month from three months ago; the lapsed one gave once, two years ago
+	thisMonth := time.Now().UTC()
+	thisMonth = time.Date(thisMonth.Year(), thisMonth.Month(), 1, 12, 0, 0, 0, time.UTC)
+	for i := 3; i >= 0; i-- {
+		s.donate(loyalID, causeID, thisMonth.AddDate(0, -i, 0))
+	}
+	s.donate(lapsedID, causeID, thisMonth.AddDate(-2, 0, 0))
+
+	organiser.expect(http.StatusForbidden, http.MethodGet, "/api/analytics/cohorts", nil)
+	admin.expect(http.StatusBadRequest, http.MethodGet, "/api/analytics/cohorts?from=2026-13", nil)
+
+	var cohorts struct {
+		Cohorts []struct {
+			Month     string `json:"month"`
+			Donors    int64  `json:"donors"`
+			Retention []struct {
+				Rate float64 `json:"rate"`
+			} `json:"retention"`
+		} `json:"cohorts"`
+	}
+	admin.expect(http.StatusOK, http.MethodGet, "/api/analytics/cohorts", nil).decode(t, &cohorts)
+	if len(cohorts.Cohorts) != 1 || cohorts.Cohorts[0].Donors != 1 || len(cohorts.Cohorts[0].Retention) != 4 || cohorts.Cohorts[0].Retention[3].Rate != 1 {
+		t.Errorf("cohorts = %+v, want the loyal donor retained every month", cohorts)
+	}
+
+	var lapsed struct {
+		Lapsed       int64 `json:"lapsed"`
+		LapsedDonors []struct {
+			UserID int32 `json:"user_id"`
+		} `json:"lapsed_donors"`
+	}
+	admin.expect(http.StatusOK, http.MethodGet, "/api/analytics/lapsed-donors", nil).decode(t, &lapsed)
+	if lapsed.Lapsed != 1 || lapsed.LapsedDonors[0].UserID != lapsedID {
+		t.Errorf("lapsed = %+v, want the lapsed donor", lapsed)
+	}
+
+	res := admin.expect(http.StatusOK, http.MethodGet, "/api/analytics/recurring-donors?from=2020-01&format=csv", nil)
+	if res.header.Get("Content-Type") != "text/csv; charset=utf-8" || !strings.Contains(string(res.body), "\nrecurring,1,4,40.00,") {
+		t.Errorf("recurring CSV = %s %q", res.header.Get("Content-Type"), res.body)
+	}
+
+	// Joining a team records when, so giving before and after can be compared
+	if _, err := s.conn.Exec(`INSERT INTO teams (name) VALUES ('Paddlers')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.conn.Exec(`INSERT INTO user_team (user_id, team_id, role, joined_at) SELECT $1, id, 'member', $2 FROM teams WHERE name = 'Paddlers'`, loyalID, thisMonth.AddDate(0, -2, 0).Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	var teams struct {
+		JoinedAfterFirstGift struct {
+			Donors          int64   `json:"donors"`
+			ActiveRateAfter float64 `json:"active_rate_after"`
+		} `json:"joined_after_first_gift"`
+	}
+	admin.expect(http.StatusOK, http.MethodGet, "/api/analytics/team-retention", nil).decode(t, &teams)
+	if teams.JoinedAfterFirstGift.Donors != 1 || teams.JoinedAfterFirstGift.ActiveRateAfter != 1 {
+		t.Errorf("team retention = %+v", teams)
+	}
+}
+
+// donate records a donation made at a given time
+func (s *suite) donate(userID, causeID int32, at time.Time) {
+	s.t.Helper()
+	if _, err := s.conn.Exec(`INSERT INTO donations (user_id, cause_id, amount, donation_type, status, created_at) VALUES ($1, $2, 10, 'money', 'completed', $3)`, userID, causeID, at); err != nil {
+		s.t.Fatal(err)
+	}
+}
@@ -0,0 +1,32 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIKeysFollowTwoFactorPolicy(t *testing.T) {
+	s := newSuite(t)
+	alice, aliceID := s.signUp("alice")
+	team := fmt.Sprintf("/api/teams/%d", createTeam(s, alice, "Green Runners"))
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	alice.expect(http.StatusCreated, http.MethodPost, "/api/api-keys", gin.H{"name": "reporting", "scopes": []string{"teams:read"}}).decode(t, &created)
+	script := s.newClient()
+	script.token = created.Key
+	script.expect(http.StatusOK, http.MethodGet, team, nil)
+
+	// Once the owner's role requires two-factor, their keys stop working until they enable it
+	if _, err := s.conn.Exec(`UPDATE users SET user_role = 'organiser' WHERE id = $1`, aliceID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.conn.Exec(`INSERT INTO role_policies (user_role, require_2fa) VALUES ('organiser', true)`); err != nil {
+		t.Fatal(err)
+	}
+	script.expect(http.StatusUnauthorized, http.MethodGet, team, nil)
+}
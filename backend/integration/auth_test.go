@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSignUpSetsSessionCookie(t *testing.T) {
+	s := newSuite(t)
+	c := s.newClient()
+
+	res := c.expect(http.StatusCreated, http.MethodPost, "/api/signup", gin.H{
+		"username":   "alice",
+		"email":      email("alice"),
+		"password":   password,
+		"first_name": "Alice",
+		"last_name":  "Liddell",
+	})
+
+	cookies := (&http.Response{Header: res.header}).Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "token" {
+		t.Fatalf("sign-up set cookies %v, want one session token", cookies)
+	}
+	if cookie := cookies[0]; !cookie.HttpOnly || cookie.Path != "/" || cookie.MaxAge != 24*60*60 {
+		t.Errorf("session cookie = %+v, want an HTTP-only cookie on / lasting a day", cookie)
+	}
+
+	// The cookie alone authenticates later requests
+	var me struct {
+		Email string `json:"email"`
+	}
+	c.expect(http.StatusOK, http.MethodGet, "/api/user/me", nil).decode(t, &me)
+	if me.Email != email("alice") {
+		t.Errorf("current user = %q, want %q", me.Email, email("alice"))
+	}
+
+	if got := s.mailer.recipients(); len(got) != 1 || got[0] != email("alice") {
+		t.Errorf("emailed %v, want a verification email to alice", got)
+	}
+	if n := s.count(`SELECT count(*) FROM user_tokens`); n != 1 {
+		t.Errorf("%d session tokens stored, want 1", n)
+	}
+}
+
+func TestSignUpRejectsTakenEmail(t *testing.T) {
+	s := newSuite(t)
+	s.signUp("alice")
+
+	s.newClient().expect(http.StatusConflict, http.MethodPost, "/api/signup", gin.H{
+		"username":   "alice2",
+		"email":      email("alice"),
+		"password":   password,
+		"first_name": "Alice",
+		"last_name":  "Again",
+	})
+	if n := s.count(`SELECT count(*) FROM users`); n != 1 {
+		t.Errorf("%d users stored, want 1", n)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	s := newSuite(t)
+	_, userID := s.signUp("alice")
+
+	// A new browser logs in and authenticates with the returned token
+	c := s.newClient()
+	c.expect(http.StatusUnauthorized, http.MethodGet, "/api/user/me", nil)
+	var login struct {
+		ID    int32  `json:"id"`
+		Token string `json:"token"`
+	}
+	c.expect(http.StatusOK, http.MethodPost, "/api/login", gin.H{"email": email("alice"), "password": password}).decode(t, &login)
+	if login.ID != userID || login.Token == "" {
+		t.Fatalf("login = %+v, want a token for user %d", login, userID)
+	}
+
+	c.token = login.Token
+	c.expect(http.StatusOK, http.MethodGet, "/api/user/me", nil)
+
+	if n := s.count(`SELECT count(*) FROM security_events WHERE user_id = $1 AND event_type = 'login_success'`, userID); n != 1 {
+		t.Errorf("%d login_success events, want 1", n)
+	}
+}
+
+func TestLoginBacksOffAfterFailures(t *testing.T) {
+	s := newSuite(t)
+	s.signUp("alice")
+	c := s.newClient()
+
+	wrong := gin.H{"email": email("alice"), "password": "not the password"}
+	// The first few failures are free; the one after them starts the back-off
+	for i := 0; i <= 3; i++ {
+		c.expect(http.StatusUnauthorized, http.MethodPost, "/api/login", wrong)
+	}
+
+	// The counters live in Postgres, so the next attempt waits even with the right password
+	res := c.expect(http.StatusTooManyRequests, http.MethodPost, "/api/login", gin.H{"email": email("alice"), "password": password})
+	if res.header.Get("Retry-After") == "" {
+		t.Error("throttled login has no Retry-After header")
+	}
+}
+
+func TestSessionIsRequired(t *testing.T) {
+	s := newSuite(t)
+	c := s.newClient()
+
+	c.expect(http.StatusUnauthorized, http.MethodGet, "/api/teams/1", nil)
+
+	// A forged cookie is refused too
+	base, _ := url.Parse(s.url)
+	c.http.Jar.SetCookies(base, []*http.Cookie{{Name: "token", Value: "forged"}})
+	c.expect(http.StatusUnauthorized, http.MethodGet, "/api/teams/1", nil)
+}
+
+func TestTwoFactorChangesAreAudited(t *testing.T) {
+	s := newSuite(t)
+	alice, aliceID := s.signUp("alice")
+
+	var enrolment struct {
+		Secret string `json:"secret"`
+	}
+	alice.expect(http.StatusOK, http.MethodPost, "/api/2fa/enroll", nil).decode(t, &enrolment)
+	step := util.TOTPStep(time.Now())
+	alice.expect(http.StatusOK, http.MethodPost, "/api/2fa/enable", gin.H{"code": totpCode(t, enrolment.Secret, step)})
+	// Each code is accepted once, so turning it off again takes the next one
+	alice.expect(http.StatusOK, http.MethodPost, "/api/2fa/disable", gin.H{"password": password, "code": totpCode(t, enrolment.Secret, step+1)})
+
+	for _, action := range []string{"create", "delete"} {
+		if n := s.count(`SELECT count(*) FROM audit_log WHERE entity_type = 'two_factor' AND action = $1 AND actor_id = $2`, action, aliceID); n != 1 {
+			t.Errorf("%d two-factor %s entries, want 1", n, action)
+		}
+	}
+	if n := s.count(`SELECT count(*) FROM audit_log WHERE entity_type = 'two_factor' AND (before::text LIKE '%secret%' OR after::text LIKE '%secret%')`); n != 0 {
+		t.Errorf("%d two-factor entries record the secret", n)
+	}
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	code, err := util.TOTPCode(secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
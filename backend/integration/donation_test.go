@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createCause has a verified organiser open a cause and returns its ID
+func createCause(s *suite, organiser *client, name string) int32 {
+	s.t.Helper()
+	start := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	var cause struct {
+		ID int32 `json:"id"`
+	}
+	organiser.expect(http.StatusOK, http.MethodPost, "/api/causes", gin.H{
+		"name":       name,
+		"goal":       5000,
+		"start_date": start,
+		"end_date":   start.AddDate(0, 1, 0),
+		"status":     "active",
+		"category":   "environment",
+	}).decode(s.t, &cause)
+	return cause.ID
+}
+
+func TestDonationFlow(t *testing.T) {
+	s := newSuite(t)
+	organiser, organiserID := s.signUp("organiser")
+	s.verify(organiserID)
+	causeID := createCause(s, organiser, "Clean Rivers")
+
+	donor, donorID := s.signUp("donor")
+	donation := gin.H{"user_id": donorID, "cause_id": causeID, "amount": 25.5, "donation_type": "money"}
+
+	var created struct {
+		ID     int32 `json:"id"`
+		Amount struct {
+			String string `json:"String"`
+		} `json:"amount"`
+	}
+	donor.expect(http.StatusOK, http.MethodPost, "/api/donations", donation).decode(t, &created)
+	if created.Amount.String != "25.50" {
+		t.Errorf("stored amount = %q, want 25.50 from the numeric column", created.Amount.String)
+	}
+
+	// Large gifts wait for a verified email address
+	donation["amount"] = largeDonation
+	donor.expect(http.StatusForbidden, http.MethodPost, "/api/donations", donation)
+	s.verify(donorID)
+	donor.expect(http.StatusOK, http.MethodPost, "/api/donations", donation)
+
+	// Donations must point at an existing cause
+	donation["cause_id"] = causeID + 1000
+	if res := donor.do(http.MethodPost, "/api/donations", donation, nil); res.status < 400 {
+		t.Errorf("donation to a missing cause got status %d", res.status)
+	}
+
+	donor.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/api/donations/%d", created.ID), nil)
+	donor.expect(http.StatusNotFound, http.MethodGet, "/api/donations/999999", nil)
+
+	var donations []struct {
+		CauseID struct {
+			Int32 int32 `json:"Int32"`
+		} `json:"cause_id"`
+	}
+	donor.expect(http.StatusOK, http.MethodGet, "/api/listDonations", gin.H{"limit": 10}).decode(t, &donations)
+	if len(donations) != 2 {
+		t.Fatalf("listDonations returned %d donations, want 2", len(donations))
+	}
+	for _, d := range donations {
+		if d.CauseID.Int32 != causeID {
+			t.Errorf("donation to cause %d, want %d", d.CauseID.Int32, causeID)
+		}
+	}
+
+	if n := s.count(`SELECT count(*) FROM audit_log WHERE entity_type = 'donation' AND actor_id = $1`, donorID); n != 2 {
+		t.Errorf("%d donations audited against the donor, want 2", n)
+	}
+}
+
+func TestCauseRequiresVerifiedOrganiser(t *testing.T) {
+	s := newSuite(t)
+	organiser, organiserID := s.signUp("organiser")
+
+	start := time.Now().UTC().Truncate(time.Second)
+	cause := gin.H{"name": "Clean Rivers", "goal": 100, "start_date": start, "end_date": start.AddDate(0, 1, 0), "status": "active"}
+	organiser.expect(http.StatusForbidden, http.MethodPost, "/api/causes", cause)
+
+	s.verify(organiserID)
+	var created struct {
+		OwnerID struct {
+			Int32 int32 `json:"Int32"`
+		} `json:"owner_id"`
+	}
+	organiser.expect(http.StatusOK, http.MethodPost, "/api/causes", cause).decode(t, &created)
+	if created.OwnerID.Int32 != organiserID {
+		t.Errorf("cause owned by %d, want %d", created.OwnerID.Int32, organiserID)
+	}
+}
@@ -0,0 +1,269 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"play4good-backend/controllers"
+	"play4good-backend/db/migration"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/health"
+	"play4good-backend/routes"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+)
+
+// largeDonation is the threshold above which donors must have verified their email
+const largeDonation = 1000
+
+// suite is one test's view of the application: the full router served over HTTP, on a schema of
+// its own with every migration applied
+type suite struct {
+	t      *testing.T
+	conn   *sql.DB
+	store  *db.Store
+	mailer *mailbox
+	url    string
+}
+
+func newSuite(t *testing.T) *suite {
+	t.Helper()
+	if unavailable != nil {
+		t.Skipf("no postgres for integration tests: %v", unavailable)
+	}
+
+	schema := createSchema(t)
+	url := withSearchPath(t, server.url, schema)
+	migrate(t, url)
+	conn, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("opening schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	gin.SetMode(gin.TestMode)
+	config := util.Config{
+		LargeDonationThreshold: largeDonation,
+		FrontendURL:            "http://play4good.test",
+	}
+	store := db.NewStore(conn)
+	mailer := &mailbox{}
+	controller := controllers.NewPlay4GoodController(store, config, mailer)
+	httpServer := httptest.NewServer(routes.NewRouter(controller, health.NewChecker(conn, migration.Latest())))
+	t.Cleanup(httpServer.Close)
+
+	return &suite{t: t, conn: conn, store: store, mailer: mailer, url: httpServer.URL}
+}
+
+// migrate applies every migration to a schema on a pool of its own, the way a deploy runs them
+// before the server starts
+func migrate(t *testing.T, url string) {
+	t.Helper()
+	conn, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("opening a pool for migrations: %v", err)
+	}
+	defer conn.Close()
+	if err := migration.Up(conn); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+}
+
+// createSchema makes an empty schema for one test and drops it, with everything in it, afterwards
+func createSchema(t *testing.T) string {
+	t.Helper()
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatal(err)
+	}
+	schema := "test_" + hex.EncodeToString(suffix)
+
+	admin, err := sql.Open("postgres", server.url)
+	if err != nil {
+		t.Fatalf("connecting to postgres: %v", err)
+	}
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		admin.Close()
+		t.Fatalf("creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		defer admin.Close()
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("dropping schema %s: %v", schema, err)
+		}
+	})
+	return schema
+}
+
+// withSearchPath points every connection made with dsn at schema. lib/pq sends parameters it does
+// not know itself to the server as settings.
+func withSearchPath(t *testing.T, dsn, schema string) string {
+	t.Helper()
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("parsing database URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// verify marks a user's email address as verified, as following the emailed link would
+func (s *suite) verify(userID int32) {
+	s.t.Helper()
+	if _, err := s.store.MarkUserEmailVerified(context.Background(), userID); err != nil {
+		s.t.Fatalf("verifying user %d: %v", userID, err)
+	}
+}
+
+// count runs a COUNT query against the test's schema
+func (s *suite) count(query string, args ...interface{}) int {
+	s.t.Helper()
+	var n int
+	if err := s.conn.QueryRow(query, args...).Scan(&n); err != nil {
+		s.t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+// mailbox records emails instead of sending them
+type mailbox struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (m *mailbox) Send(to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, to)
+	return nil
+}
+
+// recipients lists who has been emailed, in order
+func (m *mailbox) recipients() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.sent...)
+}
+
+// client is one browser: it keeps the cookies the API sets and can also send a bearer token
+type client struct {
+	s     *suite
+	http  *http.Client
+	token string
+}
+
+func (s *suite) newClient() *client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return &client{s: s, http: &http.Client{Jar: jar}}
+}
+
+// signUp creates an account through the API and returns a client holding its session cookie
+func (s *suite) signUp(username string) (*client, int32) {
+	s.t.Helper()
+	c := s.newClient()
+	res := c.expect(http.StatusCreated, http.MethodPost, "/api/signup", gin.H{
+		"username":   username,
+		"email":      email(username),
+		"password":   password,
+		"first_name": "Test",
+		"last_name":  "User",
+	})
+	var user struct {
+		ID int32 `json:"id"`
+	}
+	res.decode(s.t, &user)
+	return c, user.ID
+}
+
+const password = "correct horse battery"
+
+func email(username string) string {
+	return username + "@play4good.test"
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (r *response) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		t.Fatalf("decoding %s: %v", r.body, err)
+	}
+}
+
+// do sends a request with body encoded as JSON. header adds to or overrides the defaults.
+func (c *client) do(method, path string, body interface{}, header http.Header) *response {
+	c.s.t.Helper()
+
+	var payload io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			c.s.t.Fatalf("encoding %s %s body: %v", method, path, err)
+		}
+		payload = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, c.s.url+path, payload)
+	if err != nil {
+		c.s.t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		c.s.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.s.t.Fatalf("%s %s: reading body: %v", method, path, err)
+	}
+	return &response{status: res.StatusCode, header: res.Header, body: data}
+}
+
+// expect sends a request and fails the test unless the answer has status
+func (c *client) expect(status int, method, path string, body interface{}) *response {
+	c.s.t.Helper()
+	return c.expectWith(status, method, path, body, nil)
+}
+
+func (c *client) expectWith(status int, method, path string, body interface{}, header http.Header) *response {
+	c.s.t.Helper()
+	res := c.do(method, path, body, header)
+	if res.status != status {
+		c.s.t.Fatalf("%s %s: got status %d, want %d: %s", method, path, res.status, status, res.body)
+	}
+	return res
+}
+
+// ifMatch is the header for a conditional update against an ETag
+func ifMatch(etag string) http.Header {
+	return http.Header{"If-Match": {etag}}
+}
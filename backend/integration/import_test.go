@@ -0,0 +1,216 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"play4good-backend/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// promote gives a user the admin role
+func (s *suite) promote(userID int32) {
+	s.t.Helper()
+	if _, err := s.conn.Exec(`UPDATE users SET user_role = 'admin' WHERE id = $1`, userID); err != nil {
+		s.t.Fatalf("promoting user %d: %v", userID, err)
+	}
+}
+
+// upload posts a file as the multipart form of an import
+func (c *client) upload(status int, path, filename, body string, fields map[string]string) *response {
+	c.s.t.Helper()
+	var form bytes.Buffer
+	w := multipart.NewWriter(&form)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		c.s.t.Fatal(err)
+	}
+	io.WriteString(part, body)
+	for name, value := range fields {
+		w.WriteField(name, value)
+	}
+	w.Close()
+
+	req, err := http.NewRequest(http.MethodPost, c.s.url+path, &form)
+	if err != nil {
+		c.s.t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	res, err := c.http.Do(req)
+	if err != nil {
+		c.s.t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+	if res.StatusCode != status {
+		c.s.t.Fatalf("uploading %s: got status %d, want %d: %s", filename, res.StatusCode, status, data)
+	}
+	return &response{status: res.StatusCode, header: res.Header, body: data}
+}
+
+func TestDonationImport(t *testing.T) {
+	s := newSuite(t)
+	admin, adminID := s.signUp("admin")
+	s.verify(adminID)
+	s.promote(adminID)
+	causeID := createCause(s, admin, "Clean Rivers")
+	s.signUp("alice")
+	bob, _ := s.signUp("bob")
+
+	rows := []string{"Donor,Cause,Gift,donation_type,donated_at"}
+	for i := 0; i < 5; i++ {
+		rows = append(rows, fmt.Sprintf("%s,%d,\"1,000.%02d\",money,2026-01-%02d", email("alice"), causeID, i, i+1))
+	}
+	rows = append(rows, fmt.Sprintf("%s,%d,12,cash,", email("bob"), causeID))
+	file := strings.Join(rows, "\n")
+	fields := map[string]string{"source": "winter-gala", "mapping": `{"user_email": "Donor", "cause_id": "Cause", "amount": "gift"}`}
+
+	var report struct {
+		Import *struct {
+			ID int32 `json:"id"`
+		} `json:"import"`
+		Report struct {
+			ValidRows int `json:"valid_rows"`
+			Errors    []struct {
+				Row int `json:"row"`
+			} `json:"errors"`
+		} `json:"report"`
+	}
+
+	// A dry run reports the bad row and stores nothing
+	fields["dry_run"] = "true"
+	admin.upload(http.StatusOK, "/api/admin/donation-imports", "gala.csv", file, fields).decode(t, &report)
+	if report.Import != nil || report.Report.ValidRows != 5 || len(report.Report.Errors) != 1 || report.Report.Errors[0].Row != 7 {
+		t.Fatalf("dry run = %+v, want 5 valid rows and an error on row 7", report)
+	}
+	if n := s.count(`SELECT count(*) FROM donation_imports`); n != 0 {
+		t.Errorf("dry run stored %d imports", n)
+	}
+
+	delete(fields, "dry_run")
+	admin.upload(http.StatusCreated, "/api/admin/donation-imports", "gala.csv", file, fields).decode(t, &report)
+	batch := fmt.Sprintf("/api/admin/donation-imports/%d", report.Import.ID)
+
+	// Skipping the invalid row has to be asked for
+	admin.expect(http.StatusUnprocessableEntity, http.MethodPost, batch+"/commit", gin.H{})
+	var committed struct {
+		Status       string `json:"status"`
+		ImportedRows int    `json:"imported_rows"`
+	}
+	donations, raised := testutil.ToFloat64(metrics.DonationsCreated.WithLabelValues("money")), testutil.ToFloat64(metrics.AmountRaised.WithLabelValues("money"))
+	admin.expect(http.StatusOK, http.MethodPost, batch+"/commit", gin.H{"mode": "chunked", "chunk_size": 2, "skip_invalid": true}).decode(t, &committed)
+	if committed.Status != "completed" || committed.ImportedRows != 5 {
+		t.Errorf("commit = %+v, want 5 rows completed", committed)
+	}
+	// Imported donations count towards the donation metrics like any other
+	if n := testutil.ToFloat64(metrics.DonationsCreated.WithLabelValues("money")) - donations; n != 5 {
+		t.Errorf("donation metric rose by %v, want 5", n)
+	}
+	if sum := testutil.ToFloat64(metrics.AmountRaised.WithLabelValues("money")) - raised; sum < 5000.09 || sum > 5000.11 {
+		t.Errorf("amount raised rose by %v, want 5000.10", sum)
+	}
+	if n := s.count(`SELECT count(*) FROM donations WHERE source = 'winter-gala' AND import_id = $1 AND created_at < '2026-02-01'`, report.Import.ID); n != 5 {
+		t.Errorf("%d donations tagged with the batch, want 5", n)
+	}
+	if n := s.count(`SELECT count(*) FROM donation_import_rows`); n != 0 {
+		t.Errorf("%d staged rows left after the commit", n)
+	}
+
+	// Committing again is a no-op
+	admin.expect(http.StatusOK, http.MethodPost, batch+"/commit", gin.H{"skip_invalid": true})
+	if n := s.count(`SELECT count(*) FROM donations`); n != 5 {
+		t.Errorf("%d donations after a second commit, want 5", n)
+	}
+
+	// Only admins see imports
+	bob.expect(http.StatusForbidden, http.MethodGet, batch, nil)
+
+	// Receipts for rolled back donations are kept, but voided
+	var imported int32
+	if err := s.conn.QueryRow(`SELECT min(id) FROM donations`).Scan(&imported); err != nil {
+		t.Fatal(err)
+	}
+	admin.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/api/donations/%d/receipt", imported), nil)
+
+	admin.expect(http.StatusOK, http.MethodPost, batch+"/rollback", nil)
+	if n := s.count(`SELECT count(*) FROM donations`); n != 0 {
+		t.Errorf("%d donations left after the rollback", n)
+	}
+	if n := s.count(`SELECT count(*) FROM receipts WHERE donation_id = $1 AND voided_at IS NOT NULL`, imported); n != 1 {
+		t.Errorf("%d voided receipts for the rolled back donation, want 1", n)
+	}
+	admin.expect(http.StatusConflict, http.MethodPost, batch+"/rollback", nil)
+	admin.expect(http.StatusConflict, http.MethodPost, batch+"/commit", gin.H{"skip_invalid": true})
+	if n := s.count(`SELECT count(*) FROM audit_log WHERE entity_type = 'donation_import'`); n != 3 {
+		t.Errorf("%d import changes audited, want staging, completion and rollback", n)
+	}
+}
+
+func TestCauseAndTeamImport(t *testing.T) {
+	s := newSuite(t)
+	admin, adminID := s.signUp("admin")
+	s.verify(adminID)
+	s.promote(adminID)
+
+	causes := strings.Join([]string{
+		"Name,Goal,Start,End,Status,Category",
+		"Clean Rivers,\"5,000\",2026-01-01,2026-12-31,Active,environment",
+		"Tree Planting,2000,2026-03-01,2026-02-01,active,environment",
+		"Food Bank,1000,2026-01-01,2026-06-30,paused,",
+	}, "\n")
+	fields := map[string]string{"mapping": `{"start_date": "Start", "end_date": "End"}`}
+
+	var result struct {
+		Causes []struct {
+			ID   int32  `json:"id"`
+			Name string `json:"name"`
+		} `json:"causes"`
+		Report struct {
+			ValidRows int `json:"valid_rows"`
+			Errors    []struct {
+				Row    int `json:"row"`
+				Errors []struct {
+					Field string `json:"field"`
+				} `json:"errors"`
+			} `json:"errors"`
+		} `json:"report"`
+	}
+	fields["dry_run"] = "true"
+	admin.upload(http.StatusOK, "/api/admin/cause-imports", "causes.csv", causes, fields).decode(t, &result)
+	if result.Report.ValidRows != 1 || len(result.Report.Errors) != 2 ||
+		result.Report.Errors[0].Errors[0].Field != "end_date" || result.Report.Errors[1].Errors[0].Field != "status" {
+		t.Fatalf("dry run = %+v, want an end date error on row 3 and a status error on row 4", result.Report)
+	}
+
+	// Nothing is created while the file has invalid rows, unless they are skipped
+	delete(fields, "dry_run")
+	admin.upload(http.StatusUnprocessableEntity, "/api/admin/cause-imports", "causes.csv", causes, fields)
+	if n := s.count(`SELECT count(*) FROM causes`); n != 0 {
+		t.Fatalf("%d causes created from a file with invalid rows", n)
+	}
+	fields["skip_invalid"] = "true"
+	admin.upload(http.StatusCreated, "/api/admin/cause-imports", "causes.csv", causes, fields).decode(t, &result)
+	if len(result.Causes) != 1 || result.Causes[0].Name != "Clean Rivers" {
+		t.Fatalf("causes = %+v, want Clean Rivers", result.Causes)
+	}
+	if n := s.count(`SELECT count(*) FROM causes WHERE owner_id = $1 AND goal = 5000`, adminID); n != 1 {
+		t.Errorf("%d imported causes owned by the admin, want 1", n)
+	}
+
+	teams := "name,description,avatar_url\nRiver Runners,We run,https://play4good.test/runners.png\nXY,,\n"
+	admin.upload(http.StatusUnprocessableEntity, "/api/admin/team-imports", "teams.csv", teams, nil)
+	admin.upload(http.StatusCreated, "/api/admin/team-imports", "teams.csv", teams, map[string]string{"skip_invalid": "true"})
+	if n := s.count(`SELECT count(*) FROM teams WHERE name = 'River Runners'`); n != 1 {
+		t.Errorf("%d imported teams, want 1", n)
+	}
+	if n := s.count(`SELECT count(*) FROM audit_log WHERE entity_type IN ('cause', 'team') AND action = 'create'`); n != 2 {
+		t.Errorf("%d imported records audited, want 2", n)
+	}
+}
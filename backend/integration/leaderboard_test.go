@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLeaderboardListing(t *testing.T) {
+	s := newSuite(t)
+	alice, aliceID := s.signUp("alice")
+	_, bobID := s.signUp("bob")
+	teamID := createTeam(s, alice, "Green Runners")
+	join(s, alice, aliceID, teamID, "admin")
+	join(s, alice, bobID, teamID, "member")
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var ids []int32
+	for i, name := range []string{"Spring Challenge", "Summer Challenge", "Autumn Challenge"} {
+		var leaderboard struct {
+			ID int32 `json:"id"`
+		}
+		alice.expect(http.StatusOK, http.MethodPost, "/api/leaderboards", gin.H{
+			"name":       name,
+			"type":       "individual",
+			"start_date": start.AddDate(0, 3*i, 0),
+			"end_date":   start.AddDate(0, 3*i+3, 0),
+		}).decode(t, &leaderboard)
+		ids = append(ids, leaderboard.ID)
+	}
+
+	var page []struct {
+		ID   int32  `json:"id"`
+		Name string `json:"name"`
+	}
+	alice.expect(http.StatusOK, http.MethodPut, "/api/listLeaderBoards", gin.H{"limit": 2, "offset": 1}).decode(t, &page)
+	if len(page) != 2 || page[0].ID != ids[1] || page[1].ID != ids[2] {
+		t.Errorf("listLeaderBoards page = %+v, want leaderboards %v", page, ids[1:])
+	}
+	alice.expect(http.StatusBadRequest, http.MethodPut, "/api/listLeaderBoards", gin.H{})
+
+	// Scores are upserted per user and team
+	entries := fmt.Sprintf("/api/leaderboards/%d/entries", ids[0])
+	for _, entry := range []gin.H{
+		{"leaderboard_id": ids[0], "user_id": aliceID, "team_id": teamID, "score": 10},
+		{"leaderboard_id": ids[0], "user_id": bobID, "team_id": teamID, "score": 30},
+		{"leaderboard_id": ids[0], "user_id": aliceID, "team_id": teamID, "score": 45.5},
+	} {
+		alice.expect(http.StatusOK, http.MethodPut, entries, entry)
+	}
+
+	var score string
+	if err := s.conn.QueryRow(`SELECT score::text FROM leaderboard_entries WHERE leaderboard_id = $1 AND user_id = $2`, ids[0], aliceID).Scan(&score); err != nil {
+		t.Fatal(err)
+	}
+	if score != "45.50" {
+		t.Errorf("alice's score = %s, want 45.50", score)
+	}
+	if n := s.count(`SELECT count(*) FROM leaderboard_entries WHERE leaderboard_id = $1`, ids[0]); n != 2 {
+		t.Errorf("%d entries, want one each for alice and bob", n)
+	}
+
+	var leaderboard struct {
+		Name      string `json:"name"`
+		StartDate struct {
+			Time time.Time `json:"Time"`
+		} `json:"start_date"`
+	}
+	alice.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/api/leaderboards/%d", ids[0]), nil).decode(t, &leaderboard)
+	if leaderboard.Name != "Spring Challenge" || !leaderboard.StartDate.Time.Equal(start) {
+		t.Errorf("leaderboard = %+v, want the spring challenge from %s", leaderboard, start)
+	}
+}
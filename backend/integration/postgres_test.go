@@ -0,0 +1,158 @@
+package integration
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+// server is the Postgres every test in the package shares. When none could be found or started,
+// unavailable says why and the tests skip.
+var (
+	server      *postgres
+	unavailable error
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	// Request logs drown out test failures
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	if testing.Short() {
+		unavailable = errors.New("integration tests do not run with -short")
+	} else {
+		server, unavailable = startPostgres()
+	}
+
+	code := m.Run()
+	if server != nil {
+		if err := server.stop(); err != nil {
+			fmt.Fprintln(os.Stderr, "could not stop postgres:", err)
+		}
+	}
+	os.Exit(code)
+}
+
+// postgres is a running server and how to stop it
+type postgres struct {
+	url  string
+	stop func() error
+}
+
+func startPostgres() (*postgres, error) {
+	if url := os.Getenv("TEST_DB_SOURCE"); url != "" {
+		return &postgres{url: url, stop: func() error { return nil }}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "play4good-postgres-")
+	if err != nil {
+		return nil, err
+	}
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	var pg *postgres
+	if bin, ok := localPostgres(); ok {
+		pg, err = startLocal(bin, dir, port)
+	} else {
+		pg, err = startEmbedded(dir, port)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	stop := pg.stop
+	pg.stop = func() error {
+		defer os.RemoveAll(dir)
+		return stop()
+	}
+	return pg, nil
+}
+
+// localPostgres finds the directory holding an installed server's binaries
+func localPostgres() (string, bool) {
+	if path, err := exec.LookPath("pg_ctl"); err == nil {
+		return filepath.Dir(path), true
+	}
+	if out, err := exec.Command("pg_config", "--bindir").Output(); err == nil {
+		dir := strings.TrimSpace(string(out))
+		if _, err := os.Stat(filepath.Join(dir, "pg_ctl")); err == nil {
+			return dir, true
+		}
+	}
+	// Debian and Ubuntu keep the server binaries off PATH; take the newest version
+	matches, _ := filepath.Glob("/usr/lib/postgresql/*/bin/pg_ctl")
+	if len(matches) == 0 {
+		return "", false
+	}
+	sort.Strings(matches)
+	return filepath.Dir(matches[len(matches)-1]), true
+}
+
+// startLocal creates a cluster in dir with initdb and runs it on port with durability turned off
+func startLocal(bin, dir string, port int) (*postgres, error) {
+	data := filepath.Join(dir, "data")
+	if err := run(filepath.Join(bin, "initdb"), "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync"); err != nil {
+		return nil, err
+	}
+
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -c fsync=off -c full_page_writes=off", port, dir)
+	if err := run(filepath.Join(bin, "pg_ctl"), "-D", data, "-o", options, "-l", filepath.Join(dir, "postgres.log"), "-w", "start"); err != nil {
+		return nil, err
+	}
+
+	return &postgres{
+		url: fmt.Sprintf("postgresql://postgres@127.0.0.1:%d/postgres?sslmode=disable", port),
+		stop: func() error {
+			return run(filepath.Join(bin, "pg_ctl"), "-D", data, "-m", "immediate", "-w", "stop")
+		},
+	}, nil
+}
+
+// startEmbedded runs Postgres from the embedded-postgres binaries, which are downloaded once and
+// cached in the user's home directory
+func startEmbedded(dir string, port int) (*postgres, error) {
+	var log bytes.Buffer
+	config := embeddedpostgres.DefaultConfig().
+		Port(uint32(port)).
+		RuntimePath(filepath.Join(dir, "runtime")).
+		StartParameters(map[string]string{"fsync": "off", "full_page_writes": "off"}).
+		Logger(&log)
+
+	db := embeddedpostgres.NewDatabase(config)
+	if err := db.Start(); err != nil {
+		return nil, fmt.Errorf("no local postgres and the embedded one did not start: %w\n%s", err, log.String())
+	}
+	return &postgres{url: config.GetConnectionURL() + "?sslmode=disable", stop: db.Stop}, nil
+}
+
+func run(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w\n%s", filepath.Base(name), err, out)
+	}
+	return nil
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"play4good-backend/privacy"
+	"play4good-backend/util"
+)
+
+func TestDataExports(t *testing.T) {
+	s := newSuite(t)
+	alice, aliceID := s.signUp("alice")
+	bob, _ := s.signUp("bob")
+
+	var export struct {
+		ID     int32  `json:"id"`
+		Status string `json:"status"`
+	}
+	alice.expect(http.StatusAccepted, http.MethodPost, "/api/user/me/exports", nil).decode(t, &export)
+	download := fmt.Sprintf("/api/user/me/exports/%d/download", export.ID)
+	alice.expect(http.StatusConflict, http.MethodGet, download, nil)
+
+	// An instance claimed the export and stopped before finishing it
+	if _, err := s.conn.Exec(`UPDATE data_exports SET status = 'running', started_at = now() - interval '1 hour' WHERE id = $1`, export.ID); err != nil {
+		t.Fatal(err)
+	}
+	worker := privacy.NewService(s.store, util.Config{ExportTTL: time.Hour})
+	if err := worker.ProcessExports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.count(`SELECT count(*) FROM data_export_archives WHERE export_id = $1`, export.ID); n != 1 {
+		t.Fatalf("%d archives stored for the reclaimed export, want 1", n)
+	}
+
+	// The archive comes from the database, whichever instance built it
+	res := alice.expect(http.StatusOK, http.MethodGet, download, nil)
+	archive, err := zip.NewReader(bytes.NewReader(res.body), int64(len(res.body)))
+	if err != nil {
+		t.Fatalf("download is not a ZIP archive: %v", err)
+	}
+	if len(archive.File) == 0 || archive.File[0].Name != "profile.json" {
+		t.Errorf("archive holds %d files, want profile.json first", len(archive.File))
+	}
+	bob.expect(http.StatusNotFound, http.MethodGet, download, nil)
+
+	// Past its window the export is gone, even before the cleanup job removes it
+	if _, err := s.conn.Exec(`UPDATE data_exports SET expires_at = now() - interval '1 minute' WHERE id = $1`, export.ID); err != nil {
+		t.Fatal(err)
+	}
+	alice.expect(http.StatusGone, http.MethodGet, download, nil)
+	if err := worker.RemoveExpiredExports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.count(`SELECT count(*) FROM data_exports WHERE user_id = $1`, aliceID); n != 0 {
+		t.Errorf("%d expired exports left", n)
+	}
+	if n := s.count(`SELECT count(*) FROM data_export_archives`); n != 0 {
+		t.Errorf("%d archives left after the cleanup", n)
+	}
+}
+
+func TestErasureRequestsAreAudited(t *testing.T) {
+	s := newSuite(t)
+	alice, aliceID := s.signUp("alice")
+
+	alice.expect(http.StatusAccepted, http.MethodPost, "/api/user/me/erasure", nil)
+	alice.expect(http.StatusOK, http.MethodDelete, "/api/user/me/erasure", nil)
+	alice.expect(http.StatusNotFound, http.MethodDelete, "/api/user/me/erasure", nil)
+
+	if n := s.count(`SELECT count(*) FROM audit_log WHERE entity_type = 'user' AND entity_id = $1 AND actor_id = $2 AND action = 'update' AND diff ? 'erasure_scheduled_for'`, fmt.Sprint(aliceID), aliceID); n != 2 {
+		t.Errorf("%d audited erasure changes, want the request and its cancellation", n)
+	}
+}
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDonationReceipts(t *testing.T) {
+	s := newSuite(t)
+	organiser, organiserID := s.signUp("organiser")
+	s.verify(organiserID)
+	causeID := createCause(s, organiser, "Clean Rivers")
+	donor, donorID := s.signUp("donor")
+	other, _ := s.signUp("other")
+	admin, adminID := s.signUp("admin")
+	s.promote(adminID)
+
+	var donation struct {
+		ID int32 `json:"id"`
+	}
+	donor.expect(http.StatusOK, http.MethodPost, "/api/donations", gin.H{"user_id": donorID, "cause_id": causeID, "amount": 25.5, "donation_type": "money"}).decode(t, &donation)
+	path := fmt.Sprintf("/api/donations/%d/receipt", donation.ID)
+	year := time.Now().UTC().Year()
+
+	// Cause branding applies to receipts issued after it is saved
+	donor.expect(http.StatusForbidden, http.MethodPut, fmt.Sprintf("/api/causes/%d/receipt-template", causeID), gin.H{"organization_name": "Not mine"})
+	organiser.expect(http.StatusBadRequest, http.MethodPut, fmt.Sprintf("/api/causes/%d/receipt-template", causeID), gin.H{"accent_color": "teal"})
+	organiser.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("/api/causes/%d/receipt-template", causeID), gin.H{"organization_name": "Clean Rivers Trust", "accent_color": "#0055aa"})
+	admin.expect(http.StatusOK, http.MethodPut, "/api/admin/receipt-template", gin.H{"organization_name": "Play4Good Foundation", "registration_number": "CH-1234"})
+
+	// The first download issues the receipt and later ones get the same bytes
+	first := donor.expect(http.StatusOK, http.MethodGet, path, nil)
+	if !bytes.HasPrefix(first.body, []byte("%PDF-")) || first.header.Get("Content-Type") != "application/pdf" {
+		t.Fatalf("receipt is not a PDF: %s %q", first.header.Get("Content-Type"), first.body[:min(len(first.body), 16)])
+	}
+	wantName := fmt.Sprintf(`attachment; filename="R-%d-000001.pdf"`, year)
+	if got := first.header.Get("Content-Disposition"); got != wantName {
+		t.Errorf("Content-Disposition = %q, want %q", got, wantName)
+	}
+	if again := organiser.expect(http.StatusOK, http.MethodGet, path, nil); !bytes.Equal(again.body, first.body) {
+		t.Error("a second download gave a different PDF")
+	}
+	other.expect(http.StatusForbidden, http.MethodGet, path, nil)
+
+	// Issued receipts cannot be edited or deleted, only voided
+	if _, err := s.conn.Exec(`UPDATE receipts SET amount = 1`); err == nil {
+		t.Error("changing an issued receipt succeeded")
+	}
+	if _, err := s.conn.Exec(`DELETE FROM receipts`); err == nil {
+		t.Error("deleting a receipt succeeded")
+	}
+
+	voidPath := fmt.Sprintf("/api/admin/donations/%d/receipt/void", donation.ID)
+	reissuePath := fmt.Sprintf("/api/admin/donations/%d/receipt/reissue", donation.ID)
+	donor.expect(http.StatusForbidden, http.MethodPost, voidPath, gin.H{"reason": "refund"})
+	admin.expect(http.StatusOK, http.MethodPost, voidPath, gin.H{"reason": "Refunded"})
+	admin.expect(http.StatusConflict, http.MethodPost, voidPath, gin.H{"reason": "Refunded"})
+	donor.expect(http.StatusConflict, http.MethodGet, path, nil)
+
+	var reissued struct {
+		Number   string `json:"number"`
+		Replaces *int32 `json:"replaces"`
+	}
+	admin.expect(http.StatusCreated, http.MethodPost, reissuePath, gin.H{"reason": "Refund reversed"}).decode(t, &reissued)
+	if reissued.Number != fmt.Sprintf("R-%d-000002", year) || reissued.Replaces == nil {
+		t.Errorf("re-issued receipt = %+v, want the next number replacing the first", reissued)
+	}
+	if res := donor.expect(http.StatusOK, http.MethodGet, path, nil); bytes.Equal(res.body, first.body) {
+		t.Error("the re-issued receipt is the voided PDF")
+	}
+
+	// A statement is re-issued when the year's donations change
+	statementPath := fmt.Sprintf("/api/user/me/statements/%d", year)
+	other.expect(http.StatusNotFound, http.MethodGet, statementPath, nil)
+	donor.expect(http.StatusBadRequest, http.MethodGet, "/api/user/me/statements/1999", nil)
+	statement := donor.expect(http.StatusOK, http.MethodGet, statementPath, nil)
+	if same := donor.expect(http.StatusOK, http.MethodGet, statementPath, nil); !bytes.Equal(same.body, statement.body) {
+		t.Error("an unchanged statement was re-issued")
+	}
+	donor.expect(http.StatusOK, http.MethodPost, "/api/donations", gin.H{"user_id": donorID, "cause_id": causeID, "amount": 10, "donation_type": "goods"})
+	updated := admin.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/api/admin/users/%d/statements/%d", donorID, year), nil)
+	if want := fmt.Sprintf(`attachment; filename="S-%d-000002.pdf"`, year); updated.header.Get("Content-Disposition") != want {
+		t.Errorf("updated statement is %q, want %q", updated.header.Get("Content-Disposition"), want)
+	}
+
+	var issued []struct {
+		Kind     string     `json:"kind"`
+		Amount   string     `json:"amount"`
+		VoidedAt *time.Time `json:"voided_at"`
+	}
+	donor.expect(http.StatusOK, http.MethodGet, "/api/user/me/receipts", nil).decode(t, &issued)
+	if len(issued) != 4 {
+		t.Fatalf("donor has %d receipts, want two receipts and two statements: %+v", len(issued), issued)
+	}
+	if latest := issued[0]; latest.Kind != "statement" || latest.Amount != "35.50" || latest.VoidedAt != nil {
+		t.Errorf("latest receipt = %+v, want the current statement for 35.50", latest)
+	}
+
+	// Donations that did not go through get no receipt and stay off the statement
+	var refunded struct {
+		ID int32 `json:"id"`
+	}
+	donor.expect(http.StatusOK, http.MethodPost, "/api/donations", gin.H{"user_id": donorID, "cause_id": causeID, "amount": 5, "donation_type": "money"}).decode(t, &refunded)
+	if _, err := s.conn.Exec(`UPDATE donations SET status = 'refunded' WHERE id = $1`, refunded.ID); err != nil {
+		t.Fatal(err)
+	}
+	donor.expect(http.StatusUnprocessableEntity, http.MethodGet, fmt.Sprintf("/api/donations/%d/receipt", refunded.ID), nil)
+	if same := donor.expect(http.StatusOK, http.MethodGet, statementPath, nil); !bytes.Equal(same.body, updated.body) {
+		t.Error("a refunded donation changed the statement")
+	}
+}
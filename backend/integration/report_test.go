@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"play4good-backend/reports"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReports(t *testing.T) {
+	s := newSuite(t)
+	organiser, organiserID := s.signUp("organiser")
+	s.verify(organiserID)
+	rivers := createCause(s, organiser, "Clean Rivers")
+	forests := createCause(s, organiser, "Plant Forests")
+	donor, donorID := s.signUp("donor")
+	other, otherID := s.signUp("other")
+	s.verify(otherID)
+	createCause(s, other, "Other Cause")
+
+	for _, d := range []gin.H{
+		{"user_id": donorID, "cause_id": rivers, "amount": 10, "donation_type": "money"},
+		{"user_id": donorID, "cause_id": rivers, "amount": 15.5, "donation_type": "goods"},
+		{"user_id": donorID, "cause_id": forests, "amount": 40, "donation_type": "money"},
+	} {
+		donor.expect(http.StatusOK, http.MethodPost, "/api/donations", d)
+	}
+
+	// Cause owners export their own causes; everything else is for admins
+	path := fmt.Sprintf("/api/exports/donations?cause_id=%d&columns=amount,donation_type,cause_name", rivers)
+	res := organiser.expect(http.StatusOK, http.MethodGet, path, nil)
+	want := "amount,donation_type,cause_name\n10.00,money,Clean Rivers\n15.50,goods,Clean Rivers\n"
+	if string(res.body) != want {
+		t.Errorf("CSV export =\n%s\nwant\n%s", res.body, want)
+	}
+	if disposition := res.header.Get("Content-Disposition"); !strings.Contains(disposition, "attachment") {
+		t.Errorf("Content-Disposition = %q, want an attachment", disposition)
+	}
+	other.expect(http.StatusForbidden, http.MethodGet, path, nil)
+	organiser.expect(http.StatusForbidden, http.MethodGet, "/api/exports/donations", nil)
+	organiser.expect(http.StatusBadRequest, http.MethodGet, path+",password_hash", nil)
+
+	var donors []string
+	res = organiser.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/api/exports/donors?cause_id=%d&format=ndjson&columns=email,donation_count,total_amount", rivers), nil)
+	donors = strings.Split(strings.TrimSpace(string(res.body)), "\n")
+	if len(donors) != 1 || donors[0] != fmt.Sprintf(`{"email":%q,"donation_count":2,"total_amount":25.50}`, email("donor")) {
+		t.Errorf("donor export = %q", donors)
+	}
+
+	// Queued reports are built by the background job and downloaded by whoever asked for them
+	var job struct {
+		ID     int32  `json:"id"`
+		Status string `json:"status"`
+	}
+	organiser.expect(http.StatusAccepted, http.MethodPost, "/api/export-jobs", gin.H{"dataset": "donations", "cause_id": forests, "columns": []string{"amount"}}).decode(t, &job)
+	download := fmt.Sprintf("/api/export-jobs/%d/download", job.ID)
+	organiser.expect(http.StatusConflict, http.MethodGet, download, nil)
+
+	worker := reports.NewService(s.store, util.Config{ExportTTL: time.Hour})
+	if err := worker.ProcessExports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	organiser.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/api/export-jobs/%d", job.ID), nil).decode(t, &job)
+	if job.Status != reports.ExportCompleted {
+		t.Fatalf("job status = %s, want completed", job.Status)
+	}
+	if res := organiser.expect(http.StatusOK, http.MethodGet, download, nil); string(res.body) != "amount\n40.00\n" {
+		t.Errorf("downloaded report = %q", res.body)
+	}
+	other.expect(http.StatusNotFound, http.MethodGet, download, nil)
+
+	// A report left running by an instance that stopped is built by the next worker
+	organiser.expect(http.StatusAccepted, http.MethodPost, "/api/export-jobs", gin.H{"dataset": "donations", "cause_id": rivers, "columns": []string{"amount"}}).decode(t, &job)
+	if _, err := s.conn.Exec(`UPDATE report_exports SET status = 'running', started_at = now() - interval '1 hour' WHERE id = $1`, job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := worker.ProcessExports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	download = fmt.Sprintf("/api/export-jobs/%d/download", job.ID)
+	if res := organiser.expect(http.StatusOK, http.MethodGet, download, nil); string(res.body) != "amount\n10.00\n15.50\n" {
+		t.Errorf("reclaimed report = %q", res.body)
+	}
+
+	// Past its window a report is gone, and the cleanup job removes it with its file
+	if _, err := s.conn.Exec(`UPDATE report_exports SET expires_at = now() - interval '1 minute'`); err != nil {
+		t.Fatal(err)
+	}
+	organiser.expect(http.StatusGone, http.MethodGet, download, nil)
+	if err := worker.RemoveExpiredExports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.count(`SELECT count(*) FROM report_exports`) + s.count(`SELECT count(*) FROM report_export_files`); n != 0 {
+		t.Errorf("%d reports and files left after the cleanup", n)
+	}
+}
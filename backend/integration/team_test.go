@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createTeam creates a team through the API and returns its ID
+func createTeam(s *suite, c *client, name string) int32 {
+	s.t.Helper()
+	var team struct {
+		ID int32 `json:"id"`
+	}
+	c.expect(http.StatusOK, http.MethodPost, "/api/teams", gin.H{"name": name}).decode(s.t, &team)
+	return team.ID
+}
+
+// join adds a user to a team with role
+func join(s *suite, c *client, userID, teamID int32, role string) {
+	s.t.Helper()
+	c.expect(http.StatusOK, http.MethodPost, "/api/user-team", gin.H{"user_id": userID, "team_id": teamID, "role": role})
+}
+
+func TestTeamMembership(t *testing.T) {
+	s := newSuite(t)
+	alice, aliceID := s.signUp("alice")
+	bob, bobID := s.signUp("bob")
+
+	teamID := createTeam(s, alice, "Green Runners")
+	join(s, alice, aliceID, teamID, "admin")
+	join(s, alice, bobID, teamID, "member")
+	if n := s.count(`SELECT count(*) FROM user_team WHERE team_id = $1`, teamID); n != 2 {
+		t.Fatalf("team has %d members, want 2", n)
+	}
+
+	// Joining twice or joining a team that does not exist fails on the table's constraints
+	if res := alice.do(http.MethodPost, "/api/user-team", gin.H{"user_id": bobID, "team_id": teamID, "role": "member"}, nil); res.status < 400 {
+		t.Errorf("adding a member twice got status %d", res.status)
+	}
+	if res := alice.do(http.MethodPost, "/api/user-team", gin.H{"user_id": bobID, "team_id": teamID + 1000, "role": "member"}, nil); res.status < 400 {
+		t.Errorf("joining a missing team got status %d", res.status)
+	}
+
+	// Only team admins may read the team's history
+	history := fmt.Sprintf("/api/teams/%d/history", teamID)
+	bob.expect(http.StatusForbidden, http.MethodGet, history, nil)
+	var entries []struct {
+		Action string `json:"action"`
+	}
+	alice.expect(http.StatusOK, http.MethodGet, history, nil).decode(t, &entries)
+	if len(entries) != 1 || entries[0].Action != "create" {
+		t.Errorf("team history = %+v, want its creation", entries)
+	}
+
+	member := fmt.Sprintf("/api/user-team/%d/%d", bobID, teamID)
+	var role struct {
+		Role string `json:"role"`
+	}
+	alice.expect(http.StatusOK, http.MethodPut, member, gin.H{"role": "admin"}).decode(t, &role)
+	if role.Role != "admin" {
+		t.Errorf("role = %q, want admin", role.Role)
+	}
+	bob.expect(http.StatusOK, http.MethodGet, history, nil)
+
+	alice.expect(http.StatusOK, http.MethodDelete, member, nil)
+	if n := s.count(`SELECT count(*) FROM user_team WHERE team_id = $1`, teamID); n != 1 {
+		t.Errorf("team has %d members after the removal, want 1", n)
+	}
+	bob.expect(http.StatusForbidden, http.MethodGet, history, nil)
+
+	if n := s.count(`SELECT count(*) FROM audit_log WHERE entity_type = 'team_member'`); n != 4 {
+		t.Errorf("%d membership changes audited, want 4", n)
+	}
+}
+
+func TestTeamUpdatesNeedCurrentVersion(t *testing.T) {
+	s := newSuite(t)
+	alice, _ := s.signUp("alice")
+	teamID := createTeam(s, alice, "Green Runners")
+	team := fmt.Sprintf("/api/teams/%d", teamID)
+
+	etag := alice.expect(http.StatusOK, http.MethodGet, team, nil).header.Get("ETag")
+	update := gin.H{"name": "Green Sprinters"}
+	alice.expect(http.StatusPreconditionRequired, http.MethodPut, team, update)
+	next := alice.expectWith(http.StatusOK, http.MethodPut, team, update, ifMatch(etag)).header.Get("ETag")
+	if next == etag {
+		t.Errorf("ETag %s did not change with the update", etag)
+	}
+
+	// A second writer holding the old ETag loses
+	alice.expectWith(http.StatusPreconditionFailed, http.MethodPatch, team, gin.H{"name": "Green Walkers"}, ifMatch(etag))
+
+	alice.expect(http.StatusOK, http.MethodDelete, team, nil)
+	alice.expect(http.StatusNotFound, http.MethodGet, team, nil)
+	if n := s.count(`SELECT count(*) FROM teams WHERE id = $1 AND deleted_at IS NOT NULL`, teamID); n != 1 {
+		t.Error("deleting a team did not keep it as a soft-deleted row")
+	}
+
+	// Deleting and restoring both change the version, so the ETag from before the delete is stale
+	admin, adminID := s.signUp("admin")
+	s.verify(adminID)
+	s.promote(adminID)
+	admin.expect(http.StatusOK, http.MethodPost, fmt.Sprintf("/api/admin/teams/%d/restore", teamID), nil)
+	if n := s.count(`SELECT count(*) FROM teams WHERE id = $1 AND version = 4`, teamID); n != 1 {
+		t.Error("deleting and restoring a team did not bump its version twice")
+	}
+	alice.expectWith(http.StatusPreconditionFailed, http.MethodPut, team, gin.H{"name": "Green Walkers"}, ifMatch(next))
+}
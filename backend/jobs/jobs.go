@@ -0,0 +1,62 @@
+// Package jobs runs background tasks on a fixed interval alongside the API server
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"play4good-backend/tracing"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Task is a unit of background work that runs once at start-up and then every Interval
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start runs each task in its own goroutine until ctx is cancelled. Errors are logged and the task retried on its next tick.
+// Wait on the returned group after cancelling ctx to let runs in progress finish.
+func Start(ctx context.Context, tasks ...Task) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(ctx, task)
+		}()
+	}
+	return &wg
+}
+
+func run(ctx context.Context, task Task) {
+	ticker := time.NewTicker(task.Interval)
+	defer ticker.Stop()
+
+	for {
+		// A run in progress is not cancelled with ctx; shutdown waits for it instead
+		runOnce(context.WithoutCancel(ctx), task)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs the task in its own root span so the queries it makes are grouped under one trace
+func runOnce(ctx context.Context, task Task) {
+	ctx, span := tracing.Tracer().Start(ctx, "job "+task.Name)
+	defer span.End()
+
+	if err := task.Run(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "job failed", "job", task.Name, "error", err)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"play4good-backend/loadgen"
+	"play4good-backend/seed"
+	"play4good-backend/util"
+)
+
+// runLoadgen implements the loadgen command. It needs no database, only a server whose database
+// was seeded with the same password.
+func runLoadgen(ctx context.Context, config util.Config, args []string) error {
+	var opts loadgen.Options
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	fs.StringVar(&opts.BaseURL, "url", "http://localhost:"+config.ServerAddress, "base URL of the server")
+	fs.IntVar(&opts.Concurrency, "c", 10, "concurrent workers")
+	fs.DurationVar(&opts.Duration, "d", 30*time.Second, "how long to send requests")
+	fs.IntVar(&opts.Users, "users", 20, "seeded users to sign in as")
+	fs.StringVar(&opts.Password, "password", seed.DefaultPassword, "password of the seeded users")
+	fs.Int64Var(&opts.Seed, "seed", 1, "random seed for the request mix")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+
+	report, err := loadgen.Run(ctx, opts)
+	if err != nil {
+		return err
+	}
+	report.Print(os.Stdout)
+	return nil
+}
@@ -0,0 +1,311 @@
+// Package loadgen replays donation traffic against a running server, signed in as users created by
+// the seed command, and reports throughput and latency per operation
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"play4good-backend/seed"
+)
+
+// Operations in the traffic mix
+const (
+	OpDonate          = "donate"
+	OpLeaderboard     = "get_leaderboard"
+	OpListCauses      = "list_causes"
+	OpListLeaderboard = "list_leaderboards"
+)
+
+// mix is the share of requests per operation, in percent. Donations dominate, as on a live campaign,
+// with reads of the leaderboards they move.
+var mix = []struct {
+	op      string
+	percent int
+}{
+	{OpDonate, 70},
+	{OpLeaderboard, 20},
+	{OpListCauses, 7},
+	{OpListLeaderboard, 3},
+}
+
+type Options struct {
+	// BaseURL of the server, e.g. http://localhost:8080
+	BaseURL     string
+	Concurrency int
+	Duration    time.Duration
+	// Users is how many seeded users to sign in as; workers share them round robin
+	Users    int
+	Password string
+	Seed     int64
+}
+
+// Stats holds the results of one operation
+type Stats struct {
+	Requests  int
+	Errors    int
+	latencies []time.Duration
+}
+
+// Percentile returns the latency below which p percent of requests completed
+func (s *Stats) Percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	i := int(float64(len(s.latencies)-1) * p / 100)
+	return s.latencies[i]
+}
+
+// Max returns the slowest request
+func (s *Stats) Max() time.Duration {
+	return s.Percentile(100)
+}
+
+// Report is the outcome of a run
+type Report struct {
+	Elapsed time.Duration
+	Ops     map[string]*Stats
+}
+
+// Total sums requests and errors over every operation
+func (r Report) Total() (requests, errors int) {
+	for _, s := range r.Ops {
+		requests += s.Requests
+		errors += s.Errors
+	}
+	return requests, errors
+}
+
+// Print writes the report as a table
+func (r Report) Print(w io.Writer) {
+	requests, errs := r.Total()
+	fmt.Fprintf(w, "%d requests, %d errors in %s (%.1f req/s)\n\n", requests, errs, r.Elapsed.Round(time.Millisecond),
+		float64(requests)/r.Elapsed.Seconds())
+	fmt.Fprintf(w, "%-18s %8s %7s %9s %10s %10s %10s %10s\n", "operation", "requests", "errors", "req/s", "p50", "p90", "p99", "max")
+	for _, m := range mix {
+		s := r.Ops[m.op]
+		fmt.Fprintf(w, "%-18s %8d %7d %9.1f %10s %10s %10s %10s\n", m.op, s.Requests, s.Errors,
+			float64(s.Requests)/r.Elapsed.Seconds(), round(s.Percentile(50)), round(s.Percentile(90)),
+			round(s.Percentile(99)), round(s.Max()))
+	}
+}
+
+type session struct {
+	userID int32
+	token  string
+}
+
+type runner struct {
+	opts           Options
+	client         *http.Client
+	sessions       []session
+	causeIDs       []int32
+	leaderboardIDs []int32
+}
+
+// Run signs in, discovers causes and leaderboards, then sends requests from Concurrency workers until
+// Duration passes or ctx is cancelled
+func Run(ctx context.Context, opts Options) (Report, error) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.Users < 1 {
+		opts.Users = 1
+	}
+	if opts.Password == "" {
+		opts.Password = seed.DefaultPassword
+	}
+	r := &runner{
+		opts: opts,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{MaxIdleConnsPerHost: opts.Concurrency},
+		},
+	}
+	if err := r.prepare(ctx); err != nil {
+		return Report{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	results := make([]map[string]*Stats, opts.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			results[w] = r.work(ctx, w, rand.New(rand.NewSource(opts.Seed+int64(w))))
+		}(w)
+	}
+	wg.Wait()
+
+	report := Report{Elapsed: time.Since(start), Ops: map[string]*Stats{}}
+	for _, m := range mix {
+		total := &Stats{}
+		for _, result := range results {
+			s := result[m.op]
+			total.Requests += s.Requests
+			total.Errors += s.Errors
+			total.latencies = append(total.latencies, s.latencies...)
+		}
+		sort.Slice(total.latencies, func(i, j int) bool { return total.latencies[i] < total.latencies[j] })
+		report.Ops[m.op] = total
+	}
+	return report, nil
+}
+
+func (r *runner) prepare(ctx context.Context) error {
+	for i := 1; i <= r.opts.Users; i++ {
+		var out struct {
+			ID    int32  `json:"id"`
+			Token string `json:"token"`
+		}
+		login := map[string]string{"email": seed.Email(i), "password": r.opts.Password}
+		if err := r.do(ctx, http.MethodPost, "/api/login", "", login, &out); err != nil {
+			return fmt.Errorf("sign in as %s (has the database been seeded?): %w", seed.Email(i), err)
+		}
+		if out.Token == "" {
+			return fmt.Errorf("sign in as %s: no session token; is two-factor enabled?", seed.Email(i))
+		}
+		r.sessions = append(r.sessions, session{userID: out.ID, token: out.Token})
+	}
+
+	token := r.sessions[0].token
+	var causes []struct {
+		ID int32 `json:"id"`
+	}
+	if err := r.do(ctx, http.MethodPost, "/api/listCauses", token, map[string]int{"limit": 1000}, &causes); err != nil {
+		return fmt.Errorf("list causes: %w", err)
+	}
+	var leaderboards []struct {
+		ID int32 `json:"id"`
+	}
+	if err := r.do(ctx, http.MethodPut, "/api/listLeaderBoards", token, map[string]int{"limit": 1000}, &leaderboards); err != nil {
+		return fmt.Errorf("list leaderboards: %w", err)
+	}
+	for _, c := range causes {
+		r.causeIDs = append(r.causeIDs, c.ID)
+	}
+	for _, l := range leaderboards {
+		r.leaderboardIDs = append(r.leaderboardIDs, l.ID)
+	}
+	if len(r.causeIDs) == 0 || len(r.leaderboardIDs) == 0 {
+		return errors.New("the server has no causes or leaderboards; run the seed command first")
+	}
+	return nil
+}
+
+// work sends requests until ctx is done and returns the stats of this worker
+func (r *runner) work(ctx context.Context, w int, rng *rand.Rand) map[string]*Stats {
+	stats := map[string]*Stats{}
+	for _, m := range mix {
+		stats[m.op] = &Stats{}
+	}
+	for i := w; ctx.Err() == nil; i += r.opts.Concurrency {
+		op := pickOp(rng)
+		sess := r.sessions[i%len(r.sessions)]
+
+		began := time.Now()
+		err := r.send(ctx, op, sess, rng)
+		if ctx.Err() != nil {
+			// Requests cut short by the end of the run say nothing about the server
+			break
+		}
+		s := stats[op]
+		s.Requests++
+		s.latencies = append(s.latencies, time.Since(began))
+		if err != nil {
+			s.Errors++
+		}
+	}
+	return stats
+}
+
+func (r *runner) send(ctx context.Context, op string, sess session, rng *rand.Rand) error {
+	switch op {
+	case OpDonate:
+		body := map[string]interface{}{
+			"user_id":       sess.userID,
+			"cause_id":      r.causeIDs[rng.Intn(len(r.causeIDs))],
+			"amount":        amount(rng),
+			"donation_type": seed.DonationType(rng),
+		}
+		return r.do(ctx, http.MethodPost, "/api/donations", sess.token, body, nil)
+	case OpLeaderboard:
+		id := r.leaderboardIDs[rng.Intn(len(r.leaderboardIDs))]
+		return r.do(ctx, http.MethodGet, "/api/leaderboards/"+strconv.Itoa(int(id)), sess.token, nil, nil)
+	case OpListCauses:
+		return r.do(ctx, http.MethodPost, "/api/listCauses", sess.token, map[string]int{"limit": 20, "offset": 20 * rng.Intn(5)}, nil)
+	default:
+		return r.do(ctx, http.MethodPut, "/api/listLeaderBoards", sess.token, map[string]int{"limit": 20}, nil)
+	}
+}
+
+// do sends a JSON request and decodes a 2xx response into out; anything else is an error
+func (r *runner) do(ctx context.Context, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.opts.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func pickOp(rng *rand.Rand) string {
+	p := rng.Intn(100)
+	for _, m := range mix {
+		if p < m.percent {
+			return m.op
+		}
+		p -= m.percent
+	}
+	return mix[0].op
+}
+
+func amount(rng *rand.Rand) float64 {
+	v, _ := strconv.ParseFloat(seed.Amount(rng), 64)
+	return v
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,70 @@
+// Package logging configures the process-wide structured JSON logger
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"play4good-backend/tracing"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log records carry the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored by WithRequestID, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ParseLevel accepts debug, info, warn or error
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(level))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	return l, nil
+}
+
+// Setup installs a JSON logger at level as the slog default. Output from the standard log
+// package goes through it too.
+func Setup(w io.Writer, level string) (*slog.Logger, error) {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: l})})
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// contextHandler adds the request ID and trace context from the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if traceID, spanID := tracing.IDs(ctx); traceID != "" {
+		r.AddAttrs(slog.String("trace_id", traceID), slog.String("span_id", spanID))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
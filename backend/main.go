@@ -1,83 +1,112 @@
 package main
 
 import (
-	"context"
-	"database/sql"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
+    "context"
+    "database/sql"
+    "fmt"
+    "log"
+    "log/slog"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
+
+    "play4good-backend/logging"
+    "play4good-backend/util"
+
+    _ "github.com/lib/pq"
+)
 
-	"play4good-backend/controllers"
-	dbCon "play4good-backend/db/sqlc"
-	"play4good-backend/routes"
-	"play4good-backend/util"
+const usage = `usage: play4good [command] [arguments]
 
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-	_ "github.com/lib/pq"
-)
+  serve      run the API server (the default)
+  migrate    apply, roll back or inspect schema migrations
+  admin      operator tasks: admin users, roles, cause totals, leaderboards, tokens, import/export
+  seed       fill the database with deterministic fake users, teams, causes and donations
+  loadgen    replay donation traffic against a running server and report throughput and latency
 
-var (
-    server *gin.Engine
-    db     *dbCon.Queries
-    ctx    context.Context
+Run "play4good <command> -h" for a command's arguments.
+`
 
-    Play4GoodController *controllers.Play4GoodController
-    Play4GoodRoutes     routes.Play4GoodRoutes
-)
+func main() {
+    command, args := "serve", os.Args[1:]
+    if len(args) > 0 {
+        command, args = args[0], args[1:]
+    }
 
-func init() {
-    ctx = context.TODO()
-    config, err := util.LoadConfig(".")
+    // Cancelled on SIGINT or SIGTERM: the server shuts down gracefully, other commands are interrupted
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
 
+    config, err := util.LoadConfig(".")
     if err != nil {
         log.Fatalf("could not load config: %v", err)
     }
 
-    conn, err := sql.Open(config.DbDriver, config.DbSource)
-    if err != nil {
-        log.Fatalf("Could not connect to database: %v", err)
+    // Command output goes to stdout, so only the server logs there
+    logOutput := os.Stderr
+    if command == "serve" {
+        logOutput = os.Stdout
+    }
+    if _, err := logging.Setup(logOutput, config.LogLevel); err != nil {
+        log.Fatalf("could not set up logging: %v", err)
     }
 
-    db = dbCon.New(conn)
-
-    fmt.Println("PostgreSql connected successfully...")
-
-    Play4GoodController = controllers.NewPlay4GoodController(db, ctx)
-    Play4GoodRoutes = routes.NewPlay4GoodRoutes(Play4GoodController)
-
-    server = gin.Default()
-
-    // Add CORS middleware
-    server.Use(cors.New(cors.Config{
-        AllowOrigins:     []string{"http://localhost:3000"}, // Replace with your frontend domain
-        AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-        AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-        ExposeHeaders:    []string{"Content-Length"},
-        AllowCredentials: true,
-        MaxAge:           12 * time.Hour,
-    }))
+    switch command {
+    case "serve":
+        err = runServe(ctx, config)
+    case "migrate":
+        err = withDB(ctx, config, func(conn *sql.DB) error { return runMigrate(ctx, conn, args) })
+    case "admin":
+        err = withDB(ctx, config, func(conn *sql.DB) error { return runAdmin(ctx, conn, args) })
+    case "seed":
+        err = withDB(ctx, config, func(conn *sql.DB) error { return runSeed(ctx, conn, args) })
+    case "loadgen":
+        err = runLoadgen(ctx, config, args)
+    case "help", "-h", "--help":
+        fmt.Print(usage)
+    default:
+        fmt.Fprint(os.Stderr, usage)
+        err = fmt.Errorf("unknown command %q", command)
+    }
+    if err != nil {
+        stop()
+        fatal(command+" failed", err)
+    }
 }
 
-func main() {
-    config, err := util.LoadConfig(".")
-
+// openDB opens the connection pool with the configured limits and checks the database answers
+func openDB(ctx context.Context, config util.Config) (*sql.DB, error) {
+    conn, err := sql.Open(config.DbDriver, config.DbSource)
     if err != nil {
-        log.Fatalf("failed to load config: %v", err)
+        return nil, fmt.Errorf("could not connect to database: %w", err)
     }
+    conn.SetMaxOpenConns(config.DbMaxOpenConns)
+    conn.SetMaxIdleConns(config.DbMaxIdleConns)
+    conn.SetConnMaxLifetime(config.DbConnMaxLifetime)
+    conn.SetConnMaxIdleTime(config.DbConnMaxIdleTime)
+
+    pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+    if err := conn.PingContext(pingCtx); err != nil {
+        conn.Close()
+        return nil, fmt.Errorf("could not reach database: %w", err)
+    }
+    return conn, nil
+}
 
-    router := server.Group("/api")
-
-    router.GET("/healthcheck", func(ctx *gin.Context) {
-        ctx.JSON(http.StatusOK, gin.H{"message": "The Play4Good API is working fine"})
-    })
-
-    Play4GoodRoutes.SetupRoutes(router)
-
-    server.NoRoute(func(ctx *gin.Context) {
-        ctx.JSON(http.StatusNotFound, gin.H{"status": "failed", "message": fmt.Sprintf("The specified route %s not found", ctx.Request.URL)})
-    })
+// withDB runs fn with an open database and closes it afterwards
+func withDB(ctx context.Context, config util.Config, fn func(conn *sql.DB) error) error {
+    conn, err := openDB(ctx, config)
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+    return fn(conn)
+}
 
-    log.Fatal(server.Run(":" + config.ServerAddress))
+// fatal logs err and exits
+func fatal(msg string, err error) {
+    slog.Error(msg, "error", err)
+    os.Exit(1)
 }
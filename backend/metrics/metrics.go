@@ -0,0 +1,80 @@
+// Package metrics defines the Prometheus metrics exposed at /metrics
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "play4good"
+
+var (
+	// RequestDuration is labelled with the route pattern, not the raw path, to keep cardinality bounded
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	DonationsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "donations_created_total",
+		Help:      "Donations recorded.",
+	}, []string{"donation_type"})
+
+	AmountRaised = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "donation_amount_raised_total",
+		Help:      "Sum of recorded donation amounts.",
+	}, []string{"donation_type"})
+
+	LeaderboardsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "leaderboards_created_total",
+		Help:      "Leaderboards created.",
+	})
+
+	LeaderboardEntriesUpdated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "leaderboard_entries_updated_total",
+		Help:      "Leaderboard entries created or rescored.",
+	})
+)
+
+// RecordDonation counts a committed donation and its amount
+func RecordDonation(donationType string, amount float64) {
+	RecordDonations(donationType, 1, amount)
+}
+
+// RecordDonations counts donations committed together, such as a chunk of an import, and their total
+func RecordDonations(donationType string, count int64, amount float64) {
+	if donationType == "" {
+		donationType = "unspecified"
+	}
+	DonationsCreated.WithLabelValues(donationType).Add(float64(count))
+	AmountRaised.WithLabelValues(donationType).Add(amount)
+}
+
+// RegisterDBStats exports the connection pool statistics of db
+func RegisterDBStats(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatchUsesStrongComparison(t *testing.T) {
+	for _, tc := range []struct {
+		header, etag string
+		want         bool
+	}{
+		{`"v3"`, `"v3"`, true},
+		{`"v2", "v3"`, `"v3"`, true},
+		{`*`, `"v3"`, true},
+		{`W/"v3"`, `"v3"`, false},
+		{`"v3"`, `W/"v3"`, false},
+		{`"v2"`, `"v3"`, false},
+	} {
+		if got := StrongETagMatches(tc.header, tc.etag); got != tc.want {
+			t.Errorf("StrongETagMatches(%s, %s) = %v, want %v", tc.header, tc.etag, got, tc.want)
+		}
+	}
+
+	// If-None-Match keeps weak comparison
+	if !ETagMatches(`W/"v3"`, `"v3"`) {
+		t.Error(`ETagMatches(W/"v3", "v3") = false, want true`)
+	}
+}
+
+func TestQueryETagAnswersNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(ETag())
+	engine.PUT("/list", QueryETag(), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"items": []int{1, 2}})
+	})
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/list", nil))
+	etag := recorder.Header().Get("ETag")
+	if recorder.Code != http.StatusOK || etag == "" {
+		t.Fatalf("got status %d and ETag %q, want 200 with an ETag", recorder.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/list", nil)
+	req.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusNotModified || recorder.Body.Len() != 0 {
+		t.Fatalf("got status %d with %d bytes, want an empty 304", recorder.Code, recorder.Body.Len())
+	}
+}
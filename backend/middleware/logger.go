@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"play4good-backend/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger writes one structured log line per request and records its latency. Requests that
+// matched no route are grouped under a single route label.
+func Logger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		metrics.RequestsInFlight.Inc()
+		defer metrics.RequestsInFlight.Dec()
+
+		ctx.Next()
+
+		duration := time.Since(start)
+		status := ctx.Writer.Status()
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.RequestDuration.WithLabelValues(ctx.Request.Method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+			slog.Int("bytes", max(ctx.Writer.Size(), 0)),
+			slog.String("ip", ctx.ClientIP()),
+			slog.String("user_agent", ctx.Request.UserAgent()),
+		}
+		if userID := ctx.GetInt("userID"); userID != 0 {
+			attrs = append(attrs, slog.Int("user_id", userID))
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", ctx.Errors.String()))
+		}
+		slog.LogAttrs(ctx.Request.Context(), level, "request", attrs...)
+	}
+}
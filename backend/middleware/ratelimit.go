@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"play4good-backend/problem"
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit rejects requests from a client IP once it exceeds the limiter's budget for the route
+func RateLimit(limiter *util.RateLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		allowed, retryAfter := limiter.Allow(ctx.ClientIP() + " " + ctx.FullPath())
+		if !allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			problem.Write(ctx, problem.New(http.StatusTooManyRequests, "", "Too many requests, please try again later"))
+			return
+		}
+		ctx.Next()
+	}
+}
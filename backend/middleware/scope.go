@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"play4good-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scope declares the API key scope a route requires. Routes without one cannot be called with an API key.
+func Scope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(util.RequiredScopeKey, scope)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+
+	"play4good-backend/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span per request, continuing a trace sent in the traceparent header.
+// Handlers and queries see the span through the request context. Spans are named after the route
+// template so requests for different IDs group together.
+func Tracing() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		parent := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+
+		route := ctx.FullPath()
+		name := ctx.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		spanCtx, span := tracing.Tracer().Start(parent, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(ctx.Request.URL.Path),
+				semconv.ClientAddress(ctx.ClientIP()),
+				semconv.UserAgentOriginal(ctx.Request.UserAgent()),
+				attribute.String("request.id", ctx.GetString(RequestIDKey)),
+			),
+		)
+		defer span.End()
+
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if userID := ctx.GetInt("userID"); userID != 0 {
+			span.SetAttributes(attribute.Int("user.id", userID))
+		}
+		if len(ctx.Errors) > 0 {
+			span.SetAttributes(attribute.String("gin.errors", ctx.Errors.String()))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
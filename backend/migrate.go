@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"play4good-backend/db/migration"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateUsage = `usage: play4good migrate <command>
+
+  up            apply every pending migration
+  down [N]      roll back the last N migrations (default 1)
+  status        show the current version and which migrations are applied
+  force V       record version V as clean without running anything, after fixing a failed migration by hand
+`
+
+// runMigrate implements the migrate command
+func runMigrate(ctx context.Context, conn *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(fs.Output(), migrateUsage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing migrate command")
+	}
+
+	if fs.Arg(0) == "status" {
+		return migrateStatus(ctx, conn)
+	}
+
+	m, err := migration.New(conn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch fs.Arg(0) {
+	case "up":
+		err = m.Up()
+	case "down":
+		steps := 1
+		if fs.NArg() > 1 {
+			if steps, err = strconv.Atoi(fs.Arg(1)); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", fs.Arg(1))
+			}
+		}
+		err = m.Steps(-steps)
+	case "force":
+		if fs.NArg() < 2 {
+			return errors.New("force needs a version")
+		}
+		version, perr := strconv.Atoi(fs.Arg(1))
+		if perr != nil {
+			return fmt.Errorf("invalid version %q", fs.Arg(1))
+		}
+		err = m.Force(version)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown migrate command %q", fs.Arg(0))
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return migrateStatus(ctx, conn)
+}
+
+func migrateStatus(ctx context.Context, conn *sql.DB) error {
+	version, dirty, err := migration.Version(ctx, conn)
+	if err != nil {
+		return err
+	}
+	files, err := migration.Files()
+	if err != nil {
+		return err
+	}
+
+	state := "clean"
+	if dirty {
+		state = "dirty"
+	}
+	fmt.Printf("database version %d (%s), latest %d\n\n", version, state, migration.Latest())
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, f := range files {
+		mark := "pending"
+		switch {
+		case f.Version == version && dirty:
+			mark = "dirty"
+		case f.Version <= version:
+			mark = "applied"
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\n", f.Version, f.Name, mark)
+	}
+	return w.Flush()
+}
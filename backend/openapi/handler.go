@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"play4good-backend/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the document generated by build. It is built on the first request so the
+// routes registered after the handler itself are included.
+func Handler(build func() *Document) gin.HandlerFunc {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+	return func(ctx *gin.Context) {
+		once.Do(func() {
+			body, err = json.Marshal(build())
+		})
+		if err != nil {
+			problem.Respond(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		ctx.Data(http.StatusOK, "application/json", body)
+	}
+}
+
+// UIHandler serves Swagger UI pointed at the document at specURL
+func UIHandler(title, specURL string) gin.HandlerFunc {
+	page := fmt.Sprintf(uiTemplate, title, specURL)
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+}
+
+const uiTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: %q, dom_id: "#swagger-ui", withCredentials: true });
+  </script>
+</body>
+</html>
+`
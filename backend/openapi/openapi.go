@@ -0,0 +1,353 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"play4good-backend/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI version of the generated document
+const Version = "3.1.0"
+
+// MergePatchJSON is the media type of JSON merge patch request bodies
+const MergePatchJSON = "application/merge-patch+json"
+
+// MultipartForm is the media type of file uploads
+const MultipartForm = "multipart/form-data"
+
+// Auth is the authentication an operation requires
+type Auth int
+
+const (
+	// AuthNone operations are public
+	AuthNone Auth = iota
+	// AuthSession operations need a session token, or an API key when the operation declares a scope
+	AuthSession
+	// AuthAdmin operations need the session of a user with the admin role
+	AuthAdmin
+)
+
+// Operation documents one route. Request, Query and Response are zero values of the Go types
+// bound or returned by the handler; their schemas are generated from the struct tags.
+type Operation struct {
+	Method  string
+	Path    string
+	Summary string
+	Tag     string
+	Auth    Auth
+	// Scope is the API key scope accepted in place of a session
+	Scope string
+	// Request is the JSON body; RequestType overrides its media type. Multipart forms are
+	// described by their form tags.
+	Request     interface{}
+	RequestType string
+	// Query is a struct whose form tags are the query string parameters
+	Query    interface{}
+	Response interface{}
+	// ResponseType overrides the media type of the response, for file downloads
+	ResponseType string
+	// Status is the success status, 200 when unset
+	Status int
+	// IfMatch marks updates that need the current ETag in If-Match
+	IfMatch bool
+	// Conditional marks reads sent with a request body that still answer If-None-Match with 304
+	Conditional bool
+	// RateLimited marks routes behind a rate limiter
+	RateLimited bool
+}
+
+// Info describes the API in the document header
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document is a generated OpenAPI document
+type Document struct {
+	OpenAPI    string                            `json:"openapi"`
+	Info       Info                              `json:"info"`
+	Tags       []map[string]string               `json:"tags,omitempty"`
+	Paths      map[string]map[string]interface{} `json:"paths"`
+	Components map[string]interface{}            `json:"components"`
+}
+
+// Build generates the document for the registered routes. Routes without an operation are left out;
+// Check reports them.
+func Build(info Info, routes gin.RoutesInfo, ops []Operation) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]map[string]interface{}{},
+	}
+
+	byRoute := map[string]Operation{}
+	for _, op := range ops {
+		byRoute[routeKey(op.Method, op.Path)] = op
+	}
+
+	tags := map[string]bool{}
+	for _, route := range routes {
+		op, ok := byRoute[routeKey(route.Method, route.Path)]
+		if !ok {
+			continue
+		}
+		path := specPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]interface{}{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = g.operation(op, operationID(route.Handler))
+		if op.Tag != "" {
+			tags[op.Tag] = true
+		}
+	}
+
+	names := make([]string, 0, len(tags))
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		doc.Tags = append(doc.Tags, map[string]string{"name": name})
+	}
+
+	g.schema(reflect.TypeOf(problem.Problem{}))
+	doc.Components = map[string]interface{}{
+		"schemas":         g.schemas,
+		"securitySchemes": securitySchemes,
+	}
+	return doc
+}
+
+// Check lists the differences between the registered routes and the documented operations
+func Check(routes gin.RoutesInfo, ops []Operation) []string {
+	var problems []string
+
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[routeKey(route.Method, route.Path)] = true
+	}
+
+	documented := map[string]bool{}
+	for _, op := range ops {
+		key := routeKey(op.Method, op.Path)
+		if documented[key] {
+			problems = append(problems, fmt.Sprintf("%s is documented twice", key))
+		}
+		documented[key] = true
+		if !registered[key] {
+			problems = append(problems, fmt.Sprintf("%s is documented but not routed", key))
+		}
+	}
+
+	for _, route := range routes {
+		key := routeKey(route.Method, route.Path)
+		if !documented[key] {
+			problems = append(problems, fmt.Sprintf("%s is routed but not documented", key))
+		}
+	}
+	return problems
+}
+
+var securitySchemes = object{
+	"sessionCookie": object{
+		"type":        "apiKey",
+		"in":          "cookie",
+		"name":        "token",
+		"description": "Session token set by /api/signup and /api/login",
+	},
+	"sessionBearer": object{
+		"type":         "http",
+		"scheme":       "bearer",
+		"bearerFormat": "JWT",
+		"description":  "Session token returned by /api/login",
+	},
+	"apiKey": object{
+		"type":        "http",
+		"scheme":      "bearer",
+		"description": "API key (p4g_...). The key must hold the scope listed on the operation.",
+	},
+}
+
+func (g *generator) operation(op Operation, id string) object {
+	res := object{
+		"operationId": id,
+		"summary":     op.Summary,
+	}
+	if op.Tag != "" {
+		res["tags"] = []string{op.Tag}
+	}
+
+	var params []object
+	for _, name := range pathParams(op.Path) {
+		params = append(params, object{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   pathParamSchema(name),
+		})
+	}
+	if op.Query != nil {
+		params = append(params, g.queryParams(reflect.TypeOf(op.Query))...)
+	}
+	if op.IfMatch {
+		params = append(params, object{
+			"name":        "If-Match",
+			"in":          "header",
+			"required":    true,
+			"description": "ETag of the version being changed",
+			"schema":      object{"type": "string"},
+		})
+	}
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+
+	if op.Request != nil {
+		contentType := op.RequestType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		res["requestBody"] = object{
+			"required": true,
+			"content": object{
+				contentType: object{"schema": g.schema(reflect.TypeOf(op.Request))},
+			},
+		}
+	}
+
+	switch op.Auth {
+	case AuthNone:
+		res["security"] = []object{}
+	case AuthSession, AuthAdmin:
+		security := []object{
+			{"sessionCookie": []string{}},
+			{"sessionBearer": []string{}},
+		}
+		if op.Scope != "" {
+			security = append(security, object{"apiKey": []string{op.Scope}})
+		}
+		res["security"] = security
+	}
+
+	res["responses"] = g.responses(op)
+	return res
+}
+
+func (g *generator) responses(op Operation) object {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	success := object{"description": http.StatusText(status)}
+	if op.Response != nil || op.ResponseType != "" {
+		contentType := op.ResponseType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		var schema object
+		if op.ResponseType != "" {
+			schema = object{"type": "string", "contentMediaType": contentType}
+		} else {
+			schema = g.schema(reflect.TypeOf(op.Response))
+		}
+		success["content"] = object{contentType: object{"schema": schema}}
+	}
+	res := object{strconv.Itoa(status): success}
+
+	if (op.Method == http.MethodGet || op.Conditional) && op.ResponseType == "" {
+		res["304"] = object{"description": "Not Modified; the If-None-Match header matched the current ETag"}
+	}
+
+	hasParams := len(pathParams(op.Path)) > 0
+	errors := map[int]string{}
+	if op.Request != nil || op.Query != nil || hasParams {
+		errors[http.StatusBadRequest] = "The request failed validation"
+	}
+	if op.Auth != AuthNone {
+		errors[http.StatusUnauthorized] = "No valid session or API key"
+	}
+	if op.Auth == AuthAdmin || op.Scope != "" {
+		errors[http.StatusForbidden] = "The caller lacks the role or scope for this operation"
+	}
+	if hasParams {
+		errors[http.StatusNotFound] = "The resource does not exist"
+	}
+	if op.IfMatch {
+		errors[http.StatusPreconditionFailed] = "If-Match does not match the current version"
+		errors[http.StatusPreconditionRequired] = "If-Match is missing"
+	}
+	if op.RequestType == MergePatchJSON {
+		errors[http.StatusUnsupportedMediaType] = "The body is not a JSON merge patch"
+	}
+	if op.RequestType == MultipartForm {
+		errors[http.StatusRequestEntityTooLarge] = "The uploaded file is too large"
+		errors[http.StatusUnsupportedMediaType] = "The uploaded file has an unsupported format"
+	}
+	if op.RateLimited {
+		errors[http.StatusTooManyRequests] = "Too many requests; retry later"
+	}
+	errors[http.StatusInternalServerError] = "Unexpected server error"
+
+	for code, description := range errors {
+		res[strconv.Itoa(code)] = object{
+			"description": description,
+			"content": object{
+				problem.ContentType: object{"schema": ref("Problem")},
+			},
+		}
+	}
+	return res
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// specPath converts gin path parameters (:id, *path) to OpenAPI templates ({id})
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+func pathParamSchema(name string) object {
+	if name == "id" || strings.HasSuffix(name, "Id") {
+		return object{"type": "integer", "format": "int32"}
+	}
+	return object{"type": "string"}
+}
+
+// operationID derives an operation ID from the handler name gin reports,
+// e.g. play4good-backend/controllers.(*Play4GoodController).GetUser-fm becomes getUser
+func operationID(handler string) string {
+	name := handler[strings.LastIndex(handler, ".")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
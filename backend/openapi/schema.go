@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type object = map[string]interface{}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	numberType     = reflect.TypeOf(json.Number(""))
+	fileType       = reflect.TypeOf(&multipart.FileHeader{})
+)
+
+// generator turns Go types into JSON schemas, collecting named structs as components
+type generator struct {
+	schemas map[string]interface{}
+	types   map[string]reflect.Type
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: map[string]interface{}{},
+		types:   map[string]reflect.Type{},
+	}
+}
+
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+// schema returns the schema for t, registering structs as components
+func (g *generator) schema(t reflect.Type) object {
+	switch {
+	case t == timeType:
+		return object{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return object{}
+	case t == numberType:
+		return object{"type": "number"}
+	case t == fileType:
+		return object{"type": "string", "format": "binary"}
+	case isPatchField(t):
+		return nullable(g.schema(t.Field(2).Type))
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(g.schema(t.Elem()))
+	case reflect.Interface:
+		return object{}
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return object{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return object{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return object{"type": "string", "contentEncoding": "base64"}
+		}
+		return object{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := g.componentName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// Reserve the name first so recursive types terminate
+			g.schemas[name] = object{}
+			g.schemas[name] = g.structSchema(t)
+		}
+		return ref(name)
+	}
+	return object{}
+}
+
+// componentName is the exported form of the type name, qualified by package on a clash
+func (g *generator) componentName(t reflect.Type) string {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if existing, ok := g.types[name]; ok && existing != t {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	g.types[name] = t
+	return name
+}
+
+func (g *generator) structSchema(t reflect.Type) object {
+	properties := object{}
+	var required []string
+	g.addFields(t, properties, &required)
+
+	res := object{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		res["required"] = required
+	}
+	return res
+}
+
+func (g *generator) addFields(t reflect.Type, properties object, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			g.addFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		schema := g.schema(field.Type)
+		if applyBinding(schema, field.Tag.Get("binding"), field.Type, t) {
+			*required = append(*required, name)
+		}
+		properties[name] = schema
+	}
+}
+
+// queryParams lists the form-tagged fields of t as query string parameters
+func (g *generator) queryParams(t reflect.Type) []object {
+	var params []object
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := g.schema(field.Type)
+		params = append(params, object{
+			"name":     name,
+			"in":       "query",
+			"required": applyBinding(schema, field.Tag.Get("binding"), field.Type, t),
+			"schema":   schema,
+		})
+	}
+	return params
+}
+
+// fieldName is the name a struct field is sent as, for rules that refer to it by Go name
+func fieldName(t reflect.Type, name string) string {
+	field, ok := t.FieldByName(name)
+	if !ok {
+		return name
+	}
+	if form := strings.Split(field.Tag.Get("form"), ",")[0]; form != "" {
+		return form
+	}
+	return jsonName(field)
+}
+
+// jsonName is the name a body field is sent as: its json tag, or its form tag in multipart forms
+func jsonName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// applyBinding copies validator constraints from a binding tag onto the schema and reports
+// whether the field is required. Rules after dive apply to elements and are not mapped.
+// parent resolves the fields that cross-field rules refer to.
+func applyBinding(schema object, tag string, t, parent reflect.Type) bool {
+	if tag == "" {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	required := false
+	var notes []string
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "required_without":
+			notes = append(notes, "Required when "+fieldName(parent, param)+" is not set.")
+		case "gtfield":
+			notes = append(notes, "Must be after "+fieldName(parent, param)+".")
+		case "email":
+			schema["format"] = "email"
+		case "url":
+			schema["format"] = "uri"
+		case "numeric":
+			schema["pattern"] = "^[0-9]+$"
+		case "oneof":
+			values := strings.Fields(param)
+			enum := make([]interface{}, len(values))
+			for i, value := range values {
+				enum[i] = value
+			}
+			schema["enum"] = enum
+		case "len":
+			setBound(schema, t, "min", param)
+			setBound(schema, t, "max", param)
+		case "min", "max":
+			setBound(schema, t, name, param)
+		}
+	}
+	if len(notes) > 0 {
+		schema["description"] = strings.Join(notes, " ")
+	}
+	return required
+}
+
+func setBound(schema object, t reflect.Type, bound, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	var keyword string
+	switch t.Kind() {
+	case reflect.String:
+		keyword = bound + "Length"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		keyword = bound + "Items"
+	default:
+		keyword = map[string]string{"min": "minimum", "max": "maximum"}[bound]
+	}
+	if value == float64(int64(value)) {
+		schema[keyword] = int64(value)
+	} else {
+		schema[keyword] = value
+	}
+}
+
+// nullable widens a schema to also accept null
+func nullable(schema object) object {
+	if typ, ok := schema["type"].(string); ok {
+		res := object{}
+		for k, v := range schema {
+			res[k] = v
+		}
+		res["type"] = []string{typ, "null"}
+		return res
+	}
+	return object{"oneOf": []object{schema, {"type": "null"}}}
+}
+
+// isPatchField recognizes schemas.Field, whose members may be set, null or absent
+func isPatchField(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || !strings.HasPrefix(t.Name(), "Field[") || t.NumField() != 3 {
+		return false
+	}
+	return t.Field(0).Name == "Set" && t.Field(1).Name == "Null" && t.Field(2).Name == "Value"
+}
@@ -0,0 +1,64 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+)
+
+// ErrAlreadyErased is returned when erasing an account that has already been anonymized
+var ErrAlreadyErased = errors.New("user has already been erased")
+
+// Actor identifies who triggered an erasure for the audit log. The zero value means the background job.
+type Actor struct {
+	ID        int32
+	RequestID string
+	IP        string
+}
+
+// Erase anonymizes a user's profile and removes their credentials, sessions, memberships and
+// exports. Donations and leaderboard entries keep pointing at the anonymized row so totals and
+// accounting records stay intact, and issued receipts are kept as tax records.
+func (s *Service) Erase(ctx context.Context, userID int32, actor Actor) error {
+	return s.store.ExecTx(ctx, func(q db.Querier) error {
+		user, err := q.GetUserIncludingDeleted(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.AnonymizedAt.Valid {
+			return ErrAlreadyErased
+		}
+
+		anonymized, err := q.AnonymizeUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteUserPersonalData(ctx, userID); err != nil {
+			return err
+		}
+		if err := q.DeleteLoginThrottleForEmail(ctx, user.Email); err != nil {
+			return err
+		}
+		if err := q.DeleteDataExportsByUser(ctx, userID); err != nil {
+			return err
+		}
+
+		// Earlier audit entries keep who did what, but not the personal details they captured
+		if err := q.ScrubAuditLogForUser(ctx, userID); err != nil {
+			return err
+		}
+		_, err = audit.Record(ctx, q, audit.Entry{
+			ActorID:    actor.ID,
+			Action:     audit.ActionDelete,
+			EntityType: audit.EntityUser,
+			EntityID:   fmt.Sprint(userID),
+			After:      anonymized,
+			RequestID:  actor.RequestID,
+			IP:         actor.IP,
+		})
+		return err
+	})
+}
@@ -0,0 +1,306 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+)
+
+// securityEventExportLimit caps how much login history goes into one export
+const securityEventExportLimit = 10000
+
+type profileExport struct {
+	ID              int32      `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	AvatarURL       string     `json:"avatar_url"`
+	UserRole        string     `json:"user_role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       *time.Time `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+type donationExport struct {
+	ID           int32      `json:"id"`
+	CauseID      *int32     `json:"cause_id"`
+	TeamID       *int32     `json:"team_id"`
+	Amount       string     `json:"amount"`
+	DonationType string     `json:"donation_type"`
+	Status       string     `json:"status"`
+	CreatedAt    *time.Time `json:"created_at"`
+}
+
+type achievementExport struct {
+	LeaderboardID   int32  `json:"leaderboard_id"`
+	LeaderboardName string `json:"leaderboard_name"`
+	TeamID          int32  `json:"team_id"`
+	Score           string `json:"score"`
+	Rank            *int32 `json:"rank"`
+}
+
+// tokensExport lists credentials by metadata only; secrets and hashes are never exported
+type tokensExport struct {
+	Sessions   []db.ListUserTokensByUserRow `json:"sessions"`
+	APIKeys    []apiKeyExport               `json:"api_keys"`
+	Identities []identityExport             `json:"identities"`
+}
+
+type apiKeyExport struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	TeamID     *int32     `json:"team_id"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+type identityExport struct {
+	Provider    string    `json:"provider"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+type securityEventExport struct {
+	EventType string    `json:"event_type"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// buildExport writes the user's data to a ZIP archive and returns its bytes
+func (s *Service) buildExport(ctx context.Context, export db.DataExport) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.writeExport(ctx, zip.NewWriter(&buf), export.UserID); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Service) writeExport(ctx context.Context, zw *zip.Writer, userID int32) error {
+	user, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "profile.json", profileExport{
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		FirstName:       user.FirstName.String,
+		LastName:        user.LastName.String,
+		AvatarURL:       user.AvatarUrl.String,
+		UserRole:        user.UserRole.String,
+		EmailVerifiedAt: timePtr(user.EmailVerifiedAt),
+		CreatedAt:       timePtr(user.CreatedAt),
+		UpdatedAt:       timePtr(user.UpdatedAt),
+	}); err != nil {
+		return err
+	}
+
+	donations, err := s.store.ListDonationsByUser(ctx, sql.NullInt32{Int32: userID, Valid: true})
+	if err != nil {
+		return err
+	}
+	donationRows := make([]donationExport, len(donations))
+	donationCSV := make([][]string, len(donations))
+	for i, d := range donations {
+		donationRows[i] = donationExport{
+			ID:           d.ID,
+			CauseID:      int32Ptr(d.CauseID),
+			TeamID:       int32Ptr(d.TeamID),
+			Amount:       d.Amount.String,
+			DonationType: d.DonationType.String,
+			Status:       d.Status.String,
+			CreatedAt:    timePtr(d.CreatedAt),
+		}
+		donationCSV[i] = []string{
+			strconv.Itoa(int(d.ID)), nullInt32String(d.CauseID), nullInt32String(d.TeamID),
+			d.Amount.String, d.DonationType.String, d.Status.String, nullTimeString(d.CreatedAt),
+		}
+	}
+	if err := writeJSON(zw, "donations.json", donationRows); err != nil {
+		return err
+	}
+	if err := writeCSV(zw, "donations.csv", []string{"id", "cause_id", "team_id", "amount", "donation_type", "status", "created_at"}, donationCSV); err != nil {
+		return err
+	}
+
+	teams, err := s.store.ListTeamsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	teamCSV := make([][]string, len(teams))
+	for i, t := range teams {
+		teamCSV[i] = []string{strconv.Itoa(int(t.ID)), t.Name, t.Role, t.JoinedAt.Format(time.RFC3339)}
+	}
+	if err := writeJSON(zw, "teams.json", teams); err != nil {
+		return err
+	}
+	if err := writeCSV(zw, "teams.csv", []string{"id", "name", "role", "joined_at"}, teamCSV); err != nil {
+		return err
+	}
+
+	// Leaderboard standings are the achievements the platform tracks
+	entries, err := s.store.ListLeaderboardEntriesByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	achievements := make([]achievementExport, len(entries))
+	achievementCSV := make([][]string, len(entries))
+	for i, e := range entries {
+		achievements[i] = achievementExport{
+			LeaderboardID:   e.LeaderboardID,
+			LeaderboardName: e.LeaderboardName,
+			TeamID:          e.TeamID,
+			Score:           e.Score.String,
+			Rank:            int32Ptr(e.Rank),
+		}
+		achievementCSV[i] = []string{
+			strconv.Itoa(int(e.LeaderboardID)), e.LeaderboardName, strconv.Itoa(int(e.TeamID)),
+			e.Score.String, nullInt32String(e.Rank),
+		}
+	}
+	if err := writeJSON(zw, "achievements.json", achievements); err != nil {
+		return err
+	}
+	if err := writeCSV(zw, "achievements.csv", []string{"leaderboard_id", "leaderboard_name", "team_id", "score", "rank"}, achievementCSV); err != nil {
+		return err
+	}
+
+	tokens, err := s.tokensExport(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "tokens.json", tokens); err != nil {
+		return err
+	}
+
+	events, err := s.store.ListSecurityEventsByUser(ctx, db.ListSecurityEventsByUserParams{
+		UserID: sql.NullInt32{Int32: userID, Valid: true},
+		Limit:  securityEventExportLimit,
+	})
+	if err != nil {
+		return err
+	}
+	eventRows := make([]securityEventExport, len(events))
+	for i, e := range events {
+		eventRows[i] = securityEventExport{
+			EventType: e.EventType,
+			IP:        e.Ip.String,
+			UserAgent: e.UserAgent.String,
+			CreatedAt: e.CreatedAt,
+		}
+	}
+	if err := writeJSON(zw, "security_events.json", eventRows); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func (s *Service) tokensExport(ctx context.Context, userID int32) (tokensExport, error) {
+	sessions, err := s.store.ListUserTokensByUser(ctx, sql.NullInt32{Int32: userID, Valid: true})
+	if err != nil {
+		return tokensExport{}, err
+	}
+
+	keys, err := s.store.ListAPIKeysByUser(ctx, userID)
+	if err != nil {
+		return tokensExport{}, err
+	}
+	apiKeys := make([]apiKeyExport, len(keys))
+	for i, k := range keys {
+		apiKeys[i] = apiKeyExport{
+			ID:         k.ID,
+			Name:       k.Name,
+			Prefix:     k.Prefix,
+			TeamID:     int32Ptr(k.TeamID),
+			Scopes:     k.Scopes,
+			CreatedAt:  k.CreatedAt,
+			ExpiresAt:  timePtr(k.ExpiresAt),
+			LastUsedAt: timePtr(k.LastUsedAt),
+			RevokedAt:  timePtr(k.RevokedAt),
+		}
+	}
+
+	identities, err := s.store.ListUserIdentities(ctx, userID)
+	if err != nil {
+		return tokensExport{}, err
+	}
+	identityRows := make([]identityExport, len(identities))
+	for i, identity := range identities {
+		identityRows[i] = identityExport{
+			Provider:    identity.Provider,
+			Email:       identity.Email.String,
+			CreatedAt:   identity.CreatedAt,
+			LastLoginAt: identity.LastLoginAt,
+		}
+	}
+
+	return tokensExport{Sessions: sessions, APIKeys: apiKeys, Identities: identityRows}, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeCSV(zw *zip.Writer, name string, header []string, rows [][]string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func int32Ptr(i sql.NullInt32) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func nullInt32String(i sql.NullInt32) string {
+	if !i.Valid {
+		return ""
+	}
+	return strconv.Itoa(int(i.Int32))
+}
+
+func nullTimeString(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
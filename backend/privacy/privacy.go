@@ -0,0 +1,128 @@
+// Package privacy implements self-service data exports and account erasure
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/jobs"
+	"play4good-backend/util"
+)
+
+// Data export statuses
+const (
+	ExportPending   = "pending"
+	ExportRunning   = "running"
+	ExportCompleted = "completed"
+	ExportFailed    = "failed"
+)
+
+// staleExportAfter is how long a claimed export may run before another worker takes it over, on
+// the assumption that the instance building it stopped
+const staleExportAfter = 15 * time.Minute
+
+// errExportReclaimed means another worker took over an export while it was being built
+var errExportReclaimed = errors.New("export was claimed by another worker")
+
+type Service struct {
+	store       *db.Store
+	exportTTL   time.Duration
+	gracePeriod time.Duration
+}
+
+func NewService(store *db.Store, config util.Config) *Service {
+	return &Service{
+		store:       store,
+		exportTTL:   config.ExportTTL,
+		gracePeriod: config.ErasureGracePeriod,
+	}
+}
+
+// GracePeriod is how long a requested erasure waits before it is carried out
+func (s *Service) GracePeriod() time.Duration {
+	return s.gracePeriod
+}
+
+// Tasks returns the background jobs that build exports, remove expired ones and carry out due erasures
+func (s *Service) Tasks() []jobs.Task {
+	return []jobs.Task{
+		{Name: "data-exports", Interval: 30 * time.Second, Run: s.ProcessExports},
+		{Name: "expired-data-exports", Interval: time.Hour, Run: s.RemoveExpiredExports},
+		{Name: "account-erasures", Interval: 10 * time.Minute, Run: s.ProcessErasures},
+	}
+}
+
+// ProcessExports builds every pending export, one at a time, so several instances can share the
+// queue. Archives are stored in the database, so any instance can serve the download, and an export
+// left running by an instance that stopped is claimed again once it is stale.
+func (s *Service) ProcessExports(ctx context.Context) error {
+	for {
+		export, err := s.store.ClaimPendingDataExport(ctx, staleExportAfter.Seconds())
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		archive, err := s.buildExport(ctx, export)
+		if err != nil {
+			slog.ErrorContext(ctx, "data export failed", "export_id", export.ID, "error", err)
+			if err := s.store.FailDataExport(ctx, db.FailDataExportParams{
+				ID:        export.ID,
+				StartedAt: export.StartedAt,
+				Error:     sql.NullString{String: err.Error(), Valid: true},
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = s.store.ExecTx(ctx, func(q db.Querier) error {
+			n, err := q.CompleteDataExport(ctx, db.CompleteDataExportParams{
+				ID:        export.ID,
+				StartedAt: export.StartedAt,
+				ExpiresAt: sql.NullTime{Time: time.Now().Add(s.exportTTL), Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return errExportReclaimed
+			}
+			return q.CreateDataExportArchive(ctx, db.CreateDataExportArchiveParams{ExportID: export.ID, Content: archive})
+		})
+		if errors.Is(err, errExportReclaimed) {
+			slog.WarnContext(ctx, "data export was taken over by another worker", "export_id", export.ID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// RemoveExpiredExports deletes export archives once their download window has passed
+func (s *Service) RemoveExpiredExports(ctx context.Context) error {
+	_, err := s.store.DeleteExpiredDataExports(ctx)
+	return err
+}
+
+// ProcessErasures anonymizes every account whose grace period has ended
+func (s *Service) ProcessErasures(ctx context.Context) error {
+	ids, err := s.store.ListDueErasures(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.Erase(ctx, id, Actor{}); err != nil {
+			return fmt.Errorf("erase user %d: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+// Package problem writes API errors as RFC 7807 problem details with stable, machine-readable codes.
+package problem
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"play4good-backend/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// typePrefix makes a code into the problem's type URI
+const typePrefix = "urn:play4good:problem:"
+
+// Codes clients can branch on. They are part of the API contract; add new ones rather than renaming.
+const (
+	CodeBadRequest            = "bad_request"
+	CodeMalformedBody         = "malformed_body"
+	CodeInvalidParameter      = "invalid_parameter"
+	CodeValidationFailed      = "validation_failed"
+	CodeUnauthorized          = "unauthorized"
+	CodeInvalidCredentials    = "invalid_credentials"
+	CodeLoginChallengeExpired = "login_challenge_expired"
+	CodeInvalidTwoFactorCode  = "invalid_two_factor_code"
+	CodeTwoFactorRequired     = "two_factor_required"
+	CodeAccountLocked         = "account_locked"
+	CodeForbidden             = "forbidden"
+	CodeNotFound              = "not_found"
+	CodeRouteNotFound         = "route_not_found"
+	CodeConflict              = "conflict"
+	CodeAlreadyExists         = "already_exists"
+	CodeEmailTaken            = "email_taken"
+	CodeExportNotReady        = "export_not_ready"
+	CodeExportExpired         = "export_expired"
+	CodeVersionConflict       = "version_conflict"
+	CodeUnprocessable         = "unprocessable_entity"
+	CodeReferenceNotFound     = "reference_not_found"
+	CodeConstraintViolation   = "constraint_violation"
+	CodePreconditionRequired  = "precondition_required"
+	CodeUnsupportedMedia      = "unsupported_media_type"
+	CodePayloadTooLarge       = "payload_too_large"
+	CodeLocked                = "locked"
+	CodeRateLimited           = "rate_limited"
+	CodeInternal              = "internal_error"
+	CodeUnavailable           = "unavailable"
+)
+
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusPreconditionFailed:    CodeVersionConflict,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMedia,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnprocessableEntity:   CodeUnprocessable,
+	http.StatusLocked:                CodeLocked,
+	http.StatusPreconditionRequired:  CodePreconditionRequired,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+}
+
+// FieldError is one failed validation rule on a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Problem is an RFC 7807 problem detail. It also implements error, so handlers and services can
+// return one to choose the status and code of the response.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	return p.Detail
+}
+
+// New returns a problem with the given status. An empty code defaults to the status's code.
+func New(status int, code, detail string) *Problem {
+	if code == "" {
+		code = statusCode(status)
+	}
+	return &Problem{
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+// WithErrors returns a copy of p listing the fields that failed validation
+func (p *Problem) WithErrors(errs ...FieldError) *Problem {
+	res := *p
+	res.Errors = append([]FieldError(nil), errs...)
+	return &res
+}
+
+// Invalid is a validation_failed problem for a single field
+func Invalid(field, rule, message string) *Problem {
+	return New(http.StatusBadRequest, CodeValidationFailed, field+" "+message).
+		WithErrors(FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// From classifies err. Problems, validation errors, malformed bodies and Postgres errors get their
+// own status and code; anything else gets status. Details of server errors are not exposed.
+func From(err error, status int) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		copied := *p
+		return &copied
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		res := New(http.StatusBadRequest, CodeValidationFailed, "The request has invalid fields")
+		for _, fe := range validationErrs {
+			res.Errors = append(res.Errors, FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: Describe(fe),
+			})
+		}
+		return res
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return New(http.StatusBadRequest, CodeMalformedBody, "The request body is not valid JSON")
+	}
+	if errors.As(err, &typeErr) {
+		res := New(http.StatusBadRequest, CodeValidationFailed, "The request has invalid fields")
+		res.Errors = []FieldError{{Field: typeErr.Field, Rule: "type", Message: "must be a " + typeErr.Type.String()}}
+		return res
+	}
+
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return New(http.StatusBadRequest, CodeInvalidParameter, "Invalid number "+strconv.Quote(numErr.Num))
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if res := fromPostgres(pqErr); res != nil {
+			return res
+		}
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		if status == http.StatusNotFound || status >= http.StatusInternalServerError {
+			return New(http.StatusNotFound, CodeNotFound, "The resource does not exist")
+		}
+		return New(status, "", "")
+	}
+
+	if status >= http.StatusInternalServerError {
+		return New(status, "", "")
+	}
+	return New(status, "", err.Error())
+}
+
+// fromPostgres maps constraint and input errors to client errors; other database errors stay 500s
+func fromPostgres(err *pq.Error) *Problem {
+	switch err.Code.Name() {
+	case "unique_violation":
+		res := New(http.StatusConflict, CodeAlreadyExists, "A record with the same value already exists")
+		res.Errors = constraintField(err, "unique")
+		return res
+	case "foreign_key_violation":
+		res := New(http.StatusUnprocessableEntity, CodeReferenceNotFound, "A referenced record does not exist")
+		res.Errors = constraintField(err, "exists")
+		return res
+	case "not_null_violation", "check_violation", "exclusion_violation":
+		res := New(http.StatusUnprocessableEntity, CodeConstraintViolation, "The request breaks a data constraint")
+		res.Errors = constraintField(err, err.Code.Name())
+		return res
+	case "invalid_text_representation", "numeric_value_out_of_range", "invalid_datetime_format",
+		"datetime_field_overflow", "string_data_right_truncation":
+		return New(http.StatusBadRequest, CodeInvalidParameter, "A value has the wrong format or is out of range")
+	}
+	return nil
+}
+
+func constraintField(err *pq.Error, rule string) []FieldError {
+	field := err.Column
+	if field == "" {
+		field = err.Constraint
+	}
+	if field == "" {
+		return nil
+	}
+	return []FieldError{{Field: field, Rule: rule, Message: err.Message}}
+}
+
+func statusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// Write sends p, filling in the request path and ID, and stops the handler chain
+func Write(ctx *gin.Context, p *Problem) {
+	res := *p
+	res.Instance = ctx.Request.URL.Path
+	res.RequestID = ctx.Writer.Header().Get("X-Request-ID")
+	res.TraceID, _ = tracing.IDs(ctx.Request.Context())
+
+	ctx.Header("Content-Type", ContentType)
+	ctx.AbortWithStatusJSON(res.Status, res)
+}
+
+// Respond classifies err with From and writes it, logging server errors since their details are hidden
+func Respond(ctx *gin.Context, status int, err error) {
+	p := From(err, status)
+	if p.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "request failed", "method", ctx.Request.Method, "path", ctx.Request.URL.Path, "error", err)
+		trace.SpanFromContext(ctx.Request.Context()).RecordError(err)
+	}
+	Write(ctx, p)
+}
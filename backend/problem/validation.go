@@ -0,0 +1,88 @@
+package problem
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report fields by the names clients send rather than Go struct field names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(wireName)
+	}
+}
+
+func wireName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Describe puts a failed rule into words, e.g. "must be at least 3 characters"
+func Describe(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return "is required when " + snakeCase(param) + " is not set"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "numeric":
+		return "must contain only digits"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "gtfield":
+		return "must be after " + snakeCase(param)
+	case "len":
+		if fe.Kind() == reflect.String {
+			return "must be exactly " + param + " characters"
+		}
+		return "must have exactly " + param + " items"
+	case "min":
+		switch fe.Kind() {
+		case reflect.String:
+			return "must be at least " + param + " characters"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return "must have at least " + param + " items"
+		}
+		return "must be at least " + param
+	case "max":
+		switch fe.Kind() {
+		case reflect.String:
+			return "must be at most " + param + " characters"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return "must have at most " + param + " items"
+		}
+		return "must be at most " + param
+	}
+	return "failed the " + fe.Tag() + " rule"
+}
+
+// snakeCase turns the Go field names cross-field rules refer to into the names clients send
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,276 @@
+// Package receipts lays out donation receipts and yearly donor statements as PDFs. It only renders;
+// numbering, storage and voiding are done by the receipt service, which keeps every PDF it issues
+// exactly as it was rendered.
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Receipt kinds, as stored in receipts.kind
+const (
+	KindDonation  = "donation"
+	KindStatement = "statement"
+)
+
+// DefaultOrganizationName is printed when no template names the organization
+const DefaultOrganizationName = "Play4Good"
+
+// defaultAccent is the colour of the header rule and table headings when no template sets one
+var defaultAccent = [3]int{0x1f, 0x6f, 0x5c}
+
+// Number formats the n-th receipt of kind issued in year, such as R-2026-000042. Donation receipts
+// and statements are numbered in separate series.
+func Number(kind string, year int, n int32) string {
+	return fmt.Sprintf("%s-%d-%06d", Series(kind), year, n)
+}
+
+// Series is the prefix, and counter, that receipts of kind are numbered in
+func Series(kind string) string {
+	if kind == KindStatement {
+		return "S"
+	}
+	return "R"
+}
+
+// Branding is the organization details and wording printed on a receipt. Cause templates are merged
+// over the organization's, field by field.
+type Branding struct {
+	OrganizationName   string
+	Address            string
+	RegistrationNumber string
+	ContactEmail       string
+	// AccentColor is a hex colour such as #1f6f5c
+	AccentColor string
+	HeaderText  string
+	FooterText  string
+	Signatory   string
+}
+
+// Merge returns b with every field that over sets replaced
+func (b Branding) Merge(over Branding) Branding {
+	pick := func(base, override string) string {
+		if override != "" {
+			return override
+		}
+		return base
+	}
+	return Branding{
+		OrganizationName:   pick(b.OrganizationName, over.OrganizationName),
+		Address:            pick(b.Address, over.Address),
+		RegistrationNumber: pick(b.RegistrationNumber, over.RegistrationNumber),
+		ContactEmail:       pick(b.ContactEmail, over.ContactEmail),
+		AccentColor:        pick(b.AccentColor, over.AccentColor),
+		HeaderText:         pick(b.HeaderText, over.HeaderText),
+		FooterText:         pick(b.FooterText, over.FooterText),
+		Signatory:          pick(b.Signatory, over.Signatory),
+	}
+}
+
+// Line is one donation on a receipt
+type Line struct {
+	Date         time.Time
+	Cause        string
+	DonationType string
+	// Amount is a decimal string such as "25.50"
+	Amount string
+}
+
+// Document is everything printed on a receipt or statement
+type Document struct {
+	Kind     string
+	Number   string
+	IssuedAt time.Time
+	// Replaces is the number of the voided receipt this one is issued in place of
+	Replaces   string
+	Year       int
+	Branding   Branding
+	DonorName  string
+	DonorEmail string
+	Lines      []Line
+}
+
+// Render lays doc out as a PDF. The same document always renders to the same bytes.
+func Render(doc Document) ([]byte, error) {
+	total, err := Total(doc.Lines)
+	if err != nil {
+		return nil, err
+	}
+	totalText, err := formatAmount(total)
+	if err != nil {
+		return nil, err
+	}
+	words, err := AmountInWords(total)
+	if err != nil {
+		return nil, err
+	}
+	brand := doc.Branding
+	if brand.OrganizationName == "" {
+		brand.OrganizationName = DefaultOrganizationName
+	}
+	accent := parseColor(brand.AccentColor)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(doc.IssuedAt)
+	pdf.SetModificationDate(doc.IssuedAt)
+	pdf.SetCatalogSort(true)
+	// The core fonts are single-byte; names and addresses are translated from UTF-8
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	title := "Donation receipt"
+	if doc.Kind == KindStatement {
+		title = fmt.Sprintf("Donation statement %d", doc.Year)
+	}
+	pdf.SetTitle(title+" "+doc.Number, true)
+	pdf.SetAuthor(brand.OrganizationName, true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, 5, tr(fmt.Sprintf("%s %s - page %d of {nb}", title, doc.Number, pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	content := width - left - right
+
+	// Organization
+	pdf.SetFillColor(accent[0], accent[1], accent[2])
+	pdf.Rect(left, 10, content, 2, "F")
+	pdf.SetY(16)
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.SetTextColor(accent[0], accent[1], accent[2])
+	pdf.MultiCell(content, 8, tr(brand.OrganizationName), "", "L", false)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetTextColor(60, 60, 60)
+	for _, line := range []string{brand.Address, labelled("Registration number", brand.RegistrationNumber), brand.ContactEmail} {
+		if line != "" {
+			pdf.MultiCell(content, 4.5, tr(line), "", "L", false)
+		}
+	}
+	pdf.Ln(6)
+
+	// Receipt details
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(content, 8, tr(strings.ToUpper(title)), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	details := [][2]string{
+		{"Receipt number", doc.Number},
+		{"Date issued", doc.IssuedAt.Format("2 January 2006")},
+	}
+	if doc.Replaces != "" {
+		details = append(details, [2]string{"Replaces", "receipt " + doc.Replaces + ", which is void"})
+	}
+	details = append(details, [2]string{"Received from", doc.DonorName}, [2]string{"Email", doc.DonorEmail})
+	for _, d := range details {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 6, tr(d[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(content-40, 6, tr(d[1]), "", 1, "L", false, 0, "")
+	}
+	if brand.HeaderText != "" {
+		pdf.Ln(4)
+		pdf.MultiCell(content, 5, tr(brand.HeaderText), "", "L", false)
+	}
+	pdf.Ln(6)
+
+	// Donations
+	columns := []struct {
+		title string
+		width float64
+		align string
+	}{
+		{"Date", 30, "L"},
+		{"Cause", content - 30 - 30 - 35, "L"},
+		{"Type", 30, "L"},
+		{"Amount", 35, "R"},
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(accent[0], accent[1], accent[2])
+	pdf.SetTextColor(255, 255, 255)
+	for _, col := range columns {
+		pdf.CellFormat(col.width, 7, col.title, "", 0, col.align, true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetDrawColor(200, 200, 200)
+	for _, line := range doc.Lines {
+		amount, err := formatAmount(line.Amount)
+		if err != nil {
+			return nil, err
+		}
+		cells := []string{line.Date.Format("2006-01-02"), line.Cause, line.DonationType, amount}
+		for i, col := range columns {
+			pdf.CellFormat(col.width, 7, tr(fit(pdf, tr, cells[i], col.width-2)), "B", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(content-35, 9, "Total", "", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 9, totalText, "", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "I", 10)
+	pdf.MultiCell(content, 5, tr("Amount in words: "+words), "", "L", false)
+	pdf.Ln(12)
+
+	// Signature and footer
+	if brand.Signatory != "" {
+		pdf.SetDrawColor(0, 0, 0)
+		y := pdf.GetY()
+		pdf.Line(left, y, left+70, y)
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(70, 6, tr(brand.Signatory), "", 1, "L", false, 0, "")
+		pdf.CellFormat(70, 5, tr("For "+brand.OrganizationName), "", 1, "L", false, 0, "")
+		pdf.Ln(6)
+	}
+	if brand.FooterText != "" {
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(60, 60, 60)
+		pdf.MultiCell(content, 4.5, tr(brand.FooterText), "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fit shortens s with an ellipsis until it fits width once translated
+func fit(pdf *fpdf.Fpdf, tr func(string) string, s string, width float64) string {
+	if pdf.GetStringWidth(tr(s)) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(tr(string(runes)+"...")) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func labelled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + ": " + value
+}
+
+// parseColor reads a #rgb or #rrggbb colour, falling back to the default accent
+func parseColor(hex string) [3]int {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return defaultAccent
+	}
+	return [3]int{int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff)}
+}
@@ -0,0 +1,100 @@
+package receipts
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestAmountInWords(t *testing.T) {
+	for amount, want := range map[string]string{
+		"0.50":         "Zero and 50/100",
+		"7":            "Seven and 00/100",
+		"25.5":         "Twenty-five and 50/100",
+		"125.50":       "One hundred twenty-five and 50/100",
+		"1000.00":      "One thousand and 00/100",
+		"2019.07":      "Two thousand nineteen and 07/100",
+		"1300040.99":   "One million three hundred thousand forty and 99/100",
+		"999999999.01": "Nine hundred ninety-nine million nine hundred ninety-nine thousand nine hundred ninety-nine and 01/100",
+	} {
+		got, err := AmountInWords(amount)
+		if err != nil || got != want {
+			t.Errorf("AmountInWords(%q) = %q, %v; want %q", amount, got, err, want)
+		}
+	}
+	for _, amount := range []string{"abc", "1.234", "-5.00", "1e3"} {
+		if _, err := AmountInWords(amount); err == nil {
+			t.Errorf("AmountInWords(%q) should fail", amount)
+		}
+	}
+}
+
+func TestTotal(t *testing.T) {
+	total, err := Total([]Line{{Amount: "10"}, {Amount: "15.5"}, {Amount: "0.05"}})
+	if err != nil || total != "25.55" {
+		t.Errorf("Total = %q, %v; want 25.55", total, err)
+	}
+	if formatted, _ := formatAmount("1234567.8"); formatted != "1,234,567.80" {
+		t.Errorf("formatAmount = %q, want 1,234,567.80", formatted)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	if got := Number(KindDonation, 2026, 42); got != "R-2026-000042" {
+		t.Errorf("donation receipt number = %s", got)
+	}
+	if got := Number(KindStatement, 2026, 7); got != "S-2026-000007" {
+		t.Errorf("statement number = %s", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	organization := Branding{OrganizationName: "Play4Good", Address: "1 Main St", FooterText: "Thank you"}
+	cause := Branding{OrganizationName: "Clean Rivers Trust", AccentColor: "#0055aa"}
+	got := organization.Merge(cause)
+	want := Branding{OrganizationName: "Clean Rivers Trust", Address: "1 Main St", AccentColor: "#0055aa", FooterText: "Thank you"}
+	if got != want {
+		t.Errorf("Merge = %+v, want %+v", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	doc := Document{
+		Kind:       KindStatement,
+		Number:     "S-2026-000001",
+		IssuedAt:   time.Date(2027, 1, 5, 10, 0, 0, 0, time.UTC),
+		Replaces:   "S-2026-000000",
+		Year:       2026,
+		Branding:   Branding{Address: "1 Main St\nSpringfield", AccentColor: "#c33", Signatory: "Zoë Treasurer"},
+		DonorName:  "Renée Donor",
+		DonorEmail: "renee@example.com",
+	}
+	for i := 0; i < 60; i++ {
+		doc.Lines = append(doc.Lines, Line{
+			Date:         time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i),
+			Cause:        "A cause with a name far too long to fit in its column on the statement",
+			DonationType: "money",
+			Amount:       "12.50",
+		})
+	}
+
+	first, err := Render(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(first, []byte("%PDF-")) {
+		t.Fatalf("Render did not produce a PDF: %q", first[:16])
+	}
+	second, err := Render(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("rendering the same document twice gave different bytes")
+	}
+
+	doc.Lines[0].Amount = "not money"
+	if _, err := Render(doc); err == nil {
+		t.Error("Render accepted an invalid amount")
+	}
+}
@@ -0,0 +1,118 @@
+package receipts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ones = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scales = []string{"", "thousand", "million", "billion"}
+)
+
+// cents parses a decimal amount such as "1234.5" into cents, without going through floating point
+func cents(amount string) (int64, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("amount %q has more than two decimal places", amount)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w < 0 {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	f, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	return w*100 + f, nil
+}
+
+// Total adds up the amounts of lines as a decimal string with two places
+func Total(lines []Line) (string, error) {
+	var total int64
+	for _, line := range lines {
+		c, err := cents(line.Amount)
+		if err != nil {
+			return "", err
+		}
+		total += c
+	}
+	return fmt.Sprintf("%d.%02d", total/100, total%100), nil
+}
+
+// formatAmount writes a decimal amount with thousands separators, as printed on receipts
+func formatAmount(amount string) (string, error) {
+	c, err := cents(amount)
+	if err != nil {
+		return "", err
+	}
+	whole := strconv.FormatInt(c/100, 10)
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s.%02d", b.String(), c%100), nil
+}
+
+// AmountInWords spells out a decimal amount the way it is written on a cheque, with the cents as a
+// fraction: "125.50" is "One hundred twenty-five and 50/100".
+func AmountInWords(amount string) (string, error) {
+	c, err := cents(amount)
+	if err != nil {
+		return "", err
+	}
+	whole := c / 100
+	if whole >= 1_000_000_000_000 {
+		return "", fmt.Errorf("amount %q is too large to write out", amount)
+	}
+
+	var words []string
+	if whole == 0 {
+		words = append(words, ones[0])
+	}
+	for scale := len(scales) - 1; scale >= 0; scale-- {
+		unit := int64(1)
+		for i := 0; i < scale; i++ {
+			unit *= 1000
+		}
+		group := whole / unit % 1000
+		if group == 0 {
+			continue
+		}
+		words = append(words, hundreds(group))
+		if scales[scale] != "" {
+			words = append(words, scales[scale])
+		}
+	}
+
+	text := strings.Join(words, " ")
+	return fmt.Sprintf("%s%s and %02d/100", strings.ToUpper(text[:1]), text[1:], c%100), nil
+}
+
+// hundreds spells out a number below a thousand
+func hundreds(n int64) string {
+	var words []string
+	if n >= 100 {
+		words = append(words, ones[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n >= 20 && n%10 != 0:
+		words = append(words, tens[n/10]+"-"+ones[n%10])
+	case n >= 20:
+		words = append(words, tens[n/10])
+	case n > 0:
+		words = append(words, ones[n])
+	}
+	return strings.Join(words, " ")
+}
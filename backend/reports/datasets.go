@@ -0,0 +1,155 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+)
+
+// Datasets that can be exported
+const (
+	DatasetDonations = "donations"
+	DatasetDonors    = "donors"
+	DatasetTeams     = "teams"
+)
+
+// Filter narrows the donations a report covers. Zero fields do not filter. Donor and team reports
+// total the donations that match.
+type Filter struct {
+	From    time.Time `json:"from,omitempty"`
+	To      time.Time `json:"to,omitempty"`
+	CauseID int32     `json:"cause_id,omitempty"`
+	TeamID  int32     `json:"team_id,omitempty"`
+	Status  string    `json:"status,omitempty"`
+}
+
+// amount is a decimal read from a numeric column. CSV writes it as stored, JSON as a number and XLSX
+// as a numeric cell.
+type amount string
+
+// dataset reads one kind of report a page at a time. page returns the rows after key after, each
+// with a value for every column, and the key of the last row.
+type dataset struct {
+	columns []string
+	page    func(ctx context.Context, q db.Querier, f Filter, after, limit int32) ([][]interface{}, int32, error)
+}
+
+var datasets = map[string]dataset{
+	DatasetDonations: {
+		columns: []string{"id", "created_at", "amount", "donation_type", "status", "user_id", "donor_username", "donor_email",
+			"cause_id", "cause_name", "team_id", "team_name", "source", "import_id"},
+		page: func(ctx context.Context, q db.Querier, f Filter, after, limit int32) ([][]interface{}, int32, error) {
+			rows, err := q.ExportDonations(ctx, db.ExportDonationsParams{
+				AfterID:     after,
+				CreatedFrom: nullTime(f.From),
+				CreatedTo:   nullTime(f.To),
+				CauseID:     nullInt32(f.CauseID),
+				TeamID:      nullInt32(f.TeamID),
+				Status:      nullString(f.Status),
+				Limit:       limit,
+			})
+			if err != nil || len(rows) == 0 {
+				return nil, after, err
+			}
+			values := make([][]interface{}, len(rows))
+			for i, r := range rows {
+				values[i] = []interface{}{r.ID, value(r.CreatedAt), decimal(r.Amount), value(r.DonationType), value(r.Status),
+					value(r.UserID), value(r.DonorUsername), value(r.DonorEmail), value(r.CauseID), value(r.CauseName),
+					value(r.TeamID), value(r.TeamName), value(r.Source), value(r.ImportID)}
+			}
+			return values, rows[len(rows)-1].ID, nil
+		},
+	},
+	DatasetDonors: {
+		columns: []string{"user_id", "username", "email", "first_name", "last_name", "donation_count", "total_amount",
+			"first_donation_at", "last_donation_at"},
+		page: func(ctx context.Context, q db.Querier, f Filter, after, limit int32) ([][]interface{}, int32, error) {
+			rows, err := q.ExportDonors(ctx, db.ExportDonorsParams{
+				AfterID:     after,
+				CreatedFrom: nullTime(f.From),
+				CreatedTo:   nullTime(f.To),
+				CauseID:     nullInt32(f.CauseID),
+				TeamID:      nullInt32(f.TeamID),
+				Status:      nullString(f.Status),
+				Limit:       limit,
+			})
+			if err != nil || len(rows) == 0 {
+				return nil, after, err
+			}
+			values := make([][]interface{}, len(rows))
+			for i, r := range rows {
+				values[i] = []interface{}{r.ID, r.Username, r.Email, value(r.FirstName), value(r.LastName), r.DonationCount,
+					amount(r.TotalAmount), r.FirstDonationAt, r.LastDonationAt}
+			}
+			return values, rows[len(rows)-1].ID, nil
+		},
+	},
+	DatasetTeams: {
+		columns: []string{"team_id", "name", "created_at", "member_count", "donation_count", "donor_count", "total_amount"},
+		page: func(ctx context.Context, q db.Querier, f Filter, after, limit int32) ([][]interface{}, int32, error) {
+			rows, err := q.ExportTeamStats(ctx, db.ExportTeamStatsParams{
+				AfterID:     after,
+				CreatedFrom: nullTime(f.From),
+				CreatedTo:   nullTime(f.To),
+				CauseID:     nullInt32(f.CauseID),
+				TeamID:      nullInt32(f.TeamID),
+				Status:      nullString(f.Status),
+				Limit:       limit,
+			})
+			if err != nil || len(rows) == 0 {
+				return nil, after, err
+			}
+			values := make([][]interface{}, len(rows))
+			for i, r := range rows {
+				values[i] = []interface{}{r.ID, r.Name, value(r.CreatedAt), r.MemberCount, r.DonationCount, r.DonorCount,
+					amount(r.TotalAmount)}
+			}
+			return values, rows[len(rows)-1].ID, nil
+		},
+	},
+}
+
+// Columns lists the columns of a dataset in their default order
+func Columns(name string) []string {
+	return append([]string(nil), datasets[name].columns...)
+}
+
+// value unwraps a nullable column, giving nil for NULL
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case sql.NullString:
+		if v.Valid {
+			return v.String
+		}
+	case sql.NullInt32:
+		if v.Valid {
+			return v.Int32
+		}
+	case sql.NullTime:
+		if v.Valid {
+			return v.Time
+		}
+	}
+	return nil
+}
+
+func decimal(v sql.NullString) interface{} {
+	if !v.Valid {
+		return nil
+	}
+	return amount(v.String)
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func nullInt32(n int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: n, Valid: n != 0}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
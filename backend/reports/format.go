@@ -0,0 +1,228 @@
+package reports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"play4good-backend/problem"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats a report can be written in
+const (
+	FormatCSV    = "csv"
+	FormatXLSX   = "xlsx"
+	FormatNDJSON = "ndjson"
+)
+
+// ErrTooManyRowsForXLSX stops an XLSX report at the worksheet's row limit
+var ErrTooManyRowsForXLSX error = problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable,
+	fmt.Sprintf("XLSX worksheets hold at most %d rows; narrow the filters or export CSV or JSON Lines", excelize.TotalRows-1))
+
+// ContentType is the media type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// writer writes report rows in one format. flush passes the rows written so far on to the
+// underlying writer; close finishes the file.
+type writer interface {
+	header(columns []string) error
+	row(values []interface{}) error
+	flush() error
+	close() error
+}
+
+func newWriter(format string, w io.Writer) writer {
+	switch format {
+	case FormatXLSX:
+		return &xlsxWriter{w: w}
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w, buf: bufio.NewWriter(w)}
+	}
+	return &csvWriter{w: w, csv: csv.NewWriter(w)}
+}
+
+// flushTo pushes buffered output to clients as it is written, when w is a response
+func flushTo(w io.Writer) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type csvWriter struct {
+	w   io.Writer
+	csv *csv.Writer
+}
+
+func (c *csvWriter) header(columns []string) error {
+	return c.csv.Write(columns)
+}
+
+func (c *csvWriter) row(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = text(v)
+	}
+	return c.csv.Write(record)
+}
+
+func (c *csvWriter) flush() error {
+	c.csv.Flush()
+	flushTo(c.w)
+	return c.csv.Error()
+}
+
+func (c *csvWriter) close() error {
+	return c.flush()
+}
+
+func text(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case amount:
+		return string(v)
+	case int32:
+		return strconv.Itoa(int(v))
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+// ndjsonWriter writes one JSON object per line, with keys in column order
+type ndjsonWriter struct {
+	w       io.Writer
+	buf     *bufio.Writer
+	columns [][]byte
+}
+
+func (n *ndjsonWriter) header(columns []string) error {
+	for _, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		n.columns = append(n.columns, key)
+	}
+	return nil
+}
+
+func (n *ndjsonWriter) row(values []interface{}) error {
+	n.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			n.buf.WriteByte(',')
+		}
+		n.buf.Write(n.columns[i])
+		n.buf.WriteByte(':')
+		if a, ok := v.(amount); ok {
+			v = json.Number(a)
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		n.buf.Write(encoded)
+	}
+	n.buf.WriteString("}\n")
+	return nil
+}
+
+func (n *ndjsonWriter) flush() error {
+	if err := n.buf.Flush(); err != nil {
+		return err
+	}
+	flushTo(n.w)
+	return nil
+}
+
+func (n *ndjsonWriter) close() error {
+	return n.flush()
+}
+
+// xlsxWriter streams rows into a single worksheet. excelize keeps the sheet in a temporary file
+// once it grows, and the workbook is only written out on close.
+type xlsxWriter struct {
+	w    io.Writer
+	file *excelize.File
+	rows *excelize.StreamWriter
+	next int
+}
+
+func (x *xlsxWriter) header(columns []string) error {
+	x.file = excelize.NewFile()
+	rows, err := x.file.NewStreamWriter(x.file.GetSheetName(0))
+	if err != nil {
+		return err
+	}
+	x.rows = rows
+	x.next = 1
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+	return x.write(values)
+}
+
+func (x *xlsxWriter) row(values []interface{}) error {
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case amount:
+			f, err := strconv.ParseFloat(string(v), 64)
+			if err != nil {
+				return err
+			}
+			cells[i] = f
+		case time.Time:
+			cells[i] = v.UTC()
+		default:
+			cells[i] = v
+		}
+	}
+	return x.write(cells)
+}
+
+func (x *xlsxWriter) write(values []interface{}) error {
+	if x.next > excelize.TotalRows {
+		return ErrTooManyRowsForXLSX
+	}
+	cell, err := excelize.CoordinatesToCellName(1, x.next)
+	if err != nil {
+		return err
+	}
+	x.next++
+	return x.rows.SetRow(cell, values)
+}
+
+// flush does nothing: a workbook cannot be read until it is complete
+func (x *xlsxWriter) flush() error {
+	return nil
+}
+
+func (x *xlsxWriter) close() error {
+	defer x.file.Close()
+	if err := x.rows.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.w)
+}
@@ -0,0 +1,105 @@
+package reports
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"play4good-backend/problem"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	testColumns = []string{"id", "created_at", "amount", "cause_name", "team_id"}
+	testRows    = [][]interface{}{
+		{int32(1), time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC), amount("25.50"), "Clean \"Rivers\", Ltd", int32(4)},
+		{int32(2), nil, amount("1000.00"), nil, nil},
+	}
+)
+
+func writeAll(t *testing.T, format string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	out := newWriter(format, &buf)
+	if err := out.header(testColumns); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range testRows {
+		if err := out.row(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := out.close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCSV(t *testing.T) {
+	want := "id,created_at,amount,cause_name,team_id\n" +
+		"1,2026-03-01T09:30:00Z,25.50,\"Clean \"\"Rivers\"\", Ltd\",4\n" +
+		"2,,1000.00,,\n"
+	if got := string(writeAll(t, FormatCSV)); got != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	// Keys keep column order and amounts are numbers
+	want := `{"id":1,"created_at":"2026-03-01T09:30:00Z","amount":25.50,"cause_name":"Clean \"Rivers\", Ltd","team_id":4}` + "\n" +
+		`{"id":2,"created_at":null,"amount":1000.00,"cause_name":null,"team_id":null}` + "\n"
+	if got := string(writeAll(t, FormatNDJSON)); got != want {
+		t.Errorf("NDJSON =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	f, err := excelize.OpenReader(bytes.NewReader(writeAll(t, FormatXLSX)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows(f.GetSheetName(0), excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(testColumns, ",") {
+		t.Fatalf("rows = %q, want a header and two rows", rows)
+	}
+	if rows[1][2] != "25.5" || rows[2][2] != "1000" {
+		t.Errorf("amounts = %q and %q, want numeric cells", rows[1][2], rows[2][2])
+	}
+	created, err := f.GetCellValue(f.GetSheetName(0), "B2")
+	if err != nil || created == "" || created == rows[1][1] {
+		t.Errorf("created_at shows as %q, want a formatted date", created)
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	spec := Spec{Dataset: DatasetTeams}
+	if err := spec.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Format != FormatCSV || strings.Join(spec.Columns, ",") != strings.Join(Columns(DatasetTeams), ",") {
+		t.Errorf("defaults = %+v, want CSV with every team column", spec)
+	}
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, test := range map[string]struct {
+		spec  Spec
+		field string
+	}{
+		"dataset":  {Spec{Dataset: "users"}, "dataset"},
+		"format":   {Spec{Dataset: DatasetDonations, Format: "pdf"}, "format"},
+		"columns":  {Spec{Dataset: DatasetDonors, Columns: []string{"email", "password_hash"}}, "columns"},
+		"backward": {Spec{Dataset: DatasetDonations, Filter: Filter{From: from, To: from.AddDate(0, 0, -1)}}, "to"},
+	} {
+		var p *problem.Problem
+		if err := test.spec.Validate(); !errors.As(err, &p) || len(p.Errors) != 1 || p.Errors[0].Field != test.field {
+			t.Errorf("%s: err = %v, want a problem with %s", name, err, test.field)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/jobs"
+	"play4good-backend/util"
+)
+
+// Report export job statuses
+const (
+	ExportPending   = "pending"
+	ExportRunning   = "running"
+	ExportCompleted = "completed"
+	ExportFailed    = "failed"
+)
+
+// staleExportAfter is how long a claimed report may run before another worker takes it over, on
+// the assumption that the instance building it stopped
+const staleExportAfter = 15 * time.Minute
+
+// errExportReclaimed means another worker took over a report while it was being built
+var errExportReclaimed = errors.New("report was claimed by another worker")
+
+// Service queues reports to build in the background and cleans them up once they expire
+type Service struct {
+	store     *db.Store
+	exportTTL time.Duration
+}
+
+func NewService(store *db.Store, config util.Config) *Service {
+	return &Service{
+		store:     store,
+		exportTTL: config.ExportTTL,
+	}
+}
+
+// Tasks returns the background jobs that build queued reports and remove expired ones
+func (s *Service) Tasks() []jobs.Task {
+	return []jobs.Task{
+		{Name: "report-exports", Interval: 15 * time.Second, Run: s.ProcessExports},
+		{Name: "expired-report-exports", Interval: time.Hour, Run: s.RemoveExpiredExports},
+	}
+}
+
+// Request queues a validated spec as a report for userID
+func (s *Service) Request(ctx context.Context, userID int32, spec Spec) (db.ReportExport, error) {
+	filters, err := json.Marshal(spec.Filter)
+	if err != nil {
+		return db.ReportExport{}, err
+	}
+	return s.store.CreateReportExport(ctx, db.CreateReportExportParams{
+		RequestedBy: userID,
+		Dataset:     spec.Dataset,
+		Format:      spec.Format,
+		Columns:     spec.Columns,
+		Filters:     filters,
+	})
+}
+
+// SpecOf is the spec a queued report was requested with
+func SpecOf(export db.ReportExport) (Spec, error) {
+	spec := Spec{Dataset: export.Dataset, Format: export.Format, Columns: export.Columns}
+	if err := json.Unmarshal(export.Filters, &spec.Filter); err != nil {
+		return Spec{}, err
+	}
+	return spec, spec.Validate()
+}
+
+// ProcessExports builds every pending report, one at a time, so several instances can share the
+// queue. Files are stored in the database, so any instance can serve the download, and a report left
+// running by an instance that stopped is claimed again once it is stale.
+func (s *Service) ProcessExports(ctx context.Context) error {
+	for {
+		export, err := s.store.ClaimPendingReportExport(ctx, staleExportAfter.Seconds())
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		content, rows, err := s.build(ctx, export)
+		if err != nil {
+			slog.ErrorContext(ctx, "report export failed", "export_id", export.ID, "error", err)
+			if err := s.store.FailReportExport(ctx, db.FailReportExportParams{
+				ID:        export.ID,
+				StartedAt: export.StartedAt,
+				Error:     sql.NullString{String: err.Error(), Valid: true},
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = s.store.ExecTx(ctx, func(q db.Querier) error {
+			n, err := q.CompleteReportExport(ctx, db.CompleteReportExportParams{
+				ID:        export.ID,
+				StartedAt: export.StartedAt,
+				RowCount:  int32(rows),
+				ExpiresAt: sql.NullTime{Time: time.Now().Add(s.exportTTL), Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return errExportReclaimed
+			}
+			return q.CreateReportExportFile(ctx, db.CreateReportExportFileParams{ExportID: export.ID, Content: content})
+		})
+		if errors.Is(err, errExportReclaimed) {
+			slog.WarnContext(ctx, "report export was taken over by another worker", "export_id", export.ID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// build writes a report and returns its contents and row count
+func (s *Service) build(ctx context.Context, export db.ReportExport) ([]byte, int, error) {
+	spec, err := SpecOf(export)
+	if err != nil {
+		return nil, 0, err
+	}
+	var buf bytes.Buffer
+	rows, err := Write(ctx, s.store, &buf, spec)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), rows, nil
+}
+
+// RemoveExpiredExports deletes reports once their download window has passed
+func (s *Service) RemoveExpiredExports(ctx context.Context) error {
+	_, err := s.store.DeleteExpiredReportExports(ctx)
+	return err
+}
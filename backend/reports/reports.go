@@ -0,0 +1,110 @@
+// Package reports exports donations, donor totals and team stats as CSV, XLSX or JSON Lines. Rows are
+// read a page at a time and written as they arrive, so memory stays flat however large the report.
+// Large reports can also run as background jobs whose files are downloaded when ready.
+package reports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+)
+
+// pageSize is how many rows are read from the database at a time
+const pageSize = 1000
+
+// Spec describes one report: which dataset, in which format, with which columns and filters. No
+// columns means all of them.
+type Spec struct {
+	Dataset string
+	Format  string
+	Columns []string
+	Filter  Filter
+}
+
+// Validate checks the dataset, format and columns, and fills in the default format and columns
+func (s *Spec) Validate() error {
+	ds, ok := datasets[s.Dataset]
+	if !ok {
+		return problem.Invalid("dataset", "oneof", "must be one of: "+DatasetDonations+", "+DatasetDonors+", "+DatasetTeams)
+	}
+	switch s.Format {
+	case "":
+		s.Format = FormatCSV
+	case FormatCSV, FormatXLSX, FormatNDJSON:
+	default:
+		return problem.Invalid("format", "oneof", "must be one of: "+FormatCSV+", "+FormatXLSX+", "+FormatNDJSON)
+	}
+	if !s.Filter.From.IsZero() && !s.Filter.To.IsZero() && !s.Filter.To.After(s.Filter.From) {
+		return problem.Invalid("to", "gtfield", "must be after from")
+	}
+	if len(s.Columns) == 0 {
+		s.Columns = Columns(s.Dataset)
+		return nil
+	}
+	for _, column := range s.Columns {
+		if indexOf(ds.columns, column) < 0 {
+			return problem.Invalid("columns", "oneof", fmt.Sprintf("has %q; %s columns are: %s", column, s.Dataset, strings.Join(ds.columns, ", ")))
+		}
+	}
+	return nil
+}
+
+// Filename is the name a report is downloaded as
+func (s Spec) Filename(at time.Time) string {
+	return fmt.Sprintf("play4good-%s-%s.%s", s.Dataset, at.UTC().Format("20060102-150405"), s.Format)
+}
+
+// Write writes the report described by a validated spec to w and returns the number of rows written.
+// CSV and JSON Lines reach w page by page; XLSX is written once the last row is in.
+func Write(ctx context.Context, q db.Querier, w io.Writer, spec Spec) (int, error) {
+	ds := datasets[spec.Dataset]
+	picks := make([]int, len(spec.Columns))
+	for i, column := range spec.Columns {
+		picks[i] = indexOf(ds.columns, column)
+	}
+
+	out := newWriter(spec.Format, w)
+	if err := out.header(spec.Columns); err != nil {
+		return 0, err
+	}
+	written := 0
+	after := int32(0)
+	for {
+		rows, last, err := ds.page(ctx, q, spec.Filter, after, pageSize)
+		if err != nil {
+			return written, err
+		}
+		for _, row := range rows {
+			values := make([]interface{}, len(picks))
+			for i, pick := range picks {
+				values[i] = row[pick]
+			}
+			if err := out.row(values); err != nil {
+				return written, err
+			}
+		}
+		written += len(rows)
+		if len(rows) < pageSize {
+			break
+		}
+		if err := out.flush(); err != nil {
+			return written, err
+		}
+		after = last
+	}
+	return written, out.close()
+}
+
+func indexOf(columns []string, column string) int {
+	for i, c := range columns {
+		if c == column {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,156 @@
+// Package retention permanently removes soft-deleted users, teams and causes once their retention period has passed
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/jobs"
+	"play4good-backend/privacy"
+	"play4good-backend/util"
+)
+
+// Purger deletes soft-deleted rows that nothing financial refers to. Rows still referenced by
+// donations or receipts are kept; deleted users among them are anonymized instead.
+type Purger struct {
+	store     *db.Store
+	privacy   *privacy.Service
+	retention time.Duration
+}
+
+func NewPurger(store *db.Store, privacy *privacy.Service, config util.Config) *Purger {
+	return &Purger{
+		store:     store,
+		privacy:   privacy,
+		retention: config.SoftDeleteRetention,
+	}
+}
+
+// Task returns the background job that runs the purge
+func (p *Purger) Task() jobs.Task {
+	return jobs.Task{Name: "retention-purge", Interval: time.Hour, Run: p.Purge}
+}
+
+// Purge removes everything soft-deleted before the retention cutoff. A row that cannot be purged
+// does not hold up the others, nor the anonymization of deleted donors; every failure is returned.
+func (p *Purger) Purge(ctx context.Context) error {
+	cutoff := sql.NullTime{Time: time.Now().Add(-p.retention), Valid: true}
+	return errors.Join(
+		p.purgeCauses(ctx, cutoff),
+		p.purgeTeams(ctx, cutoff),
+		p.purgeUsers(ctx, cutoff),
+		p.anonymizeDonors(ctx, cutoff),
+	)
+}
+
+// anonymizeDonors erases the personal details of deleted users who are kept for their donations
+func (p *Purger) anonymizeDonors(ctx context.Context, cutoff sql.NullTime) error {
+	donors, err := p.store.ListExpiredDeletedDonors(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range donors {
+		if err := p.privacy.Erase(ctx, id, privacy.Actor{}); err != nil && err != privacy.ErrAlreadyErased {
+			errs = append(errs, fmt.Errorf("anonymize user %d: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Purger) purgeCauses(ctx context.Context, cutoff sql.NullTime) error {
+	causes, err := p.store.ListPurgeableCauses(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, cause := range causes {
+		err := p.store.ExecTx(ctx, func(q db.Querier) error {
+			rows, err := q.PurgeCause(ctx, cause.ID)
+			if err != nil || rows == 0 {
+				return err
+			}
+			return record(ctx, q, audit.EntityCause, cause.ID, cause)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("purge cause %d: %w", cause.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Purger) purgeTeams(ctx context.Context, cutoff sql.NullTime) error {
+	teams, err := p.store.ListPurgeableTeams(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, team := range teams {
+		err := p.store.ExecTx(ctx, func(q db.Querier) error {
+			if err := q.DeleteTeamMembershipsByTeam(ctx, team.ID); err != nil {
+				return err
+			}
+			if err := q.DeleteLeaderboardEntriesByTeam(ctx, team.ID); err != nil {
+				return err
+			}
+			rows, err := q.PurgeTeam(ctx, team.ID)
+			if err != nil || rows == 0 {
+				return err
+			}
+			return record(ctx, q, audit.EntityTeam, team.ID, team)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("purge team %d: %w", team.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Purger) purgeUsers(ctx context.Context, cutoff sql.NullTime) error {
+	users, err := p.store.ListPurgeableUsers(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, user := range users {
+		err := p.store.ExecTx(ctx, func(q db.Querier) error {
+			if err := q.DeleteTeamMembershipsByUser(ctx, user.ID); err != nil {
+				return err
+			}
+			if err := q.DeleteLeaderboardEntriesByUser(ctx, user.ID); err != nil {
+				return err
+			}
+			if err := q.ClearCauseOwner(ctx, sql.NullInt32{Int32: user.ID, Valid: true}); err != nil {
+				return err
+			}
+			rows, err := q.PurgeUser(ctx, user.ID)
+			if err != nil || rows == 0 {
+				return err
+			}
+			// The purged row's personal details stay out of the audit log
+			if err := q.ScrubAuditLogForUser(ctx, user.ID); err != nil {
+				return err
+			}
+			return record(ctx, q, audit.EntityUser, user.ID, nil)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("purge user %d: %w", user.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func record(ctx context.Context, q db.Querier, entityType string, id int32, before interface{}) error {
+	_, err := audit.Record(ctx, q, audit.Entry{
+		Action:     audit.ActionPurge,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(id),
+		Before:     before,
+	})
+	return err
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsAreServedApartFromTheAPI(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newTestEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("GET /metrics on the API: got status %d, want 404", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	NewMetricsRouter().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("GET /metrics on the metrics listener: got status %d, want 200", recorder.Code)
+	}
+}
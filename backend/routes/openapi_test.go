@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"play4good-backend/controllers"
+	"play4good-backend/health"
+	"play4good-backend/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	router := engine.Group("/api")
+
+	routes := NewPlay4GoodRoutes(nil)
+	routes.SetupRoutes(router)
+	SetupSystemRoutes(router, engine, health.NewChecker(nil, 0))
+	return engine
+}
+
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	engine := newTestEngine()
+
+	for _, problem := range openapi.Check(engine.Routes(), controllers.Operations) {
+		t.Error(problem)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	engine := newTestEngine()
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET /api/openapi.json: got status %d", recorder.Code)
+	}
+
+	var doc struct {
+		OpenAPI    string                            `json:"openapi"`
+		Paths      map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decoding document: %v", err)
+	}
+
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+	if _, ok := doc.Paths["/api/users/{id}"]["patch"]; !ok {
+		t.Error("PATCH /api/users/{id} is missing from the document")
+	}
+
+	create, ok := doc.Components.Schemas["CauseCreateRequest"].(map[string]interface{})
+	if !ok {
+		t.Fatal("CauseCreateRequest schema is missing")
+	}
+	status := create["properties"].(map[string]interface{})["status"].(map[string]interface{})
+	if enum, _ := status["enum"].([]interface{}); len(enum) != 3 {
+		t.Errorf("status enum = %v, want the three oneof values", status["enum"])
+	}
+}
@@ -1,8 +1,12 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"play4good-backend/controllers"
+	"play4good-backend/middleware"
+	"play4good-backend/util"
 )
 
 type Play4GoodRoutes struct {
@@ -16,43 +20,124 @@ func NewPlay4GoodRoutes(play4goodController *controllers.Play4GoodController) Pl
 func (pr *Play4GoodRoutes) SetupRoutes(rg *gin.RouterGroup) {
 	router := rg.Group("")
 
+	scope := middleware.Scope
+
 	router.POST("/signup", pr.play4goodController.SignUpUser)
 	router.POST("/login", pr.play4goodController.LoginUser)
 	router.GET("/user/me", pr.play4goodController.GetCurrentUser)
+
+	// Account recovery and verification routes
+	accountLimit := middleware.RateLimit(util.NewRateLimiter(5, 15*time.Minute))
+	router.POST("/auth/verify-email/request", accountLimit, pr.play4goodController.RequestEmailVerification)
+	router.POST("/auth/verify-email", accountLimit, pr.play4goodController.VerifyEmail)
+	router.POST("/auth/forgot-password", accountLimit, pr.play4goodController.ForgotPassword)
+	router.POST("/auth/reset-password", accountLimit, pr.play4goodController.ResetPassword)
+	router.POST("/auth/unlock", accountLimit, pr.play4goodController.UnlockAccount)
+	router.GET("/user/me/security-events", pr.play4goodController.ListSecurityEvents)
+
+	// External identity provider routes
+	router.POST("/auth/oidc", accountLimit, pr.play4goodController.ExchangeIdentityToken)
+	router.GET("/user/me/identities", pr.play4goodController.ListIdentities)
+	router.POST("/user/me/exports", pr.play4goodController.RequestDataExport)
+	router.GET("/user/me/exports", pr.play4goodController.ListDataExports)
+	router.GET("/user/me/exports/:id/download", pr.play4goodController.DownloadDataExport)
+	router.GET("/user/me/erasure", pr.play4goodController.GetErasureStatus)
+	router.POST("/user/me/erasure", pr.play4goodController.RequestErasure)
+	router.DELETE("/user/me/erasure", pr.play4goodController.CancelErasure)
+	router.GET("/user/me/receipts", pr.play4goodController.ListReceipts)
+	router.GET("/user/me/statements/:year", pr.play4goodController.DownloadStatement)
+
+	// Two-factor authentication routes
+	router.POST("/login/2fa", accountLimit, pr.play4goodController.VerifyLoginTwoFactor)
+	router.GET("/2fa/status", pr.play4goodController.GetTwoFactorStatus)
+	router.POST("/2fa/enroll", pr.play4goodController.EnrollTwoFactor)
+	router.POST("/2fa/enable", pr.play4goodController.EnableTwoFactor)
+	router.POST("/2fa/disable", pr.play4goodController.DisableTwoFactor)
+	router.POST("/2fa/recovery-codes", pr.play4goodController.RegenerateRecoveryCodes)
+
+	// Report routes: download directly, or queue large reports and download them when ready
+	router.GET("/exports/:dataset", pr.play4goodController.ExportReport)
+	router.POST("/export-jobs", pr.play4goodController.RequestReportExport)
+	router.GET("/export-jobs", pr.play4goodController.ListReportExports)
+	router.GET("/export-jobs/:id", pr.play4goodController.GetReportExport)
+	router.GET("/export-jobs/:id/download", pr.play4goodController.DownloadReportExport)
+
+	// Analytics routes
+	router.GET("/analytics/donations", pr.play4goodController.GetDonationAnalytics)
+	router.GET("/analytics/cohorts", pr.play4goodController.GetDonorCohorts)
+	router.GET("/analytics/lapsed-donors", pr.play4goodController.GetLapsedDonors)
+	router.GET("/analytics/lifetime-value", pr.play4goodController.GetDonorLifetimeValue)
+	router.GET("/analytics/recurring-donors", pr.play4goodController.GetRecurringDonors)
+	router.GET("/analytics/team-retention", pr.play4goodController.GetTeamRetention)
+
+	// API key routes; keys are managed with a session only
+	router.POST("/api-keys", pr.play4goodController.CreateAPIKey)
+	router.GET("/api-keys", pr.play4goodController.ListAPIKeys)
+	router.DELETE("/api-keys/:id", pr.play4goodController.RevokeAPIKey)
+
+	// Admin routes
+	router.GET("/admin/roles", pr.play4goodController.ListRolePolicies)
+	router.PUT("/admin/roles/:role", pr.play4goodController.UpdateRolePolicy)
+	router.GET("/admin/audit", pr.play4goodController.ListAuditLog)
+	router.POST("/admin/users/:id/restore", pr.play4goodController.RestoreUser)
+	router.POST("/admin/teams/:id/restore", pr.play4goodController.RestoreTeam)
+	router.POST("/admin/causes/:id/restore", pr.play4goodController.RestoreCause)
+	router.POST("/admin/donation-imports", pr.play4goodController.ImportDonations)
+	router.GET("/admin/donation-imports", pr.play4goodController.ListDonationImports)
+	router.GET("/admin/donation-imports/:id", pr.play4goodController.GetDonationImport)
+	router.POST("/admin/donation-imports/:id/commit", pr.play4goodController.CommitDonationImport)
+	router.POST("/admin/donation-imports/:id/rollback", pr.play4goodController.RollBackDonationImport)
+	router.POST("/admin/cause-imports", pr.play4goodController.ImportCauses)
+	router.POST("/admin/team-imports", pr.play4goodController.ImportTeams)
+	router.POST("/admin/donations/:id/receipt/void", pr.play4goodController.VoidDonationReceipt)
+	router.POST("/admin/donations/:id/receipt/reissue", pr.play4goodController.ReissueDonationReceipt)
+	router.GET("/admin/users/:id/statements/:year", pr.play4goodController.DownloadUserStatement)
+	router.GET("/admin/receipt-template", pr.play4goodController.GetReceiptTemplate)
+	router.PUT("/admin/receipt-template", pr.play4goodController.UpdateReceiptTemplate)
+
 	// User routes
-	router.POST("/users", pr.play4goodController.CreateUser)
-	router.GET("/users/id/:id", pr.play4goodController.GetUser)
-	router.GET("/users/email/:email", pr.play4goodController.GetUserByEmail)
-	router.GET("/listUsers", pr.play4goodController.ListUsers)
-	router.PUT("/users/:id", pr.play4goodController.UpdateUser)
-	router.DELETE("/users/:id", pr.play4goodController.DeleteUser)
+	router.POST("/users", scope(util.ScopeUsersWrite), pr.play4goodController.CreateUser)
+	router.GET("/users/id/:id", scope(util.ScopeUsersRead), pr.play4goodController.GetUser)
+	router.GET("/users/email/:email", scope(util.ScopeUsersRead), pr.play4goodController.GetUserByEmail)
+	router.GET("/listUsers", scope(util.ScopeUsersRead), pr.play4goodController.ListUsers)
+	router.PUT("/users/:id", scope(util.ScopeUsersWrite), pr.play4goodController.UpdateUser)
+	router.PATCH("/users/:id", scope(util.ScopeUsersWrite), pr.play4goodController.PatchUser)
+	router.DELETE("/users/:id", scope(util.ScopeUsersWrite), pr.play4goodController.DeleteUser)
 
 	// Team routes
-	router.POST("/teams", pr.play4goodController.CreateTeam)
-	router.GET("/teams/:id", pr.play4goodController.GetTeam)
-	router.GET("/listTeams", pr.play4goodController.ListTeams)
-	router.PUT("/teams/:id", pr.play4goodController.UpdateTeam)
-	router.DELETE("/teams/:id", pr.play4goodController.DeleteTeam)
+	router.POST("/teams", scope(util.ScopeTeamsWrite), pr.play4goodController.CreateTeam)
+	router.GET("/teams/:id", scope(util.ScopeTeamsRead), pr.play4goodController.GetTeam)
+	router.GET("/listTeams", scope(util.ScopeTeamsRead), pr.play4goodController.ListTeams)
+	router.PUT("/teams/:id", scope(util.ScopeTeamsWrite), pr.play4goodController.UpdateTeam)
+	router.PATCH("/teams/:id", scope(util.ScopeTeamsWrite), pr.play4goodController.PatchTeam)
+	router.DELETE("/teams/:id", scope(util.ScopeTeamsWrite), pr.play4goodController.DeleteTeam)
+	router.GET("/teams/:id/history", scope(util.ScopeTeamsRead), pr.play4goodController.GetTeamHistory)
 
 	// Cause routes
-	router.POST("/causes", pr.play4goodController.CreateCause)
-	router.GET("/causes/:id", pr.play4goodController.GetCause)
-	router.POST("/listCauses", pr.play4goodController.ListCauses)
-	router.PUT("/causes/:id", pr.play4goodController.UpdateCause)
-	router.DELETE("/causes/:id", pr.play4goodController.DeleteCause)
+	router.POST("/causes", scope(util.ScopeCausesWrite), pr.play4goodController.CreateCause)
+	router.GET("/causes/:id", scope(util.ScopeCausesRead), pr.play4goodController.GetCause)
+	router.POST("/listCauses", scope(util.ScopeCausesRead), pr.play4goodController.ListCauses)
+	router.PUT("/causes/:id", scope(util.ScopeCausesWrite), pr.play4goodController.UpdateCause)
+	router.PATCH("/causes/:id", scope(util.ScopeCausesWrite), pr.play4goodController.PatchCause)
+	router.DELETE("/causes/:id", scope(util.ScopeCausesWrite), pr.play4goodController.DeleteCause)
+	router.GET("/causes/:id/history", scope(util.ScopeCausesRead), pr.play4goodController.GetCauseHistory)
+	router.GET("/causes/:id/receipt-template", pr.play4goodController.GetCauseReceiptTemplate)
+	router.PUT("/causes/:id/receipt-template", pr.play4goodController.UpdateCauseReceiptTemplate)
 
 	// Donation routes
-	router.POST("/donations", pr.play4goodController.CreateDonation)
-	router.GET("/donations/:id", pr.play4goodController.GetDonation)
-	router.GET("/listDonations", pr.play4goodController.ListDonations)
+	router.POST("/donations", scope(util.ScopeDonationsWrite), pr.play4goodController.CreateDonation)
+	router.GET("/donations/:id", scope(util.ScopeDonationsRead), pr.play4goodController.GetDonation)
+	router.GET("/donations/:id/receipt", scope(util.ScopeDonationsRead), pr.play4goodController.DownloadDonationReceipt)
+	router.GET("/listDonations", scope(util.ScopeDonationsRead), pr.play4goodController.ListDonations)
 
 	// Leaderboard routes
-	router.POST("/leaderboards", pr.play4goodController.CreateLeaderboard)
-	router.GET("/leaderboards/:id", pr.play4goodController.GetLeaderboard)
-	router.PUT("/leaderboards/:id/entries", pr.play4goodController.UpdateLeaderboardEntry)
-	router.PUT("/listLeaderBoards", pr.play4goodController.ListLeaderboards)
-
-	router.POST("/user-team", pr.play4goodController.AddUserToTeam)
-	router.PUT("/user-team/:userId/:teamId", pr.play4goodController.UpdateUserTeamRole)
-	router.DELETE("/user-team/:userId/:teamId", pr.play4goodController.RemoveUserFromTeam)
+	router.POST("/leaderboards", scope(util.ScopeLeaderboardsWrite), pr.play4goodController.CreateLeaderboard)
+	router.GET("/leaderboards/:id", scope(util.ScopeLeaderboardsRead), pr.play4goodController.GetLeaderboard)
+	router.PATCH("/leaderboards/:id", scope(util.ScopeLeaderboardsWrite), pr.play4goodController.PatchLeaderboard)
+	router.PUT("/leaderboards/:id/entries", scope(util.ScopeLeaderboardsWrite), pr.play4goodController.UpdateLeaderboardEntry)
+	router.PUT("/listLeaderBoards", scope(util.ScopeLeaderboardsRead), middleware.QueryETag(), pr.play4goodController.ListLeaderboards)
+
+	router.POST("/user-team", scope(util.ScopeTeamsWrite), pr.play4goodController.AddUserToTeam)
+	router.PUT("/user-team/:userId/:teamId", scope(util.ScopeTeamsWrite), pr.play4goodController.UpdateUserTeamRole)
+	router.DELETE("/user-team/:userId/:teamId", scope(util.ScopeTeamsWrite), pr.play4goodController.RemoveUserFromTeam)
 }
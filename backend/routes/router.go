@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"play4good-backend/controllers"
+	"play4good-backend/health"
+	"play4good-backend/middleware"
+	"play4good-backend/problem"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// NewRouter builds the API server: middleware, the /api routes and the system endpoints
+func NewRouter(controller *controllers.Play4GoodController, checker *health.Checker) *gin.Engine {
+	server := gin.New()
+	// Handlers pass the gin context to queries; fall back to the request context so they see its span
+	server.ContextWithFallback = true
+	server.Use(middleware.RequestID())
+	server.Use(middleware.Tracing())
+	server.Use(middleware.Logger())
+	server.Use(gin.Recovery())
+	server.Use(middleware.ETag())
+
+	// Add CORS middleware
+	server.Use(cors.New(cors.Config{
+		AllowOrigins:     []string{"http://localhost:3000"}, // Replace with your frontend domain
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Request-ID", "If-Match", "If-None-Match", "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "ETag"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	}))
+
+	router := server.Group("/api")
+	play4GoodRoutes := NewPlay4GoodRoutes(controller)
+	play4GoodRoutes.SetupRoutes(router)
+	SetupSystemRoutes(router, server, checker)
+
+	server.NoRoute(func(ctx *gin.Context) {
+		problem.Write(ctx, problem.New(http.StatusNotFound, problem.CodeRouteNotFound, fmt.Sprintf("The specified route %s not found", ctx.Request.URL)))
+	})
+	return server
+}
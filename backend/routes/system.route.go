@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"net/http"
+
+	"play4good-backend/controllers"
+	"play4good-backend/health"
+	"play4good-backend/metrics"
+	"play4good-backend/openapi"
+	"play4good-backend/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+var apiInfo = openapi.Info{
+	Title:   "Play4Good API",
+	Version: "1.0.0",
+}
+
+// SetupSystemRoutes registers the health checks and the API documentation. The document is
+// generated from every route registered on engine by the time it is first requested.
+func SetupSystemRoutes(rg *gin.RouterGroup, engine *gin.Engine, checker *health.Checker) {
+	rg.GET("/healthcheck", func(ctx *gin.Context) {
+		if _, err := checker.Ready(ctx.Request.Context()); err != nil {
+			problem.Write(ctx, problem.New(http.StatusServiceUnavailable, problem.CodeUnavailable, err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "The Play4Good API is working fine"})
+	})
+
+	rg.GET("/openapi.json", openapi.Handler(func() *openapi.Document {
+		return openapi.Build(apiInfo, engine.Routes(), controllers.Operations)
+	}))
+	rg.GET("/docs", openapi.UIHandler(apiInfo.Title, rg.BasePath()+"/openapi.json"))
+
+	// Probes and Prometheus use root paths by convention
+	engine.GET("/livez", checker.Live)
+	engine.GET("/readyz", checker.Readiness)
+}
+
+// NewMetricsRouter serves /metrics for Prometheus. It listens on a port of its own so the metrics
+// are never published with the API.
+func NewMetricsRouter() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	return mux
+}
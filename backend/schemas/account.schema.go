@@ -0,0 +1,74 @@
+package schemas
+
+import "time"
+
+// LoginRequest represents the request body for signing in with a password
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// VerifyEmailRequest represents the request body for confirming an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPasswordRequest represents the request body for starting a password reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents the request body for choosing a new password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// TwoFactorCodeRequest represents a request confirmed with a code from the authenticator app
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TwoFactorDisableRequest represents the request body for turning two-factor authentication off
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// LoginTwoFactorRequest represents the second step of a login for accounts with two-factor enabled.
+// Either an authenticator code or an unused recovery code must be provided.
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode   string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+// RolePolicyUpdateRequest represents the request body for changing a role's security policy
+type RolePolicyUpdateRequest struct {
+	RequireTwoFactor *bool `json:"require_2fa" binding:"required"`
+}
+
+// IdentityTokenRequest represents an OIDC ID token or NextAuth JWT exchanged for a session
+type IdentityTokenRequest struct {
+	IDToken string `json:"id_token" binding:"required"`
+}
+
+// APIKeyCreateRequest represents the request body for creating an API key.
+// Setting TeamID creates a team-scoped key, which may only hold team scopes.
+type APIKeyCreateRequest struct {
+	Name      string    `json:"name" binding:"required,min=3,max=100"`
+	Scopes    []string  `json:"scopes" binding:"required,min=1,dive,required"`
+	TeamID    int64     `json:"team_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UnlockAccountRequest represents the request body for unlocking an account from the emailed link
+type UnlockAccountRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ListSecurityEventsRequest represents the query string for paging through security events
+type ListSecurityEventsRequest struct {
+	Limit  int64 `form:"limit" binding:"omitempty,min=1,max=200"`
+	Offset int64 `form:"offset" binding:"omitempty,min=0"`
+}
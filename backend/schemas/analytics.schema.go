@@ -0,0 +1,30 @@
+package schemas
+
+import "time"
+
+// DonationAnalyticsQuery represents the query string for donation analytics. From and To are days,
+// both included, in the time zone tz.
+type DonationAnalyticsQuery struct {
+	Interval     string    `form:"interval" binding:"omitempty,oneof=day week month"`
+	GroupBy      string    `form:"group_by" binding:"omitempty,oneof=category cause team donation_type"`
+	From         time.Time `form:"from" time_format:"2006-01-02"`
+	To           time.Time `form:"to" time_format:"2006-01-02"`
+	TimeZone     string    `form:"tz" binding:"max=64"`
+	CauseID      int64     `form:"cause_id" binding:"omitempty,min=1"`
+	TeamID       int64     `form:"team_id" binding:"omitempty,min=1"`
+	DonationType string    `form:"donation_type" binding:"omitempty,oneof=money goods service"`
+	Compare      bool      `form:"compare"`
+}
+
+// DonorAnalyticsQuery represents the query string for the donor cohort reports. From and To are
+// the first and last cohort months, as 2006-01. Months only applies to retention, LapsedDays to
+// lapsed donors and RecurringMonths to the recurring donor split.
+type DonorAnalyticsQuery struct {
+	From            time.Time `form:"from" time_format:"2006-01"`
+	To              time.Time `form:"to" time_format:"2006-01"`
+	TimeZone        string    `form:"tz" binding:"max=64"`
+	Months          int       `form:"months" binding:"omitempty,min=1,max=36"`
+	LapsedDays      int       `form:"lapsed_days" binding:"omitempty,min=30,max=3650"`
+	RecurringMonths int       `form:"recurring_months" binding:"omitempty,min=2,max=24"`
+	Format          string    `form:"format" binding:"omitempty,oneof=json csv"`
+}
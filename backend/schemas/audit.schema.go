@@ -0,0 +1,16 @@
+package schemas
+
+import "time"
+
+// ListAuditLogRequest represents the query string for searching the audit log
+type ListAuditLogRequest struct {
+	ActorID    int64     `form:"actor_id"`
+	Action     string    `form:"action" binding:"omitempty,oneof=create update delete restore purge"`
+	EntityType string    `form:"entity_type"`
+	EntityID   string    `form:"entity_id"`
+	RequestID  string    `form:"request_id"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int64     `form:"limit" binding:"omitempty,min=1,max=500"`
+	Offset     int64     `form:"offset" binding:"omitempty,min=0"`
+}
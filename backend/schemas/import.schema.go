@@ -0,0 +1,35 @@
+package schemas
+
+import "mime/multipart"
+
+// DonationImportRequest represents the multipart form for uploading a CSV or XLSX file of donations.
+// Mapping is a JSON object from donation field to column header; DryRun only validates the file.
+type DonationImportRequest struct {
+	File    *multipart.FileHeader `form:"file" binding:"required"`
+	Source  string                `form:"source" binding:"max=100"`
+	Mapping string                `form:"mapping"`
+	DryRun  bool                  `form:"dry_run"`
+}
+
+// RecordImportRequest represents the multipart form for uploading a CSV or XLSX file of causes or
+// teams. Mapping is a JSON object from field to column header; DryRun only validates the file, and
+// SkipInvalid creates the valid rows of a file that has invalid ones.
+type RecordImportRequest struct {
+	File        *multipart.FileHeader `form:"file" binding:"required"`
+	Mapping     string                `form:"mapping"`
+	DryRun      bool                  `form:"dry_run"`
+	SkipInvalid bool                  `form:"skip_invalid"`
+}
+
+// DonationImportCommitRequest represents the request body for committing a staged import
+type DonationImportCommitRequest struct {
+	Mode        string `json:"mode" binding:"omitempty,oneof=atomic chunked"`
+	ChunkSize   int32  `json:"chunk_size" binding:"omitempty,min=1,max=5000"`
+	SkipInvalid bool   `json:"skip_invalid"`
+}
+
+// ListDonationImportsRequest represents the query string for listing imports
+type ListDonationImportsRequest struct {
+	Limit  int64 `form:"limit" binding:"omitempty,min=1,max=500"`
+	Offset int64 `form:"offset" binding:"omitempty,min=0"`
+}
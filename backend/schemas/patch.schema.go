@@ -0,0 +1,141 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+
+	"play4good-backend/problem"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+// Field is one member of a JSON merge patch (RFC 7396). Set reports whether the member was
+// present at all, Null whether it was sent as null to clear the column.
+type Field[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// Present reports whether the member was sent with a value
+func (f Field[T]) Present() bool {
+	return f.Set && !f.Null
+}
+
+// check validates a present member against a validator tag. Members that are absent pass;
+// null passes only when the column can be cleared.
+func check[T any](name string, f Field[T], nullable bool, tag string) error {
+	if !f.Set {
+		return nil
+	}
+	if f.Null {
+		if !nullable {
+			return problem.Invalid(name, "nullable", "cannot be null")
+		}
+		return nil
+	}
+	if tag == "" {
+		return nil
+	}
+	if err := validate.Var(f.Value, tag); err != nil {
+		fe := err.(validator.ValidationErrors)[0]
+		return problem.Invalid(name, fe.Tag(), problem.Describe(fe))
+	}
+	return nil
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UserPatchRequest is a merge patch for a user
+type UserPatchRequest struct {
+	Username  Field[string] `json:"username"`
+	Email     Field[string] `json:"email"`
+	FirstName Field[string] `json:"first_name"`
+	LastName  Field[string] `json:"last_name"`
+	AvatarURL Field[string] `json:"avatar_url"`
+}
+
+func (r UserPatchRequest) Validate() error {
+	return firstError(
+		check("username", r.Username, false, "min=3,max=50"),
+		check("email", r.Email, false, "email"),
+		check("first_name", r.FirstName, true, ""),
+		check("last_name", r.LastName, true, ""),
+		check("avatar_url", r.AvatarURL, true, "url"),
+	)
+}
+
+// TeamPatchRequest is a merge patch for a team
+type TeamPatchRequest struct {
+	Name        Field[string] `json:"name"`
+	Description Field[string] `json:"description"`
+	AvatarURL   Field[string] `json:"avatar_url"`
+}
+
+func (r TeamPatchRequest) Validate() error {
+	return firstError(
+		check("name", r.Name, false, "min=3,max=100"),
+		check("description", r.Description, true, ""),
+		check("avatar_url", r.AvatarURL, true, "url"),
+	)
+}
+
+// CausePatchRequest is a merge patch for a cause. end_date is checked against the merged start_date.
+type CausePatchRequest struct {
+	Name        Field[string]    `json:"name"`
+	Description Field[string]    `json:"description"`
+	Goal        Field[float64]   `json:"goal"`
+	StartDate   Field[time.Time] `json:"start_date"`
+	EndDate     Field[time.Time] `json:"end_date"`
+	Status      Field[string]    `json:"status"`
+	Image       Field[string]    `json:"image"`
+	Category    Field[string]    `json:"category"`
+}
+
+func (r CausePatchRequest) Validate() error {
+	return firstError(
+		check("name", r.Name, false, "min=3,max=100"),
+		check("description", r.Description, true, ""),
+		check("goal", r.Goal, true, "min=0"),
+		check("start_date", r.StartDate, true, ""),
+		check("end_date", r.EndDate, true, ""),
+		check("status", r.Status, false, "oneof=active inactive completed"),
+		check("image", r.Image, true, "url"),
+		check("category", r.Category, true, "min=3,max=50"),
+	)
+}
+
+// LeaderboardPatchRequest is a merge patch for a leaderboard. end_date is checked against the merged start_date.
+type LeaderboardPatchRequest struct {
+	Name      Field[string]    `json:"name"`
+	Type      Field[string]    `json:"type"`
+	StartDate Field[time.Time] `json:"start_date"`
+	EndDate   Field[time.Time] `json:"end_date"`
+}
+
+func (r LeaderboardPatchRequest) Validate() error {
+	return firstError(
+		check("name", r.Name, false, "min=3,max=100"),
+		check("type", r.Type, false, "oneof=individual team"),
+		check("start_date", r.StartDate, true, ""),
+		check("end_date", r.EndDate, true, ""),
+	)
+}
@@ -0,0 +1,19 @@
+package schemas
+
+// ReceiptTemplateRequest represents the request body for replacing a receipt branding template.
+// Fields left empty on a cause's template fall back to the organization's.
+type ReceiptTemplateRequest struct {
+	OrganizationName   string `json:"organization_name" binding:"max=200"`
+	Address            string `json:"address" binding:"max=500"`
+	RegistrationNumber string `json:"registration_number" binding:"max=100"`
+	ContactEmail       string `json:"contact_email" binding:"omitempty,email,max=100"`
+	AccentColor        string `json:"accent_color" binding:"omitempty,hexcolor,max=7"`
+	HeaderText         string `json:"header_text" binding:"max=1000"`
+	FooterText         string `json:"footer_text" binding:"max=1000"`
+	Signatory          string `json:"signatory" binding:"max=200"`
+}
+
+// ReceiptVoidRequest represents the request body for voiding or re-issuing a donation's receipt
+type ReceiptVoidRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
@@ -0,0 +1,27 @@
+package schemas
+
+import "time"
+
+// ReportQuery represents the query string for downloading a report. Columns is a comma-separated
+// list; leaving it out exports every column.
+type ReportQuery struct {
+	Format  string    `form:"format" binding:"omitempty,oneof=csv xlsx ndjson"`
+	Columns string    `form:"columns"`
+	From    time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	CauseID int64     `form:"cause_id" binding:"omitempty,min=1"`
+	TeamID  int64     `form:"team_id" binding:"omitempty,min=1"`
+	Status  string    `form:"status" binding:"max=20"`
+}
+
+// ReportExportRequest represents the request body for queueing a report to build in the background
+type ReportExportRequest struct {
+	Dataset string    `json:"dataset" binding:"required,oneof=donations donors teams"`
+	Format  string    `json:"format" binding:"omitempty,oneof=csv xlsx ndjson"`
+	Columns []string  `json:"columns"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	CauseID int64     `json:"cause_id" binding:"omitempty,min=1"`
+	TeamID  int64     `json:"team_id" binding:"omitempty,min=1"`
+	Status  string    `json:"status" binding:"max=20"`
+}
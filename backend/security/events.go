@@ -0,0 +1,13 @@
+package security
+
+// Event types recorded in a user's security log
+const (
+	EventLoginSuccess      = "login_success"
+	EventLoginFailure      = "login_failure"
+	EventTwoFactorFailure  = "login_2fa_failure"
+	EventAccountLocked     = "account_locked"
+	EventAccountUnlocked   = "account_unlocked"
+	EventPasswordReset     = "password_reset"
+	EventTwoFactorEnabled  = "2fa_enabled"
+	EventTwoFactorDisabled = "2fa_disabled"
+)
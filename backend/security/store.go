@@ -0,0 +1,135 @@
+package security
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	db "play4good-backend/db/sqlc"
+)
+
+// NewAttemptStore returns the store named in configuration: "memory" keeps counters in
+// this process only, anything else shares them through Postgres
+func NewAttemptStore(kind string, queries db.Querier) AttemptStore {
+	if kind == "memory" {
+		return NewMemoryStore()
+	}
+	return NewPostgresStore(queries)
+}
+
+// PostgresStore keeps counters in the login_throttle table
+type PostgresStore struct {
+	db db.Querier
+}
+
+func NewPostgresStore(queries db.Querier) *PostgresStore {
+	return &PostgresStore{db: queries}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) (Attempts, error) {
+	row, err := s.db.GetLoginThrottle(ctx, key)
+	if err == sql.ErrNoRows {
+		return Attempts{}, nil
+	}
+	if err != nil {
+		return Attempts{}, err
+	}
+	return attemptsFromRow(row), nil
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, policy Policy) (Attempts, bool, error) {
+	row, err := s.db.TakeLoginAttempt(ctx, db.TakeLoginAttemptParams{
+		Key:               key,
+		FreeAttempts:      int32(policy.FreeAttempts),
+		BaseDelaySeconds:  policy.BaseDelay.Seconds(),
+		MaxDelaySeconds:   policy.MaxDelay.Seconds(),
+		LockoutAfter:      int32(policy.LockoutAfter),
+		LockoutSeconds:    policy.LockoutFor.Seconds(),
+		ResetAfterSeconds: policy.ResetAfter.Seconds(),
+	})
+	if err == sql.ErrNoRows {
+		// The attempt was refused; read the counters that refused it
+		attempts, err := s.Get(ctx, key)
+		return attempts, false, err
+	}
+	if err != nil {
+		return Attempts{}, false, err
+	}
+	return attemptsFromRow(row), true, nil
+}
+
+func (s *PostgresStore) Release(ctx context.Context, key string) error {
+	return s.db.ReleaseLoginAttempt(ctx, key)
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	return s.db.DeleteLoginThrottle(ctx, key)
+}
+
+func attemptsFromRow(row db.LoginThrottle) Attempts {
+	return Attempts{
+		Failures:    int(row.Failures),
+		LastFailure: row.LastFailureAt,
+		LockedUntil: row.LockedUntil.Time,
+	}
+}
+
+// MemoryStore keeps counters in process memory, for single-instance deployments and development
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]Attempts
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{attempts: make(map[string]Attempts)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts[key], nil
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy) (Attempts, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	attempts := s.attempts[key]
+	if attempts.LockedUntil.After(now) {
+		return attempts, false, nil
+	}
+	if now.Sub(attempts.LastFailure) > policy.ResetAfter {
+		attempts.Failures = 0
+	} else if attempts.LastFailure.Add(policy.delay(attempts.Failures)).After(now) {
+		return attempts, false, nil
+	}
+
+	attempts.Failures++
+	attempts.LastFailure = now
+	attempts.LockedUntil = time.Time{}
+	if policy.LockoutAfter > 0 && attempts.Failures >= policy.LockoutAfter {
+		attempts.LockedUntil = now.Add(policy.LockoutFor)
+	}
+	s.attempts[key] = attempts
+	return attempts, true, nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempts, ok := s.attempts[key]; ok && attempts.Failures > 0 {
+		attempts.Failures--
+		s.attempts[key] = attempts
+	}
+	return nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, key)
+	return nil
+}
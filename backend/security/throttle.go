@@ -0,0 +1,138 @@
+package security
+
+import (
+	"context"
+	"time"
+)
+
+// Attempts is the throttle state tracked for one key (an account, a client IP or an email address)
+type Attempts struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+// AttemptStore persists throttle counters. Use the Postgres store when several
+// API instances run behind a load balancer so they enforce the same limits.
+type AttemptStore interface {
+	Get(ctx context.Context, key string) (Attempts, error)
+	// Take records an attempt for key in one atomic step unless the policy says it must wait or is
+	// locked, restarting counters older than the policy's ResetAfter and locking the key once it
+	// reaches LockoutAfter. It reports whether the attempt was recorded.
+	Take(ctx context.Context, key string, policy Policy) (Attempts, bool, error)
+	// Release forgets one attempt recorded by Take
+	Release(ctx context.Context, key string) error
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy controls how quickly repeated failures are slowed down and when a key is locked
+type Policy struct {
+	// Failures allowed before any delay is imposed
+	FreeAttempts int
+	// Delay after the first throttled failure; it doubles with each further failure up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Failures after which the key is locked for LockoutFor; zero disables lockout
+	LockoutAfter int
+	LockoutFor   time.Duration
+	// Failures older than this are forgotten
+	ResetAfter time.Duration
+}
+
+var (
+	// AccountPolicy applies to failures against a single email address
+	AccountPolicy = Policy{
+		FreeAttempts: 3,
+		BaseDelay:    time.Second,
+		MaxDelay:     5 * time.Minute,
+		LockoutAfter: 10,
+		LockoutFor:   30 * time.Minute,
+		ResetAfter:   time.Hour,
+	}
+
+	// IPPolicy applies to failures from one client IP across all accounts. It only backs off,
+	// since locking out an IP would also lock out everyone sharing it.
+	IPPolicy = Policy{
+		FreeAttempts: 10,
+		BaseDelay:    time.Second,
+		MaxDelay:     15 * time.Minute,
+		ResetAfter:   time.Hour,
+	}
+
+	// EmailPolicy allows three verification or password reset emails to an address per hour
+	EmailPolicy = Policy{
+		FreeAttempts: 3,
+		LockoutAfter: 3,
+		LockoutFor:   time.Hour,
+		ResetAfter:   time.Hour,
+	}
+
+	// TwoFactorPolicy allows five two-factor codes per login challenge user every 15 minutes
+	TwoFactorPolicy = Policy{
+		FreeAttempts: 5,
+		LockoutAfter: 5,
+		LockoutFor:   15 * time.Minute,
+		ResetAfter:   15 * time.Minute,
+	}
+)
+
+// Throttle applies a Policy to counters kept in an AttemptStore
+type Throttle struct {
+	store  AttemptStore
+	policy Policy
+}
+
+func NewThrottle(store AttemptStore, policy Policy) *Throttle {
+	return &Throttle{store: store, policy: policy}
+}
+
+// Take counts an attempt for key before it is made, so concurrent attempts cannot all slip
+// past the limit. When the key is backing off or locked nothing is counted and the returned
+// wait is how long the caller must hold off. locked reports whether the key is locked, either
+// already or by this attempt.
+func (t *Throttle) Take(ctx context.Context, key string) (wait time.Duration, locked bool, err error) {
+	attempts, taken, err := t.store.Take(ctx, key, t.policy)
+	if err != nil {
+		return 0, false, err
+	}
+
+	now := time.Now()
+	locked = attempts.LockedUntil.After(now)
+	if taken {
+		return 0, locked, nil
+	}
+
+	if locked {
+		wait = attempts.LockedUntil.Sub(now)
+	} else {
+		wait = attempts.LastFailure.Add(t.policy.delay(attempts.Failures)).Sub(now)
+	}
+	// The store refused the attempt on its own clock, so never report a wait of nothing
+	return max(wait, time.Second), locked, nil
+}
+
+// Release gives back an attempt counted by Take that turned out not to be a failure
+func (t *Throttle) Release(ctx context.Context, key string) error {
+	return t.store.Release(ctx, key)
+}
+
+// Succeed clears the failures recorded for key
+func (t *Throttle) Succeed(ctx context.Context, key string) error {
+	return t.store.Reset(ctx, key)
+}
+
+// delay is the exponential backoff owed after the given number of consecutive failures
+func (p Policy) delay(failures int) time.Duration {
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
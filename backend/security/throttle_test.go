@@ -0,0 +1,83 @@
+package security
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentAttemptsCannotExceedTheLimit(t *testing.T) {
+	throttle := NewThrottle(NewMemoryStore(), EmailPolicy)
+	ctx := context.Background()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait, _, err := throttle.Take(ctx, "reset:alice@play4good.test")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if wait <= 0 {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != EmailPolicy.LockoutAfter {
+		t.Errorf("%d attempts allowed, want %d", allowed, EmailPolicy.LockoutAfter)
+	}
+}
+
+func TestTakeBacksOffAndLocks(t *testing.T) {
+	policy := Policy{FreeAttempts: 1, BaseDelay: time.Hour, MaxDelay: time.Hour, LockoutAfter: 3, LockoutFor: time.Hour, ResetAfter: 2 * time.Hour}
+	store := NewMemoryStore()
+	throttle := NewThrottle(store, policy)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if wait, _, err := throttle.Take(ctx, "account:bob"); err != nil || wait > 0 {
+			t.Fatalf("attempt %d: wait %v, err %v", i+1, wait, err)
+		}
+	}
+	// The second failure is past the free attempt, so the next one waits
+	if wait, locked, _ := throttle.Take(ctx, "account:bob"); wait <= 0 || locked {
+		t.Fatalf("third attempt: wait %v, locked %v; want a back-off", wait, locked)
+	}
+
+	// A released attempt no longer counts towards the back-off
+	if err := throttle.Release(ctx, "account:bob"); err != nil {
+		t.Fatal(err)
+	}
+	if wait, locked, _ := throttle.Take(ctx, "account:bob"); wait > 0 || locked {
+		t.Fatalf("attempt after release: wait %v, locked %v; want it allowed", wait, locked)
+	}
+
+	// Once the back-off has passed, the attempt that reaches LockoutAfter locks the key
+	attempts, _ := store.Get(ctx, "account:bob")
+	attempts.LastFailure = attempts.LastFailure.Add(-policy.BaseDelay)
+	store.attempts["account:bob"] = attempts
+	if wait, locked, _ := throttle.Take(ctx, "account:bob"); wait > 0 || !locked {
+		t.Fatalf("locking attempt: wait %v, locked %v; want it allowed and locked", wait, locked)
+	}
+	if wait, locked, _ := throttle.Take(ctx, "account:bob"); wait <= 0 || !locked {
+		t.Fatalf("attempt while locked: wait %v, locked %v; want it refused", wait, locked)
+	}
+
+	if err := throttle.Succeed(ctx, "account:bob"); err != nil {
+		t.Fatal(err)
+	}
+	if wait, _, _ := throttle.Take(ctx, "account:bob"); wait > 0 {
+		t.Fatalf("attempt after success waits %v", wait)
+	}
+}
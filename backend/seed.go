@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	dbCon "play4good-backend/db/sqlc"
+	"play4good-backend/seed"
+)
+
+// runSeed implements the seed command
+func runSeed(ctx context.Context, conn *sql.DB, args []string) error {
+	var opts seed.Options
+	var until string
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.IntVar(&opts.Users, "users", 200, "number of users")
+	fs.IntVar(&opts.Teams, "teams", 20, "number of teams; most users join one")
+	fs.IntVar(&opts.Causes, "causes", 30, "number of causes, spread across categories")
+	fs.IntVar(&opts.Donations, "donations", 5000, "number of donations")
+	fs.IntVar(&opts.Days, "days", 365, "spread donations over this many days")
+	fs.StringVar(&until, "until", "", "date (YYYY-MM-DD) the donations lead up to; today when empty")
+	fs.Int64Var(&opts.Seed, "seed", 1, "random seed; the same seed and flags give the same data")
+	fs.StringVar(&opts.Password, "password", seed.DefaultPassword, "password of every seeded user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if until != "" {
+		t, err := time.Parse(time.DateOnly, until)
+		if err != nil {
+			return fmt.Errorf("invalid -until: %w", err)
+		}
+		opts.Until = t
+	}
+
+	started := time.Now()
+	summary, err := seed.Run(ctx, dbCon.NewStore(conn), opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("seeded %d users (%d team memberships), %d teams, %d causes, %d donations and %d leaderboards in %s\n",
+		summary.Users, summary.Memberships, summary.Teams, summary.Causes, summary.Donations, summary.Leaderboards,
+		time.Since(started).Round(time.Millisecond))
+	fmt.Printf("users sign in as %s ... %s\n", seed.Email(1), seed.Email(summary.Users))
+	return nil
+}
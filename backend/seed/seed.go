@@ -0,0 +1,270 @@
+// Package seed fills a database with deterministic fake users, teams, causes and donations for local
+// development and benchmarks. The same options always produce the same rows.
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"play4good-backend/admin"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/util"
+)
+
+// DefaultPassword is the password of every seeded user unless Options.Password is set
+const DefaultPassword = "play4good-seed"
+
+// batchSize is how many donations are inserted per transaction
+const batchSize = 500
+
+// Categories seeded causes are spread across
+var Categories = []string{"education", "health", "environment", "animals", "disaster-relief", "community"}
+
+var (
+	teamAdjectives = []string{"Bright", "Swift", "Kind", "Bold", "Green", "Golden", "Steady", "Brave"}
+	teamNouns      = []string{"Otters", "Falcons", "Builders", "Giants", "Lanterns", "Rovers", "Oaks", "Sparks"}
+	firstNames     = []string{"Ada", "Ben", "Chloe", "Dev", "Elif", "Femi", "Grace", "Hiro", "Ines", "Jon", "Kemi", "Luca"}
+	lastNames      = []string{"Okafor", "Silva", "Nguyen", "Kowalski", "Haddad", "Moreau", "Tanaka", "Reyes", "Novak", "Park"}
+	causeSubjects  = map[string][]string{
+		"education":       {"School Books", "Laptops for Learners", "Reading Corners", "Scholarship Fund"},
+		"health":          {"Clinic Supplies", "Clean Water Wells", "Vaccination Drive", "Mobile Health Van"},
+		"environment":     {"Tree Planting", "River Cleanup", "Solar Schools", "Beach Restoration"},
+		"animals":         {"Shelter Meals", "Wildlife Rescue", "Vet Care Fund", "Habitat Protection"},
+		"disaster-relief": {"Flood Relief", "Emergency Shelter", "Earthquake Recovery", "Winter Blankets"},
+		"community":       {"Food Bank", "Youth Sports Kits", "Community Garden", "Senior Meals"},
+	}
+)
+
+type Options struct {
+	Users     int
+	Teams     int
+	Causes    int
+	Donations int
+	// Donations are spread over the Days days before Until
+	Days  int
+	Until time.Time
+	Seed  int64
+	// Password of every seeded user; DefaultPassword when empty
+	Password string
+}
+
+// Summary counts what Run created
+type Summary struct {
+	Users        int
+	Teams        int
+	Memberships  int
+	Causes       int
+	Donations    int
+	Leaderboards int
+}
+
+// Email is the address of the i-th seeded user, counting from 1
+func Email(i int) string {
+	return fmt.Sprintf("donor%05d@seed.play4good.test", i)
+}
+
+// Run inserts the fake data, then brings cause totals and leaderboards in line with the donations
+// using the same admin operations as the CLI. It refuses to run twice against the same database.
+func Run(ctx context.Context, store *db.Store, opts Options) (Summary, error) {
+	var summary Summary
+	if opts.Users < 1 || opts.Causes < 1 {
+		return summary, errors.New("at least one user and one cause are needed")
+	}
+	if opts.Days < 1 {
+		opts.Days = 365
+	}
+	if opts.Until.IsZero() {
+		opts.Until = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if opts.Password == "" {
+		opts.Password = DefaultPassword
+	}
+	if _, err := store.GetUserByEmail(ctx, Email(1)); err == nil {
+		return summary, errors.New("database already has seed data")
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return summary, err
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	// bcrypt is deliberately slow, so every user shares one hash
+	hash, err := util.HashPassword(opts.Password)
+	if err != nil {
+		return summary, err
+	}
+
+	var userIDs, teamIDs, causeIDs []int32
+	userTeam := map[int32]int32{}
+
+	err = store.ExecTx(ctx, func(q db.Querier) error {
+		for i := 1; i <= opts.Users; i++ {
+			user, err := q.CreateUser(ctx, db.CreateUserParams{
+				Username:     fmt.Sprintf("seed_donor_%05d", i),
+				Email:        Email(i),
+				PasswordHash: hash,
+				FirstName:    sql.NullString{String: pick(rng, firstNames), Valid: true},
+				LastName:     sql.NullString{String: pick(rng, lastNames), Valid: true},
+				UserRole:     sql.NullString{String: admin.RoleUser, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("user %d: %w", i, err)
+			}
+			// Verified, so large seeded or generated donations are accepted
+			if _, err := q.MarkUserEmailVerified(ctx, user.ID); err != nil {
+				return err
+			}
+			userIDs = append(userIDs, user.ID)
+		}
+
+		for i := 1; i <= opts.Teams; i++ {
+			team, err := q.CreateTeam(ctx, db.CreateTeamParams{
+				Name:        fmt.Sprintf("%s %s %02d", pick(rng, teamAdjectives), pick(rng, teamNouns), i),
+				Description: sql.NullString{String: "Generated team for local development", Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("team %d: %w", i, err)
+			}
+			teamIDs = append(teamIDs, team.ID)
+		}
+
+		// Four in five users join one team; the first member of each team runs it
+		if len(teamIDs) > 0 {
+			hasAdmin := map[int32]bool{}
+			for _, userID := range userIDs {
+				if rng.Intn(5) == 0 {
+					continue
+				}
+				teamID := teamIDs[rng.Intn(len(teamIDs))]
+				role := "member"
+				if !hasAdmin[teamID] {
+					role, hasAdmin[teamID] = "admin", true
+				}
+				if _, err := q.AddUserToTeam(ctx, db.AddUserToTeamParams{UserID: userID, TeamID: teamID, Role: role}); err != nil {
+					return err
+				}
+				userTeam[userID] = teamID
+				summary.Memberships++
+			}
+		}
+
+		start := opts.Until.AddDate(0, 0, -opts.Days)
+		for i := 1; i <= opts.Causes; i++ {
+			category := Categories[(i-1)%len(Categories)]
+			subjects := causeSubjects[category]
+			causeStart := start.AddDate(0, 0, rng.Intn(opts.Days/4+1))
+			cause, err := q.CreateCause(ctx, db.CreateCauseParams{
+				Name:        fmt.Sprintf("%s #%d", subjects[rng.Intn(len(subjects))], i),
+				Description: sql.NullString{String: "Generated " + category + " cause", Valid: true},
+				Goal:        sql.NullString{String: strconv.Itoa(1000 * (5 + rng.Intn(96))), Valid: true},
+				StartDate:   sql.NullTime{Time: causeStart, Valid: true},
+				EndDate:     sql.NullTime{Time: opts.Until.AddDate(0, 0, 30+rng.Intn(180)), Valid: true},
+				Status:      sql.NullString{String: "active", Valid: true},
+				Category:    sql.NullString{String: category, Valid: true},
+				OwnerID:     sql.NullInt32{Int32: userIDs[rng.Intn(len(userIDs))], Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("cause %d: %w", i, err)
+			}
+			causeIDs = append(causeIDs, cause.ID)
+		}
+
+		for _, board := range []struct{ name, kind string }{{"Top Donors", "individual"}, {"Top Teams", "team"}} {
+			if _, err := q.CreateLeaderboard(ctx, db.CreateLeaderboardParams{
+				Name:      board.name,
+				Type:      sql.NullString{String: board.kind, Valid: true},
+				StartDate: sql.NullTime{Time: start, Valid: true},
+				EndDate:   sql.NullTime{Time: opts.Until, Valid: true},
+			}); err != nil {
+				return err
+			}
+			summary.Leaderboards++
+		}
+		return nil
+	})
+	if err != nil {
+		return summary, err
+	}
+	summary.Users, summary.Teams, summary.Causes = len(userIDs), len(teamIDs), len(causeIDs)
+
+	// Donations go in batches so a large run does not hold one huge transaction
+	span := time.Duration(opts.Days) * 24 * time.Hour
+	for done := 0; done < opts.Donations; {
+		n := min(batchSize, opts.Donations-done)
+		err := store.ExecTx(ctx, func(q db.Querier) error {
+			for i := 0; i < n; i++ {
+				userID := userIDs[rng.Intn(len(userIDs))]
+				teamID, inTeam := userTeam[userID]
+				if _, err := q.CreateSeedDonation(ctx, db.CreateSeedDonationParams{
+					UserID:       sql.NullInt32{Int32: userID, Valid: true},
+					CauseID:      sql.NullInt32{Int32: causeIDs[popular(rng, len(causeIDs))], Valid: true},
+					TeamID:       sql.NullInt32{Int32: teamID, Valid: inTeam},
+					Amount:       sql.NullString{String: Amount(rng), Valid: true},
+					DonationType: sql.NullString{String: DonationType(rng), Valid: true},
+					CreatedAt:    sql.NullTime{Time: backdate(rng, opts.Until, span), Valid: true},
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return summary, fmt.Errorf("donations: %w", err)
+		}
+		done += n
+		summary.Donations = done
+	}
+
+	service := admin.NewService(store)
+	if _, err := service.RecomputeCauseTotals(ctx); err != nil {
+		return summary, err
+	}
+	if _, err := service.RebuildLeaderboards(ctx, 0); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// Amount returns a gift size: mostly small, some medium and a few large, below the verification threshold
+func Amount(rng *rand.Rand) string {
+	var cents int
+	switch p := rng.Intn(100); {
+	case p < 70:
+		cents = 500 + rng.Intn(4500)
+	case p < 95:
+		cents = 5000 + rng.Intn(20000)
+	default:
+		cents = 25000 + rng.Intn(65000)
+	}
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+// DonationType is money four times in five, otherwise goods or a service
+func DonationType(rng *rand.Rand) string {
+	switch p := rng.Intn(100); {
+	case p < 80:
+		return "money"
+	case p < 92:
+		return "goods"
+	default:
+		return "service"
+	}
+}
+
+// popular picks an index in [0, n) favouring the low end, so a few causes draw most donations
+func popular(rng *rand.Rand, n int) int {
+	return int(float64(n) * rng.Float64() * rng.Float64())
+}
+
+// backdate returns a time in the span before until, weighted towards recent days
+func backdate(rng *rand.Rand, until time.Time, span time.Duration) time.Time {
+	ago := time.Duration(float64(span) * rng.Float64() * rng.Float64())
+	return until.Add(-ago).Truncate(time.Second)
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
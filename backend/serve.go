@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"play4good-backend/analytics"
+	"play4good-backend/controllers"
+	"play4good-backend/db/migration"
+	dbCon "play4good-backend/db/sqlc"
+	"play4good-backend/health"
+	"play4good-backend/jobs"
+	"play4good-backend/metrics"
+	"play4good-backend/privacy"
+	"play4good-backend/reports"
+	"play4good-backend/retention"
+	"play4good-backend/routes"
+	"play4good-backend/tracing"
+	"play4good-backend/util"
+)
+
+// runServe runs the API server and its background jobs until ctx is cancelled, then shuts both down
+func runServe(ctx context.Context, config util.Config) error {
+	shutdownTracing, err := tracing.Setup(ctx, config)
+	if err != nil {
+		return fmt.Errorf("could not set up tracing: %w", err)
+	}
+	defer func() {
+		// ctx is already cancelled by the time this runs, so flush with a deadline of its own
+		flushCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("could not flush traces", "error", err)
+		}
+	}()
+
+	conn, err := openDB(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Error("could not close database", "error", err)
+		}
+	}()
+	metrics.RegisterDBStats(conn, config.PostgresDb)
+	slog.Info("postgres connection established", "database", config.PostgresDb, "max_open_conns", config.DbMaxOpenConns)
+
+	// Only start against the schema this build was written for, migrating first if allowed to
+	if config.MigrateOnStart {
+		if err := migration.Up(conn); err != nil {
+			return fmt.Errorf("could not migrate database: %w", err)
+		}
+	}
+	if err := migration.Check(ctx, conn, migration.Latest()); err != nil {
+		return fmt.Errorf("refusing to start, run migrate up or set MIGRATE_ON_START: %w", err)
+	}
+
+	db := dbCon.NewStore(conn)
+	checker := health.NewChecker(conn, migration.Latest())
+
+	controller := controllers.NewPlay4GoodController(db, config, util.NewMailer(config))
+	server := routes.NewRouter(controller, checker)
+
+	// Background work: data exports, report exports, donation rollups, scheduled account erasures and
+	// the soft-delete purge
+	privacyService := privacy.NewService(db, config)
+	reportService := reports.NewService(db, config)
+	analyticsService := analytics.NewService(db)
+	purger := retention.NewPurger(db, privacyService, config)
+	tasks := append(privacyService.Tasks(), reportService.Tasks()...)
+	tasks = append(tasks, analyticsService.Tasks()...)
+	workers := jobs.Start(ctx, append(tasks, purger.Task())...)
+
+	httpServer := &http.Server{
+		Addr:              ":" + config.ServerAddress,
+		Handler:           server,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+
+	servers := []*http.Server{httpServer}
+	if config.MetricsAddress != "" {
+		servers = append(servers, &http.Server{
+			Addr:              ":" + config.MetricsAddress,
+			Handler:           routes.NewMetricsRouter(),
+			ReadHeaderTimeout: 5 * time.Second,
+		})
+	}
+
+	serveErr := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			slog.Info("starting server", "address", srv.Addr)
+			serveErr <- srv.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down", "timeout", config.ShutdownTimeout.String())
+	shutdown(servers, checker, workers, config.ShutdownTimeout)
+	return nil
+}
+
+// shutdown fails the readiness probe, stops accepting connections on every server and waits up to
+// timeout for in-flight requests and background jobs. The database and tracing are closed by runServe.
+func shutdown(servers []*http.Server, checker *health.Checker, workers *sync.WaitGroup, timeout time.Duration) {
+	checker.Drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("http server did not shut down cleanly", "address", server.Addr, "error", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		slog.Warn("background jobs still running at shutdown deadline")
+	}
+
+	slog.Info("servers and background jobs stopped")
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+	"play4good-backend/util"
+)
+
+// SessionTTL is how long a session token stays valid
+const SessionTTL = 24 * time.Hour
+
+// defaultRole is the role of users who sign up themselves
+const defaultRole = "user"
+
+var (
+	ErrEmailTaken         error = problem.New(http.StatusConflict, problem.CodeEmailTaken, "Email already registered")
+	ErrInvalidCredentials error = problem.New(http.StatusUnauthorized, problem.CodeInvalidCredentials, "Invalid credentials")
+	// ErrInvalidSession covers malformed, expired, revoked and mismatched session tokens alike
+	ErrInvalidSession = errors.New("invalid token")
+)
+
+// AuthService signs users up and in and manages their session tokens
+type AuthService interface {
+	// SignUp creates a user with the default role
+	SignUp(ctx context.Context, req schemas.UserCreateRequest) (db.User, error)
+	// Authenticate checks an email and password. With ErrInvalidCredentials it still returns the
+	// user when the account exists, so the failure can be counted against it.
+	Authenticate(ctx context.Context, email, password string) (db.User, error)
+	// StartSession returns the user's session token, reusing one that is still valid
+	StartSession(ctx context.Context, userID int32) (string, error)
+	// ResolveSession returns the user a session token belongs to
+	ResolveSession(ctx context.Context, token string) (int32, error)
+}
+
+type authService struct {
+	repo db.Repository
+}
+
+func NewAuthService(repo db.Repository) AuthService {
+	return &authService{repo: repo}
+}
+
+func (s *authService) SignUp(ctx context.Context, req schemas.UserCreateRequest) (db.User, error) {
+	if _, err := s.repo.GetUserByEmail(ctx, req.Email); err == nil {
+		return db.User{}, ErrEmailTaken
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, err
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	var user db.User
+	err = s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		user, err = q.CreateUser(ctx, db.CreateUserParams{
+			Username:     req.Username,
+			Email:        req.Email,
+			PasswordHash: hashedPassword,
+			FirstName:    sql.NullString{String: req.FirstName, Valid: true},
+			LastName:     sql.NullString{String: req.LastName, Valid: true},
+			AvatarUrl:    nullString(req.AvatarURL),
+			UserRole:     sql.NullString{String: defaultRole, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		// The new account is the actor of its own sign-up
+		actor := audit.ActorFrom(ctx)
+		actor.ID = user.ID
+		return record(audit.WithActor(ctx, actor), q, audit.ActionCreate, audit.EntityUser, user.ID, nil, user)
+	})
+	return user, err
+}
+
+func (s *authService) Authenticate(ctx context.Context, email, password string) (db.User, error) {
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return db.User{}, err
+	}
+	if err := util.CheckPassword(password, user.PasswordHash); err != nil {
+		return user, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (s *authService) StartSession(ctx context.Context, userID int32) (string, error) {
+	owner := sql.NullInt32{Int32: userID, Valid: true}
+	existing, err := s.repo.GetUserTokenByUserID(ctx, owner)
+	if err == nil && existing.Expiry.After(time.Now()) {
+		return existing.Token, nil
+	}
+
+	token, err := util.GenerateJWT(int(userID))
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.repo.CreateUserToken(ctx, db.CreateUserTokenParams{
+		UserID: owner,
+		Token:  token,
+		Expiry: time.Now().Add(SessionTTL),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *authService) ResolveSession(ctx context.Context, token string) (int32, error) {
+	userID, err := util.ParseToken(token)
+	if err != nil {
+		return 0, ErrInvalidSession
+	}
+	// The token must still be on record; password resets revoke every stored token
+	stored, err := s.repo.GetUserTokenByToken(ctx, token)
+	if err != nil || stored.Expiry.Before(time.Now()) || stored.UserID.Int32 != int32(userID) {
+		return 0, ErrInvalidSession
+	}
+	return int32(userID), nil
+}
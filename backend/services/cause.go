@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/schemas"
+)
+
+// CauseService manages causes
+type CauseService interface {
+	// Create adds a cause owned by ownerID
+	Create(ctx context.Context, ownerID int32, req schemas.CauseCreateRequest) (db.Cause, error)
+	Get(ctx context.Context, id int32) (db.Cause, error)
+	List(ctx context.Context, limit, offset int32) ([]db.Cause, error)
+	Update(ctx context.Context, id int32, req schemas.CauseUpdateRequest, check VersionCheck) (db.Cause, error)
+	Patch(ctx context.Context, id int32, patch schemas.CausePatchRequest, check VersionCheck) (db.Cause, error)
+	// Delete soft-deletes a cause; admins can restore it
+	Delete(ctx context.Context, id int32) error
+}
+
+type causeService struct {
+	repo db.Repository
+}
+
+func NewCauseService(repo db.Repository) CauseService {
+	return &causeService{repo: repo}
+}
+
+func (s *causeService) Create(ctx context.Context, ownerID int32, req schemas.CauseCreateRequest) (db.Cause, error) {
+	var cause db.Cause
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		cause, err = q.CreateCause(ctx, db.CreateCauseParams{
+			Name:        req.Name,
+			Description: nullString(req.Description),
+			Goal:        amount(req.Goal),
+			StartDate:   nullTime(req.StartDate),
+			EndDate:     nullTime(req.EndDate),
+			Status:      nullString(req.Status),
+			Image:       nullString(req.Image),
+			Category:    nullString(req.Category),
+			OwnerID:     sql.NullInt32{Int32: ownerID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityCause, cause.ID, nil, cause)
+	})
+	return cause, err
+}
+
+func (s *causeService) Get(ctx context.Context, id int32) (db.Cause, error) {
+	return s.repo.GetCause(ctx, id)
+}
+
+func (s *causeService) List(ctx context.Context, limit, offset int32) ([]db.Cause, error) {
+	return s.repo.ListCauses(ctx, db.ListCausesParams{Limit: limit, Offset: offset})
+}
+
+func (s *causeService) Update(ctx context.Context, id int32, req schemas.CauseUpdateRequest, check VersionCheck) (db.Cause, error) {
+	var cause db.Cause
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetCause(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(check, before.Version); err != nil {
+			return err
+		}
+		cause, err = q.UpdateCause(ctx, db.UpdateCauseParams{
+			ID:          id,
+			Name:        req.Name,
+			Description: nullString(req.Description),
+			Goal:        amount(req.Goal),
+			StartDate:   nullTime(req.StartDate),
+			EndDate:     nullTime(req.EndDate),
+			Status:      nullString(req.Status),
+			Image:       nullString(req.Image),
+			Category:    nullString(req.Category),
+			// The raised amount is maintained by donations, not by edits
+			CurrentAmount: before.CurrentAmount,
+			Version:       before.Version,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityCause, id, before, cause)
+	})
+	return cause, err
+}
+
+func (s *causeService) Patch(ctx context.Context, id int32, patch schemas.CausePatchRequest, check VersionCheck) (db.Cause, error) {
+	var cause db.Cause
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetCause(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(check, before.Version); err != nil {
+			return err
+		}
+		if err := checkPatchedDates(before.StartDate, before.EndDate, patch.StartDate, patch.EndDate); err != nil {
+			return err
+		}
+		cause, err = q.PatchCause(ctx, db.PatchCauseParams{
+			SetName:        patch.Name.Set,
+			Name:           patch.Name.Value,
+			SetDescription: patch.Description.Set,
+			Description:    patchString(patch.Description),
+			SetGoal:        patch.Goal.Set,
+			Goal:           patchAmount(patch.Goal),
+			SetStartDate:   patch.StartDate.Set,
+			StartDate:      patchTime(patch.StartDate),
+			SetEndDate:     patch.EndDate.Set,
+			EndDate:        patchTime(patch.EndDate),
+			SetStatus:      patch.Status.Set,
+			Status:         patchString(patch.Status),
+			SetImage:       patch.Image.Set,
+			Image:          patchString(patch.Image),
+			SetCategory:    patch.Category.Set,
+			Category:       patchString(patch.Category),
+			ID:             id,
+			Version:        before.Version,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityCause, id, before, cause)
+	})
+	return cause, err
+}
+
+func (s *causeService) Delete(ctx context.Context, id int32) error {
+	return s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetCause(ctx, id)
+		if err != nil {
+			return err
+		}
+		after, err := q.DeleteCause(ctx, id)
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionDelete, audit.EntityCause, id, before, after)
+	})
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/metrics"
+	"play4good-backend/schemas"
+)
+
+// DonationService records and reads donations
+type DonationService interface {
+	Create(ctx context.Context, req schemas.DonationCreateRequest) (db.Donation, error)
+	Get(ctx context.Context, id int32) (db.Donation, error)
+	List(ctx context.Context, limit, offset int32) ([]db.Donation, error)
+}
+
+type donationService struct {
+	repo db.Repository
+}
+
+func NewDonationService(repo db.Repository) DonationService {
+	return &donationService{repo: repo}
+}
+
+func (s *donationService) Create(ctx context.Context, req schemas.DonationCreateRequest) (db.Donation, error) {
+	arg := db.CreateDonationParams{
+		UserID:       sql.NullInt32{Int32: int32(req.UserID), Valid: true},
+		CauseID:      sql.NullInt32{Int32: int32(req.CauseID), Valid: true},
+		TeamID:       sql.NullInt32{Int32: int32(req.TeamID), Valid: req.TeamID != 0},
+		Amount:       amount(req.Amount),
+		DonationType: nullString(req.DonationType),
+	}
+
+	var donation db.Donation
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		donation, err = q.CreateDonation(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityDonation, donation.ID, nil, donation)
+	})
+	if err != nil {
+		return db.Donation{}, err
+	}
+	metrics.RecordDonation(req.DonationType, req.Amount)
+	return donation, nil
+}
+
+func (s *donationService) Get(ctx context.Context, id int32) (db.Donation, error) {
+	return s.repo.GetDonation(ctx, id)
+}
+
+func (s *donationService) List(ctx context.Context, limit, offset int32) ([]db.Donation, error) {
+	return s.repo.ListDonations(ctx, db.ListDonationsParams{Limit: limit, Offset: offset})
+}
@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/imports"
+	"play4good-backend/metrics"
+	"play4good-backend/problem"
+)
+
+// Import statuses, as stored in donation_imports.status
+const (
+	ImportValidated  = "validated"
+	ImportImporting  = "importing"
+	ImportCompleted  = "completed"
+	ImportRolledBack = "rolled_back"
+)
+
+// Commit modes. An atomic commit imports every row or none; a chunked one commits each chunk on its
+// own and, if interrupted, carries on from the last committed chunk when run again.
+const (
+	CommitAtomic  = "atomic"
+	CommitChunked = "chunked"
+)
+
+// DefaultChunkSize is the number of rows per chunk when the caller does not choose one
+const DefaultChunkSize = 500
+
+var (
+	// ErrImportRolledBack rejects commits and rollbacks of a batch that was already rolled back
+	ErrImportRolledBack error = problem.New(http.StatusConflict, problem.CodeConflict, "The import has been rolled back")
+	// ErrImportHasInvalidRows rejects a commit that would silently drop rows
+	ErrImportHasInvalidRows error = problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, "The import has invalid rows; fix the file and upload it again, or commit with skip_invalid to import only the valid rows")
+)
+
+// ImportFile is an uploaded spreadsheet and how to read it
+type ImportFile struct {
+	Name    string
+	Source  string
+	Mapping imports.Mapping
+	Body    io.Reader
+}
+
+// ImportReport is the outcome of validating a file, row by row
+type ImportReport struct {
+	ImportID    int32              `json:"import_id,omitempty"`
+	DryRun      bool               `json:"dry_run"`
+	TotalRows   int                `json:"total_rows"`
+	ValidRows   int                `json:"valid_rows"`
+	InvalidRows int                `json:"invalid_rows"`
+	Errors      []imports.RowError `json:"errors"`
+}
+
+// CommitOptions chooses how a batch is committed
+type CommitOptions struct {
+	Mode        string
+	ChunkSize   int32
+	SkipInvalid bool
+}
+
+// RecordImportOptions chooses how a file of causes or teams is imported
+type RecordImportOptions struct {
+	DryRun      bool
+	SkipInvalid bool
+}
+
+// ImportService loads donations, causes and teams in bulk from spreadsheets. A donation file is
+// validated and staged as a batch first, then committed; every donation it creates is tagged with
+// the batch so the whole batch can be rolled back. Causes and teams are created in one transaction
+// straight from the file, and are not staged.
+type ImportService interface {
+	// Preview validates file without storing anything
+	Preview(ctx context.Context, file ImportFile) (ImportReport, error)
+	// Stage validates file and stores its valid rows as a batch ready to commit
+	Stage(ctx context.Context, createdBy int32, file ImportFile) (db.DonationImport, ImportReport, error)
+	Get(ctx context.Context, id int32) (db.DonationImport, error)
+	List(ctx context.Context, limit, offset int32) ([]db.DonationImport, error)
+	Commit(ctx context.Context, id int32, opts CommitOptions) (db.DonationImport, error)
+	RollBack(ctx context.Context, id int32) (db.DonationImport, error)
+	// ImportCauses validates a file of causes and, unless it is a dry run, creates them all, owned by
+	// ownerID, or none
+	ImportCauses(ctx context.Context, ownerID int32, file ImportFile, opts RecordImportOptions) ([]db.Cause, ImportReport, error)
+	// ImportTeams validates a file of teams and, unless it is a dry run, creates them all or none
+	ImportTeams(ctx context.Context, file ImportFile, opts RecordImportOptions) ([]db.Team, ImportReport, error)
+}
+
+type importService struct {
+	repo db.Repository
+}
+
+func NewImportService(repo db.Repository) ImportService {
+	return &importService{repo: repo}
+}
+
+func (s *importService) Preview(ctx context.Context, file ImportFile) (ImportReport, error) {
+	_, report, err := s.validate(ctx, file)
+	report.DryRun = true
+	return report, err
+}
+
+func (s *importService) Stage(ctx context.Context, createdBy int32, file ImportFile) (db.DonationImport, ImportReport, error) {
+	donations, report, err := s.validate(ctx, file)
+	if err != nil {
+		return db.DonationImport{}, ImportReport{}, err
+	}
+	errs, err := json.Marshal(report.Errors)
+	if err != nil {
+		return db.DonationImport{}, ImportReport{}, err
+	}
+
+	rows := db.CreateDonationImportRowsParams{}
+	for _, d := range donations {
+		rows.RowNumbers = append(rows.RowNumbers, int32(d.Row))
+		rows.UserIds = append(rows.UserIds, int32(d.Request.UserID))
+		rows.CauseIds = append(rows.CauseIds, int32(d.Request.CauseID))
+		rows.TeamIds = append(rows.TeamIds, int32(d.Request.TeamID))
+		rows.Amounts = append(rows.Amounts, strconv.FormatFloat(d.Request.Amount, 'f', -1, 64))
+		rows.DonationTypes = append(rows.DonationTypes, d.Request.DonationType)
+		var donatedAt string
+		if !d.DonatedAt.IsZero() {
+			donatedAt = d.DonatedAt.Format(time.RFC3339)
+		}
+		rows.DonatedAt = append(rows.DonatedAt, donatedAt)
+	}
+
+	var batch db.DonationImport
+	err = s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		batch, err = q.CreateDonationImport(ctx, db.CreateDonationImportParams{
+			CreatedBy: sql.NullInt32{Int32: createdBy, Valid: createdBy != 0},
+			Source:    file.Source,
+			Filename:  file.Name,
+			TotalRows: int32(report.TotalRows),
+			ValidRows: int32(report.ValidRows),
+			Errors:    errs,
+		})
+		if err != nil {
+			return err
+		}
+		if len(donations) > 0 {
+			rows.ImportID = batch.ID
+			if _, err := q.CreateDonationImportRows(ctx, rows); err != nil {
+				return err
+			}
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityDonationImport, batch.ID, nil, batch)
+	})
+	if err != nil {
+		return db.DonationImport{}, ImportReport{}, err
+	}
+	report.ImportID = batch.ID
+	return batch, report, nil
+}
+
+// validate parses file and checks every row, outside any transaction
+func (s *importService) validate(ctx context.Context, file ImportFile) ([]imports.Donation, ImportReport, error) {
+	sheet, columns, err := parse(file, imports.Donations)
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+	donations, rowErrs, err := imports.NewValidator(s.repo, columns).Validate(ctx, sheet)
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+	return donations, report(sheet, rowErrs), nil
+}
+
+// parse reads file and finds the column of each of kind's fields
+func parse(file ImportFile, kind imports.Kind) (*imports.Sheet, map[string]int, error) {
+	sheet, err := imports.Parse(file.Name, file.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns, err := file.Mapping.Columns(kind, sheet.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sheet, columns, nil
+}
+
+func report(sheet *imports.Sheet, rowErrs []imports.RowError) ImportReport {
+	if rowErrs == nil {
+		rowErrs = []imports.RowError{}
+	}
+	return ImportReport{
+		TotalRows:   len(sheet.Rows),
+		ValidRows:   len(sheet.Rows) - len(rowErrs),
+		InvalidRows: len(rowErrs),
+		Errors:      rowErrs,
+	}
+}
+
+func (s *importService) Get(ctx context.Context, id int32) (db.DonationImport, error) {
+	return s.repo.GetDonationImport(ctx, id)
+}
+
+func (s *importService) List(ctx context.Context, limit, offset int32) ([]db.DonationImport, error) {
+	return s.repo.ListDonationImports(ctx, db.ListDonationImportsParams{Limit: limit, Offset: offset})
+}
+
+func (s *importService) Commit(ctx context.Context, id int32, opts CommitOptions) (db.DonationImport, error) {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	if opts.Mode == CommitChunked {
+		return s.commitChunked(ctx, id, opts)
+	}
+
+	var batch db.DonationImport
+	var totals importedTotals
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := lockForCommit(ctx, q, id, opts)
+		if err != nil || before.Status == ImportCompleted {
+			batch = before
+			return err
+		}
+		if batch, totals, err = importRemaining(ctx, q, before, opts.ChunkSize, 0); err != nil {
+			return err
+		}
+		batch, err = complete(ctx, q, before)
+		return err
+	})
+	if err != nil {
+		return db.DonationImport{}, err
+	}
+	totals.record()
+	return batch, nil
+}
+
+// commitChunked commits one chunk per transaction. Progress is stored with each chunk, so a commit
+// that stops part way, through an error or a cancelled request, resumes when called again.
+func (s *importService) commitChunked(ctx context.Context, id int32, opts CommitOptions) (db.DonationImport, error) {
+	var start db.DonationImport
+	for {
+		if err := ctx.Err(); err != nil {
+			return db.DonationImport{}, err
+		}
+
+		var batch db.DonationImport
+		var totals importedTotals
+		done := false
+		err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+			current, err := lockForCommit(ctx, q, id, opts)
+			if err != nil {
+				return err
+			}
+			if start.ID == 0 {
+				start = current
+			}
+			switch {
+			case current.Status == ImportCompleted:
+				batch, done = current, true
+				return nil
+			case current.ImportedRows >= current.ValidRows:
+				batch, err = complete(ctx, q, start)
+				done = true
+				return err
+			}
+			batch, totals, err = importRemaining(ctx, q, current, opts.ChunkSize, 1)
+			return err
+		})
+		if err != nil {
+			return db.DonationImport{}, err
+		}
+		totals.record()
+		if done {
+			return batch, nil
+		}
+	}
+}
+
+// lockForCommit locks a batch and checks that it may be committed
+func lockForCommit(ctx context.Context, q db.Querier, id int32, opts CommitOptions) (db.DonationImport, error) {
+	batch, err := q.LockDonationImport(ctx, id)
+	if err != nil {
+		return db.DonationImport{}, err
+	}
+	if batch.Status == ImportRolledBack {
+		return db.DonationImport{}, ErrImportRolledBack
+	}
+	if batch.ValidRows < batch.TotalRows && !opts.SkipInvalid {
+		return db.DonationImport{}, ErrImportHasInvalidRows
+	}
+	return batch, nil
+}
+
+// importRemaining imports up to limit chunks of batch's staged rows, or all of them when limit is 0,
+// and returns what they added up to
+func importRemaining(ctx context.Context, q db.Querier, batch db.DonationImport, chunkSize int32, limit int) (db.DonationImport, importedTotals, error) {
+	var totals importedTotals
+	for chunks := 0; batch.ImportedRows < batch.ValidRows && (limit == 0 || chunks < limit); chunks++ {
+		chunk, err := q.SummarizeDonationImportChunk(ctx, db.SummarizeDonationImportChunkParams{ImportID: batch.ID, ChunkSize: chunkSize})
+		if err != nil {
+			return db.DonationImport{}, nil, err
+		}
+		imported := batch.ImportedRows
+		batch, err = q.ImportDonationChunk(ctx, db.ImportDonationChunkParams{ImportID: batch.ID, ChunkSize: chunkSize})
+		if err != nil {
+			return db.DonationImport{}, nil, err
+		}
+		if batch.ImportedRows == imported {
+			return db.DonationImport{}, nil, fmt.Errorf("import %d stalled at row %d", batch.ID, batch.ImportedThrough)
+		}
+		totals = append(totals, chunk...)
+	}
+	return batch, totals, nil
+}
+
+// importedTotals are the donations imported in one transaction, by type. They are added to the
+// donation metrics only once the transaction commits, so a chunk that is retried is counted once.
+type importedTotals []db.SummarizeDonationImportChunkRow
+
+func (t importedTotals) record() {
+	for _, row := range t {
+		metrics.RecordDonations(row.DonationType, row.Donations, row.Amount)
+	}
+}
+
+func complete(ctx context.Context, q db.Querier, before db.DonationImport) (db.DonationImport, error) {
+	batch, err := q.CompleteDonationImport(ctx, before.ID)
+	if err != nil {
+		return db.DonationImport{}, err
+	}
+	if err := q.DeleteDonationImportRows(ctx, batch.ID); err != nil {
+		return db.DonationImport{}, err
+	}
+	return batch, record(ctx, q, audit.ActionUpdate, audit.EntityDonationImport, batch.ID, before, batch)
+}
+
+// RollBack deletes every donation a batch created, voids their receipts and marks the batch rolled
+// back. A batch that was staged but never committed is simply discarded.
+func (s *importService) RollBack(ctx context.Context, id int32) (db.DonationImport, error) {
+	var batch db.DonationImport
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.LockDonationImport(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.Status == ImportRolledBack {
+			return ErrImportRolledBack
+		}
+		// Receipts are kept for the record, but no longer stand for a donation
+		if _, err := q.VoidImportedDonationReceipts(ctx, db.VoidImportedDonationReceiptsParams{
+			ImportID:   sql.NullInt32{Int32: id, Valid: true},
+			VoidReason: sql.NullString{String: fmt.Sprintf("Donation import %d was rolled back", id), Valid: true},
+		}); err != nil {
+			return err
+		}
+		if _, err := q.DeleteImportedDonations(ctx, sql.NullInt32{Int32: id, Valid: true}); err != nil {
+			return err
+		}
+		if err := q.DeleteDonationImportRows(ctx, id); err != nil {
+			return err
+		}
+		if batch, err = q.RollBackDonationImport(ctx, id); err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionDelete, audit.EntityDonationImport, id, before, batch)
+	})
+	if err != nil {
+		return db.DonationImport{}, err
+	}
+	return batch, nil
+}
+
+func (s *importService) ImportCauses(ctx context.Context, ownerID int32, file ImportFile, opts RecordImportOptions) ([]db.Cause, ImportReport, error) {
+	sheet, columns, err := parse(file, imports.Causes)
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+	rows, rowErrs := imports.ValidateCauses(sheet, columns)
+	report := report(sheet, rowErrs)
+	report.DryRun = opts.DryRun
+	if opts.DryRun {
+		return []db.Cause{}, report, nil
+	}
+	if report.InvalidRows > 0 && !opts.SkipInvalid {
+		return nil, ImportReport{}, ErrImportHasInvalidRows
+	}
+
+	causes := make([]db.Cause, 0, len(rows))
+	err = s.repo.ExecTx(ctx, func(q db.Querier) error {
+		for _, row := range rows {
+			req := row.Request
+			cause, err := q.CreateCause(ctx, db.CreateCauseParams{
+				Name:        req.Name,
+				Description: nullString(req.Description),
+				Goal:        amount(req.Goal),
+				StartDate:   nullTime(req.StartDate),
+				EndDate:     nullTime(req.EndDate),
+				Status:      nullString(req.Status),
+				Image:       nullString(req.Image),
+				Category:    nullString(req.Category),
+				OwnerID:     sql.NullInt32{Int32: ownerID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if err := record(ctx, q, audit.ActionCreate, audit.EntityCause, cause.ID, nil, cause); err != nil {
+				return err
+			}
+			causes = append(causes, cause)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+	return causes, report, nil
+}
+
+func (s *importService) ImportTeams(ctx context.Context, file ImportFile, opts RecordImportOptions) ([]db.Team, ImportReport, error) {
+	sheet, columns, err := parse(file, imports.Teams)
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+	rows, rowErrs := imports.ValidateTeams(sheet, columns)
+	report := report(sheet, rowErrs)
+	report.DryRun = opts.DryRun
+	if opts.DryRun {
+		return []db.Team{}, report, nil
+	}
+	if report.InvalidRows > 0 && !opts.SkipInvalid {
+		return nil, ImportReport{}, ErrImportHasInvalidRows
+	}
+
+	teams := make([]db.Team, 0, len(rows))
+	err = s.repo.ExecTx(ctx, func(q db.Querier) error {
+		for _, row := range rows {
+			team, err := q.CreateTeam(ctx, db.CreateTeamParams{
+				Name:        row.Request.Name,
+				Description: nullString(row.Request.Description),
+				AvatarUrl:   nullString(row.Request.AvatarURL),
+			})
+			if err != nil {
+				return err
+			}
+			if err := record(ctx, q, audit.ActionCreate, audit.EntityTeam, team.ID, nil, team); err != nil {
+				return err
+			}
+			teams = append(teams, team)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+	return teams, report, nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/metrics"
+	"play4good-backend/schemas"
+)
+
+// LeaderboardService manages leaderboards and their entries
+type LeaderboardService interface {
+	Create(ctx context.Context, req schemas.LeaderboardCreateRequest) (db.Leaderboard, error)
+	Get(ctx context.Context, id int32) (db.Leaderboard, error)
+	List(ctx context.Context, limit, offset int32) ([]db.Leaderboard, error)
+	Patch(ctx context.Context, id int32, patch schemas.LeaderboardPatchRequest, check VersionCheck) (db.Leaderboard, error)
+	// SetEntry creates or replaces the score of a user or team on a leaderboard
+	SetEntry(ctx context.Context, req schemas.LeaderboardEntryUpdateRequest) (db.LeaderboardEntry, error)
+}
+
+type leaderboardService struct {
+	repo db.Repository
+}
+
+func NewLeaderboardService(repo db.Repository) LeaderboardService {
+	return &leaderboardService{repo: repo}
+}
+
+func (s *leaderboardService) Create(ctx context.Context, req schemas.LeaderboardCreateRequest) (db.Leaderboard, error) {
+	var leaderboard db.Leaderboard
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		leaderboard, err = q.CreateLeaderboard(ctx, db.CreateLeaderboardParams{
+			Name:      req.Name,
+			Type:      nullString(req.Type),
+			StartDate: nullTime(req.StartDate),
+			EndDate:   nullTime(req.EndDate),
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityLeaderboard, leaderboard.ID, nil, leaderboard)
+	})
+	if err != nil {
+		return db.Leaderboard{}, err
+	}
+	metrics.LeaderboardsCreated.Inc()
+	return leaderboard, nil
+}
+
+func (s *leaderboardService) Get(ctx context.Context, id int32) (db.Leaderboard, error) {
+	return s.repo.GetLeaderboard(ctx, id)
+}
+
+func (s *leaderboardService) List(ctx context.Context, limit, offset int32) ([]db.Leaderboard, error) {
+	return s.repo.ListLeaderboards(ctx, db.ListLeaderboardsParams{Limit: limit, Offset: offset})
+}
+
+func (s *leaderboardService) Patch(ctx context.Context, id int32, patch schemas.LeaderboardPatchRequest, check VersionCheck) (db.Leaderboard, error) {
+	var leaderboard db.Leaderboard
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetLeaderboard(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(check, before.Version); err != nil {
+			return err
+		}
+		if err := checkPatchedDates(before.StartDate, before.EndDate, patch.StartDate, patch.EndDate); err != nil {
+			return err
+		}
+		leaderboard, err = q.PatchLeaderboard(ctx, db.PatchLeaderboardParams{
+			SetName:      patch.Name.Set,
+			Name:         patch.Name.Value,
+			SetType:      patch.Type.Set,
+			Type:         patchString(patch.Type),
+			SetStartDate: patch.StartDate.Set,
+			StartDate:    patchTime(patch.StartDate),
+			SetEndDate:   patch.EndDate.Set,
+			EndDate:      patchTime(patch.EndDate),
+			ID:           id,
+			Version:      before.Version,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityLeaderboard, id, before, leaderboard)
+	})
+	return leaderboard, err
+}
+
+func (s *leaderboardService) SetEntry(ctx context.Context, req schemas.LeaderboardEntryUpdateRequest) (db.LeaderboardEntry, error) {
+	key := db.GetLeaderboardEntryParams{
+		LeaderboardID: int32(req.LeaderboardID),
+		UserID:        int32(req.UserID),
+		TeamID:        int32(req.TeamID),
+	}
+
+	var entry db.LeaderboardEntry
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetLeaderboardEntry(ctx, key)
+		// The entry is upserted, so a missing row means this call creates it
+		action := audit.ActionUpdate
+		var previous interface{} = before
+		if errors.Is(err, sql.ErrNoRows) {
+			action, previous = audit.ActionCreate, nil
+		} else if err != nil {
+			return err
+		}
+		entry, err = q.UpdateLeaderboardEntry(ctx, db.UpdateLeaderboardEntryParams{
+			LeaderboardID: key.LeaderboardID,
+			UserID:        key.UserID,
+			TeamID:        key.TeamID,
+			Score:         amount(req.Score),
+		})
+		if err != nil {
+			return err
+		}
+		entryID := fmt.Sprintf("%d:%d:%d", entry.LeaderboardID, entry.UserID, entry.TeamID)
+		return record(ctx, q, action, audit.EntityLeaderboardEntry, entryID, previous, entry)
+	})
+	if err != nil {
+		return db.LeaderboardEntry{}, err
+	}
+	metrics.LeaderboardEntriesUpdated.Inc()
+	return entry, nil
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/receipts"
+)
+
+var (
+	// ErrReceiptVoided means the donation's receipt was voided and has not been re-issued
+	ErrReceiptVoided error = problem.New(http.StatusConflict, problem.CodeConflict, "The receipt for this donation has been voided; an admin can re-issue it")
+	// ErrDonationNotReceiptable rejects receipts for donations without a donor or an amount
+	ErrDonationNotReceiptable error = problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, "The donation has no donor or amount to put on a receipt")
+	// ErrDonationNotCollected rejects receipts for donations that failed, were refunded or were cancelled
+	ErrDonationNotCollected error = problem.New(http.StatusUnprocessableEntity, problem.CodeUnprocessable, "The donation failed, was refunded or was cancelled, so it cannot be receipted")
+	// ErrNoDonationsInYear means there is nothing to put on a yearly statement
+	ErrNoDonationsInYear error = problem.New(http.StatusNotFound, problem.CodeNotFound, "No donations were made in that year")
+)
+
+// ReceiptService issues donation receipts and yearly statements. Every receipt is rendered once and
+// stored as issued, with a number that is gapless within its series and year. A receipt that is
+// wrong is voided, and a replacement that names it is issued in its place.
+type ReceiptService interface {
+	// DonationReceipt returns the donation's current receipt, issuing the first one on demand
+	DonationReceipt(ctx context.Context, donationID int32) (db.Receipt, error)
+	// Void voids the donation's current receipt without replacing it
+	Void(ctx context.Context, donationID int32, reason string) (db.Receipt, error)
+	// Reissue voids the donation's current receipt, if any, and issues a new one with the current
+	// details and branding
+	Reissue(ctx context.Context, donationID int32, reason string) (db.Receipt, error)
+	// Statement returns userID's statement of the donations made in year. When the year's donations
+	// have changed since the last statement, it is voided and a new one issued.
+	Statement(ctx context.Context, userID int32, year int) (db.Receipt, error)
+	List(ctx context.Context, userID int32) ([]db.ListReceiptsByUserRow, error)
+	// Template returns the branding template of a cause, or the organization's when causeID is 0
+	Template(ctx context.Context, causeID int32) (db.ReceiptTemplate, error)
+	SaveTemplate(ctx context.Context, causeID int32, branding receipts.Branding) (db.ReceiptTemplate, error)
+}
+
+type receiptService struct {
+	repo db.Repository
+}
+
+func NewReceiptService(repo db.Repository) ReceiptService {
+	return &receiptService{repo: repo}
+}
+
+func (s *receiptService) DonationReceipt(ctx context.Context, donationID int32) (db.Receipt, error) {
+	// Most downloads are of a receipt that already exists
+	latest, err := s.repo.GetLatestDonationReceipt(ctx, sql.NullInt32{Int32: donationID, Valid: true})
+	if err == nil {
+		if latest.VoidedAt.Valid {
+			return db.Receipt{}, ErrReceiptVoided
+		}
+		return latest, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return db.Receipt{}, err
+	}
+
+	var receipt db.Receipt
+	err = s.repo.ExecTx(ctx, func(q db.Querier) error {
+		donation, err := q.LockDonation(ctx, donationID)
+		if err != nil {
+			return err
+		}
+		// Another request may have issued it while this one waited for the lock
+		latest, err := q.GetLatestDonationReceipt(ctx, sql.NullInt32{Int32: donationID, Valid: true})
+		switch {
+		case err == nil && latest.VoidedAt.Valid:
+			return ErrReceiptVoided
+		case err == nil:
+			receipt = latest
+			return nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+		receipt, err = issueDonationReceipt(ctx, q, donation, nil)
+		return err
+	})
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	return receipt, nil
+}
+
+func (s *receiptService) Void(ctx context.Context, donationID int32, reason string) (db.Receipt, error) {
+	var receipt db.Receipt
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		latest, err := q.GetLatestDonationReceipt(ctx, sql.NullInt32{Int32: donationID, Valid: true})
+		if err != nil {
+			return err
+		}
+		if latest.VoidedAt.Valid {
+			return ErrReceiptVoided
+		}
+		receipt, err = voidReceipt(ctx, q, latest, reason)
+		return err
+	})
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	return receipt, nil
+}
+
+func (s *receiptService) Reissue(ctx context.Context, donationID int32, reason string) (db.Receipt, error) {
+	var receipt db.Receipt
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		donation, err := q.LockDonation(ctx, donationID)
+		if err != nil {
+			return err
+		}
+		var replaces *db.Receipt
+		latest, err := q.GetLatestDonationReceipt(ctx, sql.NullInt32{Int32: donationID, Valid: true})
+		switch {
+		case err == nil && !latest.VoidedAt.Valid:
+			if latest, err = voidReceipt(ctx, q, latest, reason); err != nil {
+				return err
+			}
+			replaces = &latest
+		case err == nil:
+			replaces = &latest
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+		receipt, err = issueDonationReceipt(ctx, q, donation, replaces)
+		return err
+	})
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	return receipt, nil
+}
+
+func (s *receiptService) Statement(ctx context.Context, userID int32, year int) (db.Receipt, error) {
+	var receipt db.Receipt
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		if err := q.LockStatement(ctx, db.LockStatementParams{UserID: userID, Year: int32(year)}); err != nil {
+			return err
+		}
+		donations, err := q.ListStatementDonations(ctx, db.ListStatementDonationsParams{
+			UserID:    userID,
+			YearStart: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+			YearEnd:   time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			return err
+		}
+		if len(donations) == 0 {
+			return ErrNoDonationsInYear
+		}
+		lines := make([]receipts.Line, len(donations))
+		for i, d := range donations {
+			lines[i] = receipts.Line{Date: d.CreatedAt.Time, Cause: d.CauseName, DonationType: d.DonationType.String, Amount: d.Amount.String}
+		}
+		total, err := receipts.Total(lines)
+		if err != nil {
+			return err
+		}
+
+		var replaces *db.Receipt
+		latest, err := q.GetLatestStatement(ctx, db.GetLatestStatementParams{UserID: userID, Year: int32(year)})
+		switch {
+		case err == nil && !latest.VoidedAt.Valid:
+			if latest.Amount == total && int(latest.DonationCount) == len(lines) {
+				receipt = latest
+				return nil
+			}
+			if latest, err = voidReceipt(ctx, q, latest, "Superseded: the donations in the year have changed"); err != nil {
+				return err
+			}
+			replaces = &latest
+		case err == nil:
+			replaces = &latest
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		branding, err := receiptBranding(ctx, q, 0)
+		if err != nil {
+			return err
+		}
+		receipt, err = issue(ctx, q, receipts.Document{
+			Kind:     receipts.KindStatement,
+			Year:     year,
+			Branding: branding,
+			Lines:    lines,
+		}, userID, sql.NullInt32{}, replaces)
+		return err
+	})
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	return receipt, nil
+}
+
+func (s *receiptService) List(ctx context.Context, userID int32) ([]db.ListReceiptsByUserRow, error) {
+	return s.repo.ListReceiptsByUser(ctx, userID)
+}
+
+func (s *receiptService) Template(ctx context.Context, causeID int32) (db.ReceiptTemplate, error) {
+	templates, err := s.repo.ListReceiptTemplates(ctx, sql.NullInt32{Int32: causeID, Valid: causeID != 0})
+	if err != nil {
+		return db.ReceiptTemplate{}, err
+	}
+	for _, t := range templates {
+		if t.CauseID.Int32 == causeID {
+			return t, nil
+		}
+	}
+	// Nothing saved yet: an empty template, which prints the defaults
+	return db.ReceiptTemplate{CauseID: sql.NullInt32{Int32: causeID, Valid: causeID != 0}}, nil
+}
+
+func (s *receiptService) SaveTemplate(ctx context.Context, causeID int32, b receipts.Branding) (db.ReceiptTemplate, error) {
+	var template db.ReceiptTemplate
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		if causeID == 0 {
+			template, err = q.SaveOrganizationReceiptTemplate(ctx, db.SaveOrganizationReceiptTemplateParams{
+				OrganizationName:   b.OrganizationName,
+				Address:            b.Address,
+				RegistrationNumber: b.RegistrationNumber,
+				ContactEmail:       b.ContactEmail,
+				AccentColor:        b.AccentColor,
+				HeaderText:         b.HeaderText,
+				FooterText:         b.FooterText,
+				Signatory:          b.Signatory,
+			})
+		} else {
+			template, err = q.SaveCauseReceiptTemplate(ctx, db.SaveCauseReceiptTemplateParams{
+				CauseID:            sql.NullInt32{Int32: causeID, Valid: true},
+				OrganizationName:   b.OrganizationName,
+				Address:            b.Address,
+				RegistrationNumber: b.RegistrationNumber,
+				ContactEmail:       b.ContactEmail,
+				AccentColor:        b.AccentColor,
+				HeaderText:         b.HeaderText,
+				FooterText:         b.FooterText,
+				Signatory:          b.Signatory,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityReceiptTemplate, template.ID, nil, template)
+	})
+	if err != nil {
+		return db.ReceiptTemplate{}, err
+	}
+	return template, nil
+}
+
+// collected reports whether a donation with status counts as received. Failed, refunded and
+// cancelled donations are left off receipts and statements, as they are left out of cause totals.
+func collected(status sql.NullString) bool {
+	switch status.String {
+	case "failed", "refunded", "cancelled":
+		return false
+	}
+	return true
+}
+
+// issueDonationReceipt issues a receipt for one donation, with its cause's branding
+func issueDonationReceipt(ctx context.Context, q db.Querier, donation db.Donation, replaces *db.Receipt) (db.Receipt, error) {
+	if !donation.UserID.Valid || !donation.Amount.Valid {
+		return db.Receipt{}, ErrDonationNotReceiptable
+	}
+	if !collected(donation.Status) {
+		return db.Receipt{}, ErrDonationNotCollected
+	}
+	var cause string
+	if donation.CauseID.Valid {
+		row, err := q.GetReceiptCause(ctx, donation.CauseID.Int32)
+		if err != nil {
+			return db.Receipt{}, err
+		}
+		cause = row.Name
+	}
+	branding, err := receiptBranding(ctx, q, donation.CauseID.Int32)
+	if err != nil {
+		return db.Receipt{}, err
+	}
+
+	return issue(ctx, q, receipts.Document{
+		Kind:     receipts.KindDonation,
+		Year:     donation.CreatedAt.Time.Year(),
+		Branding: branding,
+		Lines: []receipts.Line{{
+			Date:         donation.CreatedAt.Time,
+			Cause:        cause,
+			DonationType: donation.DonationType.String,
+			Amount:       donation.Amount.String,
+		}},
+	}, donation.UserID.Int32, sql.NullInt32{Int32: donation.ID, Valid: true}, replaces)
+}
+
+// issue numbers doc, fills in the donor, renders it and stores the PDF
+func issue(ctx context.Context, q db.Querier, doc receipts.Document, userID int32, donationID sql.NullInt32, replaces *db.Receipt) (db.Receipt, error) {
+	donor, err := q.GetReceiptDonor(ctx, userID)
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	doc.DonorName = strings.TrimSpace(donor.FirstName.String + " " + donor.LastName.String)
+	if doc.DonorName == "" {
+		doc.DonorName = donor.Username
+	}
+	doc.DonorEmail = donor.Email
+
+	doc.IssuedAt = time.Now().UTC().Truncate(time.Second)
+	n, err := q.NextReceiptNumber(ctx, db.NextReceiptNumberParams{Series: receipts.Series(doc.Kind), Year: int32(doc.IssuedAt.Year())})
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	doc.Number = receipts.Number(doc.Kind, doc.IssuedAt.Year(), n)
+	var replacesID sql.NullInt32
+	if replaces != nil {
+		doc.Replaces = replaces.Number
+		replacesID = sql.NullInt32{Int32: replaces.ID, Valid: true}
+	}
+
+	pdf, err := receipts.Render(doc)
+	if err != nil {
+		return db.Receipt{}, fmt.Errorf("rendering receipt %s: %w", doc.Number, err)
+	}
+	total, err := receipts.Total(doc.Lines)
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	sum := sha256.Sum256(pdf)
+	receipt, err := q.CreateReceipt(ctx, db.CreateReceiptParams{
+		Number:        doc.Number,
+		Kind:          doc.Kind,
+		UserID:        userID,
+		DonationID:    donationID,
+		Year:          int32(doc.Year),
+		Amount:        total,
+		DonationCount: int32(len(doc.Lines)),
+		Pdf:           pdf,
+		Sha256:        hex.EncodeToString(sum[:]),
+		Replaces:      replacesID,
+		IssuedAt:      doc.IssuedAt,
+	})
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	return receipt, record(ctx, q, audit.ActionCreate, audit.EntityReceipt, receipt.ID, nil, receipt)
+}
+
+func voidReceipt(ctx context.Context, q db.Querier, before db.Receipt, reason string) (db.Receipt, error) {
+	receipt, err := q.VoidReceipt(ctx, db.VoidReceiptParams{ID: before.ID, VoidReason: nullString(reason)})
+	if err != nil {
+		return db.Receipt{}, err
+	}
+	return receipt, record(ctx, q, audit.ActionUpdate, audit.EntityReceipt, receipt.ID, before, receipt)
+}
+
+// receiptBranding merges a cause's template over the organization's; causeID 0 is the organization's alone
+func receiptBranding(ctx context.Context, q db.Querier, causeID int32) (receipts.Branding, error) {
+	templates, err := q.ListReceiptTemplates(ctx, sql.NullInt32{Int32: causeID, Valid: causeID != 0})
+	if err != nil {
+		return receipts.Branding{}, err
+	}
+	var b receipts.Branding
+	// The organization's template sorts first
+	for _, t := range templates {
+		b = b.Merge(receipts.Branding{
+			OrganizationName:   t.OrganizationName,
+			Address:            t.Address,
+			RegistrationNumber: t.RegistrationNumber,
+			ContactEmail:       t.ContactEmail,
+			AccentColor:        t.AccentColor,
+			HeaderText:         t.HeaderText,
+			FooterText:         t.FooterText,
+			Signatory:          t.Signatory,
+		})
+	}
+	return b, nil
+}
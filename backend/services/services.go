@@ -0,0 +1,111 @@
+// Package services holds the domain logic behind the API handlers: hashing, sessions, parameter
+// mapping, optimistic locking and audit records. Services work on a db.Repository and a plain
+// context, so they can run against Postgres or an in-memory fake. The acting user for audit entries
+// comes from the audit.Actor on the context.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/problem"
+	"play4good-backend/schemas"
+)
+
+var (
+	// ErrVersionConflict means the row changed after the client fetched it
+	ErrVersionConflict error = problem.New(http.StatusPreconditionFailed, problem.CodeVersionConflict, "the resource has changed since it was fetched; reload it and try again")
+	// ErrEndBeforeStart rejects a patch whose merged dates would end before they start
+	ErrEndBeforeStart error = problem.Invalid("end_date", "gtfield", "must be after start_date")
+)
+
+// VersionCheck reports whether an update may be applied to a row at version. Handlers build one
+// from the request's If-Match header.
+type VersionCheck func(version int32) bool
+
+// Services bundles one of each service
+type Services struct {
+	Auth         AuthService
+	Donations    DonationService
+	Teams        TeamService
+	Causes       CauseService
+	Leaderboards LeaderboardService
+	Imports      ImportService
+	Receipts     ReceiptService
+}
+
+// New returns the services backed by repo
+func New(repo db.Repository) Services {
+	return Services{
+		Auth:         NewAuthService(repo),
+		Donations:    NewDonationService(repo),
+		Teams:        NewTeamService(repo),
+		Causes:       NewCauseService(repo),
+		Leaderboards: NewLeaderboardService(repo),
+		Imports:      NewImportService(repo),
+		Receipts:     NewReceiptService(repo),
+	}
+}
+
+func record(ctx context.Context, q db.Querier, action, entityType string, entityID interface{}, before, after interface{}) error {
+	_, err := audit.Record(ctx, q, audit.Entry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     before,
+		After:      after,
+	})
+	return err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func amount(f float64) sql.NullString {
+	return sql.NullString{String: strconv.FormatFloat(f, 'f', -1, 64), Valid: true}
+}
+
+func patchString(f schemas.Field[string]) sql.NullString {
+	return sql.NullString{String: f.Value, Valid: f.Present()}
+}
+
+func patchTime(f schemas.Field[time.Time]) sql.NullTime {
+	return sql.NullTime{Time: f.Value, Valid: f.Present()}
+}
+
+func patchAmount(f schemas.Field[float64]) sql.NullString {
+	return sql.NullString{String: strconv.FormatFloat(f.Value, 'f', -1, 64), Valid: f.Present()}
+}
+
+// checkPatchedDates compares the dates a row will have once the patch is applied
+func checkPatchedDates(start, end sql.NullTime, startPatch, endPatch schemas.Field[time.Time]) error {
+	if startPatch.Set {
+		start = patchTime(startPatch)
+	}
+	if endPatch.Set {
+		end = patchTime(endPatch)
+	}
+	if start.Valid && end.Valid && !end.Time.After(start.Time) {
+		return ErrEndBeforeStart
+	}
+	return nil
+}
+
+// checkVersion applies check to the row's current version
+func checkVersion(check VersionCheck, version int32) error {
+	if !check(version) {
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"play4good-backend/audit"
+	db "play4good-backend/db/sqlc"
+	"play4good-backend/schemas"
+)
+
+// TeamService manages teams and their members
+type TeamService interface {
+	Create(ctx context.Context, req schemas.TeamCreateRequest) (db.Team, error)
+	Get(ctx context.Context, id int32) (db.Team, error)
+	List(ctx context.Context, limit, offset int32) ([]db.Team, error)
+	Update(ctx context.Context, id int32, req schemas.TeamUpdateRequest, check VersionCheck) (db.Team, error)
+	Patch(ctx context.Context, id int32, patch schemas.TeamPatchRequest, check VersionCheck) (db.Team, error)
+	// Delete soft-deletes a team; admins can restore it
+	Delete(ctx context.Context, id int32) error
+
+	AddMember(ctx context.Context, req schemas.UserTeamCreateRequest) (db.UserTeam, error)
+	SetMemberRole(ctx context.Context, teamID, userID int32, role string) (db.UserTeam, error)
+	RemoveMember(ctx context.Context, teamID, userID int32) error
+}
+
+type teamService struct {
+	repo db.Repository
+}
+
+func NewTeamService(repo db.Repository) TeamService {
+	return &teamService{repo: repo}
+}
+
+func (s *teamService) Create(ctx context.Context, req schemas.TeamCreateRequest) (db.Team, error) {
+	var team db.Team
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		team, err = q.CreateTeam(ctx, db.CreateTeamParams{
+			Name:        req.Name,
+			Description: nullString(req.Description),
+			AvatarUrl:   nullString(req.AvatarURL),
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityTeam, team.ID, nil, team)
+	})
+	return team, err
+}
+
+func (s *teamService) Get(ctx context.Context, id int32) (db.Team, error) {
+	return s.repo.GetTeam(ctx, id)
+}
+
+func (s *teamService) List(ctx context.Context, limit, offset int32) ([]db.Team, error) {
+	return s.repo.ListTeams(ctx, db.ListTeamsParams{Limit: limit, Offset: offset})
+}
+
+func (s *teamService) Update(ctx context.Context, id int32, req schemas.TeamUpdateRequest, check VersionCheck) (db.Team, error) {
+	var team db.Team
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetTeam(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(check, before.Version); err != nil {
+			return err
+		}
+		team, err = q.UpdateTeam(ctx, db.UpdateTeamParams{
+			ID:          id,
+			Name:        req.Name,
+			Description: nullString(req.Description),
+			AvatarUrl:   nullString(req.AvatarURL),
+			Version:     before.Version,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityTeam, id, before, team)
+	})
+	return team, err
+}
+
+func (s *teamService) Patch(ctx context.Context, id int32, patch schemas.TeamPatchRequest, check VersionCheck) (db.Team, error) {
+	var team db.Team
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetTeam(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(check, before.Version); err != nil {
+			return err
+		}
+		team, err = q.PatchTeam(ctx, db.PatchTeamParams{
+			SetName:        patch.Name.Set,
+			Name:           patch.Name.Value,
+			SetDescription: patch.Description.Set,
+			Description:    patchString(patch.Description),
+			SetAvatarUrl:   patch.AvatarURL.Set,
+			AvatarUrl:      patchString(patch.AvatarURL),
+			ID:             id,
+			Version:        before.Version,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityTeam, id, before, team)
+	})
+	return team, err
+}
+
+func (s *teamService) Delete(ctx context.Context, id int32) error {
+	return s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetTeam(ctx, id)
+		if err != nil {
+			return err
+		}
+		after, err := q.DeleteTeam(ctx, id)
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionDelete, audit.EntityTeam, id, before, after)
+	})
+}
+
+func (s *teamService) AddMember(ctx context.Context, req schemas.UserTeamCreateRequest) (db.UserTeam, error) {
+	var member db.UserTeam
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		member, err = q.AddUserToTeam(ctx, db.AddUserToTeamParams{
+			UserID: int32(req.UserID),
+			TeamID: int32(req.TeamID),
+			Role:   req.Role,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionCreate, audit.EntityTeamMember, memberID(member.TeamID, member.UserID), nil, member)
+	})
+	return member, err
+}
+
+func (s *teamService) SetMemberRole(ctx context.Context, teamID, userID int32, role string) (db.UserTeam, error) {
+	var member db.UserTeam
+	err := s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUserTeam(ctx, db.GetUserTeamParams{UserID: userID, TeamID: teamID})
+		if err != nil {
+			return err
+		}
+		member, err = q.UpdateUserTeamRole(ctx, db.UpdateUserTeamRoleParams{UserID: userID, TeamID: teamID, Role: role})
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionUpdate, audit.EntityTeamMember, memberID(teamID, userID), before, member)
+	})
+	return member, err
+}
+
+func (s *teamService) RemoveMember(ctx context.Context, teamID, userID int32) error {
+	return s.repo.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUserTeam(ctx, db.GetUserTeamParams{UserID: userID, TeamID: teamID})
+		if err != nil {
+			return err
+		}
+		if err := q.RemoveUserFromTeam(ctx, db.RemoveUserFromTeamParams{UserID: userID, TeamID: teamID}); err != nil {
+			return err
+		}
+		return record(ctx, q, audit.ActionDelete, audit.EntityTeamMember, memberID(teamID, userID), before, nil)
+	})
+}
+
+// memberID identifies a team membership in the audit log
+func memberID(teamID, userID int32) string {
+	return fmt.Sprintf("%d:%d", teamID, userID)
+}
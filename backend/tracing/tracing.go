@@ -0,0 +1,121 @@
+// Package tracing configures the process-wide OpenTelemetry tracer provider and exporter
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"play4good-backend/util"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters accepted by TRACE_EXPORTER
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Name is the instrumentation scope of spans started by this service
+const Name = "play4good-backend"
+
+// Tracer returns the tracer every package in the service starts its spans from
+func Tracer() trace.Tracer {
+	return otel.Tracer(Name)
+}
+
+// Setup installs a tracer provider exporting to the configured backend and W3C trace context
+// propagation. The returned function flushes buffered spans; call it before exiting. With the
+// "none" exporter spans are still created, so trace IDs appear in logs, but nothing is exported.
+func Setup(ctx context.Context, config util.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(config.TraceServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("could not build trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.TraceSampleRatio))),
+	}
+
+	exporter, closer, err := newExporter(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, config util.Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch config.TraceExporter {
+	case ExporterNone, "":
+		return nil, nil, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if config.TraceOTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(config.TraceOTLPEndpoint))
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create OTLP exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create stdout exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterFile:
+		f, err := os.OpenFile(config.TraceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("could not create file exporter: %w", err)
+		}
+		return exporter, f, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter %q: use none, otlp, stdout or file", config.TraceExporter)
+	}
+}
+
+// IDs returns the trace and span ID of the span in ctx, or empty strings when there is none
+func IDs(ctx context.Context) (traceID, spanID string) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", ""
+	}
+	return sc.TraceID().String(), sc.SpanID().String()
+}
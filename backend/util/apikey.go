@@ -0,0 +1,74 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix starts every API key so bearer credentials can be told apart from session tokens
+const APIKeyPrefix = "p4g_"
+
+// RequiredScopeKey is the gin context key holding the scope a route requires from API keys
+const RequiredScopeKey = "requiredScope"
+
+// Scopes that API keys can be granted. Each route declares the one it needs.
+const (
+	ScopeUsersRead         = "users:read"
+	ScopeUsersWrite        = "users:write"
+	ScopeTeamsRead         = "teams:read"
+	ScopeTeamsWrite        = "teams:write"
+	ScopeCausesRead        = "causes:read"
+	ScopeCausesWrite       = "causes:write"
+	ScopeDonationsRead     = "donations:read"
+	ScopeDonationsWrite    = "donations:write"
+	ScopeLeaderboardsRead  = "leaderboards:read"
+	ScopeLeaderboardsWrite = "leaderboards:write"
+)
+
+var AllScopes = []string{
+	ScopeUsersRead,
+	ScopeUsersWrite,
+	ScopeTeamsRead,
+	ScopeTeamsWrite,
+	ScopeCausesRead,
+	ScopeCausesWrite,
+	ScopeDonationsRead,
+	ScopeDonationsWrite,
+	ScopeLeaderboardsRead,
+	ScopeLeaderboardsWrite,
+}
+
+// TeamScopes are the only scopes a team-scoped key may hold
+var TeamScopes = []string{ScopeTeamsRead, ScopeTeamsWrite}
+
+// GenerateAPIKey returns a new key and its public prefix. The prefix is stored in clear
+// so users can recognise their keys; the full key is only ever stored as HashToken(key).
+func GenerateAPIKey() (key string, prefix string, err error) {
+	id := make([]byte, 4)
+	secret := make([]byte, 24)
+	if _, err = rand.Read(id); err != nil {
+		return "", "", err
+	}
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix = APIKeyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + hex.EncodeToString(secret), prefix, nil
+}
+
+// IsAPIKey reports whether a bearer credential looks like an API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// HasScope reports whether scope is among the granted scopes
+func HasScope(granted []string, scope string) bool {
+	for _, s := range granted {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,10 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+    "time"
+
+    "github.com/spf13/viper"
+)
 
 type Config struct {
     DbDriver         string `mapstructure:"DB_DRIVER"`
@@ -9,7 +13,63 @@ type Config struct {
     PostgresPassword string `mapstructure:"POSTGRES_PASSWORD"`
     PostgresDb       string `mapstructure:"POSTGRES_DB"`
     ServerAddress    string `mapstructure:"SERVER_ADDRESS"`
+    // MetricsAddress is the port /metrics is served on, apart from the API; empty turns metrics off
+    MetricsAddress   string `mapstructure:"METRICS_ADDRESS"`
     JWTSecret        string `mapstructure:"JWT_Secret"`
+
+    // Connection pool limits for the database handle
+    DbMaxOpenConns    int           `mapstructure:"DB_MAX_OPEN_CONNS"`
+    DbMaxIdleConns    int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+    DbConnMaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+    DbConnMaxIdleTime time.Duration `mapstructure:"DB_CONN_MAX_IDLE_TIME"`
+
+    // MigrateOnStart applies pending migrations before serving; otherwise run "migrate up" first
+    MigrateOnStart bool `mapstructure:"MIGRATE_ON_START"`
+
+    // HTTP server timeouts; on SIGTERM in-flight requests and background jobs get ShutdownTimeout to finish
+    ReadTimeout     time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`
+    WriteTimeout    time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"`
+    IdleTimeout     time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+    ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+
+    // LogLevel is the minimum level written to the JSON log: debug, info, warn or error
+    LogLevel string `mapstructure:"LOG_LEVEL"`
+
+    // Tracing: TraceExporter is none, otlp, stdout or file. The OTLP endpoint is a full URL such as
+    // http://localhost:4318; when empty the exporter's standard OTEL_EXPORTER_OTLP_* variables apply
+    TraceExporter     string  `mapstructure:"TRACE_EXPORTER"`
+    TraceOTLPEndpoint string  `mapstructure:"TRACE_OTLP_ENDPOINT"`
+    TraceFile         string  `mapstructure:"TRACE_FILE"`
+    TraceServiceName  string  `mapstructure:"TRACE_SERVICE_NAME"`
+    TraceSampleRatio  float64 `mapstructure:"TRACE_SAMPLE_RATIO"`
+
+    // Links in outgoing emails point at the frontend
+    FrontendURL string `mapstructure:"FRONTEND_URL"`
+
+    // SMTP settings; when SMTPHost is empty emails are written to the log instead
+    SMTPHost     string `mapstructure:"SMTP_HOST"`
+    SMTPPort     int    `mapstructure:"SMTP_PORT"`
+    SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+    SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+    MailFrom     string `mapstructure:"MAIL_FROM"`
+
+    // Donations at or above this amount require a verified email
+    LargeDonationThreshold float64 `mapstructure:"LARGE_DONATION_THRESHOLD"`
+
+    // External sign-in: ID tokens from an OIDC issuer, or JWTs signed by the NextAuth frontend
+    OIDCIssuer     string `mapstructure:"OIDC_ISSUER"`
+    OIDCClientID   string `mapstructure:"OIDC_CLIENT_ID"`
+    NextAuthSecret string `mapstructure:"NEXTAUTH_SECRET"`
+
+    // Where login, email and two-factor throttle counters live: "postgres" (shared by all instances) or "memory"
+    LoginThrottleStore string `mapstructure:"LOGIN_THROTTLE_STORE"`
+
+    // Data and report exports can be downloaded for ExportTTL; erasure waits ErasureGracePeriod
+    ExportTTL          time.Duration `mapstructure:"EXPORT_TTL"`
+    ErasureGracePeriod time.Duration `mapstructure:"ERASURE_GRACE_PERIOD"`
+
+    // Soft-deleted users, teams and causes are purged after this long
+    SoftDeleteRetention time.Duration `mapstructure:"SOFT_DELETE_RETENTION"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -17,6 +77,30 @@ func LoadConfig(path string) (config Config, err error) {
     viper.SetConfigName("app")
     viper.SetConfigType("env")
 
+    viper.SetDefault("METRICS_ADDRESS", "9090")
+    viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
+    viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
+    viper.SetDefault("DB_CONN_MAX_LIFETIME", "30m")
+    viper.SetDefault("DB_CONN_MAX_IDLE_TIME", "5m")
+    viper.SetDefault("MIGRATE_ON_START", false)
+    viper.SetDefault("SERVER_READ_TIMEOUT", "15s")
+    viper.SetDefault("SERVER_WRITE_TIMEOUT", "30s")
+    viper.SetDefault("SERVER_IDLE_TIMEOUT", "120s")
+    viper.SetDefault("SHUTDOWN_TIMEOUT", "30s")
+    viper.SetDefault("LOG_LEVEL", "info")
+    viper.SetDefault("TRACE_EXPORTER", "none")
+    viper.SetDefault("TRACE_FILE", "traces.jsonl")
+    viper.SetDefault("TRACE_SERVICE_NAME", "play4good-api")
+    viper.SetDefault("TRACE_SAMPLE_RATIO", 1.0)
+    viper.SetDefault("FRONTEND_URL", "http://localhost:3000")
+    viper.SetDefault("SMTP_PORT", 587)
+    viper.SetDefault("MAIL_FROM", "no-reply@play4good.local")
+    viper.SetDefault("LARGE_DONATION_THRESHOLD", 1000)
+    viper.SetDefault("LOGIN_THROTTLE_STORE", "postgres")
+    viper.SetDefault("EXPORT_TTL", "168h")
+    viper.SetDefault("ERASURE_GRACE_PERIOD", "720h")
+    viper.SetDefault("SOFT_DELETE_RETENTION", "2160h")
+
     viper.AutomaticEnv()
 
     err = viper.ReadInConfig()
@@ -26,4 +110,4 @@ func LoadConfig(path string) (config Config, err error) {
 
     err = viper.Unmarshal(&config)
     return
-}
\ No newline at end of file
+}
@@ -0,0 +1,182 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// ExternalIdentity is the verified subject of an identity token from another provider
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+	AvatarURL     string
+}
+
+// NextAuthClaims are the claims expected in a JWT signed by the NextAuth frontend.
+// The frontend's jwt callback copies provider and providerAccountId from the OAuth account, and
+// email_verified from the provider's profile when the provider vouches for the address.
+type NextAuthClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	Picture           string `json:"picture"`
+	Provider          string `json:"provider"`
+	ProviderAccountID string `json:"providerAccountId"`
+	jwt.RegisteredClaims
+}
+
+type oidcClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// IdentityVerifier checks identity tokens against the configured OIDC issuer's JWKS
+// or the shared NextAuth secret
+type IdentityVerifier struct {
+	issuer         string
+	clientID       string
+	nextAuthSecret []byte
+	httpClient     *http.Client
+
+	mu       sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewIdentityVerifier(config Config) *IdentityVerifier {
+	return &IdentityVerifier{
+		issuer:         config.OIDCIssuer,
+		clientID:       config.OIDCClientID,
+		nextAuthSecret: []byte(config.NextAuthSecret),
+		// Discovery and JWKS fetches show up as child spans of the sign-in request
+		httpClient: &http.Client{Timeout: 10 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
+}
+
+// Verify validates a raw token and returns the identity it asserts.
+// HMAC-signed tokens are treated as NextAuth JWTs, anything else as an OIDC ID token.
+func (v *IdentityVerifier) Verify(ctx context.Context, rawToken string) (ExternalIdentity, error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(rawToken, jwt.MapClaims{})
+	if err != nil {
+		return ExternalIdentity{}, errors.New("malformed identity token")
+	}
+
+	if strings.HasPrefix(unverified.Method.Alg(), "HS") {
+		return v.verifyNextAuth(rawToken)
+	}
+	return v.verifyOIDC(ctx, rawToken)
+}
+
+func (v *IdentityVerifier) verifyNextAuth(rawToken string) (ExternalIdentity, error) {
+	if len(v.nextAuthSecret) == 0 {
+		return ExternalIdentity{}, errors.New("NextAuth sign-in is not configured")
+	}
+
+	claims := &NextAuthClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return v.nextAuthSecret, nil
+	}, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}), jwt.WithExpirationRequired())
+	if err != nil {
+		return ExternalIdentity{}, fmt.Errorf("invalid identity token: %w", err)
+	}
+
+	subject := claims.ProviderAccountID
+	if subject == "" {
+		subject = claims.Subject
+	}
+	provider := claims.Provider
+	if provider == "" {
+		provider = "nextauth"
+	}
+	if subject == "" {
+		return ExternalIdentity{}, errors.New("identity token has no subject")
+	}
+
+	firstName, lastName := splitName(claims.Name)
+	return ExternalIdentity{
+		Provider: provider,
+		Subject:  subject,
+		Email:    claims.Email,
+		// Not every OAuth provider verifies addresses, so without the claim the email is unverified
+		EmailVerified: claims.EmailVerified,
+		FirstName:     firstName,
+		LastName:      lastName,
+		AvatarURL:     claims.Picture,
+	}, nil
+}
+
+func (v *IdentityVerifier) verifyOIDC(ctx context.Context, rawToken string) (ExternalIdentity, error) {
+	verifier, err := v.oidcVerifier(ctx)
+	if err != nil {
+		return ExternalIdentity{}, err
+	}
+
+	idToken, err := verifier.Verify(oidc.ClientContext(ctx, v.httpClient), rawToken)
+	if err != nil {
+		return ExternalIdentity{}, fmt.Errorf("invalid identity token: %w", err)
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return ExternalIdentity{}, fmt.Errorf("invalid identity token claims: %w", err)
+	}
+
+	firstName, lastName := claims.GivenName, claims.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName = splitName(claims.Name)
+	}
+
+	return ExternalIdentity{
+		Provider:      idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		FirstName:     firstName,
+		LastName:      lastName,
+		AvatarURL:     claims.Picture,
+	}, nil
+}
+
+// oidcVerifier discovers the issuer on first use so the server can start while the issuer is unreachable
+func (v *IdentityVerifier) oidcVerifier(ctx context.Context) (*oidc.IDTokenVerifier, error) {
+	if v.issuer == "" {
+		return nil, errors.New("OIDC sign-in is not configured")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.verifier != nil {
+		return v.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, v.httpClient), v.issuer)
+	if err != nil {
+		return nil, fmt.Errorf("could not reach OIDC issuer: %w", err)
+	}
+
+	// The provider's key set keeps using this client to refresh the JWKS
+	v.verifier = provider.Verifier(&oidc.Config{ClientID: v.clientID})
+	return v.verifier, nil
+}
+
+func splitName(name string) (string, string) {
+	first, last, _ := strings.Cut(strings.TrimSpace(name), " ")
+	return first, strings.TrimSpace(last)
+}
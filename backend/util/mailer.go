@@ -0,0 +1,61 @@
+package util
+
+import (
+	"fmt"
+	"log/slog"
+	"net/smtp"
+	"strings"
+)
+
+// Mailer sends plain-text emails to users
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// NewMailer returns an SMTP mailer, or a mailer that only logs when no SMTP host is configured
+func NewMailer(config Config) Mailer {
+	if config.SMTPHost == "" {
+		return logMailer{}
+	}
+	return smtpMailer{
+		addr: fmt.Sprintf("%s:%d", config.SMTPHost, config.SMTPPort),
+		host: config.SMTPHost,
+		user: config.SMTPUsername,
+		pass: config.SMTPPassword,
+		from: config.MailFrom,
+	}
+}
+
+type smtpMailer struct {
+	addr string
+	host string
+	user string
+	pass string
+	from string
+}
+
+func (m smtpMailer) Send(to, subject, body string) error {
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.pass, m.host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(m.addr, auth, m.from, []string{to}, []byte(msg))
+}
+
+type logMailer struct{}
+
+func (logMailer) Send(to, subject, body string) error {
+	slog.Info("mail", "to", to, "subject", subject, "body", body)
+	return nil
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter allows at most limit events per key within a sliding window
+type RateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+	}
+}
+
+// Allow records an event for key and reports whether it is within the limit.
+// When it is not, the returned duration is how long until the next event is allowed.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-l.window)
+
+	recent := l.hits[key][:0]
+	for _, t := range l.hits[key] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.hits[key] = recent
+		return false, recent[0].Sub(cutoff)
+	}
+
+	l.hits[key] = append(recent, now)
+	l.sweep(cutoff)
+	return true, 0
+}
+
+// sweep drops keys with no recent events so the map does not grow without bound
+func (l *RateLimiter) sweep(cutoff time.Time) {
+	if len(l.hits) < 1024 {
+		return
+	}
+	for key, times := range l.hits {
+		if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+			delete(l.hits, key)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -74,3 +77,66 @@ func ParseToken(tokenString string) (int, error) {
 
 	return 0, errors.New("invalid token")
 }
+
+// Purposes for single-use action tokens
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+	// Handed out between the password and two-factor steps of login
+	TokenPurposeLoginChallenge = "login_2fa"
+	// Emailed when an account is locked after repeated failed logins
+	TokenPurposeUnlockAccount = "unlock_account"
+)
+
+type ActionClaims struct {
+	UserID  int    `json:"user_id"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken creates a signed token for a one-off action such as email verification.
+// Single use is enforced by storing HashToken of the result and consuming it on redemption.
+func GenerateActionToken(userID int, purpose string, ttl time.Duration) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	claims := &ActionClaims{
+		UserID:  userID,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(nonce),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtKey)
+}
+
+// ParseActionToken checks the signature, expiry and purpose of an action token and returns its user ID
+func ParseActionToken(tokenString, purpose string) (int, error) {
+	claims := &ActionClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtKey, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !token.Valid || claims.Purpose != purpose {
+		return 0, errors.New("invalid token")
+	}
+
+	return claims.UserID, nil
+}
+
+// HashToken returns the hex SHA-256 of a token so only digests are stored in the database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}